@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/api/v1/cost/estimate": {
             "get": {
-                "description": "Fetch estimated cost details based on provider, region, instance type, and resource specifications. Pagination support is provided through ` + "`" + `Page` + "`" + ` and ` + "`" + `Size` + "`" + ` parameters.",
+                "description": "Fetch estimated cost details based on provider, region, instance type, and resource specifications. Pagination support is provided through `+"`"+`Page`+"`"+` and `+"`"+`Size`+"`"+` parameters.",
                 "consumes": [
                     "application/json"
                 ],
@@ -101,7 +101,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Update the estimate cost based on provided specifications and retrieve the updated cost estimation. Required fields for each specification include ` + "`" + `ProviderName` + "`" + `, ` + "`" + `RegionName` + "`" + `, and ` + "`" + `InstanceType` + "`" + `. Specifications can also be provided in a formatted string using ` + "`" + `+` + "`" + ` delimiter.",
+                "description": "Update the estimate cost based on provided specifications and retrieve the updated cost estimation. Required fields for each specification include `+"`"+`ProviderName`+"`"+`, `+"`"+`RegionName`+"`"+`, and `+"`"+`InstanceType`+"`"+`. Specifications can also be provided in a formatted string using `+"`"+`+`+"`"+` delimiter.",
                 "consumes": [
                     "application/json"
                 ],
@@ -370,9 +370,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/generators": {
+        "/api/v1/load/baselines": {
             "get": {
-                "description": "Retrieve a list of all installed load generators with pagination support.",
+                "description": "Retrieve the pinned baselines with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -380,10 +380,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Generator Management]"
+                    "[Load Test Baseline Management]"
                 ],
-                "summary": "Get All Load Generator Install Info",
-                "operationId": "GetAllLoadGeneratorInstallInfo",
+                "summary": "Get All Load Test Baselines",
+                "operationId": "GetAllLoadTestBaselines",
                 "parameters": [
                     {
                         "type": "integer",
@@ -393,22 +393,40 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default 10, max 10)",
+                        "description": "Number of items per page (default 10, max 100)",
                         "name": "size",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Filter by scenario catalog entry",
+                        "name": "scenarioCatalogId",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
+                        "description": "Filter by namespace",
+                        "name": "nsId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by infra",
+                        "name": "infraId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by node",
+                        "name": "nodeId",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved monitoring agent information",
+                        "description": "Successfully retrieved load test baselines",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadGeneratorInstallInfoResult"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestBaselinesResult"
                         }
                     },
                     "400": {
@@ -418,7 +436,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve monitoring agent information",
+                        "description": "Failed to retrieve load test baselines",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -426,7 +444,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Install a load generator either locally or remotely.",
+                "description": "Pin a successful run as the baseline of a scenario catalog entry or of a target node; with neither given, of the node the run was sent to. It replaces the baseline the entry or node had. Every run of the entry, or else of the node, that finishes successfully afterwards is compared with it, and its state carries the verdict.",
                 "consumes": [
                     "application/json"
                 ],
@@ -434,36 +452,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Generator Management]"
+                    "[Load Test Baseline Management]"
                 ],
-                "summary": "Install Load Generator",
-                "operationId": "InstallLoadGenerator",
+                "summary": "Pin Load Test Baseline",
+                "operationId": "PinLoadTestBaseline",
                 "parameters": [
                     {
-                        "description": "Load Generator Installation Request",
+                        "description": "Pin Load Test Baseline Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/app.InstallLoadGeneratorReq"
+                            "$ref": "#/definitions/app.PinLoadTestBaselineReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully installed load generator",
+                        "description": "Successfully pinned load test baseline",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadGeneratorInstallInfoResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestBaselineResult"
                         }
                     },
                     "400": {
-                        "description": "Load generator installation info is not correct.| available install locations are remote or local.",
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "load test not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to pin load test baseline",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -471,9 +495,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/generators/{loadGeneratorInstallInfoId}": {
+        "/api/v1/load/baselines/{baselineId}": {
             "delete": {
-                "description": "Uninstall a previously installed load generator.",
+                "description": "Unpin a baseline by ID. Runs already compared with it keep their verdict.",
                 "consumes": [
                     "application/json"
                 ],
@@ -481,34 +505,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Generator Management]"
+                    "[Load Test Baseline Management]"
                 ],
-                "summary": "Uninstall Load Generator",
-                "operationId": "UninstallLoadGenerator",
+                "summary": "Delete Load Test Baseline",
+                "operationId": "DeleteLoadTestBaseline",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "load generator install info id",
-                        "name": "loadGeneratorInstallInfoId",
+                        "type": "integer",
+                        "description": "Load Test Baseline ID",
+                        "name": "baselineId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully uninstall load generator",
+                        "description": "Successfully deleted load test baseline",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "400": {
-                        "description": "Load generator install info id must be number.",
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Load test baseline not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "ant server has got error. please try again.",
+                        "description": "Failed to delete load test baseline",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -516,9 +546,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/monitoring/agent": {
+        "/api/v1/load/data-sets": {
             "get": {
-                "description": "Retrieve monitoring agent information based on specified criteria.",
+                "description": "Retrieve the stored CSV data sets, without their content, with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -526,47 +556,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Monitoring Agent Management]"
+                    "[Load Test Data Set Management]"
                 ],
-                "summary": "Retrieve Monitoring Agent Information",
-                "operationId": "GetAllMonitoringAgentInfos",
+                "summary": "Get All Load Test Data Sets",
+                "operationId": "GetAllLoadTestDataSets",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Namespace ID",
-                        "name": "nsId",
+                        "type": "integer",
+                        "description": "Page number for pagination (default 1)",
+                        "name": "page",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "MCI ID",
-                        "name": "infraId",
+                        "type": "integer",
+                        "description": "Number of items per page (default 10, max 100)",
+                        "name": "size",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "VM ID",
-                        "name": "nodeId",
+                        "description": "Filter by data set name",
+                        "name": "name",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test data sets",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestDataSetsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test data sets",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload a CSV file for load tests to read a line per iteration. The first line names the columns, which requests reference as ${column}. A run refers to the data set by its id.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Data Set Management]"
+                ],
+                "summary": "Create Load Test Data Set",
+                "operationId": "CreateLoadTestDataSet",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file whose first line names the columns",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of results per page",
-                        "name": "size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Data set name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Data set description",
+                        "name": "description",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved monitoring agent information",
+                        "description": "Successfully created load test data set",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_GetAllMonitoringAgentInfoResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestDataSetResult"
                         }
                     },
                     "400": {
@@ -576,7 +650,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to create load test data set",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -584,9 +658,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/monitoring/agent/install": {
-            "post": {
-                "description": "Install a monitoring agent on specific mci.",
+        "/api/v1/load/data-sets/{dataSetId}": {
+            "get": {
+                "description": "Retrieve a stored CSV data set by ID: its columns, number of lines and size.",
                 "consumes": [
                     "application/json"
                 ],
@@ -594,46 +668,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Monitoring Agent Management]"
+                    "[Load Test Data Set Management]"
                 ],
-                "summary": "Install Metrics Monitoring Agent",
-                "operationId": "InstallMonitoringAgent",
+                "summary": "Get Load Test Data Set",
+                "operationId": "GetLoadTestDataSet",
                 "parameters": [
                     {
-                        "description": "Monitoring Agent Installation Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/app.MonitoringAgentInstallationReq"
-                        }
+                        "type": "integer",
+                        "description": "Load Test Data Set ID",
+                        "name": "dataSetId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully installed monitoring agent",
+                        "description": "Successfully retrieved load test data set",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_MonitoringAgentInstallationResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestDataSetResult"
                         }
                     },
                     "400": {
-                        "description": "Monitoring agent installation info is not correct.",
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Load test data set not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to retrieve load test data set",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/monitoring/agent/uninstall": {
-            "post": {
-                "description": "Uninstall monitoring agents from specified VMs or all VMs in an mci.",
+            },
+            "delete": {
+                "description": "Delete a stored CSV data set by ID. Runs that already read it keep their own copy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -641,26 +717,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Monitoring Agent Management]"
+                    "[Load Test Data Set Management]"
                 ],
-                "summary": "Uninstall Monitoring Agents",
-                "operationId": "UninstallMonitoringAgent",
+                "summary": "Delete Load Test Data Set",
+                "operationId": "DeleteLoadTestDataSet",
                 "parameters": [
                     {
-                        "description": "Monitoring Agent Uninstallation Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/app.MonitoringAgentInstallationReq"
-                        }
+                        "type": "integer",
+                        "description": "Load Test Data Set ID",
+                        "name": "dataSetId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Number of affected results",
+                        "description": "Successfully deleted load test data set",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-int64"
+                            "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "400": {
@@ -669,8 +743,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
+                    "404": {
+                        "description": "Load test data set not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to delete load test data set",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -678,9 +758,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/templates/test-scenario-catalogs": {
+        "/api/v1/load/generators": {
             "get": {
-                "description": "Retrieve a list of all load test scenario catalogs with pagination support.",
+                "description": "Retrieve a list of all installed load generators with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -688,10 +768,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Scenario Catalog Management]"
+                    "[Load Generator Management]"
                 ],
-                "summary": "Get All Load Test Scenario Catalogs",
-                "operationId": "GetAllLoadTestScenarioCatalogs",
+                "summary": "Get All Load Generator Install Info",
+                "operationId": "GetAllLoadGeneratorInstallInfo",
                 "parameters": [
                     {
                         "type": "integer",
@@ -701,22 +781,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default 10, max 100)",
+                        "description": "Number of items per page (default 10, max 10)",
                         "name": "size",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by catalog name",
-                        "name": "name",
+                        "description": "Filter by status",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test scenario catalogs",
+                        "description": "Successfully retrieved monitoring agent information",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestScenarioCatalogsResult"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadGeneratorInstallInfoResult"
                         }
                     },
                     "400": {
@@ -726,7 +806,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test scenario catalogs",
+                        "description": "Failed to retrieve monitoring agent information",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -734,7 +814,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new load test scenario catalog template with the provided configuration.",
+                "description": "Install a load generator either locally or remotely.",
                 "consumes": [
                     "application/json"
                 ],
@@ -742,36 +822,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Scenario Catalog Management]"
+                    "[Load Generator Management]"
                 ],
-                "summary": "Create Load Test Scenario Catalog",
-                "operationId": "CreateLoadTestScenarioCatalog",
+                "summary": "Install Load Generator",
+                "operationId": "InstallLoadGenerator",
                 "parameters": [
                     {
-                        "description": "Load Test Scenario Catalog Creation Request",
+                        "description": "Load Generator Installation Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/load.CreateLoadTestScenarioCatalogReq"
+                            "$ref": "#/definitions/app.InstallLoadGeneratorReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created load test scenario catalog",
+                        "description": "Successfully installed load generator",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadGeneratorInstallInfoResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Load generator installation info is not correct.| available install locations are remote or local.",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Failed to create load test scenario catalog",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -779,9 +859,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/templates/test-scenario-catalogs/{id}": {
-            "get": {
-                "description": "Retrieve a specific load test scenario catalog by ID.",
+        "/api/v1/load/generators/{loadGeneratorInstallInfoId}": {
+            "delete": {
+                "description": "Uninstall a previously installed load generator.",
                 "consumes": [
                     "application/json"
                 ],
@@ -789,48 +869,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Scenario Catalog Management]"
+                    "[Load Generator Management]"
                 ],
-                "summary": "Get Load Test Scenario Catalog",
-                "operationId": "GetLoadTestScenarioCatalog",
+                "summary": "Uninstall Load Generator",
+                "operationId": "UninstallLoadGenerator",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Load Test Scenario Catalog ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "load generator install info id",
+                        "name": "loadGeneratorInstallInfoId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test scenario catalog",
+                        "description": "Successfully uninstall load generator",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
+                            "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Load generator install info id must be number.",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
-                    "404": {
-                        "description": "Load test scenario catalog not found",
-                        "schema": {
-                            "$ref": "#/definitions/app.AntResponse-string"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to retrieve load test scenario catalog",
+                    "500": {
+                        "description": "ant server has got error. please try again.",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing load test scenario catalog with the provided configuration.",
+            }
+        },
+        "/api/v1/load/monitoring/agent": {
+            "get": {
+                "description": "Retrieve monitoring agent information based on specified criteria.",
                 "consumes": [
                     "application/json"
                 ],
@@ -838,33 +914,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Scenario Catalog Management]"
+                    "[Monitoring Agent Management]"
                 ],
-                "summary": "Update Load Test Scenario Catalog",
-                "operationId": "UpdateLoadTestScenarioCatalog",
+                "summary": "Retrieve Monitoring Agent Information",
+                "operationId": "GetAllMonitoringAgentInfos",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace ID",
+                        "name": "nsId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "MCI ID",
+                        "name": "infraId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "VM ID",
+                        "name": "nodeId",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Load Test Scenario Catalog ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Number of results per page",
+                        "name": "size",
+                        "in": "query"
                     },
                     {
-                        "description": "Load Test Scenario Catalog Update Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/load.UpdateLoadTestScenarioCatalogReq"
-                        }
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated load test scenario catalog",
+                        "description": "Successfully retrieved monitoring agent information",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllMonitoringAgentInfoResult"
                         }
                     },
                     "400": {
@@ -873,22 +963,65 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
-                    "404": {
-                        "description": "Load test scenario catalog not found",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/monitoring/agent/install": {
+            "post": {
+                "description": "Install a monitoring agent on specific mci.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Monitoring Agent Management]"
+                ],
+                "summary": "Install Metrics Monitoring Agent",
+                "operationId": "InstallMonitoringAgent",
+                "parameters": [
+                    {
+                        "description": "Monitoring Agent Installation Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.MonitoringAgentInstallationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully installed monitoring agent",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_MonitoringAgentInstallationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Monitoring agent installation info is not correct.",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Failed to update load test scenario catalog",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a load test scenario catalog by ID.",
+            }
+        },
+        "/api/v1/load/monitoring/agent/uninstall": {
+            "post": {
+                "description": "Uninstall monitoring agents from specified VMs or all VMs in an mci.",
                 "consumes": [
                     "application/json"
                 ],
@@ -896,24 +1029,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Scenario Catalog Management]"
+                    "[Monitoring Agent Management]"
                 ],
-                "summary": "Delete Load Test Scenario Catalog",
-                "operationId": "DeleteLoadTestScenarioCatalog",
+                "summary": "Uninstall Monitoring Agents",
+                "operationId": "UninstallMonitoringAgent",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Load Test Scenario Catalog ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Monitoring Agent Uninstallation Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.MonitoringAgentInstallationReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted load test scenario catalog",
+                        "description": "Number of affected results",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-string"
+                            "$ref": "#/definitions/app.AntResponse-int64"
                         }
                     },
                     "400": {
@@ -922,14 +1057,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
-                    "404": {
-                        "description": "Load test scenario catalog not found",
-                        "schema": {
-                            "$ref": "#/definitions/app.AntResponse-string"
-                        }
-                    },
                     "500": {
-                        "description": "Failed to delete load test scenario catalog",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -937,9 +1066,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/tests/infos": {
+        "/api/v1/load/schedules": {
             "get": {
-                "description": "Retrieve a list of all load test execution information with pagination support.",
+                "description": "Retrieve the load test schedules with when each fires next, with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -947,10 +1076,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Execution Management]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get All Load Test Execution Information",
-                "operationId": "GetAllLoadTestExecutionInfos",
+                "summary": "Get All Load Test Schedules",
+                "operationId": "GetAllLoadTestSchedules",
                 "parameters": [
                     {
                         "type": "integer",
@@ -960,16 +1089,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default 10, max 10)",
+                        "description": "Number of items per page (default 10, max 100)",
                         "name": "size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by schedule name",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test execution information",
+                        "description": "Successfully retrieved load test schedules",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestExecutionInfosResult"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestSchedulesResult"
                         }
                     },
                     "400": {
@@ -979,17 +1114,15 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve all load test execution information",
+                        "description": "Failed to retrieve load test schedules",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/tests/infos/{loadTestKey}": {
-            "get": {
-                "description": "Retrieve the load test execution state information for a specific load test key.",
+            },
+            "post": {
+                "description": "Schedule load tests of a scenario catalog against a target, on a cron expression or once at a given time. The run is checked as POST /load/tests/run checks it, with the load profile of the catalog. Schedules are kept in the database and survive restarts; a time missed while the server was down fires once when it is back.",
                 "consumes": [
                     "application/json"
                 ],
@@ -997,34 +1130,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Execution Management]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get Load Test Execution State",
-                "operationId": "GetLoadTestExecutionInfo",
+                "summary": "Create Load Test Schedule",
+                "operationId": "CreateLoadTestSchedule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Load test key",
-                        "name": "loadTestKey",
-                        "in": "path",
-                        "required": true
+                        "description": "Load Test Schedule Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.LoadTestScheduleReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test execution state information",
+                        "description": "Successfully created load test schedule",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionInfoResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScheduleResult"
                         }
                     },
                     "400": {
-                        "description": "Load test key must be set.",
+                        "description": "Invalid request parameters",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test execution state information",
+                        "description": "Failed to create load test schedule",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -1032,9 +1167,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/tests/result": {
+        "/api/v1/load/schedules/{scheduleId}": {
             "get": {
-                "description": "Retrieve load test result based on provided parameters.",
+                "description": "Retrieve a load test schedule by ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1042,45 +1177,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Result]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get load test result",
-                "operationId": "GetLoadTestResult",
+                "summary": "Get Load Test Schedule",
+                "operationId": "GetLoadTestSchedule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Load test key",
-                        "name": "loadTestKey",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Load Test Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Result format (normal or aggregate)",
-                        "name": "format",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test metrics",
+                        "description": "Successfully retrieved load test schedule",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.JsonResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "[aggregate]": {
-                                            "$ref": "#/definitions/app.AntResponse-array_load_LoadTestStatistics"
-                                        },
-                                        "[normal]": {
-                                            "$ref": "#/definitions/app.AntResponse-array_load_ResultSummary"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScheduleResult"
                         }
                     },
                     "400": {
@@ -1089,18 +1203,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
+                    "404": {
+                        "description": "Load test schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to retrieve load test result",
+                        "description": "Failed to retrieve load test schedule",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/tests/result/last": {
-            "get": {
-                "description": "Retrieve last load test result based on provided parameters.",
+            },
+            "put": {
+                "description": "Replace a load test schedule as a whole; the body is the same as on creation. When it fires next is worked out again from now.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1108,79 +1226,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Result]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get last load test result by ns, mci, vm",
-                "operationId": "GetLastLoadTestResult",
+                "summary": "Update Load Test Schedule",
+                "operationId": "UpdateLoadTestSchedule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ns id",
-                        "name": "nsId",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Load Test Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "mci id",
-                        "name": "infraId",
-                        "in": "query",
-                        "required": true
+                        "description": "Load Test Schedule Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.LoadTestScheduleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated load test schedule",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScheduleResult"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "vm id",
-                        "name": "nodeId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Result format (normal or aggregate)",
-                        "name": "format",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved load test metrics",
+                    "400": {
+                        "description": "Invalid request parameters",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.JsonResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "[aggregate]": {
-                                            "$ref": "#/definitions/app.AntResponse-array_load_LoadTestStatistics"
-                                        },
-                                        "[normal]": {
-                                            "$ref": "#/definitions/app.AntResponse-array_load_ResultSummary"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request parameters",
+                    "404": {
+                        "description": "Load test schedule not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test result",
+                        "description": "Failed to update load test schedule",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/tests/result/metrics": {
-            "get": {
-                "description": "Retrieve load test metrics based on provided parameters.",
+            },
+            "delete": {
+                "description": "Delete a load test schedule by ID. Runs it already started are not stopped.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1188,24 +1284,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Result]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get load test metrics",
-                "operationId": "GetLoadTestMetrics",
+                "summary": "Delete Load Test Schedule",
+                "operationId": "DeleteLoadTestSchedule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Load test key",
-                        "name": "loadTestKey",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Load Test Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test metrics",
+                        "description": "Successfully deleted load test schedule",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-array_load_MetricsSummary"
+                            "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "400": {
@@ -1214,8 +1310,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
+                    "404": {
+                        "description": "Load test schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to retrieve load test metrics",
+                        "description": "Failed to delete load test schedule",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -1223,9 +1325,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/tests/result/metrics/last": {
+        "/api/v1/load/schedules/{scheduleId}/runs": {
             "get": {
-                "description": "Retrieve last load test metrics based on provided parameters.",
+                "description": "Retrieve the times a load test schedule fired, the latest first: the load test key of the run each started and where that run is now, or why none was started.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1233,44 +1335,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Result]"
+                    "[Load Test Schedule Management]"
                 ],
-                "summary": "Get last load test metrics by ns, mci, vm",
-                "operationId": "GetLastLoadTestMetrics",
+                "summary": "Get Load Test Schedule Runs",
+                "operationId": "GetLoadTestScheduleRuns",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ns id",
-                        "name": "nsId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "mci id",
-                        "name": "infraId",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Load Test Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "vm id",
-                        "name": "nodeId",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "Page number for pagination (default 1)",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Result format (normal for the moment)",
-                        "name": "format",
+                        "type": "integer",
+                        "description": "Number of items per page (default 10, max 100)",
+                        "name": "size",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test metrics",
+                        "description": "Successfully retrieved load test schedule runs",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-array_load_MetricsSummary"
+                            "$ref": "#/definitions/app.AntResponse-load_GetLoadTestScheduleRunsResult"
                         }
                     },
                     "400": {
@@ -1279,55 +1373,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve load test metrics",
-                        "schema": {
-                            "$ref": "#/definitions/app.AntResponse-string"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/load/tests/run": {
-            "post": {
-                "description": "Start a load test using the provided load test configuration.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "[Load Test Execution Management]"
-                ],
-                "summary": "Run Load Test",
-                "operationId": "RunLoadTest",
-                "parameters": [
-                    {
-                        "description": "Run Load Test Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/app.RunLoadTestReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "{loadTestKey}",
-                        "schema": {
-                            "$ref": "#/definitions/app.AntResponse-string"
-                        }
-                    },
-                    "400": {
-                        "description": "load test install location is invalid.",
+                    "404": {
+                        "description": "Load test schedule not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "ant server has got error. please try again.",
+                        "description": "Failed to retrieve load test schedule runs",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -1335,9 +1388,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/tests/state": {
+        "/api/v1/load/templates/test-scenario-catalogs": {
             "get": {
-                "description": "Retrieve a list of all load test execution states with pagination support.",
+                "description": "Retrieve a list of all load test scenario catalogs with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1345,10 +1398,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test State Management]"
+                    "[Load Test Scenario Catalog Management]"
                 ],
-                "summary": "Get All Load Test Execution State",
-                "operationId": "GetAllLoadTestExecutionState",
+                "summary": "Get All Load Test Scenario Catalogs",
+                "operationId": "GetAllLoadTestScenarioCatalogs",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1358,28 +1411,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default 10, max 10)",
+                        "description": "Number of items per page (default 10, max 100)",
                         "name": "size",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by load test key",
-                        "name": "loadTestKey",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by execution status",
-                        "name": "executionStatus",
+                        "description": "Filter by catalog name",
+                        "name": "name",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test execution state information",
+                        "description": "Successfully retrieved load test scenario catalogs",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestExecutionStateResult"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestScenarioCatalogsResult"
                         }
                     },
                     "400": {
@@ -1389,17 +1436,15 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test execution state information",
+                        "description": "Failed to retrieve load test scenario catalogs",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/tests/state/last": {
-            "get": {
-                "description": "Retrieve a last load test execution state by given ids.",
+            },
+            "post": {
+                "description": "Create a new load test scenario catalog template with the provided configuration.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1407,38 +1452,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test State Management]"
+                    "[Load Test Scenario Catalog Management]"
                 ],
-                "summary": "Get Last Load Test Execution State",
-                "operationId": "GetLastLoadTestExecutionState",
+                "summary": "Create Load Test Scenario Catalog",
+                "operationId": "CreateLoadTestScenarioCatalog",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "nsId",
-                        "name": "nsId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "infraId",
-                        "name": "infraId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Load test key",
-                        "name": "nodeId",
-                        "in": "query",
-                        "required": true
+                        "description": "Load Test Scenario Catalog Creation Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/load.CreateLoadTestScenarioCatalogReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test execution state information",
+                        "description": "Successfully created load test scenario catalog",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionStateResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
                         }
                     },
                     "400": {
@@ -1448,7 +1481,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test execution state information",
+                        "description": "Failed to create load test scenario catalog",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -1456,9 +1489,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/load/tests/state/{loadTestKey}": {
+        "/api/v1/load/templates/test-scenario-catalogs/{id}": {
             "get": {
-                "description": "Retrieve a load test execution state by load test key.",
+                "description": "Retrieve a specific load test scenario catalog by ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1466,44 +1499,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test State Management]"
+                    "[Load Test Scenario Catalog Management]"
                 ],
-                "summary": "Get Load Test Execution State",
-                "operationId": "GetLoadTestExecutionState",
+                "summary": "Get Load Test Scenario Catalog",
+                "operationId": "GetLoadTestScenarioCatalog",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Load test key",
-                        "name": "loadTestKey",
+                        "type": "integer",
+                        "description": "Load Test Scenario Catalog ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved load test execution state information",
+                        "description": "Successfully retrieved load test scenario catalog",
                         "schema": {
-                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionStateResult"
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
                         }
                     },
                     "400": {
-                        "description": "Load test key must be set.",
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Load test scenario catalog not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve load test execution state information",
+                        "description": "Failed to retrieve load test scenario catalog",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/load/tests/stop": {
-            "post": {
-                "description": "Stop a running load test using the provided load test key.",
+            },
+            "put": {
+                "description": "Update an existing load test scenario catalog with the provided configuration.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1511,36 +1548,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Load Test Execution Management]"
+                    "[Load Test Scenario Catalog Management]"
                 ],
-                "summary": "Stop Load Test",
-                "operationId": "StopLoadTest",
+                "summary": "Update Load Test Scenario Catalog",
+                "operationId": "UpdateLoadTestScenarioCatalog",
                 "parameters": [
                     {
-                        "description": "Stop Load Test Request",
+                        "type": "integer",
+                        "description": "Load Test Scenario Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Load Test Scenario Catalog Update Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/app.StopLoadTestReq"
+                            "$ref": "#/definitions/load.UpdateLoadTestScenarioCatalogReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "done",
+                        "description": "Successfully updated load test scenario catalog",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestScenarioCatalogResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Load test scenario catalog not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update load test scenario catalog",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a load test scenario catalog by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Scenario Catalog Management]"
+                ],
+                "summary": "Delete Load Test Scenario Catalog",
+                "operationId": "DeleteLoadTestScenarioCatalog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Load Test Scenario Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted load test scenario catalog",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "400": {
-                        "description": "load test running info is not correct.",
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Load test scenario catalog not found",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
                     },
                     "500": {
-                        "description": "ant server has got error. please try again.",
+                        "description": "Failed to delete load test scenario catalog",
                         "schema": {
                             "$ref": "#/definitions/app.AntResponse-string"
                         }
@@ -1548,9 +1647,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/readyz": {
+        "/api/v1/load/tests/infos": {
             "get": {
-                "description": "Returns CM-Ant server readiness including DB and outbound\ndependency (cb-spider, cb-tumblebug) reachability and\nauthentication. Per STANDARD-READYZ pattern B, the response\nbody always carries per-dependency status; the HTTP status is\n200 when every dependency is reachable and authenticated, and\n503 otherwise. Results are cached briefly to limit outbound\ncall rate; see STANDARD-READYZ for details.",
+                "description": "Retrieve a list of all load test execution information with pagination support.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1558,1208 +1657,4669 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "[Server Health]"
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Get All Load Test Execution Information",
+                "operationId": "GetAllLoadTestExecutionInfos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default 10, max 10)",
+                        "name": "size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Check CM-Ant API server readiness",
-                "operationId": "AntServerReadiness",
                 "responses": {
                     "200": {
-                        "description": "CM-Ant is ready (all dependencies healthy)",
+                        "description": "Successfully retrieved load test execution information",
                         "schema": {
-                            "$ref": "#/definitions/app.readyzResponse"
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestExecutionInfosResult"
                         }
                     },
-                    "503": {
-                        "description": "CM-Ant is not ready (see dependencies for the failing component)",
+                    "400": {
+                        "description": "Invalid request parameters",
                         "schema": {
-                            "$ref": "#/definitions/app.readyzResponse"
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve all load test execution information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "app.AntResponse-array_load_LoadTestStatistics": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "errorMessage": {
-                    "type": "string"
-                },
-                "result": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.LoadTestStatistics"
+        },
+        "/api/v1/load/tests/infos/{loadTestKey}": {
+            "get": {
+                "description": "Retrieve the load test execution state information for a specific load test key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Get Load Test Execution State",
+                "operationId": "GetLoadTestExecutionInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionInfoResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Load test key must be set.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
                     }
-                },
-                "successMessage": {
-                    "type": "string"
                 }
             }
         },
-        "app.AntResponse-array_load_MetricsSummary": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "errorMessage": {
-                    "type": "string"
-                },
-                "result": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.MetricsSummary"
+        "/api/v1/load/tests/result": {
+            "get": {
+                "description": "Retrieve load test result based on provided parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Get load test result",
+                "operationId": "GetLoadTestResult",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Result format (normal or aggregate)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test metrics",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.JsonResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "[aggregate]": {
+                                            "$ref": "#/definitions/app.AntResponse-array_load_LoadTestStatistics"
+                                        },
+                                        "[normal]": {
+                                            "$ref": "#/definitions/app.AntResponse-array_load_ResultSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test result",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
                     }
-                },
-                "successMessage": {
-                    "type": "string"
                 }
             }
         },
-        "app.AntResponse-array_load_ResultSummary": {
-            "type": "object",
-            "properties": {
+        "/api/v1/load/tests/result/compare": {
+            "get": {
+                "description": "Compare two or more load tests, the first being the baseline. Labels are aligned by name with a total row last, and every statistic and target metric is reported with its change from the baseline. A change is flagged as a regression when it is worse than its tolerance and statistically significant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Compare load test results",
+                "operationId": "CompareLoadTests",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Load test keys, the baseline first",
+                        "name": "loadTestKeys",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Tolerated increase of response times in percent",
+                        "name": "responseTimePercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Tolerated increase of the error rate in percentage points",
+                        "name": "errorPercentPoints",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Tolerated decrease of throughput in percent",
+                        "name": "throughputPercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Tolerated worsening of target metrics in percent",
+                        "name": "metricPercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "P-value below which a change is significant",
+                        "name": "significance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully compared load test results",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestComparisonResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "load test not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compare load test results",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/result/last": {
+            "get": {
+                "description": "Retrieve last load test result based on provided parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Get last load test result by ns, mci, vm",
+                "operationId": "GetLastLoadTestResult",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ns id",
+                        "name": "nsId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "mci id",
+                        "name": "infraId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "vm id",
+                        "name": "nodeId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Result format (normal or aggregate)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test metrics",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.JsonResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "[aggregate]": {
+                                            "$ref": "#/definitions/app.AntResponse-array_load_LoadTestStatistics"
+                                        },
+                                        "[normal]": {
+                                            "$ref": "#/definitions/app.AntResponse-array_load_ResultSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test result",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/result/metrics": {
+            "get": {
+                "description": "Retrieve load test metrics based on provided parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Get load test metrics",
+                "operationId": "GetLoadTestMetrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test metrics",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-array_load_MetricsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test metrics",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/result/metrics/last": {
+            "get": {
+                "description": "Retrieve last load test metrics based on provided parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Get last load test metrics by ns, mci, vm",
+                "operationId": "GetLastLoadTestMetrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ns id",
+                        "name": "nsId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "mci id",
+                        "name": "infraId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "vm id",
+                        "name": "nodeId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Result format (normal for the moment)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test metrics",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-array_load_MetricsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test metrics",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/result/regions": {
+            "get": {
+                "description": "Retrieve the aggregated result of a load test sent from several regions, one region at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Result]"
+                ],
+                "summary": "Get load test result by region",
+                "operationId": "GetLoadTestRegionResult",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test result by region",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-array_load_LoadTestRegionResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test result by region",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/run": {
+            "post": {
+                "description": "Start a load test using the provided load test configuration. The run is queued until the concurrency limits (in all, per load generator and per target node) let it start; meanwhile its state is queued, with its place in the queue.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Run Load Test",
+                "operationId": "RunLoadTest",
+                "parameters": [
+                    {
+                        "description": "Run Load Test Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.RunLoadTestReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{loadTestKey}",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "load test install location is invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "ant server has got error. please try again.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "503": {
+                        "description": "cm-ant is shutting down and takes no new load tests",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/run/jmx": {
+            "post": {
+                "description": "Upload a JMeter test plan (.jmx) and the CSV data files it reads, and run it like any other load test. cm-ant adds its result writer to the plan, the PerfMon collectors when additional metrics are collected, and points CSV Data Set Configs at the uploaded files.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Run Load Test With Uploaded Test Plan",
+                "operationId": "RunLoadTestWithTestPlan",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "JMeter test plan (.jmx)",
+                        "name": "testPlan",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV data files the test plan reads",
+                        "name": "dataFiles",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "local | remote; required when loadGeneratorInstallInfoId is not given",
+                        "name": "installLocation",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Already installed load generator",
+                        "name": "loadGeneratorInstallInfoId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Test name",
+                        "name": "testName",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected length of the run in seconds",
+                        "name": "duration",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected ramp up time in seconds",
+                        "name": "rampUpTime",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace of the target",
+                        "name": "nsId",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Infra of the target",
+                        "name": "infraId",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node of the target",
+                        "name": "nodeId",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Collect target metrics with PerfMon",
+                        "name": "collectAdditionalSystemMetrics",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric agent host; the target node's address when empty",
+                        "name": "agentHostname",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{loadTestKey}",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "load test running info is not correct.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "ant server has got error. please try again.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "503": {
+                        "description": "cm-ant is shutting down and takes no new load tests",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/state": {
+            "get": {
+                "description": "Retrieve a list of all load test execution states with pagination support.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test State Management]"
+                ],
+                "summary": "Get All Load Test Execution State",
+                "operationId": "GetAllLoadTestExecutionState",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default 10, max 10)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by load test key",
+                        "name": "loadTestKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by execution status",
+                        "name": "executionStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the run the runs were made again from",
+                        "name": "parentLoadTestKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the verdict against the baseline (PASS, REGRESSED or INCONCLUSIVE)",
+                        "name": "baselineVerdict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_GetAllLoadTestExecutionStateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/state/last": {
+            "get": {
+                "description": "Retrieve a last load test execution state by given ids.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test State Management]"
+                ],
+                "summary": "Get Last Load Test Execution State",
+                "operationId": "GetLastLoadTestExecutionState",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nsId",
+                        "name": "nsId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "infraId",
+                        "name": "infraId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "nodeId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionStateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/state/{loadTestKey}": {
+            "get": {
+                "description": "Retrieve a load test execution state by load test key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test State Management]"
+                ],
+                "summary": "Get Load Test Execution State",
+                "operationId": "GetLoadTestExecutionState",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-load_LoadTestExecutionStateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Load test key must be set.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/state/{loadTestKey}/stream": {
+            "get": {
+                "description": "Stream the progress of a load test as server-sent events, instead of polling its state. The first event is a state event with the run's state and steps as they are now. After it come step events for every step as it changes, state events for every change of the run's state, and, while the load runs, figures events with throughput, latency and error rate over the last 30 seconds of samples, read from the result file every 10 seconds. The stream ends after the state event of a finished run.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "[Load Test State Management]"
+                ],
+                "summary": "Stream Load Test Progress",
+                "operationId": "StreamLoadTestProgress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key",
+                        "name": "loadTestKey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event: state|step|figures, data: the event as JSON",
+                        "schema": {
+                            "$ref": "#/definitions/load.LoadTestProgressEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Load test key must be set.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve load test execution state information",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/stop": {
+            "post": {
+                "description": "Stop a running load test using the provided load test key. A run still queued is taken off the queue and never starts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Stop Load Test",
+                "operationId": "StopLoadTest",
+                "parameters": [
+                    {
+                        "description": "Stop Load Test Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/app.StopLoadTestReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "done",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "load test running info is not correct.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "500": {
+                        "description": "ant server has got error. please try again.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/load/tests/{loadTestKey}/rerun": {
+            "post": {
+                "description": "Run a load test again with the configuration it was stored with, files included. Virtual users, duration, the target node and the host requests are sent to can be overridden. The new run has a key of its own and names the run it was made from as its parentLoadTestKey. It goes on the same load generator while that is still installed; otherwise one is installed at installLocation, or where the old one was.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Load Test Execution Management]"
+                ],
+                "summary": "Rerun Load Test",
+                "operationId": "RerunLoadTest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Load test key of the run to make again",
+                        "name": "loadTestKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rerun Load Test Request",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/app.RerunLoadTestReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{loadTestKey}",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "load test rerun info is not correct.",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "404": {
+                        "description": "load test not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    },
+                    "503": {
+                        "description": "cm-ant is shutting down and takes no new load tests",
+                        "schema": {
+                            "$ref": "#/definitions/app.AntResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Returns CM-Ant server readiness including DB and outbound\ndependency (cb-spider, cb-tumblebug) reachability and\nauthentication. Per STANDARD-READYZ pattern B, the response\nbody always carries per-dependency status; the HTTP status is\n200 when every dependency is reachable and authenticated, and\n503 otherwise. Results are cached briefly to limit outbound\ncall rate; see STANDARD-READYZ for details. A server that is\nshutting down answers 503 with the drain of its running load\ntests in the drain field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "[Server Health]"
+                ],
+                "summary": "Check CM-Ant API server readiness",
+                "operationId": "AntServerReadiness",
+                "responses": {
+                    "200": {
+                        "description": "CM-Ant is ready (all dependencies healthy)",
+                        "schema": {
+                            "$ref": "#/definitions/app.readyzResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "CM-Ant is not ready (see dependencies for the failing component)",
+                        "schema": {
+                            "$ref": "#/definitions/app.readyzResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "app.AntResponse-array_load_LoadTestRegionResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestRegionResult"
+                    }
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-array_load_LoadTestStatistics": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestStatistics"
+                    }
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-array_load_MetricsSummary": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.MetricsSummary"
+                    }
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-array_load_ResultSummary": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.ResultSummary"
+                    }
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-cost_EstimateCostInfoResults": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/cost.EstimateCostInfoResults"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-cost_EstimateCostResults": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/cost.EstimateCostResults"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-cost_GetEstimateForecastCostInfoResults": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/cost.GetEstimateForecastCostInfoResults"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-cost_UpdateEstimateForecastCostInfoResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/cost.UpdateEstimateForecastCostInfoResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-int64": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "integer"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadGeneratorInstallInfoResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadGeneratorInstallInfoResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestBaselinesResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestBaselinesResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestDataSetsResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestDataSetsResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestExecutionInfosResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestExecutionInfosResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestExecutionStateResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestExecutionStateResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestScenarioCatalogsResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestScenarioCatalogsResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllLoadTestSchedulesResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllLoadTestSchedulesResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetAllMonitoringAgentInfoResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetAllMonitoringAgentInfoResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_GetLoadTestScheduleRunsResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.GetLoadTestScheduleRunsResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadGeneratorInstallInfoResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestBaselineResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestBaselineResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestComparisonResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestComparisonResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestDataSetResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestDataSetResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestExecutionInfoResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestExecutionInfoResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestExecutionStateResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestExecutionStateResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestScenarioCatalogResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestScenarioCatalogResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_LoadTestScheduleResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.LoadTestScheduleResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-load_MonitoringAgentInstallationResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/load.MonitoringAgentInstallationResult"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AntResponse-string": {
+            "type": "object",
+            "properties": {
                 "code": {
                     "type": "integer"
                 },
-                "errorMessage": {
-                    "type": "string"
+                "errorMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "successMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.AwsAdditionalInfoReq": {
+            "type": "object",
+            "properties": {
+                "ownerId": {
+                    "type": "string"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.CostResourceReq": {
+            "type": "object",
+            "properties": {
+                "resourceIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "resourceType": {
+                    "$ref": "#/definitions/constant.ResourceType"
+                }
+            }
+        },
+        "app.DepResult": {
+            "type": "object",
+            "properties": {
+                "db": {
+                    "$ref": "#/definitions/app.DepStatus"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "spider": {
+                    "$ref": "#/definitions/app.DepStatus"
+                },
+                "tumblebug": {
+                    "$ref": "#/definitions/app.DepStatus"
+                }
+            }
+        },
+        "app.DepStatus": {
+            "type": "object",
+            "properties": {
+                "authenticated": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "lastCheck": {
+                    "type": "string"
+                },
+                "reachable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "app.InstallLoadGeneratorReq": {
+            "type": "object",
+            "properties": {
+                "clusterSize": {
+                    "description": "generator servers the load is split across; remote only, 1 (default) to 10",
+                    "type": "integer"
+                },
+                "installLocation": {
+                    "$ref": "#/definitions/constant.InstallLocation"
+                }
+            }
+        },
+        "app.JsonResult": {
+            "type": "object"
+        },
+        "app.LoadTestScheduleReq": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "description": "minute hour day-of-month month day-of-week, or @daily, @hourly, ...",
+                    "type": "string",
+                    "example": "0 2 * * *"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "regression run after the migration"
+                },
+                "enabled": {
+                    "description": "true by default",
+                    "type": "boolean"
+                },
+                "infraId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly regression"
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "type": "string"
+                },
+                "run": {
+                    "description": "the rest of the run: generator, requests, data sets, SLA; its load profile and target are\ntaken from the fields above",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestReq"
+                        }
+                    ]
+                },
+                "runAt": {
+                    "description": "to run once instead of on a cron",
+                    "type": "string"
+                },
+                "scenarioCatalogId": {
+                    "description": "the scenario catalog whose load profile every run uses, as it is when the run starts",
+                    "type": "integer",
+                    "example": 1
+                },
+                "timeZone": {
+                    "description": "the cron is read in it; UTC by default",
+                    "type": "string",
+                    "example": "Asia/Seoul"
+                }
+            }
+        },
+        "app.MonitoringAgentInstallationReq": {
+            "type": "object",
+            "properties": {
+                "infraId": {
+                    "type": "string"
+                },
+                "nodeIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nsId": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.PinLoadTestBaselineReq": {
+            "type": "object",
+            "properties": {
+                "infraId": {
+                    "type": "string"
+                },
+                "loadTestKey": {
+                    "type": "string"
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "type": "string"
+                },
+                "scenarioCatalogId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.RerunLoadTestReq": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "description": "host every request and target is sent to instead",
+                    "type": "string"
+                },
+                "infraId": {
+                    "type": "string"
+                },
+                "installLocation": {
+                    "description": "where a load generator is installed when the run's one is gone; local | remote",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.InstallLocation"
+                        }
+                    ]
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "description": "another target node; nsId, infraId and nodeId go together",
+                    "type": "string"
+                },
+                "testName": {
+                    "type": "string"
+                },
+                "virtualUsers": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadGeneratorAssertionReq": {
+            "type": "object",
+            "properties": {
+                "expression": {
+                    "description": "json_path only: $.status",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "status_code, body_contains, json_path or max_duration",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.AssertionType"
+                        }
+                    ]
+                },
+                "value": {
+                    "description": "200,201 | text | expected value (any when empty) | ms",
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadGeneratorExtractorReq": {
+            "type": "object",
+            "properties": {
+                "defaultValue": {
+                    "description": "used when nothing matches",
+                    "type": "string"
+                },
+                "expression": {
+                    "description": "$.data.token or \"token\":\"(.+?)\"",
+                    "type": "string"
+                },
+                "matchNo": {
+                    "description": "1 first (default), 0 random, -1 all",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "json_path or regex",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.ExtractorType"
+                        }
+                    ]
+                },
+                "varName": {
+                    "description": "referenced later as ${varName}",
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadGeneratorFileReq": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "base64 in JSON",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "fileName": {
+                    "description": "name the target sees",
+                    "type": "string"
+                },
+                "mimeType": {
+                    "description": "application/octet-stream when empty",
+                    "type": "string"
+                },
+                "paramName": {
+                    "description": "form field the file is sent as",
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadGeneratorHttpReq": {
+            "type": "object",
+            "required": [
+                "bodyData",
+                "hostname",
+                "method",
+                "path",
+                "port",
+                "protocol"
+            ],
+            "properties": {
+                "assertions": {
+                    "description": "checks a response must pass to count as a success",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorAssertionReq"
+                    }
+                },
+                "bodyData": {
+                    "description": "{\"xxx\": \"tttt\", \"wwwww\": \"wotjkenr\"}",
+                    "type": "string"
+                },
+                "bodyType": {
+                    "description": "raw (default), form or multipart",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.HttpBodyType"
+                        }
+                    ]
+                },
+                "extractors": {
+                    "description": "values taken from this response and usable as ${varName} by the requests after it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorExtractorReq"
+                    }
+                },
+                "files": {
+                    "description": "multipart only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorFileReq"
+                    }
+                },
+                "formParams": {
+                    "description": "body fields for form and multipart",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorNameValueReq"
+                    }
+                },
+                "headers": {
+                    "description": "sent with this request only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorNameValueReq"
+                    }
+                },
+                "hostname": {
+                    "description": "xx.xx.xx.xx or asx.bbb.com",
+                    "type": "string"
+                },
+                "httpClient": {
+                    "description": "overrides the run's http client options for this request",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestHttpClientReq"
+                        }
+                    ]
+                },
+                "method": {
+                    "description": "GET, HEAD, OPTIONS, POST, PUT, PATCH or DELETE",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "/xxx/www/sss or possibly empty",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "1 ~ 65353",
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "http or https",
+                    "type": "string"
+                },
+                "queryParams": {
+                    "description": "added to any query already in path",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorNameValueReq"
+                    }
+                },
+                "thinkTime": {
+                    "description": "overrides the run's think time",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestThinkTimeReq"
+                        }
+                    ]
+                },
+                "weight": {
+                    "description": "1 ~ 100, relative to the other weighted requests; 0 sends it every iteration",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.RunLoadGeneratorNameValueReq": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadTestAutoStopReq": {
+            "type": "object",
+            "properties": {
+                "errorPercentForSec": {
+                    "description": "seconds the error rate stays above maxErrorPercent; 0 stops at once",
+                    "type": "integer"
+                },
+                "maxAgentCpuPercent": {
+                    "description": "0 ~ 100; needs collectAdditionalSystemMetrics",
+                    "type": "number"
+                },
+                "maxErrorPercent": {
+                    "description": "0 ~ 100",
+                    "type": "number"
+                },
+                "maxP95": {
+                    "description": "ms",
+                    "type": "number"
+                }
+            }
+        },
+        "app.RunLoadTestDataSetReq": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "dataSetId": {
+                    "type": "integer"
+                },
+                "fileName": {
+                    "type": "string"
+                },
+                "recycleOnEof": {
+                    "description": "start over at the top after the last line; true when not given",
+                    "type": "boolean"
+                },
+                "shareMode": {
+                    "description": "who reads through the file together; all (default) | group | thread",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataSetShareMode"
+                        }
+                    ]
+                },
+                "stopThreadOnEof": {
+                    "description": "stop a virtual user that reads past the last line instead of sending \u003cEOF\u003e; needs recycleOnEof false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "app.RunLoadTestHttpClientReq": {
+            "type": "object",
+            "properties": {
+                "connectTimeout": {
+                    "description": "ms; jmeter only, default 5000",
+                    "type": "integer"
+                },
+                "downloadEmbeddedResources": {
+                    "description": "jmeter only; images, scripts and styles of html responses",
+                    "type": "boolean"
+                },
+                "followRedirects": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "implementation": {
+                    "description": "jmeter only; HttpClient4 (default) or Java",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.HttpImplementation"
+                        }
+                    ]
+                },
+                "insecureSkipVerify": {
+                    "description": "run only; jmeter never verifies",
+                    "type": "boolean"
+                },
+                "keepAlive": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "responseTimeout": {
+                    "description": "ms; default 60000",
+                    "type": "integer"
+                },
+                "tlsVersion": {
+                    "description": "TLSv1.2 or TLSv1.3; run only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.TlsVersion"
+                        }
+                    ]
+                }
+            }
+        },
+        "app.RunLoadTestRegionReq": {
+            "type": "object",
+            "properties": {
+                "provider": {
+                    "description": "e.g. aws",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "e.g. ap-northeast-2",
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "share of the load against the other regions; 1 when not given, up to 100",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.RunLoadTestReq": {
+            "type": "object",
+            "properties": {
+                "agentHostname": {
+                    "description": "basically, it is same as host for vm",
+                    "type": "string"
+                },
+                "arrivalRate": {
+                    "description": "arrival_rate: iterations started per second",
+                    "type": "string"
+                },
+                "autoStop": {
+                    "description": "rules that stop the run while it goes, when the target is plainly falling over; the results so far are still collected",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestAutoStopReq"
+                        }
+                    ]
+                },
+                "collectAdditionalSystemMetrics": {
+                    "description": "agent tcp default port is 5555",
+                    "type": "boolean"
+                },
+                "dataSets": {
+                    "description": "CSV files read a line per iteration; every column is ${column} in paths, bodies and headers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadTestDataSetReq"
+                    }
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "engine": {
+                    "description": "load generating tool; jmeter (default) | k6",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadGeneratorType"
+                        }
+                    ]
+                },
+                "httpClient": {
+                    "description": "how requests are sent: timeouts, keep-alive, redirects, TLS; every field left out is the engine's default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestHttpClientReq"
+                        }
+                    ]
+                },
+                "httpReqs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadGeneratorHttpReq"
+                    }
+                },
+                "infraId": {
+                    "description": "for metadata usage",
+                    "type": "string"
+                },
+                "installLoadGenerator": {
+                    "description": "localhost for installing load generator; local | remote",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.InstallLoadGeneratorReq"
+                        }
+                    ]
+                },
+                "loadGeneratorInstallInfoId": {
+                    "description": "if already installed load generator simply put this field",
+                    "type": "integer"
+                },
+                "loadShape": {
+                    "description": "how the load changes over time; concurrency (default) | arrival_rate | spike | step_down | stages",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadShape"
+                        }
+                    ]
+                },
+                "nodeId": {
+                    "description": "for metadata usage",
+                    "type": "string"
+                },
+                "nsId": {
+                    "description": "for validate agent host and connect to tumblebug resources",
+                    "type": "string"
+                },
+                "rampUpSteps": {
+                    "type": "string"
+                },
+                "rampUpTime": {
+                    "type": "string"
+                },
+                "regions": {
+                    "description": "regions the load is sent from at once, split by weight; remote only, at most 5",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadTestRegionReq"
+                    }
+                },
+                "scenarioCatalogId": {
+                    "description": "the scenario catalog entry the run is of; a run that finishes is compared with the entry's baseline, or else its node's",
+                    "type": "integer"
+                },
+                "sla": {
+                    "description": "thresholds the finished run is judged by; its state then carries a PASS or FAIL verdict",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestSlaReq"
+                        }
+                    ]
+                },
+                "spikeDuration": {
+                    "description": "spike: seconds the spike lasts",
+                    "type": "string"
+                },
+                "spikeUsers": {
+                    "description": "spike: users during the spike",
+                    "type": "string"
+                },
+                "stages": {
+                    "description": "stages: replaces the fields above",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadTestStageReq"
+                    }
+                },
+                "targets": {
+                    "description": "non-HTTP samplers sent in the same thread group as httpReqs; jmeter only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.RunLoadTestTargetReq"
+                    }
+                },
+                "testName": {
+                    "description": "test scenario",
+                    "type": "string"
+                },
+                "thinkTime": {
+                    "description": "pause before every request that does not set its own",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.RunLoadTestThinkTimeReq"
+                        }
+                    ]
+                },
+                "virtualUsers": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadTestSlaReq": {
+            "type": "object",
+            "properties": {
+                "maxErrorPercent": {
+                    "description": "0 ~ 100",
+                    "type": "number"
+                },
+                "maxP95": {
+                    "description": "ms",
+                    "type": "number"
+                },
+                "maxP99": {
+                    "description": "ms",
+                    "type": "number"
+                },
+                "minThroughput": {
+                    "description": "requests per second",
+                    "type": "number"
+                }
+            }
+        },
+        "app.RunLoadTestStageReq": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.RunLoadTestTargetReq": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "driver": {
+                    "description": "jdbc: postgresql | mysql",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.JdbcDriver"
+                        }
+                    ]
+                },
+                "eolByte": {
+                    "description": "tcp: byte that ends an answer; same for every tcp target",
+                    "type": "integer"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "websocket: text frame; grpc: request as JSON; tcp: bytes sent",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "grpc: package.Service/Method",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "websocket",
+                    "type": "string"
+                },
+                "port": {
+                    "type": "string"
+                },
+                "protoFile": {
+                    "description": "grpc: content of the .proto file",
+                    "type": "string"
+                },
+                "protoFileName": {
+                    "description": "grpc: name of the .proto file",
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "websocket | grpc | tcp | jdbc",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.TargetProtocol"
+                        }
+                    ]
+                },
+                "query": {
+                    "type": "string"
+                },
+                "secure": {
+                    "description": "websocket, grpc: use TLS",
+                    "type": "boolean"
+                },
+                "timeout": {
+                    "description": "ms; default 10000",
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.RunLoadTestThinkTimeReq": {
+            "type": "object",
+            "properties": {
+                "delay": {
+                    "description": "ms; the mean for gaussian",
+                    "type": "integer"
+                },
+                "range": {
+                    "description": "ms; uniform adds up to it, gaussian deviates by it",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "constant, uniform or gaussian",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.ThinkTimeType"
+                        }
+                    ]
+                }
+            }
+        },
+        "app.StopLoadTestReq": {
+            "type": "object",
+            "properties": {
+                "infraId": {
+                    "type": "string"
+                },
+                "loadTestKey": {
+                    "type": "string"
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.UpdateAndGetEstimateCostReq": {
+            "type": "object",
+            "properties": {
+                "specs": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "required": [
+                            "instanceType",
+                            "providerName",
+                            "regionName"
+                        ],
+                        "properties": {
+                            "image": {
+                                "type": "string"
+                            },
+                            "instanceType": {
+                                "type": "string"
+                            },
+                            "providerName": {
+                                "type": "string"
+                            },
+                            "regionName": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "specsWithFormat": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "required": [
+                            "commonSpec"
+                        ],
+                        "properties": {
+                            "commonImage": {
+                                "type": "string"
+                            },
+                            "commonSpec": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "app.UpdateEstimateForecastCostRawReq": {
+            "type": "object",
+            "required": [
+                "costResources"
+            ],
+            "properties": {
+                "awsAdditionalInfo": {
+                    "$ref": "#/definitions/app.AwsAdditionalInfoReq"
+                },
+                "costResources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.CostResourceReq"
+                    }
+                }
+            }
+        },
+        "app.UpdateEstimateForecastCostReq": {
+            "type": "object",
+            "properties": {
+                "infraId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.readyzResponse": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "$ref": "#/definitions/app.DepResult"
+                },
+                "drain": {
+                    "$ref": "#/definitions/load.DrainStatus"
+                },
+                "initialized": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "constant.AssertionType": {
+            "type": "string",
+            "enum": [
+                "status_code",
+                "body_contains",
+                "json_path",
+                "max_duration"
+            ],
+            "x-enum-comments": {
+                "BodyContainsAssertion": "the body contains Value",
+                "JsonPathAssertion": "Expression finds a value, equal to Value when given",
+                "MaxDurationAssertion": "the response took at most Value ms",
+                "StatusCodeAssertion": "the status is one of Value's comma separated codes"
+            },
+            "x-enum-varnames": [
+                "StatusCodeAssertion",
+                "BodyContainsAssertion",
+                "JsonPathAssertion",
+                "MaxDurationAssertion"
+            ]
+        },
+        "constant.BaselineVerdict": {
+            "type": "string",
+            "enum": [
+                "PASS",
+                "REGRESSED",
+                "INCONCLUSIVE"
+            ],
+            "x-enum-varnames": [
+                "BaselinePass",
+                "BaselineRegressed",
+                "BaselineInconclusive"
+            ]
+        },
+        "constant.ComparisonSide": {
+            "type": "string",
+            "enum": [
+                "baseline",
+                "run"
+            ],
+            "x-enum-varnames": [
+                "BaselineSide",
+                "RunSide"
+            ]
+        },
+        "constant.DataSetShareMode": {
+            "type": "string",
+            "enum": [
+                "all",
+                "group",
+                "thread"
+            ],
+            "x-enum-varnames": [
+                "ShareAll",
+                "ShareGroup",
+                "ShareThread"
+            ]
+        },
+        "constant.ExecutionStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "on_processing",
+                "on_fetching",
+                "successed",
+                "test_failed"
+            ],
+            "x-enum-varnames": [
+                "Queued",
+                "OnProcessing",
+                "OnFetching",
+                "Successed",
+                "TestFailed"
+            ]
+        },
+        "constant.ExecutionStep": {
+            "type": "string",
+            "enum": [
+                "precheck",
+                "generator_install",
+                "agent_install",
+                "jmx_prepare",
+                "jmeter_run",
+                "result_fetch",
+                "precheck.target_exists",
+                "precheck.target_running",
+                "precheck.target_reachable",
+                "precheck.metric_port_open",
+                "precheck.remote_command",
+                "generator_install.lookup",
+                "generator_install.verify_alive",
+                "generator_install.reachable",
+                "generator_install.provision",
+                "generator_install.install",
+                "generator_install.verify_install",
+                "agent_install.install",
+                "agent_install.process_up",
+                "agent_install.port_reachable",
+                "jmx_prepare.generate",
+                "jmx_prepare.transfer",
+                "jmeter_run.start",
+                "jmeter_run.ramp_up",
+                "jmeter_run.hold",
+                "jmeter_run.exit",
+                "result_fetch.file_result",
+                "result_fetch.file_cpu",
+                "result_fetch.file_memory",
+                "result_fetch.file_disk",
+                "result_fetch.file_network",
+                "result_fetch.persist"
+            ],
+            "x-enum-varnames": [
+                "StepPrecheck",
+                "StepGeneratorInstall",
+                "StepAgentInstall",
+                "StepJmxPrepare",
+                "StepJmeterRun",
+                "StepResultFetch",
+                "SubTargetExists",
+                "SubTargetRunning",
+                "SubTargetReachable",
+                "SubMetricPortOpen",
+                "SubRemoteCommand",
+                "SubGeneratorLookup",
+                "SubGeneratorAlive",
+                "SubGeneratorReachable",
+                "SubGeneratorProvision",
+                "SubGeneratorInstall",
+                "SubGeneratorVerify",
+                "SubAgentInstall",
+                "SubAgentProcess",
+                "SubAgentPort",
+                "SubPlanGenerate",
+                "SubPlanTransfer",
+                "SubLoadStart",
+                "SubLoadRampUp",
+                "SubLoadHold",
+                "SubLoadExit",
+                "SubFileResult",
+                "SubFileCpu",
+                "SubFileMemory",
+                "SubFileDisk",
+                "SubFileNetwork",
+                "SubPersist"
+            ]
+        },
+        "constant.ExtractorType": {
+            "type": "string",
+            "enum": [
+                "json_path",
+                "regex"
+            ],
+            "x-enum-varnames": [
+                "JsonPathExtractor",
+                "RegexExtractor"
+            ]
+        },
+        "constant.HttpBodyType": {
+            "type": "string",
+            "enum": [
+                "raw",
+                "form",
+                "multipart"
+            ],
+            "x-enum-varnames": [
+                "RawBody",
+                "FormBody",
+                "MultipartBody"
+            ]
+        },
+        "constant.HttpImplementation": {
+            "type": "string",
+            "enum": [
+                "HttpClient4",
+                "Java"
+            ],
+            "x-enum-varnames": [
+                "HttpClient4Implementation",
+                "JavaImplementation"
+            ]
+        },
+        "constant.IconCode": {
+            "type": "string",
+            "enum": [
+                "IC0001",
+                "IC0002",
+                "IC0003"
+            ],
+            "x-enum-varnames": [
+                "Ok",
+                "Fail",
+                "Pending"
+            ]
+        },
+        "constant.InstallLocation": {
+            "type": "string",
+            "enum": [
+                "local",
+                "remote"
+            ],
+            "x-enum-varnames": [
+                "Local",
+                "Remote"
+            ]
+        },
+        "constant.JdbcDriver": {
+            "type": "string",
+            "enum": [
+                "postgresql",
+                "mysql"
+            ],
+            "x-enum-varnames": [
+                "PostgresDriver",
+                "MysqlDriver"
+            ]
+        },
+        "constant.LoadGeneratorType": {
+            "type": "string",
+            "enum": [
+                "jmeter",
+                "k6"
+            ],
+            "x-enum-varnames": [
+                "Jmeter",
+                "K6"
+            ]
+        },
+        "constant.LoadShape": {
+            "type": "string",
+            "enum": [
+                "concurrency",
+                "arrival_rate",
+                "spike",
+                "step_down",
+                "stages"
+            ],
+            "x-enum-varnames": [
+                "ConcurrencyShape",
+                "ArrivalRateShape",
+                "SpikeShape",
+                "StepDownShape",
+                "StagesShape"
+            ]
+        },
+        "constant.PriceCurrency": {
+            "type": "string",
+            "enum": [
+                "USD",
+                "KRW"
+            ],
+            "x-enum-varnames": [
+                "USD",
+                "KRW"
+            ]
+        },
+        "constant.PricePolicy": {
+            "type": "string",
+            "enum": [
+                "OnDemand"
+            ],
+            "x-enum-varnames": [
+                "OnDemand"
+            ]
+        },
+        "constant.PriceUnit": {
+            "type": "string",
+            "enum": [
+                "PerHour",
+                "PerYear"
+            ],
+            "x-enum-varnames": [
+                "PerHour",
+                "PerYear"
+            ]
+        },
+        "constant.ProgressEventType": {
+            "type": "string",
+            "enum": [
+                "state",
+                "step",
+                "figures"
+            ],
+            "x-enum-varnames": [
+                "StateEvent",
+                "StepEvent",
+                "FiguresEvent"
+            ]
+        },
+        "constant.ResourceType": {
+            "type": "string",
+            "enum": [
+                "VM",
+                "VNet",
+                "DataDisk",
+                "Etc"
+            ],
+            "x-enum-varnames": [
+                "VM",
+                "VNet",
+                "DataDisk",
+                "Etc"
+            ]
+        },
+        "constant.ScheduleRunStatus": {
+            "type": "string",
+            "enum": [
+                "started",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ScheduleRunStarted",
+                "ScheduleRunFailed"
+            ]
+        },
+        "constant.SlaCriterion": {
+            "type": "string",
+            "enum": [
+                "max_error_percent",
+                "max_p95",
+                "max_p99",
+                "min_throughput",
+                "result"
+            ],
+            "x-enum-varnames": [
+                "MaxErrorPercentCriterion",
+                "MaxP95Criterion",
+                "MaxP99Criterion",
+                "MinThroughputCriterion",
+                "ResultCriterion"
+            ]
+        },
+        "constant.SlaVerdict": {
+            "type": "string",
+            "enum": [
+                "PASS",
+                "FAIL"
+            ],
+            "x-enum-varnames": [
+                "SlaPass",
+                "SlaFail"
+            ]
+        },
+        "constant.StepStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "ok",
+                "failed",
+                "skipped"
+            ],
+            "x-enum-varnames": [
+                "StepPending",
+                "StepRunning",
+                "StepOk",
+                "StepFailed",
+                "StepSkipped"
+            ]
+        },
+        "constant.TargetProtocol": {
+            "type": "string",
+            "enum": [
+                "websocket",
+                "grpc",
+                "tcp",
+                "jdbc"
+            ],
+            "x-enum-comments": {
+                "GrpcTarget": "calls Method with Message as its JSON request",
+                "JdbcTarget": "runs Query on Database",
+                "TcpTarget": "sends Message and reads the answer",
+                "WebSocketTarget": "sends Message and waits for one frame back"
+            },
+            "x-enum-varnames": [
+                "WebSocketTarget",
+                "GrpcTarget",
+                "TcpTarget",
+                "JdbcTarget"
+            ]
+        },
+        "constant.ThinkTimeType": {
+            "type": "string",
+            "enum": [
+                "constant",
+                "uniform",
+                "gaussian"
+            ],
+            "x-enum-comments": {
+                "ConstantThinkTime": "Delay ms every time",
+                "GaussianThinkTime": "Delay ms on average, deviating by Range ms",
+                "UniformThinkTime": "Delay ms plus up to Range ms more, evenly spread"
+            },
+            "x-enum-varnames": [
+                "ConstantThinkTime",
+                "UniformThinkTime",
+                "GaussianThinkTime"
+            ]
+        },
+        "constant.TlsVersion": {
+            "type": "string",
+            "enum": [
+                "TLSv1.2",
+                "TLSv1.3"
+            ],
+            "x-enum-varnames": [
+                "Tls12",
+                "Tls13"
+            ]
+        },
+        "cost.EsimateCostSpecResults": {
+            "type": "object",
+            "properties": {
+                "estimateForecastCostSpecDetailResults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cost.EstimateCostSpecDetailResult"
+                    }
+                },
+                "imageName": {
+                    "type": "string"
+                },
+                "instanceType": {
+                    "type": "string"
+                },
+                "providerName": {
+                    "type": "string"
+                },
+                "regionName": {
+                    "type": "string"
+                },
+                "totalMaxMonthlyPrice": {
+                    "type": "number"
+                },
+                "totalMinMonthlyPrice": {
+                    "type": "number"
+                }
+            }
+        },
+        "cost.EstimateCostInfoResult": {
+            "type": "object",
+            "properties": {
+                "calculatedMonthlyPrice": {
+                    "type": "number"
+                },
+                "currency": {
+                    "$ref": "#/definitions/constant.PriceCurrency"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageName": {
+                    "type": "string"
+                },
+                "instanceType": {
+                    "type": "string"
+                },
+                "lastUpdatedAt": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "originalPricePolicy": {
+                    "type": "string"
+                },
+                "osType": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "priceDescription": {
+                    "type": "string"
+                },
+                "pricePolicy": {
+                    "$ref": "#/definitions/constant.PricePolicy"
+                },
+                "productDescription": {
+                    "type": "string"
+                },
+                "providerName": {
+                    "type": "string"
+                },
+                "regionName": {
+                    "type": "string"
+                },
+                "storage": {
+                    "type": "string"
+                },
+                "unit": {
+                    "$ref": "#/definitions/constant.PriceUnit"
+                },
+                "vCpu": {
+                    "type": "string"
+                }
+            }
+        },
+        "cost.EstimateCostInfoResults": {
+            "type": "object",
+            "properties": {
+                "estimateCostInfoResult": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cost.EstimateCostInfoResult"
+                    }
+                },
+                "resultCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cost.EstimateCostResults": {
+            "type": "object",
+            "properties": {
+                "esimateCostSpecResults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cost.EsimateCostSpecResults"
+                    }
+                }
+            }
+        },
+        "cost.EstimateCostSpecDetailResult": {
+            "type": "object",
+            "properties": {
+                "calculatedMonthlyPrice": {
+                    "type": "number"
+                },
+                "currency": {
+                    "$ref": "#/definitions/constant.PriceCurrency"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUpdatedAt": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "originalPricePolicy": {
+                    "type": "string"
+                },
+                "osType": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "priceDescription": {
+                    "type": "string"
+                },
+                "pricePolicy": {
+                    "$ref": "#/definitions/constant.PricePolicy"
+                },
+                "productDescription": {
+                    "type": "string"
+                },
+                "storage": {
+                    "type": "string"
+                },
+                "unit": {
+                    "$ref": "#/definitions/constant.PriceUnit"
+                },
+                "vCpu": {
+                    "type": "string"
+                }
+            }
+        },
+        "cost.GetEstimateForecastCostInfoResult": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "resourceId": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "totalCost": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "cost.GetEstimateForecastCostInfoResults": {
+            "type": "object",
+            "properties": {
+                "getEstimateForecastCostInfoResults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cost.GetEstimateForecastCostInfoResult"
+                    }
+                },
+                "resultCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cost.UpdateEstimateForecastCostInfoResult": {
+            "type": "object",
+            "properties": {
+                "fetchedDataCount": {
+                    "type": "integer"
+                },
+                "insertedDataCount": {
+                    "type": "integer"
+                },
+                "updatedDataCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.ComparedLoadTestResult": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LabelComparisonResult"
+                    }
+                },
+                "loadTestKey": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.MetricComparisonResult"
+                    }
+                },
+                "regressed": {
+                    "type": "boolean"
+                },
+                "regressions": {
+                    "description": "what regressed, in words",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "load.CreateLoadTestScenarioCatalogReq": {
+            "type": "object",
+            "required": [
+                "duration",
+                "name",
+                "rampUpSteps",
+                "rampUpTime",
+                "virtualUsers"
+            ],
+            "properties": {
+                "arrivalRate": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Basic load test scenario catalog template"
+                },
+                "duration": {
+                    "type": "string",
+                    "example": "300"
+                },
+                "loadShape": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadShape"
+                        }
+                    ],
+                    "example": "concurrency"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Basic Load Test Scenario"
+                },
+                "rampUpSteps": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "rampUpTime": {
+                    "type": "string",
+                    "example": "60"
+                },
+                "spikeDuration": {
+                    "type": "string",
+                    "example": "30"
+                },
+                "spikeUsers": {
+                    "type": "string",
+                    "example": "80"
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestStageParam"
+                    }
+                },
+                "thinkTime": {
+                    "description": "think time of the requests run with this scenario",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                        }
+                    ]
+                },
+                "virtualUsers": {
+                    "type": "string",
+                    "example": "10"
+                }
+            }
+        },
+        "load.DrainStatus": {
+            "type": "object",
+            "properties": {
+                "draining": {
+                    "type": "boolean"
+                },
+                "rsyncs": {
+                    "type": "integer"
+                },
+                "runningRuns": {
+                    "type": "integer"
+                },
+                "unsettledRuns": {
+                    "description": "UnsettledRuns are running runs not yet at a point the next server can pick them up\nfrom; Rsyncs are result transfers in progress.",
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadGeneratorInstallInfoResult": {
+            "type": "object",
+            "properties": {
+                "loadGeneratorInstallInfoResults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                    }
+                },
+                "totalRows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadTestBaselinesResult": {
+            "type": "object",
+            "properties": {
+                "loadTestBaselines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestBaselineResult"
+                    }
+                },
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadTestDataSetsResult": {
+            "type": "object",
+            "properties": {
+                "loadTestDataSets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestDataSetResult"
+                    }
+                },
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadTestExecutionInfosResult": {
+            "type": "object",
+            "properties": {
+                "loadTestExecutionInfos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionInfoResult"
+                    }
+                },
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadTestExecutionStateResult": {
+            "type": "object",
+            "properties": {
+                "loadTestExecutionStates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionStateResult"
+                    }
                 },
-                "result": {
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllLoadTestScenarioCatalogsResult": {
+            "type": "object",
+            "properties": {
+                "loadTestScenarioCatalogs": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/load.ResultSummary"
+                        "$ref": "#/definitions/load.LoadTestScenarioCatalogResult"
                     }
                 },
-                "successMessage": {
-                    "type": "string"
+                "totalRow": {
+                    "type": "integer"
                 }
             }
         },
-        "app.AntResponse-cost_EstimateCostInfoResults": {
+        "load.GetAllLoadTestSchedulesResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "loadTestSchedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestScheduleResult"
+                    }
+                },
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.GetAllMonitoringAgentInfoResult": {
+            "type": "object",
+            "properties": {
+                "monitoringAgentInfos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.MonitoringAgentInstallationResult"
+                    }
+                },
+                "totalRow": {
                     "type": "integer"
+                }
+            }
+        },
+        "load.GetLoadTestScheduleRunsResult": {
+            "type": "object",
+            "properties": {
+                "loadTestScheduleRuns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestScheduleRunResult"
+                    }
                 },
-                "errorMessage": {
+                "totalRow": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.InstallLoadGeneratorParam": {
+            "type": "object",
+            "properties": {
+                "clusterSize": {
+                    "description": "ClusterSize is how many generator servers the load is split across; 0 and 1 are one.",
+                    "type": "integer"
+                },
+                "coordinate": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "infraId": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/cost.EstimateCostInfoResults"
+                "installLocation": {
+                    "$ref": "#/definitions/constant.InstallLocation"
                 },
-                "successMessage": {
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "description": "VM 정보 추가 (CSP 매칭용)",
                     "type": "string"
+                },
+                "regions": {
+                    "description": "Regions are the places beside the generator's own that need a generator server of their\nown. One is added to the generator MCI for every region it has none in yet.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadGeneratorRegionParam"
+                    }
                 }
             }
         },
-        "app.AntResponse-cost_EstimateCostResults": {
+        "load.LabelComparisonResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "label": {
+                    "type": "string"
+                },
+                "missingIn": {
+                    "$ref": "#/definitions/constant.ComparisonSide"
+                },
+                "statistics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.StatisticComparisonResult"
+                    }
+                }
+            }
+        },
+        "load.LiveFigures": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "avgElapsed": {
+                    "type": "number"
+                },
+                "errorPercent": {
+                    "type": "number"
+                },
+                "p95Elapsed": {
+                    "type": "number"
+                },
+                "samples": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "throughput": {
+                    "description": "requests a second",
+                    "type": "number"
+                },
+                "totalErrors": {
+                    "type": "integer"
+                },
+                "totalSamples": {
+                    "type": "integer"
+                },
+                "windowSec": {
+                    "type": "integer"
+                }
+            }
+        },
+        "load.LoadGeneratorInstallInfoResult": {
+            "type": "object",
+            "properties": {
+                "clusterSize": {
+                    "type": "integer"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/cost.EstimateCostResults"
+                "id": {
+                    "type": "integer"
                 },
-                "successMessage": {
+                "installLocation": {
+                    "$ref": "#/definitions/constant.InstallLocation"
+                },
+                "installPath": {
+                    "type": "string"
+                },
+                "installType": {
+                    "type": "string"
+                },
+                "installVersion": {
+                    "type": "string"
+                },
+                "isCluster": {
+                    "type": "boolean"
+                },
+                "loadGeneratorServers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadGeneratorServerResult"
+                    }
+                },
+                "masterId": {
+                    "type": "integer"
+                },
+                "privateKeyName": {
+                    "type": "string"
+                },
+                "publicKeyName": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-cost_GetEstimateForecastCostInfoResults": {
+        "load.LoadGeneratorRegionParam": {
             "type": "object",
             "properties": {
-                "code": {
+                "provider": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                }
+            }
+        },
+        "load.LoadGeneratorServerResult": {
+            "type": "object",
+            "properties": {
+                "additionalVmKey": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "csp": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "isCluster": {
+                    "type": "boolean"
+                },
+                "isMaster": {
+                    "type": "boolean"
+                },
+                "label": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/cost.GetEstimateForecastCostInfoResults"
+                "lat": {
+                    "type": "string"
                 },
-                "successMessage": {
+                "lon": {
+                    "type": "string"
+                },
+                "machineType": {
+                    "type": "string"
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "privateIp": {
+                    "type": "string"
+                },
+                "publicDns": {
+                    "type": "string"
+                },
+                "publicIp": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "sshPort": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "zone": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-cost_UpdateEstimateForecastCostInfoResult": {
+        "load.LoadTestBaselineResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "infraId": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/cost.UpdateEstimateForecastCostInfoResult"
+                "loadTestKey": {
+                    "type": "string"
                 },
-                "successMessage": {
+                "nodeId": {
                     "type": "string"
-                }
-            }
-        },
-        "app.AntResponse-int64": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
                 },
-                "errorMessage": {
+                "nsId": {
                     "type": "string"
                 },
-                "result": {
+                "scenarioCatalogId": {
                     "type": "integer"
                 },
-                "successMessage": {
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-load_GetAllLoadGeneratorInstallInfoResult": {
+        "load.LoadTestComparisonResult": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "errorMessage": {
+                "baselineLoadTestKey": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.GetAllLoadGeneratorInstallInfoResult"
+                "regressed": {
+                    "description": "any of the runs regressed",
+                    "type": "boolean"
                 },
-                "successMessage": {
-                    "type": "string"
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.ComparedLoadTestResult"
+                    }
+                },
+                "tolerance": {
+                    "$ref": "#/definitions/load.LoadTestComparisonTolerance"
                 }
             }
         },
-        "app.AntResponse-load_GetAllLoadTestExecutionInfosResult": {
+        "load.LoadTestComparisonTolerance": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "errorPercentPoints": {
+                    "description": "error rate points higher",
+                    "type": "number"
                 },
-                "errorMessage": {
-                    "type": "string"
+                "metricPercent": {
+                    "description": "% worse target metrics",
+                    "type": "number"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.GetAllLoadTestExecutionInfosResult"
+                "responseTimePercent": {
+                    "description": "% slower; average, median and percentiles",
+                    "type": "number"
                 },
-                "successMessage": {
-                    "type": "string"
+                "significance": {
+                    "description": "p-value a change must be below",
+                    "type": "number"
+                },
+                "throughputPercent": {
+                    "description": "% fewer requests per second",
+                    "type": "number"
                 }
             }
         },
-        "app.AntResponse-load_GetAllLoadTestExecutionStateResult": {
+        "load.LoadTestDataSetResult": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "errorMessage": {
+                "createdAt": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.GetAllLoadTestExecutionStateResult"
+                "description": {
+                    "type": "string"
                 },
-                "successMessage": {
+                "fileName": {
                     "type": "string"
-                }
-            }
-        },
-        "app.AntResponse-load_GetAllLoadTestScenarioCatalogsResult": {
-            "type": "object",
-            "properties": {
-                "code": {
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "name": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.GetAllLoadTestScenarioCatalogsResult"
+                "rows": {
+                    "type": "integer"
                 },
-                "successMessage": {
+                "size": {
+                    "type": "integer"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-load_GetAllMonitoringAgentInfoResult": {
+        "load.LoadTestExecutionDataSetResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dataSetId": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "fileName": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.GetAllMonitoringAgentInfoResult"
+                "id": {
+                    "type": "integer"
                 },
-                "successMessage": {
-                    "type": "string"
+                "recycle": {
+                    "type": "boolean"
+                },
+                "shareMode": {
+                    "$ref": "#/definitions/constant.DataSetShareMode"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "stopThread": {
+                    "type": "boolean"
                 }
             }
         },
-        "app.AntResponse-load_LoadGeneratorInstallInfoResult": {
+        "load.LoadTestExecutionHttpAssertionResult": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "errorMessage": {
+                "expression": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                "id": {
+                    "type": "integer"
                 },
-                "successMessage": {
+                "type": {
+                    "$ref": "#/definitions/constant.AssertionType"
+                },
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-load_LoadTestExecutionInfoResult": {
+        "load.LoadTestExecutionHttpExtractorResult": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "defaultValue": {
+                    "type": "string"
                 },
-                "errorMessage": {
+                "expression": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.LoadTestExecutionInfoResult"
+                "id": {
+                    "type": "integer"
                 },
-                "successMessage": {
+                "matchNo": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/constant.ExtractorType"
+                },
+                "varName": {
                     "type": "string"
                 }
             }
         },
-        "app.AntResponse-load_LoadTestExecutionStateResult": {
+        "load.LoadTestExecutionHttpFileResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "fileName": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "mimeType": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.LoadTestExecutionStateResult"
-                },
-                "successMessage": {
+                "paramName": {
                     "type": "string"
+                },
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "app.AntResponse-load_LoadTestScenarioCatalogResult": {
+        "load.LoadTestExecutionHttpInfoResult": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "assertions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionHttpAssertionResult"
+                    }
                 },
-                "errorMessage": {
+                "bodyData": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.LoadTestScenarioCatalogResult"
+                "bodyType": {
+                    "$ref": "#/definitions/constant.HttpBodyType"
                 },
-                "successMessage": {
+                "extractors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionHttpExtractorResult"
+                    }
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionHttpFileResult"
+                    }
+                },
+                "formParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
+                    }
+                },
+                "headers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
+                    }
+                },
+                "hostname": {
                     "type": "string"
-                }
-            }
-        },
-        "app.AntResponse-load_MonitoringAgentInstallationResult": {
-            "type": "object",
-            "properties": {
-                "code": {
+                },
+                "httpClient": {
+                    "$ref": "#/definitions/load.RunLoadTestHttpClientParam"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "method": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/load.MonitoringAgentInstallationResult"
+                "path": {
+                    "type": "string"
                 },
-                "successMessage": {
+                "port": {
                     "type": "string"
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "queryParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
+                    }
+                },
+                "thinkTime": {
+                    "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                },
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
-        "app.AntResponse-string": {
+        "load.LoadTestExecutionInfoResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "agentHostname": {
+                    "type": "string"
+                },
+                "agentInstalled": {
+                    "type": "boolean"
+                },
+                "arrivalRate": {
+                    "type": "string"
+                },
+                "autoStop": {
+                    "$ref": "#/definitions/load.RunLoadTestAutoStopParam"
+                },
+                "compileDuration": {
+                    "type": "string"
+                },
+                "dataSets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionDataSetResult"
+                    }
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "engine": {
+                    "$ref": "#/definitions/constant.LoadGeneratorType"
+                },
+                "executionDuration": {
+                    "type": "string"
+                },
+                "httpClient": {
+                    "$ref": "#/definitions/load.RunLoadTestHttpClientParam"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "errorMessage": {
+                "loadGeneratorInstallInfo": {
+                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                },
+                "loadShape": {
+                    "$ref": "#/definitions/constant.LoadShape"
+                },
+                "loadTestExecutionHttpInfos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionHttpInfoResult"
+                    }
+                },
+                "loadTestExecutionState": {
+                    "$ref": "#/definitions/load.LoadTestExecutionStateResult"
+                },
+                "loadTestKey": {
+                    "type": "string"
+                },
+                "rampUpSteps": {
                     "type": "string"
                 },
-                "result": {
-                    "type": "string"
+                "rampUpTime": {
+                    "type": "string"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionRegionResult"
+                    }
+                },
+                "sla": {
+                    "$ref": "#/definitions/load.RunLoadTestSlaParam"
                 },
-                "successMessage": {
+                "spikeDuration": {
                     "type": "string"
-                }
-            }
-        },
-        "app.AwsAdditionalInfoReq": {
-            "type": "object",
-            "properties": {
-                "ownerId": {
+                },
+                "spikeUsers": {
                     "type": "string"
                 },
-                "regions": {
+                "stages": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/load.RunLoadTestStageParam"
                     }
-                }
-            }
-        },
-        "app.CostResourceReq": {
-            "type": "object",
-            "properties": {
-                "resourceIds": {
+                },
+                "targets": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/load.LoadTestExecutionTargetResult"
                     }
                 },
-                "resourceType": {
-                    "$ref": "#/definitions/constant.ResourceType"
-                }
-            }
-        },
-        "app.DepResult": {
-            "type": "object",
-            "properties": {
-                "db": {
-                    "$ref": "#/definitions/app.DepStatus"
+                "testName": {
+                    "type": "string"
                 },
-                "ready": {
-                    "type": "boolean"
+                "thinkTime": {
+                    "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
                 },
-                "spider": {
-                    "$ref": "#/definitions/app.DepStatus"
+                "uploadedFiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionUploadedFileResult"
+                    }
                 },
-                "tumblebug": {
-                    "$ref": "#/definitions/app.DepStatus"
+                "virtualUsers": {
+                    "type": "string"
                 }
             }
         },
-        "app.DepStatus": {
+        "load.LoadTestExecutionRegionResult": {
             "type": "object",
             "properties": {
-                "authenticated": {
-                    "type": "boolean"
+                "id": {
+                    "type": "integer"
                 },
-                "error": {
+                "provider": {
                     "type": "string"
                 },
-                "lastCheck": {
+                "region": {
                     "type": "string"
                 },
-                "reachable": {
-                    "type": "boolean"
+                "virtualUsers": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
-        "app.InstallLoadGeneratorReq": {
+        "load.LoadTestExecutionSlaViolationResult": {
             "type": "object",
             "properties": {
-                "installLocation": {
-                    "$ref": "#/definitions/constant.InstallLocation"
+                "actual": {
+                    "type": "number"
+                },
+                "criterion": {
+                    "$ref": "#/definitions/constant.SlaCriterion"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
                 }
             }
         },
-        "app.JsonResult": {
-            "type": "object"
-        },
-        "app.MonitoringAgentInstallationReq": {
+        "load.LoadTestExecutionStateResult": {
             "type": "object",
             "properties": {
-                "infraId": {
+                "baselineLoadTestKey": {
+                    "description": "BaselineVerdict is PASS, REGRESSED or INCONCLUSIVE once a run that has a baseline has\nfinished successfully; BaselineMessage tells what regressed, or why it is inconclusive.",
                     "type": "string"
                 },
-                "nodeIds": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "baselineMessage": {
+                    "type": "string"
                 },
-                "nsId": {
+                "baselineVerdict": {
+                    "$ref": "#/definitions/constant.BaselineVerdict"
+                },
+                "compileDuration": {
                     "type": "string"
-                }
-            }
-        },
-        "app.RunLoadGeneratorHttpReq": {
-            "type": "object",
-            "required": [
-                "bodyData",
-                "hostname",
-                "method",
-                "path",
-                "port",
-                "protocol"
-            ],
-            "properties": {
-                "bodyData": {
-                    "description": "{\"xxx\": \"tttt\", \"wwwww\": \"wotjkenr\"}",
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "hostname": {
-                    "description": "xx.xx.xx.xx or asx.bbb.com",
+                "engine": {
+                    "$ref": "#/definitions/constant.LoadGeneratorType"
+                },
+                "executionDuration": {
                     "type": "string"
                 },
-                "method": {
-                    "description": "GET or POST",
+                "executionStatus": {
+                    "$ref": "#/definitions/constant.ExecutionStatus"
+                },
+                "expectedFinishAt": {
                     "type": "string"
                 },
-                "path": {
-                    "description": "/xxx/www/sss or possibly empty",
+                "failureMessage": {
                     "type": "string"
                 },
-                "port": {
-                    "description": "1 ~ 65353",
+                "finishAt": {
                     "type": "string"
                 },
-                "protocol": {
-                    "description": "http or https",
+                "iconCode": {
+                    "$ref": "#/definitions/constant.IconCode"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "loadGeneratorInstallInfo": {
+                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                },
+                "loadGeneratorInstallInfoId": {
+                    "type": "integer"
+                },
+                "loadTestKey": {
                     "type": "string"
-                }
-            }
-        },
-        "app.RunLoadTestReq": {
-            "type": "object",
-            "properties": {
-                "agentHostname": {
-                    "description": "basically, it is same as host for vm",
+                },
+                "nodeUid": {
+                    "description": "NodeUid identifies which VM this run belongs to. Node ids are names and get reused,\nso a caller that keeps showing \"the last run for this node\" needs the uid to notice\nthat the answer belongs to a VM that has since been replaced.",
                     "type": "string"
                 },
-                "collectAdditionalSystemMetrics": {
-                    "description": "agent tcp default port is 5555",
-                    "type": "boolean"
+                "parentLoadTestKey": {
+                    "description": "ParentLoadTestKey is the run this one was made again from.",
+                    "type": "string"
                 },
-                "duration": {
+                "queuePosition": {
+                    "description": "of a queued run, from 1",
+                    "type": "integer"
+                },
+                "scenarioCatalogId": {
+                    "description": "ScenarioCatalogId is the scenario catalog entry the run is of.",
+                    "type": "integer"
+                },
+                "slaViolations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionSlaViolationResult"
+                    }
+                },
+                "startAt": {
                     "type": "string"
                 },
-                "httpReqs": {
+                "steps": {
+                    "description": "Steps is the per-stage progress of the run (FR-MA2-PERF-007-08), ordered by seq.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/app.RunLoadGeneratorHttpReq"
+                        "$ref": "#/definitions/load.LoadTestExecutionStepResult"
                     }
                 },
-                "infraId": {
-                    "description": "for metadata usage",
+                "totalExpectedExecutionSecond": {
+                    "type": "integer"
+                },
+                "updatedAt": {
                     "type": "string"
                 },
-                "installLoadGenerator": {
-                    "description": "localhost for installing load generator; local | remote",
+                "verdict": {
+                    "description": "Verdict is PASS or FAIL once a run that was given SLA thresholds has finished, and\nSlaViolations lists the thresholds a failed one missed.",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/app.InstallLoadGeneratorReq"
+                            "$ref": "#/definitions/constant.SlaVerdict"
                         }
                     ]
-                },
-                "loadGeneratorInstallInfoId": {
-                    "description": "if already installed load generator simply put this field",
+                }
+            }
+        },
+        "load.LoadTestExecutionStepResult": {
+            "type": "object",
+            "properties": {
+                "attempt": {
                     "type": "integer"
                 },
-                "nodeId": {
-                    "description": "for metadata usage",
-                    "type": "string"
+                "children": {
+                    "description": "Children are the sub-steps of a phase. Callers that only render the phases can ignore\nthis field and see exactly what they saw before.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestExecutionStepResult"
+                    }
                 },
-                "nsId": {
-                    "description": "for validate agent host and connect to tumblebug resources",
+                "detail": {
                     "type": "string"
                 },
-                "rampUpSteps": {
-                    "type": "string"
+                "elapsedSec": {
+                    "description": "ElapsedSec is how long this step has taken: the whole span once it is done, or how long\nit has been running so far. The console needs the running figure to tell a step that is\nslow from one that is stuck, and computing it here keeps every caller agreeing on it.",
+                    "type": "integer"
                 },
-                "rampUpTime": {
+                "finishAt": {
                     "type": "string"
                 },
-                "testName": {
-                    "description": "test scenario",
+                "message": {
                     "type": "string"
                 },
-                "virtualUsers": {
+                "name": {
+                    "$ref": "#/definitions/constant.ExecutionStep"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "startAt": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/constant.StepStatus"
                 }
             }
         },
-        "app.StopLoadTestReq": {
+        "load.LoadTestExecutionTargetResult": {
             "type": "object",
             "properties": {
-                "infraId": {
+                "database": {
                     "type": "string"
                 },
-                "loadTestKey": {
+                "driver": {
+                    "$ref": "#/definitions/constant.JdbcDriver"
+                },
+                "eolByte": {
+                    "type": "integer"
+                },
+                "hostname": {
                     "type": "string"
                 },
-                "nodeId": {
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
                 },
-                "nsId": {
+                "method": {
+                    "type": "string"
+                },
+                "path": {
                     "type": "string"
-                }
-            }
-        },
-        "app.UpdateAndGetEstimateCostReq": {
-            "type": "object",
-            "properties": {
-                "specs": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "required": [
-                            "instanceType",
-                            "providerName",
-                            "regionName"
-                        ],
-                        "properties": {
-                            "image": {
-                                "type": "string"
-                            },
-                            "instanceType": {
-                                "type": "string"
-                            },
-                            "providerName": {
-                                "type": "string"
-                            },
-                            "regionName": {
-                                "type": "string"
-                            }
-                        }
-                    }
                 },
-                "specsWithFormat": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "required": [
-                            "commonSpec"
-                        ],
-                        "properties": {
-                            "commonImage": {
-                                "type": "string"
-                            },
-                            "commonSpec": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                "port": {
+                    "type": "string"
+                },
+                "protoFileName": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "$ref": "#/definitions/constant.TargetProtocol"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "secure": {
+                    "type": "boolean"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "app.UpdateEstimateForecastCostRawReq": {
+        "load.LoadTestExecutionUploadedFileResult": {
             "type": "object",
-            "required": [
-                "costResources"
-            ],
             "properties": {
-                "awsAdditionalInfo": {
-                    "$ref": "#/definitions/app.AwsAdditionalInfoReq"
+                "fileName": {
+                    "type": "string"
                 },
-                "costResources": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/app.CostResourceReq"
-                    }
+                "id": {
+                    "type": "integer"
+                },
+                "isTestPlan": {
+                    "type": "boolean"
+                },
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "app.UpdateEstimateForecastCostReq": {
+        "load.LoadTestProgressEvent": {
             "type": "object",
             "properties": {
-                "infraId": {
-                    "type": "string"
+                "figures": {
+                    "$ref": "#/definitions/load.LiveFigures"
                 },
-                "nsId": {
-                    "type": "string"
+                "state": {
+                    "$ref": "#/definitions/load.LoadTestExecutionStateResult"
+                },
+                "step": {
+                    "$ref": "#/definitions/load.LoadTestExecutionStepResult"
+                },
+                "type": {
+                    "$ref": "#/definitions/constant.ProgressEventType"
                 }
             }
         },
-        "app.readyzResponse": {
+        "load.LoadTestRegionResult": {
             "type": "object",
             "properties": {
-                "dependencies": {
-                    "$ref": "#/definitions/app.DepResult"
+                "provider": {
+                    "type": "string"
                 },
-                "initialized": {
-                    "type": "boolean"
+                "region": {
+                    "type": "string"
                 },
-                "message": {
+                "statistics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.LoadTestStatistics"
+                    }
+                },
+                "virtualUsers": {
                     "type": "string"
                 },
-                "ready": {
-                    "type": "boolean"
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
-        "constant.ExecutionStatus": {
-            "type": "string",
-            "enum": [
-                "on_processing",
-                "on_fetching",
-                "successed",
-                "test_failed"
-            ],
-            "x-enum-varnames": [
-                "OnProcessing",
-                "OnFetching",
-                "Successed",
-                "TestFailed"
-            ]
-        },
-        "constant.ExecutionStep": {
-            "type": "string",
-            "enum": [
-                "generator_install",
-                "agent_install",
-                "jmx_prepare",
-                "jmeter_run",
-                "result_fetch"
-            ],
-            "x-enum-varnames": [
-                "StepGeneratorInstall",
-                "StepAgentInstall",
-                "StepJmxPrepare",
-                "StepJmeterRun",
-                "StepResultFetch"
-            ]
-        },
-        "constant.IconCode": {
-            "type": "string",
-            "enum": [
-                "IC0001",
-                "IC0002",
-                "IC0003"
-            ],
-            "x-enum-varnames": [
-                "Ok",
-                "Fail",
-                "Pending"
-            ]
-        },
-        "constant.InstallLocation": {
-            "type": "string",
-            "enum": [
-                "local",
-                "remote"
-            ],
-            "x-enum-varnames": [
-                "Local",
-                "Remote"
-            ]
-        },
-        "constant.PriceCurrency": {
-            "type": "string",
-            "enum": [
-                "USD",
-                "KRW"
-            ],
-            "x-enum-varnames": [
-                "USD",
-                "KRW"
-            ]
-        },
-        "constant.PricePolicy": {
-            "type": "string",
-            "enum": [
-                "OnDemand"
-            ],
-            "x-enum-varnames": [
-                "OnDemand"
-            ]
-        },
-        "constant.PriceUnit": {
-            "type": "string",
-            "enum": [
-                "PerHour",
-                "PerYear"
-            ],
-            "x-enum-varnames": [
-                "PerHour",
-                "PerYear"
-            ]
-        },
-        "constant.ResourceType": {
-            "type": "string",
-            "enum": [
-                "VM",
-                "VNet",
-                "DataDisk",
-                "Etc"
-            ],
-            "x-enum-varnames": [
-                "VM",
-                "VNet",
-                "DataDisk",
-                "Etc"
-            ]
-        },
-        "constant.StepStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "running",
-                "ok",
-                "failed",
-                "skipped"
-            ],
-            "x-enum-varnames": [
-                "StepPending",
-                "StepRunning",
-                "StepOk",
-                "StepFailed",
-                "StepSkipped"
-            ]
-        },
-        "cost.EsimateCostSpecResults": {
+        "load.LoadTestScenarioCatalogResult": {
             "type": "object",
             "properties": {
-                "estimateForecastCostSpecDetailResults": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/cost.EstimateCostSpecDetailResult"
-                    }
+                "arrivalRate": {
+                    "type": "string"
                 },
-                "imageName": {
+                "createdAt": {
                     "type": "string"
                 },
-                "instanceType": {
+                "description": {
                     "type": "string"
                 },
-                "providerName": {
+                "duration": {
                     "type": "string"
                 },
-                "regionName": {
+                "id": {
+                    "type": "integer"
+                },
+                "loadShape": {
+                    "$ref": "#/definitions/constant.LoadShape"
+                },
+                "name": {
                     "type": "string"
                 },
-                "totalMaxMonthlyPrice": {
-                    "type": "number"
+                "rampUpSteps": {
+                    "type": "string"
                 },
-                "totalMinMonthlyPrice": {
-                    "type": "number"
+                "rampUpTime": {
+                    "type": "string"
+                },
+                "spikeDuration": {
+                    "type": "string"
+                },
+                "spikeUsers": {
+                    "type": "string"
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestStageParam"
+                    }
+                },
+                "thinkTime": {
+                    "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                },
+                "totalExpectedExecutionSecond": {
+                    "description": "TotalExpectedExecutionSecond is how long a run of this scenario lasts, over its whole\nload profile.",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "virtualUsers": {
+                    "type": "string"
                 }
             }
         },
-        "cost.EstimateCostInfoResult": {
+        "load.LoadTestScheduleResult": {
             "type": "object",
             "properties": {
-                "calculatedMonthlyPrice": {
-                    "type": "number"
-                },
-                "currency": {
-                    "$ref": "#/definitions/constant.PriceCurrency"
-                },
-                "id": {
-                    "type": "integer"
+                "createdAt": {
+                    "type": "string"
                 },
-                "imageName": {
+                "cron": {
                     "type": "string"
                 },
-                "instanceType": {
+                "description": {
                     "type": "string"
                 },
-                "lastUpdatedAt": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "infraId": {
                     "type": "string"
                 },
-                "memory": {
+                "lastRunAt": {
                     "type": "string"
                 },
-                "originalPricePolicy": {
+                "name": {
                     "type": "string"
                 },
-                "osType": {
+                "nextRunAt": {
+                    "description": "NextRunAt is when the schedule fires next; absent once it has nothing left to fire.",
                     "type": "string"
                 },
-                "price": {
+                "nodeId": {
                     "type": "string"
                 },
-                "priceDescription": {
+                "nsId": {
                     "type": "string"
                 },
-                "pricePolicy": {
-                    "$ref": "#/definitions/constant.PricePolicy"
-                },
-                "productDescription": {
-                    "type": "string"
+                "run": {
+                    "$ref": "#/definitions/load.RunLoadTestParam"
                 },
-                "providerName": {
+                "runAt": {
                     "type": "string"
                 },
-                "regionName": {
-                    "type": "string"
+                "scenarioCatalogId": {
+                    "type": "integer"
                 },
-                "storage": {
+                "timeZone": {
                     "type": "string"
                 },
-                "unit": {
-                    "$ref": "#/definitions/constant.PriceUnit"
-                },
-                "vCpu": {
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "cost.EstimateCostInfoResults": {
+        "load.LoadTestScheduleRunResult": {
             "type": "object",
             "properties": {
-                "estimateCostInfoResult": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/cost.EstimateCostInfoResult"
-                    }
+                "executionStatus": {
+                    "description": "ExecutionStatus is where the started run is now; absent when none was started.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.ExecutionStatus"
+                        }
+                    ]
                 },
-                "resultCount": {
+                "id": {
                     "type": "integer"
+                },
+                "loadTestKey": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "scheduledAt": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/constant.ScheduleRunStatus"
                 }
             }
         },
-        "cost.EstimateCostResults": {
-            "type": "object",
-            "properties": {
-                "esimateCostSpecResults": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/cost.EsimateCostSpecResults"
-                    }
-                }
-            }
-        },
-        "cost.EstimateCostSpecDetailResult": {
+        "load.LoadTestStatistics": {
             "type": "object",
             "properties": {
-                "calculatedMonthlyPrice": {
+                "average": {
                     "type": "number"
                 },
-                "currency": {
-                    "$ref": "#/definitions/constant.PriceCurrency"
-                },
-                "id": {
-                    "type": "integer"
+                "errorPercent": {
+                    "type": "number"
                 },
-                "lastUpdatedAt": {
+                "label": {
                     "type": "string"
                 },
-                "memory": {
-                    "type": "string"
+                "maxTime": {
+                    "type": "number"
                 },
-                "originalPricePolicy": {
-                    "type": "string"
+                "median": {
+                    "type": "number"
                 },
-                "osType": {
-                    "type": "string"
+                "minTime": {
+                    "type": "number"
                 },
-                "price": {
-                    "type": "string"
+                "ninetyFive": {
+                    "type": "number"
                 },
-                "priceDescription": {
-                    "type": "string"
+                "ninetyNine": {
+                    "type": "number"
                 },
-                "pricePolicy": {
-                    "$ref": "#/definitions/constant.PricePolicy"
+                "ninetyPercent": {
+                    "type": "number"
                 },
-                "productDescription": {
-                    "type": "string"
+                "receivedKB": {
+                    "type": "number"
                 },
-                "storage": {
-                    "type": "string"
+                "requestCount": {
+                    "type": "integer"
                 },
-                "unit": {
-                    "$ref": "#/definitions/constant.PriceUnit"
+                "sentKB": {
+                    "type": "number"
                 },
-                "vCpu": {
-                    "type": "string"
+                "throughput": {
+                    "type": "number"
                 }
             }
         },
-        "cost.GetEstimateForecastCostInfoResult": {
+        "load.MetricComparisonResult": {
             "type": "object",
             "properties": {
-                "category": {
-                    "type": "string"
+                "baseline": {
+                    "type": "number"
                 },
-                "date": {
-                    "type": "string"
+                "delta": {
+                    "type": "number"
                 },
-                "provider": {
-                    "type": "string"
+                "deltaPercent": {
+                    "type": "number"
                 },
-                "resourceId": {
+                "label": {
                     "type": "string"
                 },
-                "resourceType": {
+                "missingIn": {
+                    "$ref": "#/definitions/constant.ComparisonSide"
+                },
+                "pValue": {
+                    "type": "number"
+                },
+                "regression": {
+                    "type": "boolean"
+                },
+                "unit": {
                     "type": "string"
                 },
-                "totalCost": {
+                "value": {
                     "type": "number"
+                }
+            }
+        },
+        "load.MetricsRawData": {
+            "type": "object",
+            "properties": {
+                "isError": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
                 },
                 "unit": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "cost.GetEstimateForecastCostInfoResults": {
+        "load.MetricsSummary": {
             "type": "object",
             "properties": {
-                "getEstimateForecastCostInfoResults": {
+                "label": {
+                    "type": "string"
+                },
+                "metrics": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/cost.GetEstimateForecastCostInfoResult"
+                        "$ref": "#/definitions/load.MetricsRawData"
                     }
-                },
-                "resultCount": {
-                    "type": "integer"
                 }
             }
         },
-        "cost.UpdateEstimateForecastCostInfoResult": {
+        "load.MonitoringAgentInstallationResult": {
             "type": "object",
             "properties": {
-                "fetchedDataCount": {
-                    "type": "integer"
+                "agentType": {
+                    "type": "string"
                 },
-                "insertedDataCount": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "updatedDataCount": {
+                "infraId": {
+                    "type": "string"
+                },
+                "nodeId": {
+                    "type": "string"
+                },
+                "nsId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "vmCount": {
                     "type": "integer"
                 }
             }
         },
-        "load.CreateLoadTestScenarioCatalogReq": {
+        "load.ResultRawData": {
             "type": "object",
-            "required": [
-                "duration",
-                "name",
-                "rampUpSteps",
-                "rampUpTime",
-                "virtualUsers"
-            ],
             "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "Basic load test scenario catalog template"
+                "bytes": {
+                    "type": "integer"
                 },
-                "duration": {
-                    "type": "string",
-                    "example": "300"
+                "connection": {
+                    "description": "time to establish connection",
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Basic Load Test Scenario"
+                "elapsed": {
+                    "description": "time to last byte",
+                    "type": "integer"
                 },
-                "rampUpSteps": {
-                    "type": "string",
-                    "example": "5"
+                "idleTime": {
+                    "description": "time not spent sampling in jmeter (milliseconds) (generally 0)",
+                    "type": "integer"
                 },
-                "rampUpTime": {
-                    "type": "string",
-                    "example": "60"
+                "isError": {
+                    "type": "boolean"
                 },
-                "virtualUsers": {
-                    "type": "string",
-                    "example": "10"
-                }
-            }
-        },
-        "load.GetAllLoadGeneratorInstallInfoResult": {
-            "type": "object",
-            "properties": {
-                "loadGeneratorInstallInfoResults": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
-                    }
+                "latency": {
+                    "description": "time to first byte",
+                    "type": "integer"
                 },
-                "totalRows": {
+                "no": {
+                    "type": "integer"
+                },
+                "sentBytes": {
                     "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "load.GetAllLoadTestExecutionInfosResult": {
+        "load.ResultSummary": {
             "type": "object",
             "properties": {
-                "loadTestExecutionInfos": {
+                "label": {
+                    "type": "string"
+                },
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/load.LoadTestExecutionInfoResult"
+                        "$ref": "#/definitions/load.ResultRawData"
                     }
-                },
-                "totalRow": {
-                    "type": "integer"
                 }
             }
         },
-        "load.GetAllLoadTestExecutionStateResult": {
+        "load.RunLoadTestAssertionParam": {
             "type": "object",
             "properties": {
-                "loadTestExecutionStates": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.LoadTestExecutionStateResult"
-                    }
+                "expression": {
+                    "type": "string"
                 },
-                "totalRow": {
-                    "type": "integer"
+                "type": {
+                    "$ref": "#/definitions/constant.AssertionType"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "load.GetAllLoadTestScenarioCatalogsResult": {
+        "load.RunLoadTestAutoStopParam": {
             "type": "object",
             "properties": {
-                "loadTestScenarioCatalogs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.LoadTestScenarioCatalogResult"
-                    }
-                },
-                "totalRow": {
+                "errorPercentForSec": {
                     "type": "integer"
+                },
+                "maxAgentCpuPercent": {
+                    "type": "number"
+                },
+                "maxErrorPercent": {
+                    "type": "number"
+                },
+                "maxP95": {
+                    "type": "number"
                 }
             }
         },
-        "load.GetAllMonitoringAgentInfoResult": {
+        "load.RunLoadTestDataFileParam": {
             "type": "object",
             "properties": {
-                "monitoringAgentInfos": {
+                "content": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/load.MonitoringAgentInstallationResult"
+                        "type": "integer"
                     }
                 },
-                "totalRow": {
-                    "type": "integer"
+                "fileName": {
+                    "type": "string"
                 }
             }
         },
-        "load.LoadGeneratorInstallInfoResult": {
+        "load.RunLoadTestDataSetParam": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "installLocation": {
-                    "$ref": "#/definitions/constant.InstallLocation"
-                },
-                "installPath": {
-                    "type": "string"
-                },
-                "installType": {
-                    "type": "string"
-                },
-                "installVersion": {
-                    "type": "string"
-                },
-                "loadGeneratorServers": {
+                "content": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/load.LoadGeneratorServerResult"
+                        "type": "integer"
                     }
                 },
-                "privateKeyName": {
-                    "type": "string"
+                "dataSetId": {
+                    "type": "integer"
                 },
-                "publicKeyName": {
+                "fileName": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "recycle": {
+                    "type": "boolean"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "shareMode": {
+                    "$ref": "#/definitions/constant.DataSetShareMode"
+                },
+                "stopThread": {
+                    "type": "boolean"
                 }
             }
         },
-        "load.LoadGeneratorServerResult": {
+        "load.RunLoadTestExtractorParam": {
             "type": "object",
             "properties": {
-                "additionalVmKey": {
-                    "type": "string"
-                },
-                "createdAt": {
+                "defaultValue": {
                     "type": "string"
                 },
-                "csp": {
+                "expression": {
                     "type": "string"
                 },
-                "id": {
+                "matchNo": {
                     "type": "integer"
                 },
-                "label": {
-                    "type": "string"
-                },
-                "lat": {
-                    "type": "string"
-                },
-                "lon": {
-                    "type": "string"
-                },
-                "machineType": {
-                    "type": "string"
+                "type": {
+                    "$ref": "#/definitions/constant.ExtractorType"
                 },
-                "nodeId": {
+                "varName": {
                     "type": "string"
+                }
+            }
+        },
+        "load.RunLoadTestFileParam": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "privateIp": {
+                "fileName": {
                     "type": "string"
                 },
-                "publicDns": {
+                "mimeType": {
                     "type": "string"
                 },
-                "publicIp": {
+                "paramName": {
                     "type": "string"
+                }
+            }
+        },
+        "load.RunLoadTestHttpClientParam": {
+            "type": "object",
+            "properties": {
+                "connectTimeout": {
+                    "type": "integer"
                 },
-                "region": {
-                    "type": "string"
+                "downloadEmbeddedResources": {
+                    "type": "boolean"
                 },
-                "sshPort": {
-                    "type": "string"
+                "followRedirects": {
+                    "type": "boolean"
                 },
-                "startTime": {
-                    "type": "string"
+                "implementation": {
+                    "$ref": "#/definitions/constant.HttpImplementation"
                 },
-                "status": {
-                    "type": "string"
+                "insecureSkipVerify": {
+                    "type": "boolean"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "keepAlive": {
+                    "type": "boolean"
                 },
-                "username": {
-                    "type": "string"
+                "responseTimeout": {
+                    "type": "integer"
                 },
-                "zone": {
-                    "type": "string"
+                "tlsVersion": {
+                    "$ref": "#/definitions/constant.TlsVersion"
                 }
             }
         },
-        "load.LoadTestExecutionHttpInfoResult": {
+        "load.RunLoadTestHttpParam": {
             "type": "object",
             "properties": {
+                "assertions": {
+                    "description": "Assertions are what a response must satisfy to count as a success. Without them only\na 4xx/5xx status or a connection failure is an error.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestAssertionParam"
+                    }
+                },
                 "bodyData": {
                     "type": "string"
                 },
+                "bodyType": {
+                    "$ref": "#/definitions/constant.HttpBodyType"
+                },
+                "extractors": {
+                    "description": "Extractors pull values out of this request's response. Requests run in order within an\niteration, so a later request can use an extracted value as ${varName} in its path, body\nor headers - which is what a login -\u003e token -\u003e API flow needs.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestExtractorParam"
+                    }
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestFileParam"
+                    }
+                },
+                "formParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
+                    }
+                },
+                "headers": {
+                    "description": "Headers apply to this request only, on top of the plan-wide ones. QueryParams are added\nto whatever query Path already has. BodyType decides whether the body is BodyData as\ngiven or is built from FormParams (and Files, for multipart).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
+                    }
+                },
                 "hostname": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "httpClient": {
+                    "description": "HttpClient overrides the run's client options for this request, field by field.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestHttpClientParam"
+                        }
+                    ]
                 },
                 "method": {
                     "type": "string"
@@ -2772,343 +6332,356 @@ const docTemplate = `{
                 },
                 "protocol": {
                     "type": "string"
-                }
-            }
-        },
-        "load.LoadTestExecutionInfoResult": {
-            "type": "object",
-            "properties": {
-                "agentHostname": {
-                    "type": "string"
-                },
-                "agentInstalled": {
-                    "type": "boolean"
-                },
-                "compileDuration": {
-                    "type": "string"
                 },
-                "duration": {
-                    "type": "string"
-                },
-                "executionDuration": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "loadGeneratorInstallInfo": {
-                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
-                },
-                "loadTestExecutionHttpInfos": {
+                "queryParams": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/load.LoadTestExecutionHttpInfoResult"
+                        "$ref": "#/definitions/load.RunLoadTestNameValueParam"
                     }
                 },
-                "loadTestExecutionState": {
-                    "$ref": "#/definitions/load.LoadTestExecutionStateResult"
-                },
-                "loadTestKey": {
-                    "type": "string"
-                },
-                "rampUpSteps": {
-                    "type": "string"
-                },
-                "rampUpTime": {
-                    "type": "string"
+                "thinkTime": {
+                    "description": "ThinkTime overrides the run's think time for this request.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                        }
+                    ]
                 },
-                "testName": {
+                "weight": {
+                    "description": "Weight makes the request part of a traffic mix: it is sent in its share of iterations,\nweight / sum of all weights, instead of in every one. 0 sends it every iteration.\nEach weighted request is picked on its own, so a chained request that reads a value\nextracted by a weighted one may run without it.",
+                    "type": "integer"
+                }
+            }
+        },
+        "load.RunLoadTestNameValueParam": {
+            "type": "object",
+            "properties": {
+                "name": {
                     "type": "string"
                 },
-                "virtualUsers": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "load.LoadTestExecutionStateResult": {
+        "load.RunLoadTestParam": {
             "type": "object",
             "properties": {
-                "compileDuration": {
+                "agentHostname": {
                     "type": "string"
                 },
-                "createdAt": {
+                "arrivalRate": {
                     "type": "string"
                 },
-                "executionDuration": {
-                    "type": "string"
+                "autoStop": {
+                    "description": "AutoStop are the rules that stop the run while its load is going, when the target is\nplainly falling over. The results up to that point are still collected.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestAutoStopParam"
+                        }
+                    ]
                 },
-                "executionStatus": {
-                    "$ref": "#/definitions/constant.ExecutionStatus"
+                "collectAdditionalSystemMetrics": {
+                    "type": "boolean"
                 },
-                "expectedFinishAt": {
-                    "type": "string"
+                "dataSets": {
+                    "description": "DataSets are CSV files read a line at a time while the test runs; every column is a\nvariable requests can reference as ${column}.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestDataSetParam"
+                    }
                 },
-                "failureMessage": {
+                "duration": {
                     "type": "string"
                 },
-                "finishAt": {
-                    "type": "string"
+                "engine": {
+                    "description": "Engine is the tool that generates the load; JMeter when empty.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadGeneratorType"
+                        }
+                    ]
                 },
-                "iconCode": {
-                    "$ref": "#/definitions/constant.IconCode"
+                "httpClient": {
+                    "description": "HttpClient are the client options every request is sent with unless it sets its own.\nOnce the run is accepted they hold the engine's defaults for whatever was not given.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestHttpClientParam"
+                        }
+                    ]
                 },
-                "id": {
-                    "type": "integer"
+                "httpReqs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestHttpParam"
+                    }
+                },
+                "infraId": {
+                    "type": "string"
                 },
-                "loadGeneratorInstallInfo": {
-                    "$ref": "#/definitions/load.LoadGeneratorInstallInfoResult"
+                "installLoadGenerator": {
+                    "$ref": "#/definitions/load.InstallLoadGeneratorParam"
                 },
                 "loadGeneratorInstallInfoId": {
                     "type": "integer"
                 },
+                "loadShape": {
+                    "description": "LoadShape is how the load changes over time; concurrency when empty. See\nconstant.LoadShape for which of the fields below each shape reads.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadShape"
+                        }
+                    ]
+                },
                 "loadTestKey": {
                     "type": "string"
                 },
-                "startAt": {
+                "nodeId": {
                     "type": "string"
                 },
-                "steps": {
-                    "description": "Steps is the per-stage progress of the run (FR-MA2-PERF-007-08), ordered by seq.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.LoadTestExecutionStepResult"
-                    }
-                },
-                "totalExpectedExecutionSecond": {
-                    "type": "integer"
-                },
-                "updatedAt": {
+                "nsId": {
+                    "description": "related tumblebug",
                     "type": "string"
-                }
-            }
-        },
-        "load.LoadTestExecutionStepResult": {
-            "type": "object",
-            "properties": {
-                "attempt": {
-                    "type": "integer"
                 },
-                "detail": {
+                "parentLoadTestKey": {
+                    "description": "ParentLoadTestKey is the run this one is made again from; see RerunLoadTest.",
                     "type": "string"
                 },
-                "finishAt": {
+                "rampUpSteps": {
                     "type": "string"
                 },
-                "message": {
+                "rampUpTime": {
                     "type": "string"
                 },
-                "name": {
-                    "$ref": "#/definitions/constant.ExecutionStep"
+                "regions": {
+                    "description": "Regions are the places the load is sent from at the same time, each by a generator\nserver of its own. The load is split between them by weight. A run given none sends\nall of it from the generator's master.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestRegionParam"
+                    }
                 },
-                "seq": {
+                "scenarioCatalogId": {
+                    "description": "ScenarioCatalogId is the scenario catalog entry the run is of, whose baseline it is held\nto; see judgeBaseline.",
                     "type": "integer"
                 },
-                "startAt": {
-                    "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/constant.StepStatus"
-                }
-            }
-        },
-        "load.LoadTestScenarioCatalogResult": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
+                "sla": {
+                    "description": "Sla are the thresholds the whole run is held to once it finishes. A run given none is\nnot judged and gets no verdict.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestSlaParam"
+                        }
+                    ]
                 },
-                "description": {
+                "spikeDuration": {
                     "type": "string"
                 },
-                "duration": {
+                "spikeUsers": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestStageParam"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "targets": {
+                    "description": "Targets are requests to targets that do not speak plain HTTP. They run after HttpReqs\nin every iteration.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestTargetParam"
+                    }
                 },
-                "rampUpSteps": {
+                "testName": {
+                    "description": "test scenario",
                     "type": "string"
                 },
-                "rampUpTime": {
-                    "type": "string"
+                "testPlan": {
+                    "description": "TestPlan is a user supplied JMeter plan. When set it is run instead of the plan built\nfrom HttpReqs, and the scenario fields above only describe the run (Duration and\nRampUpTime still give its expected length).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestPlanParam"
+                        }
+                    ]
                 },
-                "updatedAt": {
-                    "type": "string"
+                "thinkTime": {
+                    "description": "ThinkTime is the pause before every request that does not set its own.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                        }
+                    ]
                 },
                 "virtualUsers": {
                     "type": "string"
                 }
             }
         },
-        "load.LoadTestStatistics": {
+        "load.RunLoadTestPlanParam": {
             "type": "object",
             "properties": {
-                "average": {
-                    "type": "number"
+                "content": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "errorPercent": {
-                    "type": "number"
+                "dataFiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestDataFileParam"
+                    }
                 },
-                "label": {
+                "fileName": {
+                    "type": "string"
+                }
+            }
+        },
+        "load.RunLoadTestRegionParam": {
+            "type": "object",
+            "properties": {
+                "provider": {
                     "type": "string"
                 },
-                "maxTime": {
-                    "type": "number"
-                },
-                "median": {
-                    "type": "number"
-                },
-                "minTime": {
-                    "type": "number"
-                },
-                "ninetyFive": {
-                    "type": "number"
-                },
-                "ninetyNine": {
-                    "type": "number"
-                },
-                "ninetyPercent": {
-                    "type": "number"
-                },
-                "receivedKB": {
-                    "type": "number"
+                "region": {
+                    "type": "string"
                 },
-                "requestCount": {
+                "weight": {
                     "type": "integer"
-                },
-                "sentKB": {
-                    "type": "number"
-                },
-                "throughput": {
-                    "type": "number"
                 }
             }
         },
-        "load.MetricsRawData": {
+        "load.RunLoadTestSlaParam": {
             "type": "object",
             "properties": {
-                "isError": {
-                    "type": "boolean"
+                "maxErrorPercent": {
+                    "type": "number"
                 },
-                "timestamp": {
-                    "type": "string"
+                "maxP95": {
+                    "type": "number"
                 },
-                "unit": {
-                    "type": "string"
+                "maxP99": {
+                    "type": "number"
                 },
-                "value": {
-                    "type": "string"
+                "minThroughput": {
+                    "type": "number"
                 }
             }
         },
-        "load.MetricsSummary": {
+        "load.RunLoadTestStageParam": {
             "type": "object",
             "properties": {
-                "label": {
-                    "type": "string"
+                "duration": {
+                    "type": "integer"
                 },
-                "metrics": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.MetricsRawData"
-                    }
+                "target": {
+                    "type": "integer"
                 }
             }
         },
-        "load.MonitoringAgentInstallationResult": {
+        "load.RunLoadTestTargetParam": {
             "type": "object",
             "properties": {
-                "agentType": {
+                "database": {
                     "type": "string"
                 },
-                "createdAt": {
-                    "type": "string"
+                "driver": {
+                    "$ref": "#/definitions/constant.JdbcDriver"
                 },
-                "id": {
+                "eolByte": {
                     "type": "integer"
                 },
-                "infraId": {
+                "hostname": {
                     "type": "string"
                 },
-                "nodeId": {
+                "message": {
                     "type": "string"
                 },
-                "nsId": {
+                "method": {
                     "type": "string"
                 },
-                "status": {
+                "password": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "path": {
                     "type": "string"
                 },
-                "username": {
+                "port": {
                     "type": "string"
                 },
-                "vmCount": {
-                    "type": "integer"
-                }
-            }
-        },
-        "load.ResultRawData": {
-            "type": "object",
-            "properties": {
-                "bytes": {
-                    "type": "integer"
+                "protoFile": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "connection": {
-                    "description": "time to establish connection",
-                    "type": "integer"
+                "protoFileName": {
+                    "type": "string"
                 },
-                "elapsed": {
-                    "description": "time to last byte",
-                    "type": "integer"
+                "protocol": {
+                    "$ref": "#/definitions/constant.TargetProtocol"
                 },
-                "idleTime": {
-                    "description": "time not spent sampling in jmeter (milliseconds) (generally 0)",
-                    "type": "integer"
+                "query": {
+                    "type": "string"
                 },
-                "isError": {
+                "secure": {
                     "type": "boolean"
                 },
-                "latency": {
-                    "description": "time to first byte",
+                "timeout": {
                     "type": "integer"
                 },
-                "no": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "load.RunLoadTestThinkTimeParam": {
+            "type": "object",
+            "properties": {
+                "delay": {
                     "type": "integer"
                 },
-                "sentBytes": {
+                "range": {
                     "type": "integer"
                 },
-                "timestamp": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+                "type": {
+                    "$ref": "#/definitions/constant.ThinkTimeType"
                 }
             }
         },
-        "load.ResultSummary": {
+        "load.StatisticComparisonResult": {
             "type": "object",
             "properties": {
-                "label": {
+                "baseline": {
+                    "type": "number"
+                },
+                "delta": {
+                    "type": "number"
+                },
+                "deltaPercent": {
+                    "description": "none when the baseline is 0",
+                    "type": "number"
+                },
+                "name": {
                     "type": "string"
                 },
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/load.ResultRawData"
-                    }
+                "pValue": {
+                    "type": "number"
+                },
+                "regression": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
         "load.UpdateLoadTestScenarioCatalogReq": {
             "type": "object",
             "properties": {
+                "arrivalRate": {
+                    "type": "string",
+                    "example": "50"
+                },
                 "description": {
                     "type": "string",
                     "example": "Updated load test scenario catalog template"
@@ -3117,6 +6690,14 @@ const docTemplate = `{
                     "type": "string",
                     "example": "600"
                 },
+                "loadShape": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoadShape"
+                        }
+                    ],
+                    "example": "spike"
+                },
                 "name": {
                     "type": "string",
                     "example": "Updated Load Test Scenario"
@@ -3129,6 +6710,29 @@ const docTemplate = `{
                     "type": "string",
                     "example": "120"
                 },
+                "spikeDuration": {
+                    "type": "string",
+                    "example": "30"
+                },
+                "spikeUsers": {
+                    "type": "string",
+                    "example": "80"
+                },
+                "stages": {
+                    "description": "Stages replaces the stored stages when present; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/load.RunLoadTestStageParam"
+                    }
+                },
+                "thinkTime": {
+                    "description": "ThinkTime replaces the stored think time when present; a constant one of 0 ms stops the\npauses.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/load.RunLoadTestThinkTimeParam"
+                        }
+                    ]
+                },
                 "virtualUsers": {
                     "type": "string",
                     "example": "20"
@@ -3140,14 +6744,14 @@ const docTemplate = `{
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "0.6.0",
-	Host:             "",
-	BasePath:         "/ant",
-	Schemes:          []string{},
-	Title:            "CM-ANT REST API",
-	Description:      "CM-ANT REST API swagger document.",
+	Version:     "0.6.0",
+	Host:        "",
+	BasePath:    "/ant",
+	Schemes:     []string{  },
+	Title:       "CM-ANT REST API",
+	Description: "CM-ANT REST API swagger document.",
 	InfoInstanceName: "swagger",
-	SwaggerTemplate:  docTemplate,
+	SwaggerTemplate: docTemplate,
 	LeftDelim:        "{{",
 	RightDelim:       "}}",
 }
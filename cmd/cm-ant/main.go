@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"os"
 	"os/signal"
@@ -113,7 +115,7 @@ func main() {
 
 	log.Info().Msgf("Starting the CM-Ant server...")
 	go func() {
-		if err := s.Start(); err != nil {
+		if err := s.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatal().Msgf("CM-Ant start server error: %v", err)
 		}
 	}()
@@ -125,9 +127,14 @@ func main() {
 
 	log.Info().Msgf("Shutting down CM-Ant server...")
 
-	// Perform any necessary cleanup actions here, such as closing connections or saving state.
-	// Optionally wait for pending operations to complete gracefully.
+	// Running load tests are given until the deadline to reach a point the next server can
+	// pick them up from; whatever has not is settled by that server when it starts.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), app.ShutdownTimeout())
+	defer shutdownCancel()
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		log.Error().Msgf("CM-Ant server shutdown error: %v", err)
+		return
+	}
 
 	log.Info().Msgf("CM-Ant server stopped gracefully.")
-	os.Exit(0)
 }
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/cloud-barista/cm-ant/internal/core/load"
 	"github.com/labstack/echo/v4"
 )
 
@@ -11,12 +13,15 @@ import (
 // c-mig-common/design/07-DESIGN/STANDARD-READYZ.md): a structured body that
 // callers can inspect for per-dependency reachable/authenticated state in
 // addition to the HTTP status code. cm-ant itself has no separate
-// initialization step, so Initialized is always true when Ready is true.
+// initialization step, so Initialized is always true when Ready is true. Drain is
+// set once the server is shutting down, which makes it not ready whatever the
+// dependencies say.
 type readyzResponse struct {
-	Ready        bool       `json:"ready"`
-	Initialized  bool       `json:"initialized"`
-	Message      string     `json:"message"`
-	Dependencies *DepResult `json:"dependencies"`
+	Ready        bool              `json:"ready"`
+	Initialized  bool              `json:"initialized"`
+	Message      string            `json:"message"`
+	Dependencies *DepResult        `json:"dependencies"`
+	Drain        *load.DrainStatus `json:"drain,omitempty"`
 }
 
 // @Id AntServerReadiness
@@ -27,7 +32,9 @@ type readyzResponse struct {
 // @Description body always carries per-dependency status; the HTTP status is
 // @Description 200 when every dependency is reachable and authenticated, and
 // @Description 503 otherwise. Results are cached briefly to limit outbound
-// @Description call rate; see STANDARD-READYZ for details.
+// @Description call rate; see STANDARD-READYZ for details. A server that is
+// @Description shutting down answers 503 with the drain of its running load
+// @Description tests in the drain field.
 // @Tags [Server Health]
 // @Accept json
 // @Produce json
@@ -43,6 +50,14 @@ func (s *AntServer) readyz(c echo.Context) error {
 		Dependencies: res,
 	}
 
+	if drain := s.services.loadService.DrainStatus(); drain.Draining {
+		body.Ready = false
+		body.Drain = &drain
+		body.Message = fmt.Sprintf("CM-Ant is shutting down - %d load tests still settling, %d result transfers in progress",
+			drain.UnsettledRuns, drain.Rsyncs)
+		return c.JSON(http.StatusServiceUnavailable, body)
+	}
+
 	if !res.Ready {
 		body.Message = "CM-Ant is not ready - see dependencies for the failing component"
 		return c.JSON(http.StatusServiceUnavailable, body)
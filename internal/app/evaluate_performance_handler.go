@@ -1,10 +1,17 @@
 package app
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloud-barista/cm-ant/internal/config"
 	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
@@ -18,6 +25,13 @@ const (
 	seoul = "37.53/127.02"
 )
 
+// extractorVarName is what JMeter accepts inside ${...} without surprises: a name that also
+// parses as a JMeter function call or contains a brace would not resolve as a variable.
+var extractorVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// httpHeaderName is the token grammar of RFC 9110 field names.
+var httpHeaderName = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
 // getAllLoadGeneratorInstallInfo handler function that retrieves all load generator installation information.
 // @Id GetAllLoadGeneratorInstallInfo
 // @Summary Get All Load Generator Install Info
@@ -87,12 +101,18 @@ func (s *AntServer) installLoadGenerator(c echo.Context) error {
 		return errorResponseJson(http.StatusBadRequest, "available install locations are remote or local.")
 	}
 
+	if err := validateClusterSize(req); err != nil {
+		log.Error().Msgf("Invalid cluster size: %v", err)
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load generator installation info is not correct.; %v", err))
+	}
+
 	log.Info().Msgf("Calling service layer to install load generator")
 
 	// call service layer install load generator
 	param := load.InstallLoadGeneratorParam{
 		InstallLocation: req.InstallLocation,
 		Coordinates:     []string{seoul},
+		ClusterSize:     req.ClusterSize,
 	}
 	result, err := s.services.loadService.InstallLoadGenerator(param)
 
@@ -155,7 +175,7 @@ func (s *AntServer) uninstallLoadGenerator(c echo.Context) error {
 // runLoadTest handler function that initiates a load test.
 // @Id RunLoadTest
 // @Summary Run Load Test
-// @Description Start a load test using the provided load test configuration.
+// @Description Start a load test using the provided load test configuration. The run is queued until the concurrency limits (in all, per load generator and per target node) let it start; meanwhile its state is queued, with its place in the queue.
 // @Tags [Load Test Execution Management]
 // @Accept json
 // @Produce json
@@ -164,6 +184,7 @@ func (s *AntServer) uninstallLoadGenerator(c echo.Context) error {
 // @Failure 400 {object} app.AntResponse[string] "load test running info is not correct."
 // @Failure 400 {object} app.AntResponse[string] "load test install location is invalid."
 // @Failure 500 {object} app.AntResponse[string] "ant server has got error. please try again."
+// @Failure 503 {object} app.AntResponse[string] "cm-ant is shutting down and takes no new load tests"
 // @Router /api/v1/load/tests/run [post]
 func (s *AntServer) runLoadTest(c echo.Context) error {
 	var req RunLoadTestReq
@@ -172,96 +193,344 @@ func (s *AntServer) runLoadTest(c echo.Context) error {
 		return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.")
 	}
 
+	arg, err := toRunLoadTestParam(req)
+	if err != nil {
+		return err
+	}
+	if err := s.services.loadService.ValidateRunOfScenarioCatalog(c.Request().Context(), arg); err != nil {
+		if errors.Is(err, load.ErrInvalidLoadTestScenario) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
+		return errorResponseJson(http.StatusInternalServerError, "ant server has got error. please try again.")
+	}
+
+	loadTestKey, err := s.services.loadService.RunLoadTest(arg)
+
+	if err != nil {
+		if errors.Is(err, load.ErrShuttingDown) {
+			return errorResponseJson(http.StatusServiceUnavailable, err.Error())
+		}
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
+	return successResponseJson(
+		c,
+		fmt.Sprintf("Successfully run load test. Load test key: %s", loadTestKey),
+		loadTestKey,
+	)
+}
+
+// rerunLoadTest handler function that makes a load test run again.
+// @Id RerunLoadTest
+// @Summary Rerun Load Test
+// @Description Run a load test again with the configuration it was stored with, files included. Virtual users, duration, the target node and the host requests are sent to can be overridden. The new run has a key of its own and names the run it was made from as its parentLoadTestKey. It goes on the same load generator while that is still installed; otherwise one is installed at installLocation, or where the old one was.
+// @Tags [Load Test Execution Management]
+// @Accept json
+// @Produce json
+// @Param loadTestKey path string true "Load test key of the run to make again"
+// @Param body body app.RerunLoadTestReq false "Rerun Load Test Request"
+// @Success 200 {object} app.AntResponse[string] "{loadTestKey}"
+// @Failure 400 {object} app.AntResponse[string] "load test rerun info is not correct."
+// @Failure 404 {object} app.AntResponse[string] "load test not found"
+// @Failure 503 {object} app.AntResponse[string] "cm-ant is shutting down and takes no new load tests"
+// @Router /api/v1/load/tests/{loadTestKey}/rerun [post]
+func (s *AntServer) rerunLoadTest(c echo.Context) error {
+	loadTestKey := strings.TrimSpace(c.Param("loadTestKey"))
+	if loadTestKey == "" {
+		return errorResponseJson(http.StatusBadRequest, "Load test key must be set.")
+	}
+
+	var req RerunLoadTestReq
+	if err := c.Bind(&req); err != nil {
+		return errorResponseJson(http.StatusBadRequest, "load test rerun info is not correct.")
+	}
+
+	if req.InstallLocation != "" && req.InstallLocation != constant.Local && req.InstallLocation != constant.Remote {
+		return errorResponseJson(http.StatusBadRequest, "invalid load test install location")
+	}
+
+	maxVU, maxDur, _, _ := loadTestLimits()
+	if vu := strings.TrimSpace(req.VirtualUsers); vu != "" {
+		if v, err := strconv.Atoi(vu); err != nil || v < 1 || v > maxVU {
+			return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("virtual user is not correct. the range must be in 1 to %d", maxVU))
+		}
+	}
+	if d := strings.TrimSpace(req.Duration); d != "" {
+		if v, err := strconv.Atoi(d); err != nil || v < 1 || v > maxDur {
+			return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("duration is not correct. the range must be in 1 to %d", maxDur))
+		}
+	}
+
+	arg := load.RerunLoadTestParam{
+		LoadTestKey: loadTestKey,
+		InstallLoadGenerator: load.InstallLoadGeneratorParam{
+			InstallLocation: req.InstallLocation,
+			Coordinates:     []string{seoul},
+		},
+		TestName:     strings.TrimSpace(req.TestName),
+		VirtualUsers: strings.TrimSpace(req.VirtualUsers),
+		Duration:     strings.TrimSpace(req.Duration),
+		NsId:         strings.TrimSpace(req.NsId),
+		InfraId:      strings.TrimSpace(req.InfraId),
+		NodeId:       strings.TrimSpace(req.NodeId),
+		Hostname:     strings.TrimSpace(req.Hostname),
+	}
+
+	newLoadTestKey, err := s.services.loadService.RerunLoadTest(arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, load.ErrLoadTestNotFound):
+			return errorResponseJson(http.StatusNotFound, err.Error())
+		case errors.Is(err, load.ErrShuttingDown):
+			return errorResponseJson(http.StatusServiceUnavailable, err.Error())
+		}
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
+	return successResponseJson(
+		c,
+		fmt.Sprintf("Successfully rerun load test %s. Load test key: %s", loadTestKey, newLoadTestKey),
+		newLoadTestKey,
+	)
+}
+
+// toRunLoadTestParam checks a run request and converts it into the run it asks for. Its error
+// is the response to answer with.
+func toRunLoadTestParam(req RunLoadTestReq) (load.RunLoadTestParam, error) {
 	if req.LoadGeneratorInstallInfoId != uint(0) {
 		req.InstallLoadGenerator = InstallLoadGeneratorReq{}
 	} else if req.InstallLoadGenerator.InstallLocation != constant.Local &&
 		req.InstallLoadGenerator.InstallLocation != constant.Remote {
-		return errorResponseJson(http.StatusBadRequest, "invalid load test install location")
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "invalid load test install location")
+	}
+
+	if err := validateClusterSize(req.InstallLoadGenerator); err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
 	}
 
 	if strings.TrimSpace(req.TestName) == "" {
 		req.TestName = uuid.New().String()
 	}
 
-	// FR-MA2-PERF-007-01: parameter upper bounds are configurable (config.yaml load.limits /
-	// ANT_LOAD_LIMITS_*), falling back to the built-in defaults when unset.
-	lim := config.AppConfig.Load.Limits
-	maxVU, maxDur, maxRU, maxRS := lim.MaxVirtualUsers, lim.MaxDuration, lim.MaxRampUpTime, lim.MaxRampUpSteps
-	if maxVU <= 0 {
-		maxVU = 100
-	}
-	if maxDur <= 0 {
-		maxDur = 300
-	}
-	if maxRU <= 0 {
-		maxRU = 60
+	maxVU, maxDur, maxRU, maxRS := loadTestLimits()
+
+	shape := constant.LoadShape(strings.ToLower(strings.TrimSpace(string(req.LoadShape))))
+	if shape == "" {
+		shape = constant.ConcurrencyShape
 	}
-	if maxRS <= 0 {
-		maxRS = 20
+	if !load.IsSupportedLoadShape(shape) {
+		log.Error().Msgf("load shape %q is not supported; %s", req.LoadShape, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; LoadShape %s is not supported", req.LoadShape))
 	}
 
-	if v, err := strconv.Atoi(strings.TrimSpace(req.VirtualUsers)); err != nil || v < 1 || v > maxVU {
-		log.Error().Msg("virtual user count is invalid")
-		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("virtual user is not correct. the range must be in 1 to %d", maxVU))
-	}
+	// a stages run is described by its stages alone
+	if shape != constant.StagesShape {
+		if v, err := strconv.Atoi(strings.TrimSpace(req.VirtualUsers)); err != nil || v < 1 || v > maxVU {
+			log.Error().Msg("virtual user count is invalid")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("virtual user is not correct. the range must be in 1 to %d", maxVU))
+		}
 
-	if v, err := strconv.Atoi(strings.TrimSpace(req.Duration)); err != nil || v < 1 || v > maxDur {
-		log.Error().Msg("duration is invalid")
-		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("duration is not correct. the range must be in 1 to %d", maxDur))
+		if v, err := strconv.Atoi(strings.TrimSpace(req.Duration)); err != nil || v < 1 || v > maxDur {
+			log.Error().Msg("duration is invalid")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("duration is not correct. the range must be in 1 to %d", maxDur))
+		}
+
+		if v, err := strconv.Atoi(strings.TrimSpace(req.RampUpTime)); err != nil || v < 1 || v > maxRU {
+			log.Error().Msg("ramp up time is invalid")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("ramp up time is not correct. the range must be in 1 to %d", maxRU))
+		}
+
+		if v, err := strconv.Atoi(strings.TrimSpace(req.RampUpSteps)); err != nil || v < 1 || v > maxRS {
+			log.Error().Msg("ramp up steps is invalid")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("ramp up steps is not correct. the range must be in 1 to %d", maxRS))
+		}
 	}
 
-	if v, err := strconv.Atoi(strings.TrimSpace(req.RampUpTime)); err != nil || v < 1 || v > maxRU {
-		log.Error().Msg("ramp up time is invalid")
-		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("ramp up time is not correct. the range must be in 1 to %d", maxRU))
+	profile, err := toLoadShapeParams(req, shape)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
 	}
 
-	if v, err := strconv.Atoi(strings.TrimSpace(req.RampUpSteps)); err != nil || v < 1 || v > maxRS {
-		log.Error().Msg("ramp up steps is invalid")
-		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("ramp up steps is not correct. the range must be in 1 to %d", maxRS))
+	engine := constant.LoadGeneratorType(strings.ToLower(strings.TrimSpace(string(req.Engine))))
+	if engine != "" && !load.IsSupportedEngine(engine) {
+		log.Error().Msgf("load generator engine %q is not supported; %s", req.Engine, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; Engine %s is not supported", req.Engine))
 	}
 
-	if len(req.HttpReqs) == 0 {
+	if len(req.HttpReqs) == 0 && len(req.Targets) == 0 {
 		log.Error().Msgf("http request have to have at least one; %s", req.TestName)
-		return errorResponseJson(http.StatusBadRequest, "http request have to have at least one or more")
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "http request or target have to have at least one or more")
 	}
 
 	var https []load.RunLoadTestHttpParam
 	for _, h := range req.HttpReqs {
 		if h.Method == "" {
 			log.Error().Msgf("method for load test is empty. cannot start load test; %s", req.TestName)
-			return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Method")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Method")
+		}
+
+		if !load.IsRenderableHttpMethod(h.Method) {
+			log.Error().Msgf("method %q cannot be load tested; %s", h.Method, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; Method %s is not supported", h.Method))
 		}
 
 		if h.Protocol == "" {
 			h.Protocol = "http"
 		}
 
+		if p := strings.ToLower(strings.TrimSpace(h.Protocol)); p != "http" && p != "https" {
+			log.Error().Msgf("protocol %q cannot be load tested; %s", h.Protocol, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; Protocol %s is not supported", h.Protocol))
+		}
+
 		if h.Hostname == "" {
 			log.Error().Msgf("hostname for load test is empty. cannot start load test; %s", req.TestName)
-			return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Hostname")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Hostname")
 		}
 
 		if t, err := strconv.Atoi(h.Port); err != nil || t < 1 || t > 65535 {
 			log.Error().Msgf("port range is not valid. check the range of ports")
-			return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Port")
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Port")
+		}
+
+		var extractors []load.RunLoadTestExtractorParam
+		for _, e := range h.Extractors {
+			if e.Type != constant.JsonPathExtractor && e.Type != constant.RegexExtractor {
+				log.Error().Msgf("extractor type %q is not supported; %s", e.Type, req.TestName)
+				return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Extractor type must be json_path or regex")
+			}
+
+			if !extractorVarName.MatchString(strings.TrimSpace(e.VarName)) {
+				log.Error().Msgf("extractor variable name %q is not valid; %s", e.VarName, req.TestName)
+				return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Extractor varName")
+			}
+
+			if strings.TrimSpace(e.Expression) == "" {
+				log.Error().Msgf("extractor expression is empty; %s", req.TestName)
+				return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Extractor expression")
+			}
+
+			matchNo := 1
+			if e.MatchNo != nil {
+				matchNo = *e.MatchNo
+			}
+
+			if matchNo < -1 {
+				log.Error().Msgf("extractor match number %d is not valid; %s", matchNo, req.TestName)
+				return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; Extractor matchNo")
+			}
+
+			extractors = append(extractors, load.RunLoadTestExtractorParam{
+				Type:         e.Type,
+				VarName:      strings.TrimSpace(e.VarName),
+				Expression:   strings.TrimSpace(e.Expression),
+				MatchNo:      matchNo,
+				DefaultValue: e.DefaultValue,
+			})
+		}
+
+		fields, err := toHttpFieldParams(h)
+		if err != nil {
+			log.Error().Msgf("%v; %s", err, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+		}
+
+		if h.Weight < 0 || h.Weight > maxRequestWeight {
+			log.Error().Msgf("request weight %d is not valid; %s", h.Weight, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; Weight must be in 0 to %d", maxRequestWeight))
+		}
+
+		thinkTime, err := toThinkTimeParam(h.ThinkTime)
+		if err != nil {
+			log.Error().Msgf("%v; %s", err, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+		}
+
+		assertions, err := toAssertionParams(h.Assertions)
+		if err != nil {
+			log.Error().Msgf("%v; %s", err, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+		}
+
+		httpClient, err := toHttpClientParam(h.HttpClient, true)
+		if err != nil {
+			log.Error().Msgf("%v; %s", err, req.TestName)
+			return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
 		}
 
 		hh := load.RunLoadTestHttpParam{
-			Method:   strings.TrimSpace(h.Method),
-			Protocol: strings.TrimSpace(h.Protocol),
-			Hostname: strings.TrimSpace(h.Hostname),
-			Port:     strings.TrimSpace(h.Port),
-			Path:     strings.TrimSpace(h.Path),
-			BodyData: strings.TrimSpace(h.BodyData),
+			Method:     strings.ToUpper(strings.TrimSpace(h.Method)),
+			Protocol:   strings.TrimSpace(h.Protocol),
+			Hostname:   strings.TrimSpace(h.Hostname),
+			Port:       strings.TrimSpace(h.Port),
+			Path:       strings.TrimSpace(h.Path),
+			BodyData:   strings.TrimSpace(h.BodyData),
+			Extractors: extractors,
+
+			Headers:     fields.Headers,
+			QueryParams: fields.QueryParams,
+			BodyType:    fields.BodyType,
+			FormParams:  fields.FormParams,
+			Files:       fields.Files,
+
+			Weight:     h.Weight,
+			ThinkTime:  thinkTime,
+			Assertions: assertions,
+			HttpClient: httpClient,
 		}
 
 		https = append(https, hh)
 	}
 
+	thinkTime, err := toThinkTimeParam(req.ThinkTime)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	dataSets, err := toDataSetParams(req.DataSets)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	sla, err := toSlaParam(req.Sla)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	autoStop, err := toAutoStopParam(req.AutoStop, req.CollectAdditionalSystemMetrics)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	httpClient, err := toHttpClientParam(req.HttpClient, false)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	targets, err := toTargetParams(req.Targets)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	regions, err := toRegionParams(req.Regions)
+	if err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
 	arg := load.RunLoadTestParam{
 		InstallLoadGenerator: load.InstallLoadGeneratorParam{
 			InstallLocation: req.InstallLoadGenerator.InstallLocation,
 			Coordinates:     []string{seoul},
+			ClusterSize:     req.InstallLoadGenerator.ClusterSize,
 		},
 		LoadGeneratorInstallInfoId: req.LoadGeneratorInstallInfoId,
 		TestName:                   strings.TrimSpace(req.TestName),
@@ -270,6 +539,19 @@ func (s *AntServer) runLoadTest(c echo.Context) error {
 		RampUpTime:                 strings.TrimSpace(req.RampUpTime),
 		RampUpSteps:                strings.TrimSpace(req.RampUpSteps),
 
+		LoadShape:     profile.LoadShape,
+		ArrivalRate:   profile.ArrivalRate,
+		SpikeUsers:    profile.SpikeUsers,
+		SpikeDuration: profile.SpikeDuration,
+		Stages:        profile.Stages,
+		ThinkTime:     thinkTime,
+		DataSets:      dataSets,
+		Sla:           sla,
+		AutoStop:      autoStop,
+		HttpClient:    httpClient,
+
+		ScenarioCatalogId: req.ScenarioCatalogId,
+
 		CollectAdditionalSystemMetrics: req.CollectAdditionalSystemMetrics,
 		AgentHostname:                  strings.TrimSpace(req.AgentHostname),
 
@@ -278,11 +560,506 @@ func (s *AntServer) runLoadTest(c echo.Context) error {
 		NodeId:  strings.TrimSpace(req.NodeId),
 
 		HttpReqs: https,
+		Targets:  targets,
+		Regions:  regions,
+		Engine:   engine,
+	}
+	if profile.VirtualUsers != "" {
+		arg.VirtualUsers = profile.VirtualUsers
+	}
+
+	// the regions are checked against the load they split
+	if err := load.ValidateRegions(arg); err != nil {
+		log.Error().Msgf("%v; %s", err, req.TestName)
+		return load.RunLoadTestParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	return arg, nil
+}
+
+// validateClusterSize checks the generator servers asked for. Only a remote generator can
+// have more than one.
+func validateClusterSize(req InstallLoadGeneratorReq) error {
+	if req.ClusterSize < 0 || req.ClusterSize > load.MaxClusterSize {
+		return fmt.Errorf("ClusterSize must be in 1 to %d", load.MaxClusterSize)
+	}
+	if req.ClusterSize > 1 && req.InstallLocation != constant.Remote {
+		return errors.New("ClusterSize above 1 needs a remote install location")
+	}
+	return nil
+}
+
+// maxDataSets bounds the data sets of one run; each is a config element every iteration reads.
+const maxDataSets = 10
+
+// toDataSetParams validates the data sets of a run. An inline one is read here so a broken file
+// is reported now; a stored one was read when it was uploaded.
+func toDataSetParams(reqs []RunLoadTestDataSetReq) ([]load.RunLoadTestDataSetParam, error) {
+	if len(reqs) > maxDataSets {
+		return nil, fmt.Errorf("DataSets must be at most %d", maxDataSets)
+	}
+
+	var res []load.RunLoadTestDataSetParam
+	for i, d := range reqs {
+		ds := load.RunLoadTestDataSetParam{
+			DataSetId:  d.DataSetId,
+			ShareMode:  constant.DataSetShareMode(strings.ToLower(strings.TrimSpace(string(d.ShareMode)))),
+			Recycle:    d.RecycleOnEof == nil || *d.RecycleOnEof,
+			StopThread: d.StopThreadOnEof,
+		}
+		if ds.ShareMode == "" {
+			ds.ShareMode = constant.ShareAll
+		}
+		if !load.IsSupportedShareMode(ds.ShareMode) {
+			return nil, fmt.Errorf("DataSet shareMode %s is not supported", d.ShareMode)
+		}
+		if ds.Recycle && ds.StopThread {
+			return nil, fmt.Errorf("DataSet %d stopThreadOnEof needs recycleOnEof false", i+1)
+		}
+
+		switch {
+		case d.DataSetId != 0 && d.Content == "":
+		case d.DataSetId == 0 && d.Content != "":
+			ds.FileName = strings.TrimSpace(d.FileName)
+			if ds.FileName == "" {
+				ds.FileName = fmt.Sprintf("data_set_%d.csv", i+1)
+			}
+			if strings.ContainsAny(ds.FileName, "/\\") {
+				return nil, fmt.Errorf("DataSet %d fileName", i+1)
+			}
+			if _, _, err := load.DataSetColumns([]byte(d.Content)); err != nil {
+				return nil, fmt.Errorf("DataSet %d %v", i+1, err)
+			}
+			ds.Content = []byte(d.Content)
+		default:
+			return nil, fmt.Errorf("DataSet %d needs either dataSetId or content", i+1)
+		}
+		res = append(res, ds)
+	}
+	return res, nil
+}
+
+// maxAssertions bounds the assertions of one request; each runs on every response it gets.
+const maxAssertions = 10
+
+// toAssertionParams validates the assertions of one request.
+func toAssertionParams(reqs []RunLoadGeneratorAssertionReq) ([]load.RunLoadTestAssertionParam, error) {
+	if len(reqs) > maxAssertions {
+		return nil, fmt.Errorf("Assertions must be at most %d", maxAssertions)
+	}
+
+	var res []load.RunLoadTestAssertionParam
+	for _, a := range reqs {
+		assertion := load.RunLoadTestAssertionParam{
+			Type:       constant.AssertionType(strings.ToLower(strings.TrimSpace(string(a.Type)))),
+			Expression: strings.TrimSpace(a.Expression),
+			Value:      a.Value,
+		}
+		if assertion.Type != constant.BodyContainsAssertion {
+			// only a body may legitimately start or end with a space
+			assertion.Value = strings.TrimSpace(a.Value)
+		}
+		res = append(res, assertion)
+	}
+	if err := load.ValidateAssertions(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// toSlaParam validates the thresholds of a run. nil stays nil: the run is not judged.
+func toSlaParam(s *RunLoadTestSlaReq) (*load.RunLoadTestSlaParam, error) {
+	if s == nil {
+		return nil, nil
+	}
+	sla := load.RunLoadTestSlaParam{
+		MaxErrorPercent: s.MaxErrorPercent,
+		MaxP95:          s.MaxP95,
+		MaxP99:          s.MaxP99,
+		MinThroughput:   s.MinThroughput,
+	}
+	if sla.MaxErrorPercent == nil && sla.MaxP95 == nil && sla.MaxP99 == nil && sla.MinThroughput == nil {
+		return nil, errors.New("Sla needs at least one threshold")
+	}
+	if err := load.ValidateSla(&sla); err != nil {
+		return nil, err
+	}
+	return &sla, nil
+}
+
+// toAutoStopParam validates the auto-stop rules of a run. nil stays nil: the run is not stopped
+// early.
+func toAutoStopParam(a *RunLoadTestAutoStopReq, withMetrics bool) (*load.RunLoadTestAutoStopParam, error) {
+	if a == nil {
+		return nil, nil
+	}
+	autoStop := load.RunLoadTestAutoStopParam{
+		MaxErrorPercent:    a.MaxErrorPercent,
+		ErrorPercentForSec: a.ErrorPercentForSec,
+		MaxP95:             a.MaxP95,
+		MaxAgentCpuPercent: a.MaxAgentCpuPercent,
+	}
+	if autoStop.MaxErrorPercent == nil && autoStop.MaxP95 == nil && autoStop.MaxAgentCpuPercent == nil {
+		return nil, errors.New("AutoStop needs at least one rule")
+	}
+	if err := load.ValidateAutoStop(&autoStop, withMetrics); err != nil {
+		return nil, err
+	}
+	return &autoStop, nil
+}
+
+// maxHttpTimeoutMs bounds the connect and response timeouts; a request still waiting after ten
+// minutes tells nothing a shorter timeout would not.
+const maxHttpTimeoutMs = 600000
+
+// toHttpClientParam validates the HTTP client options of a run, or of one request when
+// perRequest is set. nil stays nil: the engine's defaults, or the run's.
+func toHttpClientParam(c *RunLoadTestHttpClientReq, perRequest bool) (*load.RunLoadTestHttpClientParam, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.ConnectTimeout < 0 || c.ConnectTimeout > maxHttpTimeoutMs {
+		return nil, fmt.Errorf("HttpClient connectTimeout must be in 0 to %d ms", maxHttpTimeoutMs)
+	}
+	if c.ResponseTimeout < 0 || c.ResponseTimeout > maxHttpTimeoutMs {
+		return nil, fmt.Errorf("HttpClient responseTimeout must be in 0 to %d ms", maxHttpTimeoutMs)
+	}
+	client := load.RunLoadTestHttpClientParam{
+		ConnectTimeout:            c.ConnectTimeout,
+		ResponseTimeout:           c.ResponseTimeout,
+		KeepAlive:                 c.KeepAlive,
+		FollowRedirects:           c.FollowRedirects,
+		Implementation:            constant.HttpImplementation(strings.TrimSpace(string(c.Implementation))),
+		DownloadEmbeddedResources: c.DownloadEmbeddedResources,
+		TlsVersion:                constant.TlsVersion(strings.TrimSpace(string(c.TlsVersion))),
+		InsecureSkipVerify:        c.InsecureSkipVerify,
+	}
+	if err := load.ValidateHttpClient(&client, perRequest); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// maxRegionWeight bounds the weight of a region, which only counts against the others.
+const maxRegionWeight = 100
+
+// toRegionParams checks the regions a run is sent from. Whether the load can be split between
+// them is left to load.ValidateRegions, which sees the whole run.
+func toRegionParams(reqs []RunLoadTestRegionReq) ([]load.RunLoadTestRegionParam, error) {
+	if len(reqs) > load.MaxRegions {
+		return nil, fmt.Errorf("Regions must be at most %d", load.MaxRegions)
+	}
+
+	var res []load.RunLoadTestRegionParam
+	for i, r := range reqs {
+		if r.Weight < 0 || r.Weight > maxRegionWeight {
+			return nil, fmt.Errorf("Region %d weight must be in 0 to %d", i+1, maxRegionWeight)
+		}
+		res = append(res, load.RunLoadTestRegionParam{
+			Provider: strings.ToLower(strings.TrimSpace(r.Provider)),
+			Region:   strings.ToLower(strings.TrimSpace(r.Region)),
+			Weight:   r.Weight,
+		})
+	}
+	return res, nil
+}
+
+// maxTargets bounds the protocol targets of one run.
+const maxTargets = 10
+
+// toTargetParams validates the protocol targets of a run. The .proto of a gRPC target comes
+// inline, like the content of a data set.
+func toTargetParams(reqs []RunLoadTestTargetReq) ([]load.RunLoadTestTargetParam, error) {
+	if len(reqs) > maxTargets {
+		return nil, fmt.Errorf("Targets must be at most %d", maxTargets)
+	}
+
+	var res []load.RunLoadTestTargetParam
+	for i, t := range reqs {
+		if t.Timeout < 0 || t.Timeout > maxHttpTimeoutMs {
+			return nil, fmt.Errorf("Target %d timeout must be in 0 to %d ms", i+1, maxHttpTimeoutMs)
+		}
+		target := load.RunLoadTestTargetParam{
+			Protocol:      constant.TargetProtocol(strings.ToLower(strings.TrimSpace(string(t.Protocol)))),
+			Hostname:      strings.TrimSpace(t.Hostname),
+			Port:          strings.TrimSpace(t.Port),
+			Timeout:       t.Timeout,
+			Path:          strings.TrimSpace(t.Path),
+			Secure:        t.Secure,
+			Message:       t.Message,
+			Method:        strings.TrimSpace(t.Method),
+			ProtoFileName: strings.TrimSpace(t.ProtoFileName),
+			EolByte:       t.EolByte,
+			Driver:        constant.JdbcDriver(strings.ToLower(strings.TrimSpace(string(t.Driver)))),
+			Database:      strings.TrimSpace(t.Database),
+			Username:      strings.TrimSpace(t.Username),
+			Password:      t.Password,
+			Query:         strings.TrimSpace(t.Query),
+		}
+		if strings.ContainsAny(target.ProtoFileName, "/\\") {
+			return nil, fmt.Errorf("Target %d protoFileName", i+1)
+		}
+		if t.ProtoFile != "" {
+			target.ProtoFile = []byte(t.ProtoFile)
+		}
+		res = append(res, target)
+	}
+	if err := load.ValidateTargets(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// toLoadShapeParams validates the fields the load shape reads, against the same limits as the
+// scenario fields. A stages run has no virtual user count of its own, so its peak is recorded
+// as one: listings show it, and it bounds the generator like any other run's.
+func toLoadShapeParams(req RunLoadTestReq, shape constant.LoadShape) (load.RunLoadTestParam, error) {
+	res := load.RunLoadTestParam{LoadShape: shape}
+	maxVU, maxDur, maxRU, maxRS := loadTestLimits()
+
+	switch shape {
+	case constant.ArrivalRateShape:
+		maxRate := arrivalRateLimit()
+		if v, err := strconv.Atoi(strings.TrimSpace(req.ArrivalRate)); err != nil || v < 1 || v > maxRate {
+			return res, fmt.Errorf("ArrivalRate must be in 1 to %d", maxRate)
+		}
+		res.ArrivalRate = strings.TrimSpace(req.ArrivalRate)
+	case constant.SpikeShape:
+		if v, err := strconv.Atoi(strings.TrimSpace(req.SpikeUsers)); err != nil || v < 1 || v > maxVU {
+			return res, fmt.Errorf("SpikeUsers must be in 1 to %d", maxVU)
+		}
+		duration, _ := strconv.Atoi(strings.TrimSpace(req.Duration))
+		if v, err := strconv.Atoi(strings.TrimSpace(req.SpikeDuration)); err != nil || v < 1 || v > duration {
+			return res, fmt.Errorf("SpikeDuration must be in 1 to the duration %d", duration)
+		}
+		res.SpikeUsers = strings.TrimSpace(req.SpikeUsers)
+		res.SpikeDuration = strings.TrimSpace(req.SpikeDuration)
+	case constant.StagesShape:
+		if len(req.Stages) < 1 || len(req.Stages) > maxRS {
+			return res, fmt.Errorf("Stages must have 1 to %d stages", maxRS)
+		}
+		total, peak := 0, 0
+		for i, st := range req.Stages {
+			if st.Target < 0 || st.Target > maxVU {
+				return res, fmt.Errorf("Stage %d target must be in 0 to %d", i+1, maxVU)
+			}
+			if st.Duration < 0 || st.Duration > maxDur {
+				return res, fmt.Errorf("Stage %d duration must be in 0 to %d", i+1, maxDur)
+			}
+			total += st.Duration
+			peak = max(peak, st.Target)
+			res.Stages = append(res.Stages, load.RunLoadTestStageParam{Target: st.Target, Duration: st.Duration})
+		}
+		if total < 1 || total > maxDur+maxRU {
+			return res, fmt.Errorf("Stages must last 1 to %d seconds in total", maxDur+maxRU)
+		}
+		if peak < 1 {
+			return res, errors.New("Stages must reach at least one virtual user")
+		}
+		res.VirtualUsers = strconv.Itoa(peak)
+	}
+	return res, nil
+}
+
+const (
+	// maxRequestWeight bounds a request's weight; weights are relative, so 100 is plenty to
+	// express any mix in whole percents.
+	maxRequestWeight = 100
+	// maxThinkTimeMs bounds a think time's delay and range; a minute between two requests is
+	// already longer than any person waits.
+	maxThinkTimeMs = 60000
+)
+
+// toThinkTimeParam validates a think time. nil stays nil: no pause.
+func toThinkTimeParam(t *RunLoadTestThinkTimeReq) (*load.RunLoadTestThinkTimeParam, error) {
+	if t == nil {
+		return nil, nil
+	}
+	thinkTime := load.RunLoadTestThinkTimeParam{
+		Type:  constant.ThinkTimeType(strings.ToLower(strings.TrimSpace(string(t.Type)))),
+		Delay: t.Delay,
+		Range: t.Range,
+	}
+	switch thinkTime.Type {
+	case constant.ConstantThinkTime, constant.UniformThinkTime, constant.GaussianThinkTime:
+	default:
+		return nil, fmt.Errorf("ThinkTime type %s is not supported", t.Type)
+	}
+	if thinkTime.Delay < 0 || thinkTime.Delay > maxThinkTimeMs {
+		return nil, fmt.Errorf("ThinkTime delay must be in 0 to %d ms", maxThinkTimeMs)
+	}
+	if thinkTime.Range < 0 || thinkTime.Range > maxThinkTimeMs {
+		return nil, fmt.Errorf("ThinkTime range must be in 0 to %d ms", maxThinkTimeMs)
+	}
+	return &thinkTime, nil
+}
+
+// arrivalRateLimit returns the upper bound for the iterations an arrival rate run starts per
+// second, configured next to the other limits.
+func arrivalRateLimit() int {
+	if v := config.AppConfig.Load.Limits.MaxArrivalRate; v > 0 {
+		return v
+	}
+	return 1000
+}
+
+// loadTestLimits returns the upper bounds for virtual users, duration, ramp up time and ramp
+// up steps. FR-MA2-PERF-007-01: they are configurable (config.yaml load.limits /
+// ANT_LOAD_LIMITS_*), falling back to the built-in defaults when unset.
+func loadTestLimits() (maxVU, maxDur, maxRU, maxRS int) {
+	lim := config.AppConfig.Load.Limits
+	maxVU, maxDur, maxRU, maxRS = lim.MaxVirtualUsers, lim.MaxDuration, lim.MaxRampUpTime, lim.MaxRampUpSteps
+	if maxVU <= 0 {
+		maxVU = 100
+	}
+	if maxDur <= 0 {
+		maxDur = 300
+	}
+	if maxRU <= 0 {
+		maxRU = 60
+	}
+	if maxRS <= 0 {
+		maxRS = 20
+	}
+	return
+}
+
+// runLoadTestWithTestPlan handler function that runs a user supplied JMeter test plan.
+// @Id RunLoadTestWithTestPlan
+// @Summary Run Load Test With Uploaded Test Plan
+// @Description Upload a JMeter test plan (.jmx) and the CSV data files it reads, and run it like any other load test. cm-ant adds its result writer to the plan, the PerfMon collectors when additional metrics are collected, and points CSV Data Set Configs at the uploaded files.
+// @Tags [Load Test Execution Management]
+// @Accept multipart/form-data
+// @Produce json
+// @Param testPlan formData file true "JMeter test plan (.jmx)"
+// @Param dataFiles formData file false "CSV data files the test plan reads"
+// @Param installLocation formData string false "local | remote; required when loadGeneratorInstallInfoId is not given"
+// @Param loadGeneratorInstallInfoId formData int false "Already installed load generator"
+// @Param testName formData string false "Test name"
+// @Param duration formData string true "Expected length of the run in seconds"
+// @Param rampUpTime formData string false "Expected ramp up time in seconds"
+// @Param nsId formData string true "Namespace of the target"
+// @Param infraId formData string true "Infra of the target"
+// @Param nodeId formData string true "Node of the target"
+// @Param collectAdditionalSystemMetrics formData bool false "Collect target metrics with PerfMon"
+// @Param agentHostname formData string false "Metric agent host; the target node's address when empty"
+// @Success 200 {object} app.AntResponse[string] "{loadTestKey}"
+// @Failure 400 {object} app.AntResponse[string] "load test running info is not correct."
+// @Failure 500 {object} app.AntResponse[string] "ant server has got error. please try again."
+// @Failure 503 {object} app.AntResponse[string] "cm-ant is shutting down and takes no new load tests"
+// @Router /api/v1/load/tests/run/jmx [post]
+func (s *AntServer) runLoadTestWithTestPlan(c echo.Context) error {
+	var req RunLoadTestWithTestPlanReq
+
+	if err := c.Bind(&req); err != nil {
+		return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.")
+	}
+
+	if req.LoadGeneratorInstallInfoId == uint(0) &&
+		req.InstallLocation != constant.Local && req.InstallLocation != constant.Remote {
+		return errorResponseJson(http.StatusBadRequest, "invalid load test install location")
+	}
+
+	if strings.TrimSpace(req.TestName) == "" {
+		req.TestName = uuid.New().String()
+	}
+
+	_, maxDur, maxRU, _ := loadTestLimits()
+
+	if v, err := strconv.Atoi(strings.TrimSpace(req.Duration)); err != nil || v < 1 || v > maxDur {
+		log.Error().Msg("duration is invalid")
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("duration is not correct. the range must be in 1 to %d", maxDur))
+	}
+
+	if strings.TrimSpace(req.RampUpTime) == "" {
+		req.RampUpTime = "0"
+	}
+	if v, err := strconv.Atoi(strings.TrimSpace(req.RampUpTime)); err != nil || v < 0 || v > maxRU {
+		log.Error().Msg("ramp up time is invalid")
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("ramp up time is not correct. the range must be in 0 to %d", maxRU))
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; multipart form")
+	}
+
+	planFiles := form.File["testPlan"]
+	if len(planFiles) != 1 || !strings.EqualFold(filepath.Ext(planFiles[0].Filename), ".jmx") {
+		return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; exactly one .jmx testPlan is required")
+	}
+
+	size := 0
+	readAll := func(fh *multipart.FileHeader) ([]byte, error) {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		b, err := io.ReadAll(io.LimitReader(f, load.MaxPlanAttachmentBytes+1))
+		size += len(b)
+		return b, err
+	}
+
+	content, err := readAll(planFiles[0])
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, "load test running info is not correct.; testPlan could not be read")
+	}
+
+	plan := load.RunLoadTestPlanParam{
+		FileName: filepath.Base(planFiles[0].Filename),
+		Content:  content,
+	}
+
+	for _, fh := range form.File["dataFiles"] {
+		if !strings.EqualFold(filepath.Ext(fh.Filename), ".csv") {
+			return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; data file %s is not a .csv", fh.Filename))
+		}
+		b, err := readAll(fh)
+		if err != nil {
+			return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; data file %s could not be read", fh.Filename))
+		}
+		plan.DataFiles = append(plan.DataFiles, load.RunLoadTestDataFileParam{
+			FileName: filepath.Base(fh.Filename),
+			Content:  b,
+		})
+	}
+
+	if size > load.MaxPlanAttachmentBytes {
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; uploaded files are larger than %d bytes", load.MaxPlanAttachmentBytes))
+	}
+
+	if err := load.ValidateTestPlan(plan); err != nil {
+		log.Error().Msgf("uploaded test plan is not valid; %v", err)
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("load test running info is not correct.; %v", err))
+	}
+
+	arg := load.RunLoadTestParam{
+		InstallLoadGenerator: load.InstallLoadGeneratorParam{
+			InstallLocation: req.InstallLocation,
+			Coordinates:     []string{seoul},
+		},
+		LoadGeneratorInstallInfoId: req.LoadGeneratorInstallInfoId,
+		TestName:                   strings.TrimSpace(req.TestName),
+		Duration:                   strings.TrimSpace(req.Duration),
+		RampUpTime:                 strings.TrimSpace(req.RampUpTime),
+
+		CollectAdditionalSystemMetrics: req.CollectAdditionalSystemMetrics,
+		AgentHostname:                  strings.TrimSpace(req.AgentHostname),
+
+		NsId:    strings.TrimSpace(req.NsId),
+		InfraId: strings.TrimSpace(req.InfraId),
+		NodeId:  strings.TrimSpace(req.NodeId),
+
+		TestPlan: &plan,
 	}
 
 	loadTestKey, err := s.services.loadService.RunLoadTest(arg)
 
 	if err != nil {
+		if errors.Is(err, load.ErrShuttingDown) {
+			return errorResponseJson(http.StatusServiceUnavailable, err.Error())
+		}
 		return errorResponseJson(http.StatusBadRequest, err.Error())
 	}
 
@@ -293,10 +1070,96 @@ func (s *AntServer) runLoadTest(c echo.Context) error {
 	)
 }
 
+// toHttpFieldParams validates the headers, query, form fields and files of one request. They are
+// rendered straight into the plan, so anything JMeter would choke on is rejected here where the
+// caller can still fix it, not on the generator minutes later.
+func toHttpFieldParams(h RunLoadGeneratorHttpReq) (load.RunLoadTestHttpParam, error) {
+	var res load.RunLoadTestHttpParam
+
+	for _, v := range h.Headers {
+		if !httpHeaderName.MatchString(strings.TrimSpace(v.Name)) {
+			return res, fmt.Errorf("Header name %q", v.Name)
+		}
+		res.Headers = append(res.Headers, load.RunLoadTestNameValueParam{Name: strings.TrimSpace(v.Name), Value: v.Value})
+	}
+
+	for _, v := range h.QueryParams {
+		if strings.TrimSpace(v.Name) == "" {
+			return res, errors.New("Query param name")
+		}
+		res.QueryParams = append(res.QueryParams, load.RunLoadTestNameValueParam{Name: strings.TrimSpace(v.Name), Value: v.Value})
+	}
+
+	res.BodyType = h.BodyType
+	if res.BodyType == "" {
+		res.BodyType = constant.RawBody
+	}
+
+	switch res.BodyType {
+	case constant.RawBody:
+		if len(h.FormParams) > 0 || len(h.Files) > 0 {
+			return res, errors.New("BodyType must be form or multipart to send form params or files")
+		}
+		return res, nil
+	case constant.FormBody, constant.MultipartBody:
+	default:
+		return res, fmt.Errorf("BodyType %s is not supported", h.BodyType)
+	}
+
+	if !load.HttpMethodHasBody(h.Method) {
+		return res, fmt.Errorf("BodyType %s needs a method that sends a body", res.BodyType)
+	}
+	if strings.TrimSpace(h.BodyData) != "" {
+		return res, fmt.Errorf("BodyData cannot be combined with BodyType %s", res.BodyType)
+	}
+
+	for _, v := range h.FormParams {
+		if strings.TrimSpace(v.Name) == "" {
+			return res, errors.New("Form param name")
+		}
+		res.FormParams = append(res.FormParams, load.RunLoadTestNameValueParam{Name: strings.TrimSpace(v.Name), Value: v.Value})
+	}
+
+	if len(h.Files) > 0 && res.BodyType != constant.MultipartBody {
+		return res, errors.New("Files need BodyType multipart")
+	}
+	if res.BodyType == constant.MultipartBody && !strings.EqualFold(strings.TrimSpace(h.Method), http.MethodPost) {
+		return res, errors.New("BodyType multipart needs method POST")
+	}
+
+	size := 0
+	for _, f := range h.Files {
+		name := strings.TrimSpace(f.FileName)
+		if strings.TrimSpace(f.ParamName) == "" || name == "" || strings.ContainsAny(name, "/\\") {
+			return res, errors.New("File paramName and fileName")
+		}
+		if len(f.Content) == 0 {
+			return res, fmt.Errorf("File %s is empty", name)
+		}
+		size += len(f.Content)
+
+		mimeType := strings.TrimSpace(f.MimeType)
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		res.Files = append(res.Files, load.RunLoadTestFileParam{
+			ParamName: strings.TrimSpace(f.ParamName),
+			FileName:  name,
+			MimeType:  mimeType,
+			Content:   f.Content,
+		})
+	}
+	if size > load.MaxPlanAttachmentBytes {
+		return res, fmt.Errorf("Files are larger than %d bytes", load.MaxPlanAttachmentBytes)
+	}
+
+	return res, nil
+}
+
 // stopLoadTest handler function that stops a running load test.
 // @Id StopLoadTest
 // @Summary Stop Load Test
-// @Description Stop a running load test using the provided load test key.
+// @Description Stop a running load test using the provided load test key. A run still queued is taken off the queue and never starts.
 // @Tags [Load Test Execution Management]
 // @Accept json
 // @Produce json
@@ -342,9 +1205,13 @@ func (s *AntServer) stopLoadTest(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param loadTestKey query string true "Load test key"
-// @Param format query string false "Result format (normal or aggregate)"
-// @Success 200 {object} app.JsonResult{[normal]=app.AntResponse[[]load.ResultSummary],[aggregate]=app.AntResponse[[]load.LoadTestStatistics]} "Successfully retrieved load test metrics"
+// @Param format query string false "Result format (normal, aggregate or timeseries)"
+// @Param intervalMs query int false "Bucket size of the timeseries format in ms, at least 100 (default 1000)"
+// @Param from query string false "Start of the timeseries window, RFC 3339 (default the first request)"
+// @Param to query string false "End of the timeseries window, RFC 3339 (default the last request)"
+// @Success 200 {object} app.JsonResult{[normal]=app.AntResponse[[]load.ResultSummary],[aggregate]=app.AntResponse[[]load.LoadTestStatistics],[timeseries]=app.AntResponse[load.LoadTestTimeSeries]} "Successfully retrieved load test metrics"
 // @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Raw result of the load test is not available for the timeseries format"
 // @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test result"
 // @Router /api/v1/load/tests/result [get]
 func (s *AntServer) getLoadTestResult(c echo.Context) error {
@@ -359,24 +1226,123 @@ func (s *AntServer) getLoadTestResult(c echo.Context) error {
 
 	if req.Format == "" {
 		req.Format = constant.Normal
-	} else if req.Format != constant.Normal && req.Format != constant.Aggregate {
+	} else if req.Format != constant.Normal && req.Format != constant.Aggregate && req.Format != constant.TimeSeries {
 		req.Format = constant.Normal
 	}
 
+	window, err := timeSeriesWindowOf(req.IntervalMs, req.From, req.To)
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
 	arg := load.GetLoadTestResultParam{
 		LoadTestKey: req.LoadTestKey,
 		Format:      req.Format,
+		Window:      window,
 	}
 
 	result, err := s.services.loadService.GetLoadTestResult(arg)
 
 	if err != nil {
-		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test result")
+		return loadTestResultErrorOf(err)
 	}
 
 	return successResponseJson(c, "Successfully retrieved load test result", result)
 }
 
+// getLoadTestRegionResult handler function that retrieves the result of a load test region by region.
+// @Id GetLoadTestRegionResult
+// @Summary Get load test result by region
+// @Description Retrieve the aggregated result of a load test sent from several regions, one region at a time.
+// @Tags [Load Test Result]
+// @Accept json
+// @Produce json
+// @Param loadTestKey query string true "Load test key"
+// @Success 200 {object} app.AntResponse[[]load.LoadTestRegionResult] "Successfully retrieved load test result by region"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test result by region"
+// @Router /api/v1/load/tests/result/regions [get]
+func (s *AntServer) getLoadTestRegionResult(c echo.Context) error {
+	var req GetLoadTestResultReq
+	if err := c.Bind(&req); err != nil {
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	if strings.TrimSpace(req.LoadTestKey) == "" {
+		return errorResponseJson(http.StatusBadRequest, "pass correct load test key")
+	}
+
+	arg := load.GetLoadTestResultParam{
+		LoadTestKey: req.LoadTestKey,
+		Format:      constant.Aggregate,
+	}
+
+	result, err := s.services.loadService.GetLoadTestRegionResult(arg)
+
+	if err != nil {
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test result by region")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test result by region", result)
+}
+
+// compareLoadTests handler function that compares load test results against a baseline.
+// @Id CompareLoadTests
+// @Summary Compare load test results
+// @Description Compare two or more load tests, the first being the baseline. Labels are aligned by name with a total row last, and every statistic and target metric is reported with its change from the baseline. A change is flagged as a regression when it is worse than its tolerance and statistically significant.
+// @Tags [Load Test Result]
+// @Accept json
+// @Produce json
+// @Param loadTestKeys query []string true "Load test keys, the baseline first" collectionFormat(multi)
+// @Param responseTimePercent query number false "Tolerated increase of response times in percent"
+// @Param errorPercentPoints query number false "Tolerated increase of the error rate in percentage points"
+// @Param throughputPercent query number false "Tolerated decrease of throughput in percent"
+// @Param metricPercent query number false "Tolerated worsening of target metrics in percent"
+// @Param significance query number false "P-value below which a change is significant"
+// @Success 200 {object} app.AntResponse[load.LoadTestComparisonResult] "Successfully compared load test results"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "load test not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to compare load test results"
+// @Router /api/v1/load/tests/result/compare [get]
+func (s *AntServer) compareLoadTests(c echo.Context) error {
+	var req CompareLoadTestsReq
+	if err := c.Bind(&req); err != nil {
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	var loadTestKeys []string
+	for _, k := range req.LoadTestKeys {
+		for _, key := range strings.Split(k, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				loadTestKeys = append(loadTestKeys, key)
+			}
+		}
+	}
+
+	arg := load.CompareLoadTestsParam{
+		LoadTestKeys:        loadTestKeys,
+		ResponseTimePercent: req.ResponseTimePercent,
+		ErrorPercentPoints:  req.ErrorPercentPoints,
+		ThroughputPercent:   req.ThroughputPercent,
+		MetricPercent:       req.MetricPercent,
+		Significance:        req.Significance,
+	}
+	if err := load.ValidateComparison(arg); err != nil {
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := s.services.loadService.CompareLoadTests(arg)
+	if err != nil {
+		if errors.Is(err, load.ErrLoadTestNotFound) {
+			return errorResponseJson(http.StatusNotFound, err.Error())
+		}
+		log.Error().Msgf("failed to compare load tests %v; %v", loadTestKeys, err)
+		return errorResponseJson(http.StatusInternalServerError, "Failed to compare load test results")
+	}
+
+	return successResponseJson(c, "Successfully compared load test results", result)
+}
+
 // getLoadTestMetrics handler function that retrieves metrics for a specific load test.
 // @Id GetLoadTestMetrics
 // @Summary Get load test metrics
@@ -495,6 +1461,8 @@ func (s *AntServer) getLoadTestExecutionInfo(c echo.Context) error {
 // @Param size query int false "Number of items per page (default 10, max 10)"
 // @Param loadTestKey query string false "Filter by load test key"
 // @Param executionStatus query string false "Filter by execution status"
+// @Param parentLoadTestKey query string false "Filter by the run the runs were made again from"
+// @Param baselineVerdict query string false "Filter by the verdict against the baseline (PASS, REGRESSED or INCONCLUSIVE)"
 // @Success 200 {object} app.AntResponse[load.GetAllLoadTestExecutionStateResult] "Successfully retrieved load test execution state information"
 // @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
 // @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test execution state information"
@@ -510,12 +1478,19 @@ func (s *AntServer) getAllLoadTestExecutionState(c echo.Context) error {
 	if req.Page < 1 {
 		req.Page = 1
 	}
+	switch req.BaselineVerdict {
+	case "", constant.BaselinePass, constant.BaselineRegressed, constant.BaselineInconclusive:
+	default:
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("baseline verdict %s is not one of PASS, REGRESSED or INCONCLUSIVE", req.BaselineVerdict))
+	}
 
 	arg := load.GetAllLoadTestExecutionStateParam{
-		Page:            req.Page,
-		Size:            req.Size,
-		LoadTestKey:     req.LoadTestKey,
-		ExecutionStatus: req.ExecutionStatus,
+		Page:              req.Page,
+		Size:              req.Size,
+		LoadTestKey:       req.LoadTestKey,
+		ExecutionStatus:   req.ExecutionStatus,
+		ParentLoadTestKey: req.ParentLoadTestKey,
+		BaselineVerdict:   req.BaselineVerdict,
 	}
 
 	result, err := s.services.loadService.GetAllLoadTestExecutionState(arg)
@@ -600,6 +1575,67 @@ func (s *AntServer) getLoadTestExecutionState(c echo.Context) error {
 
 }
 
+// progressKeepAlive is how often a quiet progress stream sends a comment, so proxies do not
+// take it for a dead connection.
+const progressKeepAlive = 15 * time.Second
+
+// streamLoadTestProgress handler function that streams the progress of a load test as server-sent events.
+// @Id StreamLoadTestProgress
+// @Summary Stream Load Test Progress
+// @Description Stream the progress of a load test as server-sent events, instead of polling its state. The first event is a state event with the run's state and steps as they are now. After it come step events for every step as it changes, state events for every change of the run's state, and, while the load runs, figures events with throughput, latency and error rate over the last 30 seconds of samples, read from the result file every 10 seconds. The stream ends after the state event of a finished run.
+// @Tags [Load Test State Management]
+// @Produce text/event-stream
+// @Param loadTestKey path string true "Load test key"
+// @Success 200 {object} load.LoadTestProgressEvent "event: state|step|figures, data: the event as JSON"
+// @Failure 400 {object} app.AntResponse[string] "Load test key must be set."
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test execution state information"
+// @Router /api/v1/load/tests/state/{loadTestKey}/stream [get]
+func (s *AntServer) streamLoadTestProgress(c echo.Context) error {
+	loadTestKey := c.Param("loadTestKey")
+
+	if strings.TrimSpace(loadTestKey) == "" {
+		return errorResponseJson(http.StatusBadRequest, "Load test key must be set.")
+	}
+
+	ctx := c.Request().Context()
+	events, err := s.services.loadService.WatchLoadTestProgress(ctx, loadTestKey)
+
+	if err != nil {
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test execution state information")
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	keepAlive := time.NewTicker(progressKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return nil
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
+
 // installMonitoringAgent handler function that handles a monitoring request request to collect metric.
 // @Id				InstallMonitoringAgent
 // @Summary Install Metrics Monitoring Agent
@@ -731,9 +1767,13 @@ func (s *AntServer) uninstallMonitoringAgent(c echo.Context) error {
 // @Param nsId query string true "ns id"
 // @Param infraId query string true "mci id"
 // @Param nodeId query string true "vm id"
-// @Param format query string false "Result format (normal or aggregate)"
-// @Success 200 {object} app.JsonResult{[normal]=app.AntResponse[[]load.ResultSummary],[aggregate]=app.AntResponse[[]load.LoadTestStatistics]} "Successfully retrieved load test metrics"
+// @Param format query string false "Result format (normal, aggregate or timeseries)"
+// @Param intervalMs query int false "Bucket size of the timeseries format in ms, at least 100 (default 1000)"
+// @Param from query string false "Start of the timeseries window, RFC 3339 (default the first request)"
+// @Param to query string false "End of the timeseries window, RFC 3339 (default the last request)"
+// @Success 200 {object} app.JsonResult{[normal]=app.AntResponse[[]load.ResultSummary],[aggregate]=app.AntResponse[[]load.LoadTestStatistics],[timeseries]=app.AntResponse[load.LoadTestTimeSeries]} "Successfully retrieved load test metrics"
 // @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Raw result of the load test is not available for the timeseries format"
 // @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test result"
 // @Router /api/v1/load/tests/result/last [get]
 func (s *AntServer) getLastLoadTestResult(c echo.Context) error {
@@ -748,26 +1788,68 @@ func (s *AntServer) getLastLoadTestResult(c echo.Context) error {
 
 	if req.Format == "" {
 		req.Format = constant.Normal
-	} else if req.Format != constant.Normal && req.Format != constant.Aggregate {
+	} else if req.Format != constant.Normal && req.Format != constant.Aggregate && req.Format != constant.TimeSeries {
 		req.Format = constant.Normal
 	}
 
+	window, err := timeSeriesWindowOf(req.IntervalMs, req.From, req.To)
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
 	arg := load.GetLastLoadTestResultParam{
 		NsId:    req.NsId,
 		InfraId: req.InfraId,
 		NodeId:  req.NodeId,
 		Format:  req.Format,
+		Window:  window,
 	}
 
 	result, err := s.services.loadService.GetLastLoadTestResult(arg)
 
 	if err != nil {
-		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test result")
+		return loadTestResultErrorOf(err)
 	}
 
 	return successResponseJson(c, "Successfully retrieved load test result", result)
 }
 
+// timeSeriesWindowOf reads the window of a timeseries result from the query, its bounds being
+// RFC 3339 times either of which may be left out.
+func timeSeriesWindowOf(intervalMs int, from, to string) (load.TimeSeriesWindow, error) {
+	window := load.TimeSeriesWindow{IntervalMs: intervalMs}
+	for _, bound := range []struct {
+		name  string
+		value string
+		to    *time.Time
+	}{{"from", from, &window.From}, {"to", to, &window.To}} {
+		if strings.TrimSpace(bound.value) == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(bound.value))
+		if err != nil {
+			return load.TimeSeriesWindow{}, fmt.Errorf("%s must be an RFC 3339 time such as 2006-01-02T15:04:05Z", bound.name)
+		}
+		*bound.to = t
+	}
+	if err := load.ValidateTimeSeriesWindow(window); err != nil {
+		return load.TimeSeriesWindow{}, err
+	}
+	return window, nil
+}
+
+// loadTestResultErrorOf answers for a result that could not be had.
+func loadTestResultErrorOf(err error) error {
+	switch {
+	case errors.Is(err, load.ErrInvalidTimeSeries):
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	case errors.Is(err, load.ErrRawResultUnavailable):
+		return errorResponseJson(http.StatusNotFound, err.Error())
+	default:
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test result")
+	}
+}
+
 // getLastLoadTestMetrics handler function that retrieves metrics for a specific load test.
 // @Id GetLastLoadTestMetrics
 // @Summary Get last load test metrics by ns, mci, vm
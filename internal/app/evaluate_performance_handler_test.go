@@ -1,6 +1,8 @@
 package app
 
 import (
+	"bytes"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -84,6 +86,146 @@ func TestRunLoadTest_ParamRangeValidation(t *testing.T) {
 			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"99999","path":"/"}]}`,
 			wantMsgIn: "Port",
 		},
+		{
+			name:      "Method that cannot be rendered (TRACE) -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"TRACE","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "Method TRACE is not supported",
+		},
+		{
+			name:      "Protocol other than http or https -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"ftp","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "Protocol ftp is not supported",
+		},
+		{
+			name:      "Form body on a method without a body -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/","bodyType":"form","formParams":[{"name":"a","value":"b"}]}]}`,
+			wantMsgIn: "needs a method that sends a body",
+		},
+		{
+			name:      "Header name with a space -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/","headers":[{"name":"X Tenant","value":"a"}]}]}`,
+			wantMsgIn: "Header name",
+		},
+		{
+			name:      "Files without a multipart body -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"POST","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/","files":[{"paramName":"f","fileName":"a.txt","content":"aGk="}]}]}`,
+			wantMsgIn: "multipart",
+		},
+		{
+			name:      "Extractor with an unusable variable name -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"POST","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/login","extractors":[{"type":"json_path","varName":"${token}","expression":"$.token"}]}]}`,
+			wantMsgIn: "Extractor varName",
+		},
+		{
+			name:      "Extractor of unknown type -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"POST","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/login","extractors":[{"type":"xpath","varName":"token","expression":"//token"}]}]}`,
+			wantMsgIn: "Extractor type",
+		},
+		{
+			name:      "Engine that is not supported -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","engine":"gatling","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "Engine gatling is not supported",
+		},
+		{
+			name:      "LoadShape that is not supported -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","loadShape":"soak","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "LoadShape soak is not supported",
+		},
+		{
+			name:      "Arrival rate without a rate -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","loadShape":"arrival_rate","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "ArrivalRate",
+		},
+		{
+			name:      "Spike longer than the duration -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","loadShape":"spike","spikeUsers":"50","spikeDuration":"11","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "SpikeDuration",
+		},
+		{
+			name:      "Stage target above the user limit -> 400",
+			body:      `{` + base + `,"loadShape":"stages","stages":[{"target":10,"duration":10},{"target":101,"duration":10}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "Stage 2 target",
+		},
+		{
+			name:      "Stages that never add a user -> 400",
+			body:      `{` + base + `,"loadShape":"stages","stages":[{"target":0,"duration":10}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "at least one virtual user",
+		},
+		{
+			name:      "Request weight above 100 -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/","weight":101}]}`,
+			wantMsgIn: "Weight must be in 0 to 100",
+		},
+		{
+			name:      "Think time of unknown type -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","thinkTime":{"type":"poisson","delay":500},"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "ThinkTime type poisson is not supported",
+		},
+		{
+			name:      "Inline data set with an unusable column -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","dataSets":[{"fileName":"users.csv","content":"user name,password\na,b\n"}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/${user}"}]}`,
+			wantMsgIn: "DataSet 1 data set column",
+		},
+		{
+			name:      "Data set that stops users but also recycles -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","dataSets":[{"dataSetId":1,"stopThreadOnEof":true}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "stopThreadOnEof needs recycleOnEof false",
+		},
+		{
+			name:      "Data set with both an id and content -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","dataSets":[{"dataSetId":1,"content":"user\na\n"}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "needs either dataSetId or content",
+		},
+		{
+			name:      "Assertion with a status code out of range -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/","assertions":[{"type":"status_code","value":"200,700"}]}]}`,
+			wantMsgIn: "\"700\" is not an HTTP status code",
+		},
+		{
+			name:      "Sla with an error percent above 100 -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","sla":{"maxErrorPercent":120},"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "maxErrorPercent must be at most 100",
+		},
+		{
+			name:      "Sla without a threshold -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","sla":{},"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "Sla needs at least one threshold",
+		},
+		{
+			name:      "Response timeout above ten minutes -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpClient":{"responseTimeout":600001},"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "responseTimeout must be in 0 to 600000 ms",
+		},
+		{
+			name:      "TLS version on one request -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"https","hostname":"127.0.0.1","port":"443","path":"/","httpClient":{"tlsVersion":"TLSv1.3"}}]}`,
+			wantMsgIn: "apply to the whole run",
+		},
+		{
+			name:      "gRPC target without its proto file -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","targets":[{"protocol":"grpc","hostname":"127.0.0.1","port":"50051","method":"shop.Cart/Add","message":"{}"}]}`,
+			wantMsgIn: "needs the .proto file",
+		},
+		{
+			name:      "TCP targets ending answers differently -> 400",
+			body:      `{` + base + `,"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","targets":[{"protocol":"tcp","hostname":"127.0.0.1","port":"7","message":"a","eolByte":10},{"protocol":"tcp","hostname":"127.0.0.1","port":"7","message":"b","eolByte":13}]}`,
+			wantMsgIn: "same eolByte",
+		},
+		{
+			name:      "Cluster of generators installed locally -> 400",
+			body:      `{"installLoadGenerator":{"installLocation":"local","clusterSize":3},"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "needs a remote install location",
+		},
+		{
+			name:      "Regions with a local generator -> 400",
+			body:      `{"installLoadGenerator":{"installLocation":"local"},"virtualUsers":"10","duration":"10","rampUpTime":"5","rampUpSteps":"2","regions":[{"provider":"aws","region":"ap-northeast-2"},{"provider":"gcp","region":"europe-west3"}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "needs a remote load generator",
+		},
+		{
+			name:      "Region left without virtual users -> 400",
+			body:      `{"installLoadGenerator":{"installLocation":"remote"},"virtualUsers":"2","duration":"10","rampUpTime":"5","rampUpSteps":"2","regions":[{"provider":"aws","region":"ap-northeast-2","weight":5},{"provider":"aws","region":"eu-central-1"}],"httpReqs":[{"method":"GET","protocol":"http","hostname":"127.0.0.1","port":"80","path":"/"}]}`,
+			wantMsgIn: "aws/eu-central-1 gets no load",
+		},
 
 		// --- below range (v < 1): also broken by the && bug ("0" previously passed) ---
 		{
@@ -123,6 +265,11 @@ func TestRunLoadTest_ParamRangeValidation(t *testing.T) {
 			body:      `{` + base + `,"virtualUsers":"1","duration":"1","rampUpTime":"1","rampUpSteps":"1","httpReqs":[]}`,
 			wantMsgIn: "http request",
 		},
+		{
+			name:      "stages without the concurrency fields pass range validation",
+			body:      `{` + base + `,"loadShape":"stages","stages":[{"target":20,"duration":10},{"target":20,"duration":0},{"target":0,"duration":5}],"httpReqs":[]}`,
+			wantMsgIn: "http request",
+		},
 		{
 			name:      "in-range max boundary (100/300/60/20) passes range validation",
 			body:      `{` + base + `,"virtualUsers":"100","duration":"300","rampUpTime":"60","rampUpSteps":"20","httpReqs":[]}`,
@@ -145,3 +292,61 @@ func TestRunLoadTest_ParamRangeValidation(t *testing.T) {
 		})
 	}
 }
+
+// An uploaded plan is checked before a run is created for it, so a plan that cannot work is
+// answered with 400 instead of failing on the generator.
+func TestRunLoadTestWithTestPlan_Validation(t *testing.T) {
+	upload := func(fields map[string]string, files map[string]string) *echo.HTTPError {
+		t.Helper()
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for k, v := range fields {
+			_ = w.WriteField(k, v)
+		}
+		for name, content := range files {
+			field := "dataFiles"
+			if strings.HasSuffix(name, ".jmx") {
+				field = "testPlan"
+			}
+			fw, _ := w.CreateFormFile(field, name)
+			_, _ = fw.Write([]byte(content))
+		}
+		_ = w.Close()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/load/tests/run/jmx", &body)
+		req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := (&AntServer{}).runLoadTestWithTestPlan(c)
+		he, ok := err.(*echo.HTTPError)
+		if !ok {
+			t.Fatalf("expected *echo.HTTPError, got %T: %v", err, err)
+		}
+		return he
+	}
+
+	base := map[string]string{"installLocation": "local", "duration": "10"}
+
+	cases := []struct {
+		name      string
+		files     map[string]string
+		wantMsgIn string
+	}{
+		{"no test plan", map[string]string{"users.csv": "id\n"}, ".jmx testPlan is required"},
+		{"data file that is not csv", map[string]string{"plan.jmx": "<jmeterTestPlan/>", "users.txt": "id\n"}, "is not a .csv"},
+		{"plan without thread group", map[string]string{"plan.jmx": "<jmeterTestPlan><hashTree><TestPlan/><hashTree/></hashTree></jmeterTestPlan>"}, "no enabled thread group"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			he := upload(base, tc.files)
+			if he.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", he.Code)
+			}
+			if msg := httpErrMessage(he); !strings.Contains(msg, tc.wantMsgIn) {
+				t.Fatalf("expected message to contain %q, got %q", tc.wantMsgIn, msg)
+			}
+		})
+	}
+}
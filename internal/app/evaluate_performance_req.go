@@ -1,6 +1,10 @@
 package app
 
-import "github.com/cloud-barista/cm-ant/internal/core/common/constant"
+import (
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
 
 type MonitoringAgentInstallationReq struct {
 	NsId  string   `json:"nsId"`
@@ -18,6 +22,9 @@ type GetAllMonitoringAgentInfosReq struct {
 
 type InstallLoadGeneratorReq struct {
 	InstallLocation constant.InstallLocation `json:"installLocation"`
+
+	// generator servers the load is split across; remote only, 1 (default) to 10
+	ClusterSize int `json:"clusterSize,omitempty"`
 }
 
 type GetAllLoadGeneratorInstallInfoReq struct {
@@ -40,6 +47,31 @@ type RunLoadTestReq struct {
 	RampUpTime   string `json:"rampUpTime"`
 	RampUpSteps  string `json:"rampUpSteps"`
 
+	// how the load changes over time; concurrency (default) | arrival_rate | spike | step_down | stages
+	LoadShape     constant.LoadShape    `json:"loadShape,omitempty"`
+	ArrivalRate   string                `json:"arrivalRate,omitempty"`   // arrival_rate: iterations started per second
+	SpikeUsers    string                `json:"spikeUsers,omitempty"`    // spike: users during the spike
+	SpikeDuration string                `json:"spikeDuration,omitempty"` // spike: seconds the spike lasts
+	Stages        []RunLoadTestStageReq `json:"stages,omitempty"`        // stages: replaces the fields above
+
+	// pause before every request that does not set its own
+	ThinkTime *RunLoadTestThinkTimeReq `json:"thinkTime,omitempty"`
+
+	// CSV files read a line per iteration; every column is ${column} in paths, bodies and headers
+	DataSets []RunLoadTestDataSetReq `json:"dataSets,omitempty"`
+
+	// thresholds the finished run is judged by; its state then carries a PASS or FAIL verdict
+	Sla *RunLoadTestSlaReq `json:"sla,omitempty"`
+
+	// rules that stop the run while it goes, when the target is plainly falling over; the results so far are still collected
+	AutoStop *RunLoadTestAutoStopReq `json:"autoStop,omitempty"`
+
+	// the scenario catalog entry the run is of, whose load profile it must carry; a run that finishes is compared with the entry's baseline, or else its node's
+	ScenarioCatalogId uint `json:"scenarioCatalogId,omitempty"`
+
+	// how requests are sent: timeouts, keep-alive, redirects, TLS; every field left out is the engine's default
+	HttpClient *RunLoadTestHttpClientReq `json:"httpClient,omitempty"`
+
 	// for validate agent host and connect to tumblebug resources
 	NsId  string `json:"nsId"`  // for metadata usage
 	InfraId string `json:"infraId"` // for metadata usage
@@ -50,22 +82,204 @@ type RunLoadTestReq struct {
 	AgentHostname                  string `json:"agentHostname"` // basically, it is same as host for vm
 
 	HttpReqs []RunLoadGeneratorHttpReq `json:"httpReqs,omitempty"`
+
+	// non-HTTP samplers sent in the same thread group as httpReqs; jmeter only
+	Targets []RunLoadTestTargetReq `json:"targets,omitempty"`
+
+	// regions the load is sent from at once, split by weight; remote only, at most 5
+	Regions []RunLoadTestRegionReq `json:"regions,omitempty"`
+
+	// load generating tool; jmeter (default) | k6
+	Engine constant.LoadGeneratorType `json:"engine,omitempty"`
+}
+
+// RerunLoadTestReq holds what a run is made again with instead of what it was stored with. Every
+// field left out is as the run was.
+type RerunLoadTestReq struct {
+	TestName     string `json:"testName,omitempty"`
+	VirtualUsers string `json:"virtualUsers,omitempty"`
+	Duration     string `json:"duration,omitempty"`
+
+	// another target node; nsId, infraId and nodeId go together
+	NsId    string `json:"nsId,omitempty"`
+	InfraId string `json:"infraId,omitempty"`
+	NodeId  string `json:"nodeId,omitempty"`
+	// host every request and target is sent to instead
+	Hostname string `json:"hostname,omitempty"`
+
+	// where a load generator is installed when the run's one is gone; local | remote
+	InstallLocation constant.InstallLocation `json:"installLocation,omitempty"`
+}
+
+// RunLoadTestDataSetReq is a CSV data set, either a stored one (DataSetId) or one given inline
+// (FileName and Content, the CSV text). Its first line names the columns.
+type RunLoadTestDataSetReq struct {
+	DataSetId uint   `json:"dataSetId,omitempty"`
+	FileName  string `json:"fileName,omitempty"`
+	Content   string `json:"content,omitempty"`
+
+	// who reads through the file together; all (default) | group | thread
+	ShareMode constant.DataSetShareMode `json:"shareMode,omitempty"`
+	// start over at the top after the last line; true when not given
+	RecycleOnEof *bool `json:"recycleOnEof,omitempty"`
+	// stop a virtual user that reads past the last line instead of sending <EOF>; needs recycleOnEof false
+	StopThreadOnEof bool `json:"stopThreadOnEof,omitempty"`
+}
+
+// RunLoadTestSlaReq holds the thresholds of a run, checked against all of its requests
+// together. A threshold that is not given is not checked.
+type RunLoadTestSlaReq struct {
+	MaxErrorPercent *float64 `json:"maxErrorPercent,omitempty"` // 0 ~ 100
+	MaxP95          *float64 `json:"maxP95,omitempty"`          // ms
+	MaxP99          *float64 `json:"maxP99,omitempty"`          // ms
+	MinThroughput   *float64 `json:"minThroughput,omitempty"`   // requests per second
+}
+
+// RunLoadTestAutoStopReq holds the rules that stop a run early, checked every 10 seconds on the
+// last 30 seconds of the run. A rule that is not given is not checked.
+type RunLoadTestAutoStopReq struct {
+	MaxErrorPercent    *float64 `json:"maxErrorPercent,omitempty"`    // 0 ~ 100
+	ErrorPercentForSec int      `json:"errorPercentForSec,omitempty"` // seconds the error rate stays above maxErrorPercent; 0 stops at once
+	MaxP95             *float64 `json:"maxP95,omitempty"`             // ms
+	MaxAgentCpuPercent *float64 `json:"maxAgentCpuPercent,omitempty"` // 0 ~ 100; needs collectAdditionalSystemMetrics
+}
+
+// RunLoadTestHttpClientReq holds the HTTP client options of a run or of one request. A request's
+// own options override the run's field by field, but cannot set TLS.
+type RunLoadTestHttpClientReq struct {
+	ConnectTimeout            int                         `json:"connectTimeout,omitempty"`            // ms; jmeter only, default 5000
+	ResponseTimeout           int                         `json:"responseTimeout,omitempty"`           // ms; default 60000
+	KeepAlive                 *bool                       `json:"keepAlive,omitempty"`                 // default true
+	FollowRedirects           *bool                       `json:"followRedirects,omitempty"`           // default true
+	Implementation            constant.HttpImplementation `json:"implementation,omitempty"`            // jmeter only; HttpClient4 (default) or Java
+	DownloadEmbeddedResources *bool                       `json:"downloadEmbeddedResources,omitempty"` // jmeter only; images, scripts and styles of html responses
+	TlsVersion                constant.TlsVersion         `json:"tlsVersion,omitempty"`                // TLSv1.2 or TLSv1.3; run only
+	InsecureSkipVerify        *bool                       `json:"insecureSkipVerify,omitempty"`        // run only; jmeter never verifies
+}
+
+// RunLoadTestRegionReq is a region a run sends load from, on a generator server of its own.
+type RunLoadTestRegionReq struct {
+	Provider string `json:"provider"`         // e.g. aws
+	Region   string `json:"region"`           // e.g. ap-northeast-2
+	Weight   int    `json:"weight,omitempty"` // share of the load against the other regions; 1 when not given, up to 100
+}
+
+// RunLoadTestTargetReq is a target spoken to in a protocol other than HTTP. Which fields it
+// reads depends on the protocol.
+type RunLoadTestTargetReq struct {
+	Protocol constant.TargetProtocol `json:"protocol"` // websocket | grpc | tcp | jdbc
+	Hostname string                  `json:"hostname"`
+	Port     string                  `json:"port"`
+	Timeout  int                     `json:"timeout,omitempty"` // ms; default 10000
+
+	Path    string `json:"path,omitempty"`    // websocket
+	Secure  bool   `json:"secure,omitempty"`  // websocket, grpc: use TLS
+	Message string `json:"message,omitempty"` // websocket: text frame; grpc: request as JSON; tcp: bytes sent
+
+	Method        string `json:"method,omitempty"`        // grpc: package.Service/Method
+	ProtoFileName string `json:"protoFileName,omitempty"` // grpc: name of the .proto file
+	ProtoFile     string `json:"protoFile,omitempty"`     // grpc: content of the .proto file
+
+	EolByte *int `json:"eolByte,omitempty"` // tcp: byte that ends an answer; same for every tcp target
+
+	Driver   constant.JdbcDriver `json:"driver,omitempty"` // jdbc: postgresql | mysql
+	Database string              `json:"database,omitempty"`
+	Username string              `json:"username,omitempty"`
+	Password string              `json:"password,omitempty"`
+	Query    string              `json:"query,omitempty"`
+}
+
+// RunLoadTestStageReq moves the users linearly from the previous stage's target (0 before the
+// first) to Target over Duration seconds; a Duration of 0 sets it at once.
+type RunLoadTestStageReq struct {
+	Target   int `json:"target"`
+	Duration int `json:"duration"`
+}
+
+// RunLoadTestWithTestPlanReq holds the form fields sent along with an uploaded test plan. The
+// plan and its data files themselves are read from the multipart form.
+type RunLoadTestWithTestPlanReq struct {
+	InstallLocation            constant.InstallLocation `form:"installLocation"`
+	LoadGeneratorInstallInfoId uint                     `form:"loadGeneratorInstallInfoId"`
+
+	TestName   string `form:"testName"`
+	Duration   string `form:"duration"`   // expected length of the run; the plan decides the real one
+	RampUpTime string `form:"rampUpTime"` // expected ramp up, 0 when empty
+
+	NsId    string `form:"nsId"`
+	InfraId string `form:"infraId"`
+	NodeId  string `form:"nodeId"`
+
+	CollectAdditionalSystemMetrics bool   `form:"collectAdditionalSystemMetrics"`
+	AgentHostname                  string `form:"agentHostname"`
 }
 
 type RunLoadGeneratorHttpReq struct {
-	Method   string `json:"method" validate:"required"`             // GET or POST
+	Method   string `json:"method" validate:"required"`             // GET, HEAD, OPTIONS, POST, PUT, PATCH or DELETE
 	Protocol string `json:"protocol" validate:"required"`           // http or https
 	Hostname string `json:"hostname,omitempty" validate:"required"` // xx.xx.xx.xx or asx.bbb.com
 	Port     string `json:"port,omitempty" validate:"required"`     // 1 ~ 65353
 	Path     string `json:"path,omitempty" validate:"required"`     // /xxx/www/sss or possibly empty
 	BodyData string `json:"bodyData,omitempty" validate:"required"` // {"xxx": "tttt", "wwwww": "wotjkenr"}
+
+	Headers     []RunLoadGeneratorNameValueReq `json:"headers,omitempty"`     // sent with this request only
+	QueryParams []RunLoadGeneratorNameValueReq `json:"queryParams,omitempty"` // added to any query already in path
+	BodyType    constant.HttpBodyType          `json:"bodyType,omitempty"`    // raw (default), form or multipart
+	FormParams  []RunLoadGeneratorNameValueReq `json:"formParams,omitempty"`  // body fields for form and multipart
+	Files       []RunLoadGeneratorFileReq      `json:"files,omitempty"`       // multipart only
+
+	// values taken from this response and usable as ${varName} by the requests after it
+	Extractors []RunLoadGeneratorExtractorReq `json:"extractors,omitempty"`
+
+	Weight    int                      `json:"weight,omitempty"`    // 1 ~ 100, relative to the other weighted requests; 0 sends it every iteration
+	ThinkTime *RunLoadTestThinkTimeReq `json:"thinkTime,omitempty"` // overrides the run's think time
+
+	// checks a response must pass to count as a success
+	Assertions []RunLoadGeneratorAssertionReq `json:"assertions,omitempty"`
+
+	// overrides the run's http client options for this request
+	HttpClient *RunLoadTestHttpClientReq `json:"httpClient,omitempty"`
+}
+
+type RunLoadTestThinkTimeReq struct {
+	Type  constant.ThinkTimeType `json:"type"`            // constant, uniform or gaussian
+	Delay int                    `json:"delay"`           // ms; the mean for gaussian
+	Range int                    `json:"range,omitempty"` // ms; uniform adds up to it, gaussian deviates by it
+}
+
+type RunLoadGeneratorNameValueReq struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type RunLoadGeneratorFileReq struct {
+	ParamName string `json:"paramName"`          // form field the file is sent as
+	FileName  string `json:"fileName"`           // name the target sees
+	MimeType  string `json:"mimeType,omitempty"` // application/octet-stream when empty
+	Content   []byte `json:"content"`            // base64 in JSON
+}
+
+type RunLoadGeneratorExtractorReq struct {
+	Type         constant.ExtractorType `json:"type"`                   // json_path or regex
+	VarName      string                 `json:"varName"`                // referenced later as ${varName}
+	Expression   string                 `json:"expression"`             // $.data.token or "token":"(.+?)"
+	MatchNo      *int                   `json:"matchNo,omitempty"`      // 1 first (default), 0 random, -1 all
+	DefaultValue string                 `json:"defaultValue,omitempty"` // used when nothing matches
+}
+
+type RunLoadGeneratorAssertionReq struct {
+	Type       constant.AssertionType `json:"type"`                 // status_code, body_contains, json_path or max_duration
+	Expression string                 `json:"expression,omitempty"` // json_path only: $.status
+	Value      string                 `json:"value,omitempty"`      // 200,201 | text | expected value (any when empty) | ms
 }
 
 type GetAllLoadTestExecutionStateReq struct {
-	Page            int                      `query:"page"`
-	Size            int                      `query:"size"`
-	LoadTestKey     string                   `query:"loadTestKey"`
-	ExecutionStatus constant.ExecutionStatus `query:"executionStatus"`
+	Page              int                      `query:"page"`
+	Size              int                      `query:"size"`
+	LoadTestKey       string                   `query:"loadTestKey"`
+	ExecutionStatus   constant.ExecutionStatus `query:"executionStatus"`
+	ParentLoadTestKey string                   `query:"parentLoadTestKey"`
+	BaselineVerdict   constant.BaselineVerdict `query:"baselineVerdict"`
 }
 
 type GetLastLoadTestExecutionStateReq struct {
@@ -86,9 +300,25 @@ type StopLoadTestReq struct {
 	NodeId        string `json:"nodeId"`
 }
 
+// GetLoadTestResultReq asks for the result of a run. The interval, in ms, and the RFC 3339
+// bounds of the window are for the timeseries format alone.
 type GetLoadTestResultReq struct {
 	LoadTestKey string                `query:"loadTestKey"`
 	Format      constant.ResultFormat `query:"format"`
+	IntervalMs  int                   `query:"intervalMs"`
+	From        string                `query:"from"`
+	To          string                `query:"to"`
+}
+
+// CompareLoadTestsReq names the runs to compare, the first being the baseline; the keys may be
+// repeated or given comma separated. A tolerance left out is the configured one.
+type CompareLoadTestsReq struct {
+	LoadTestKeys        []string `query:"loadTestKeys"`
+	ResponseTimePercent *float64 `query:"responseTimePercent"` // % slower; average, median and percentiles
+	ErrorPercentPoints  *float64 `query:"errorPercentPoints"`  // error rate points higher
+	ThroughputPercent   *float64 `query:"throughputPercent"`   // % fewer requests per second
+	MetricPercent       *float64 `query:"metricPercent"`       // % worse target metrics
+	Significance        *float64 `query:"significance"`        // p-value a change must be below; 0 ~ 1
 }
 
 type GetLastLoadTestResultReq struct {
@@ -96,6 +326,9 @@ type GetLastLoadTestResultReq struct {
 	InfraId  string                `query:"infraId"`
 	NodeId   string                `query:"nodeId"`
 	Format constant.ResultFormat `query:"format"`
+	IntervalMs int                   `query:"intervalMs"`
+	From       string                `query:"from"`
+	To         string                `query:"to"`
 }
 
 type GetAllLoadTestScenarioCatalogsReq struct {
@@ -103,3 +336,62 @@ type GetAllLoadTestScenarioCatalogsReq struct {
 	Size int    `query:"size"`
 	Name string `query:"name"`
 }
+
+// PinLoadTestBaselineReq pins a run as the baseline of a scenario catalog entry, or of a target
+// node; with neither given, of the node the run was sent to.
+type PinLoadTestBaselineReq struct {
+	LoadTestKey       string `json:"loadTestKey"`
+	ScenarioCatalogId uint   `json:"scenarioCatalogId,omitempty"`
+	NsId              string `json:"nsId,omitempty"`
+	InfraId           string `json:"infraId,omitempty"`
+	NodeId            string `json:"nodeId,omitempty"`
+}
+
+type GetAllLoadTestBaselinesReq struct {
+	Page              int    `query:"page"`
+	Size              int    `query:"size"`
+	ScenarioCatalogId uint   `query:"scenarioCatalogId"`
+	NsId              string `query:"nsId"`
+	InfraId           string `query:"infraId"`
+	NodeId            string `query:"nodeId"`
+}
+
+type GetAllLoadTestDataSetsReq struct {
+	Page int    `query:"page"`
+	Size int    `query:"size"`
+	Name string `query:"name"`
+}
+
+// LoadTestScheduleReq creates a schedule, or replaces one as a whole. It fires either on a cron
+// expression or once at runAt.
+type LoadTestScheduleReq struct {
+	Name        string `json:"name" example:"nightly regression"`
+	Description string `json:"description" example:"regression run after the migration"`
+
+	Cron     string     `json:"cron,omitempty" example:"0 2 * * *"`      // minute hour day-of-month month day-of-week, or @daily, @hourly, ...
+	RunAt    *time.Time `json:"runAt,omitempty"`                         // to run once instead of on a cron
+	TimeZone string     `json:"timeZone,omitempty" example:"Asia/Seoul"` // the cron is read in it; UTC by default
+	Enabled  *bool      `json:"enabled,omitempty"`                       // true by default
+
+	// the scenario catalog whose load profile every run uses, as it is when the run starts
+	ScenarioCatalogId uint `json:"scenarioCatalogId" example:"1"`
+
+	NsId    string `json:"nsId"`
+	InfraId string `json:"infraId"`
+	NodeId  string `json:"nodeId"`
+
+	// the rest of the run: generator, requests, data sets, SLA; its load profile and target are
+	// taken from the fields above
+	Run RunLoadTestReq `json:"run"`
+}
+
+type GetAllLoadTestSchedulesReq struct {
+	Page int    `query:"page"`
+	Size int    `query:"size"`
+	Name string `query:"name"`
+}
+
+type GetLoadTestScheduleRunsReq struct {
+	Page int `query:"page"`
+	Size int `query:"size"`
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/cm-ant/internal/core/load"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// pinLoadTestBaseline handler function that pins a run as a baseline.
+// @Id PinLoadTestBaseline
+// @Summary Pin Load Test Baseline
+// @Description Pin a successful run as the baseline of a scenario catalog entry or of a target node; with neither given, of the node the run was sent to. It replaces the baseline the entry or node had. Every run of the entry, or else of the node, that finishes successfully afterwards is compared with it, and its state carries the verdict.
+// @Tags [Load Test Baseline Management]
+// @Accept json
+// @Produce json
+// @Param body body app.PinLoadTestBaselineReq true "Pin Load Test Baseline Request"
+// @Success 200 {object} app.AntResponse[load.LoadTestBaselineResult] "Successfully pinned load test baseline"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "load test not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to pin load test baseline"
+// @Router /api/v1/load/baselines [post]
+func (s *AntServer) pinLoadTestBaseline(c echo.Context) error {
+	var req PinLoadTestBaselineReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	param := load.PinLoadTestBaselineParam{
+		LoadTestKey:       strings.TrimSpace(req.LoadTestKey),
+		ScenarioCatalogId: req.ScenarioCatalogId,
+		NsId:              strings.TrimSpace(req.NsId),
+		InfraId:           strings.TrimSpace(req.InfraId),
+		NodeId:            strings.TrimSpace(req.NodeId),
+	}
+	if param.LoadTestKey == "" {
+		return errorResponseJson(http.StatusBadRequest, "Required fields are missing")
+	}
+	if err := load.ValidateBaselineScope(param); err != nil {
+		return errorResponseJson(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := s.services.loadService.PinLoadTestBaseline(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to pin load test baseline")
+		switch {
+		case errors.Is(err, load.ErrLoadTestNotFound):
+			return errorResponseJson(http.StatusNotFound, err.Error())
+		case errors.Is(err, load.ErrInvalidLoadTestBaseline):
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to pin load test baseline")
+	}
+
+	return successResponseJson(c, "Successfully pinned load test baseline", result)
+}
+
+// getAllLoadTestBaselines handler function that lists the pinned baselines.
+// @Id GetAllLoadTestBaselines
+// @Summary Get All Load Test Baselines
+// @Description Retrieve the pinned baselines with pagination support.
+// @Tags [Load Test Baseline Management]
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param size query int false "Number of items per page (default 10, max 100)"
+// @Param scenarioCatalogId query int false "Filter by scenario catalog entry"
+// @Param nsId query string false "Filter by namespace"
+// @Param infraId query string false "Filter by infra"
+// @Param nodeId query string false "Filter by node"
+// @Success 200 {object} app.AntResponse[load.GetAllLoadTestBaselinesResult] "Successfully retrieved load test baselines"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test baselines"
+// @Router /api/v1/load/baselines [get]
+func (s *AntServer) getAllLoadTestBaselines(c echo.Context) error {
+	var req GetAllLoadTestBaselinesReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 10
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	param := load.GetAllLoadTestBaselinesParam{
+		Page:              req.Page,
+		Size:              req.Size,
+		ScenarioCatalogId: req.ScenarioCatalogId,
+		NsId:              req.NsId,
+		InfraId:           req.InfraId,
+		NodeId:            req.NodeId,
+	}
+
+	result, err := s.services.loadService.GetAllLoadTestBaselines(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get all load test baselines")
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test baselines")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test baselines", result)
+}
+
+// deleteLoadTestBaseline handler function that unpins a baseline.
+// @Id DeleteLoadTestBaseline
+// @Summary Delete Load Test Baseline
+// @Description Unpin a baseline by ID. Runs already compared with it keep their verdict.
+// @Tags [Load Test Baseline Management]
+// @Accept json
+// @Produce json
+// @Param baselineId path int true "Load Test Baseline ID"
+// @Success 200 {object} app.AntResponse[string] "Successfully deleted load test baseline"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test baseline not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to delete load test baseline"
+// @Router /api/v1/load/baselines/{baselineId} [delete]
+func (s *AntServer) deleteLoadTestBaseline(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("baselineId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	err = s.services.loadService.DeleteLoadTestBaseline(c.Request().Context(), uint(id))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test baseline")
+		if errors.Is(err, load.ErrLoadTestBaselineNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test baseline not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to delete load test baseline")
+	}
+
+	return successResponseJson(c, "Successfully deleted load test baseline", "Successfully deleted load test baseline")
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/cm-ant/internal/core/load"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// createLoadTestDataSet handler function that stores an uploaded CSV data set.
+// @Id CreateLoadTestDataSet
+// @Summary Create Load Test Data Set
+// @Description Upload a CSV file for load tests to read a line per iteration. The first line names the columns, which requests reference as ${column}. A run refers to the data set by its id.
+// @Tags [Load Test Data Set Management]
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file whose first line names the columns"
+// @Param name formData string true "Data set name"
+// @Param description formData string false "Data set description"
+// @Success 200 {object} app.AntResponse[load.LoadTestDataSetResult] "Successfully created load test data set"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to create load test data set"
+// @Router /api/v1/load/data-sets [post]
+func (s *AntServer) createLoadTestDataSet(c echo.Context) error {
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return errorResponseJson(http.StatusBadRequest, "Required fields are missing")
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, "Required fields are missing")
+	}
+	if !strings.EqualFold(filepath.Ext(fh.Filename), ".csv") {
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("data set file %s is not a .csv", fh.Filename))
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, "data set file could not be read")
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, load.MaxPlanAttachmentBytes+1))
+	if err != nil {
+		return errorResponseJson(http.StatusBadRequest, "data set file could not be read")
+	}
+	if len(content) > load.MaxPlanAttachmentBytes {
+		return errorResponseJson(http.StatusBadRequest, fmt.Sprintf("data set file is larger than %d bytes", load.MaxPlanAttachmentBytes))
+	}
+
+	dataSet := load.LoadTestDataSet{
+		Name:        name,
+		Description: strings.TrimSpace(c.FormValue("description")),
+		FileName:    filepath.Base(fh.Filename),
+		Content:     content,
+	}
+
+	result, err := s.services.loadService.CreateLoadTestDataSet(c.Request().Context(), dataSet)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create load test data set")
+		if errors.Is(err, load.ErrInvalidLoadTestDataSet) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to create load test data set")
+	}
+
+	return successResponseJson(c, "Successfully created load test data set", result)
+}
+
+// getAllLoadTestDataSets handler function that lists the stored data sets.
+// @Id GetAllLoadTestDataSets
+// @Summary Get All Load Test Data Sets
+// @Description Retrieve the stored CSV data sets, without their content, with pagination support.
+// @Tags [Load Test Data Set Management]
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param size query int false "Number of items per page (default 10, max 100)"
+// @Param name query string false "Filter by data set name"
+// @Success 200 {object} app.AntResponse[load.GetAllLoadTestDataSetsResult] "Successfully retrieved load test data sets"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test data sets"
+// @Router /api/v1/load/data-sets [get]
+func (s *AntServer) getAllLoadTestDataSets(c echo.Context) error {
+	var req GetAllLoadTestDataSetsReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 10
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	param := load.GetAllLoadTestDataSetsParam{
+		Page: req.Page,
+		Size: req.Size,
+		Name: req.Name,
+	}
+
+	result, err := s.services.loadService.GetAllLoadTestDataSets(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get all load test data sets")
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test data sets")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test data sets", result)
+}
+
+// getLoadTestDataSet handler function that describes one stored data set.
+// @Id GetLoadTestDataSet
+// @Summary Get Load Test Data Set
+// @Description Retrieve a stored CSV data set by ID: its columns, number of lines and size.
+// @Tags [Load Test Data Set Management]
+// @Accept json
+// @Produce json
+// @Param dataSetId path int true "Load Test Data Set ID"
+// @Success 200 {object} app.AntResponse[load.LoadTestDataSetResult] "Successfully retrieved load test data set"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test data set not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test data set"
+// @Router /api/v1/load/data-sets/{dataSetId} [get]
+func (s *AntServer) getLoadTestDataSet(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("dataSetId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	result, err := s.services.loadService.GetLoadTestDataSet(c.Request().Context(), uint(id))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get load test data set")
+		if errors.Is(err, load.ErrLoadTestDataSetNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test data set not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test data set")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test data set", result)
+}
+
+// deleteLoadTestDataSet handler function that deletes a stored data set.
+// @Id DeleteLoadTestDataSet
+// @Summary Delete Load Test Data Set
+// @Description Delete a stored CSV data set by ID. Runs that already read it keep their own copy.
+// @Tags [Load Test Data Set Management]
+// @Accept json
+// @Produce json
+// @Param dataSetId path int true "Load Test Data Set ID"
+// @Success 200 {object} app.AntResponse[string] "Successfully deleted load test data set"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test data set not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to delete load test data set"
+// @Router /api/v1/load/data-sets/{dataSetId} [delete]
+func (s *AntServer) deleteLoadTestDataSet(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("dataSetId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	err = s.services.loadService.DeleteLoadTestDataSet(c.Request().Context(), uint(id))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test data set")
+		if errors.Is(err, load.ErrLoadTestDataSetNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test data set not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to delete load test data set")
+	}
+
+	return successResponseJson(c, "Successfully deleted load test data set", "Successfully deleted load test data set")
+}
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
 	"github.com/cloud-barista/cm-ant/internal/core/load"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
@@ -28,23 +31,37 @@ func (s *AntServer) createLoadTestScenarioCatalog(c echo.Context) error {
 		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
 	}
 
-	// Validate required fields
-	if req.Name == "" || req.VirtualUsers == "" || req.Duration == "" || req.RampUpTime == "" || req.RampUpSteps == "" {
+	req.LoadShape = constant.LoadShape(strings.ToLower(strings.TrimSpace(string(req.LoadShape))))
+
+	// Validate required fields; a stages scenario is described by its stages alone
+	if req.Name == "" {
+		return errorResponseJson(http.StatusBadRequest, "Required fields are missing")
+	}
+	if req.LoadShape != constant.StagesShape && (req.VirtualUsers == "" || req.Duration == "" || req.RampUpTime == "" || req.RampUpSteps == "") {
 		return errorResponseJson(http.StatusBadRequest, "Required fields are missing")
 	}
 
 	catalog := load.LoadTestScenarioCatalog{
-		Name:         req.Name,
-		Description:  req.Description,
-		VirtualUsers: req.VirtualUsers,
-		Duration:     req.Duration,
-		RampUpTime:   req.RampUpTime,
-		RampUpSteps:  req.RampUpSteps,
+		Name:          req.Name,
+		Description:   req.Description,
+		VirtualUsers:  req.VirtualUsers,
+		Duration:      req.Duration,
+		RampUpTime:    req.RampUpTime,
+		RampUpSteps:   req.RampUpSteps,
+		LoadShape:     req.LoadShape,
+		ArrivalRate:   req.ArrivalRate,
+		SpikeUsers:    req.SpikeUsers,
+		SpikeDuration: req.SpikeDuration,
+		Stages:        toCatalogStages(req.Stages),
+		ThinkTime:     toCatalogThinkTime(req.ThinkTime),
 	}
 
 	result, err := s.services.loadService.CreateLoadTestScenarioCatalog(c.Request().Context(), catalog)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create load test scenario catalog")
+		if errors.Is(err, load.ErrInvalidLoadTestScenario) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
 		return errorResponseJson(http.StatusInternalServerError, "Failed to create load test scenario catalog")
 	}
 
@@ -157,12 +174,18 @@ func (s *AntServer) updateLoadTestScenarioCatalog(c echo.Context) error {
 	}
 
 	catalog := load.LoadTestScenarioCatalog{
-		Name:         req.Name,
-		Description:  req.Description,
-		VirtualUsers: req.VirtualUsers,
-		Duration:     req.Duration,
-		RampUpTime:   req.RampUpTime,
-		RampUpSteps:  req.RampUpSteps,
+		Name:          req.Name,
+		Description:   req.Description,
+		VirtualUsers:  req.VirtualUsers,
+		Duration:      req.Duration,
+		RampUpTime:    req.RampUpTime,
+		RampUpSteps:   req.RampUpSteps,
+		LoadShape:     constant.LoadShape(strings.ToLower(strings.TrimSpace(string(req.LoadShape)))),
+		ArrivalRate:   req.ArrivalRate,
+		SpikeUsers:    req.SpikeUsers,
+		SpikeDuration: req.SpikeDuration,
+		Stages:        toCatalogStages(req.Stages),
+		ThinkTime:     toCatalogThinkTime(req.ThinkTime),
 	}
 
 	result, err := s.services.loadService.UpdateLoadTestScenarioCatalog(c.Request().Context(), uint(id), catalog)
@@ -171,6 +194,9 @@ func (s *AntServer) updateLoadTestScenarioCatalog(c echo.Context) error {
 		if err.Error() == "load test scenario catalog not found" {
 			return errorResponseJson(http.StatusNotFound, "Load test scenario catalog not found")
 		}
+		if errors.Is(err, load.ErrInvalidLoadTestScenario) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
 		return errorResponseJson(http.StatusInternalServerError, "Failed to update load test scenario catalog")
 	}
 
@@ -209,3 +235,29 @@ func (s *AntServer) deleteLoadTestScenarioCatalog(c echo.Context) error {
 
 	return successResponseJson(c, "Successfully deleted load test scenario catalog", "Successfully deleted load test scenario catalog")
 }
+
+// toCatalogStages keeps nil apart from an empty list: on update, nil leaves the stored stages
+// alone and an empty list removes them.
+func toCatalogStages(stages []load.RunLoadTestStageParam) []load.LoadTestScenarioCatalogStage {
+	if stages == nil {
+		return nil
+	}
+	res := make([]load.LoadTestScenarioCatalogStage, 0, len(stages))
+	for _, st := range stages {
+		res = append(res, load.LoadTestScenarioCatalogStage{Target: st.Target, Duration: st.Duration})
+	}
+	return res
+}
+
+// toCatalogThinkTime stores a think time as given; the service checks it along with the rest
+// of the scenario.
+func toCatalogThinkTime(t *load.RunLoadTestThinkTimeParam) load.LoadTestThinkTime {
+	if t == nil {
+		return load.LoadTestThinkTime{}
+	}
+	return load.LoadTestThinkTime{
+		Type:  constant.ThinkTimeType(strings.ToLower(strings.TrimSpace(string(t.Type)))),
+		Delay: t.Delay,
+		Range: t.Range,
+	}
+}
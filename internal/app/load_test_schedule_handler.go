@@ -0,0 +1,316 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/cm-ant/internal/core/load"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// createLoadTestSchedule handler function that stores a schedule for load tests to run on.
+// @Id CreateLoadTestSchedule
+// @Summary Create Load Test Schedule
+// @Description Schedule load tests of a scenario catalog against a target, on a cron expression or once at a given time. The run is checked as POST /load/tests/run checks it, with the load profile of the catalog. Schedules are kept in the database and survive restarts; a time missed while the server was down fires once when it is back.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param body body app.LoadTestScheduleReq true "Load Test Schedule Request"
+// @Success 200 {object} app.AntResponse[load.LoadTestScheduleResult] "Successfully created load test schedule"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to create load test schedule"
+// @Router /api/v1/load/schedules [post]
+func (s *AntServer) createLoadTestSchedule(c echo.Context) error {
+	var req LoadTestScheduleReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	param, err := s.toSaveLoadTestScheduleParam(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.services.loadService.CreateLoadTestSchedule(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create load test schedule")
+		if errors.Is(err, load.ErrInvalidLoadTestSchedule) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to create load test schedule")
+	}
+
+	return successResponseJson(c, "Successfully created load test schedule", result)
+}
+
+// getAllLoadTestSchedules handler function that lists the load test schedules.
+// @Id GetAllLoadTestSchedules
+// @Summary Get All Load Test Schedules
+// @Description Retrieve the load test schedules with when each fires next, with pagination support.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param size query int false "Number of items per page (default 10, max 100)"
+// @Param name query string false "Filter by schedule name"
+// @Success 200 {object} app.AntResponse[load.GetAllLoadTestSchedulesResult] "Successfully retrieved load test schedules"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test schedules"
+// @Router /api/v1/load/schedules [get]
+func (s *AntServer) getAllLoadTestSchedules(c echo.Context) error {
+	var req GetAllLoadTestSchedulesReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 10
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	param := load.GetAllLoadTestSchedulesParam{
+		Page: req.Page,
+		Size: req.Size,
+		Name: req.Name,
+	}
+
+	result, err := s.services.loadService.GetAllLoadTestSchedules(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get all load test schedules")
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test schedules")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test schedules", result)
+}
+
+// getLoadTestSchedule handler function that retrieves a load test schedule.
+// @Id GetLoadTestSchedule
+// @Summary Get Load Test Schedule
+// @Description Retrieve a load test schedule by ID.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Load Test Schedule ID"
+// @Success 200 {object} app.AntResponse[load.LoadTestScheduleResult] "Successfully retrieved load test schedule"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test schedule not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test schedule"
+// @Router /api/v1/load/schedules/{scheduleId} [get]
+func (s *AntServer) getLoadTestSchedule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	result, err := s.services.loadService.GetLoadTestSchedule(c.Request().Context(), uint(id))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get load test schedule")
+		if errors.Is(err, load.ErrLoadTestScheduleNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test schedule not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test schedule")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test schedule", result)
+}
+
+// updateLoadTestSchedule handler function that replaces a load test schedule.
+// @Id UpdateLoadTestSchedule
+// @Summary Update Load Test Schedule
+// @Description Replace a load test schedule as a whole; the body is the same as on creation. When it fires next is worked out again from now.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Load Test Schedule ID"
+// @Param body body app.LoadTestScheduleReq true "Load Test Schedule Request"
+// @Success 200 {object} app.AntResponse[load.LoadTestScheduleResult] "Successfully updated load test schedule"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test schedule not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to update load test schedule"
+// @Router /api/v1/load/schedules/{scheduleId} [put]
+func (s *AntServer) updateLoadTestSchedule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	var req LoadTestScheduleReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+
+	param, err := s.toSaveLoadTestScheduleParam(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.services.loadService.UpdateLoadTestSchedule(c.Request().Context(), uint(id), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update load test schedule")
+		if errors.Is(err, load.ErrLoadTestScheduleNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test schedule not found")
+		}
+		if errors.Is(err, load.ErrInvalidLoadTestSchedule) {
+			return errorResponseJson(http.StatusBadRequest, err.Error())
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to update load test schedule")
+	}
+
+	return successResponseJson(c, "Successfully updated load test schedule", result)
+}
+
+// deleteLoadTestSchedule handler function that deletes a load test schedule.
+// @Id DeleteLoadTestSchedule
+// @Summary Delete Load Test Schedule
+// @Description Delete a load test schedule by ID. Runs it already started are not stopped.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Load Test Schedule ID"
+// @Success 200 {object} app.AntResponse[string] "Successfully deleted load test schedule"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test schedule not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to delete load test schedule"
+// @Router /api/v1/load/schedules/{scheduleId} [delete]
+func (s *AntServer) deleteLoadTestSchedule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	if err := s.services.loadService.DeleteLoadTestSchedule(c.Request().Context(), uint(id)); err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test schedule")
+		if errors.Is(err, load.ErrLoadTestScheduleNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test schedule not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to delete load test schedule")
+	}
+
+	return successResponseJson(c, "Successfully deleted load test schedule", "Successfully deleted load test schedule")
+}
+
+// getLoadTestScheduleRuns handler function that lists the times a schedule fired.
+// @Id GetLoadTestScheduleRuns
+// @Summary Get Load Test Schedule Runs
+// @Description Retrieve the times a load test schedule fired, the latest first: the load test key of the run each started and where that run is now, or why none was started.
+// @Tags [Load Test Schedule Management]
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Load Test Schedule ID"
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param size query int false "Number of items per page (default 10, max 100)"
+// @Success 200 {object} app.AntResponse[load.GetLoadTestScheduleRunsResult] "Successfully retrieved load test schedule runs"
+// @Failure 400 {object} app.AntResponse[string] "Invalid request parameters"
+// @Failure 404 {object} app.AntResponse[string] "Load test schedule not found"
+// @Failure 500 {object} app.AntResponse[string] "Failed to retrieve load test schedule runs"
+// @Router /api/v1/load/schedules/{scheduleId}/runs [get]
+func (s *AntServer) getLoadTestScheduleRuns(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid ID parameter")
+		return errorResponseJson(http.StatusBadRequest, "Invalid ID parameter")
+	}
+
+	var req GetLoadTestScheduleRunsReq
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("Failed to bind request")
+		return errorResponseJson(http.StatusBadRequest, "Invalid request parameters")
+	}
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 10
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	param := load.GetLoadTestScheduleRunsParam{
+		ScheduleId: uint(id),
+		Page:       req.Page,
+		Size:       req.Size,
+	}
+
+	result, err := s.services.loadService.GetLoadTestScheduleRuns(c.Request().Context(), param)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get load test schedule runs")
+		if errors.Is(err, load.ErrLoadTestScheduleNotFound) {
+			return errorResponseJson(http.StatusNotFound, "Load test schedule not found")
+		}
+		return errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test schedule runs")
+	}
+
+	return successResponseJson(c, "Successfully retrieved load test schedule runs", result)
+}
+
+// toSaveLoadTestScheduleParam checks a schedule request and the run it starts, with the load
+// profile of its scenario catalog as it is now. Its error is the response to answer with.
+func (s *AntServer) toSaveLoadTestScheduleParam(ctx context.Context, req LoadTestScheduleReq) (load.SaveLoadTestScheduleParam, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || req.ScenarioCatalogId == 0 || req.NsId == "" || req.InfraId == "" || req.NodeId == "" {
+		return load.SaveLoadTestScheduleParam{}, errorResponseJson(http.StatusBadRequest, "Required fields are missing")
+	}
+
+	catalog, err := s.services.loadService.GetLoadTestScenarioCatalog(ctx, req.ScenarioCatalogId)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get scenario catalog of load test schedule")
+		if err.Error() == "load test scenario catalog not found" {
+			return load.SaveLoadTestScheduleParam{}, errorResponseJson(http.StatusBadRequest, fmt.Sprintf("scenario catalog %d not found", req.ScenarioCatalogId))
+		}
+		return load.SaveLoadTestScheduleParam{}, errorResponseJson(http.StatusInternalServerError, "Failed to retrieve load test scenario catalog")
+	}
+
+	run := req.Run
+	run.TestName = req.Name
+	run.NsId, run.InfraId, run.NodeId = req.NsId, req.InfraId, req.NodeId
+	run.VirtualUsers = catalog.VirtualUsers
+	run.Duration = catalog.Duration
+	run.RampUpTime = catalog.RampUpTime
+	run.RampUpSteps = catalog.RampUpSteps
+	run.LoadShape = catalog.LoadShape
+	run.ArrivalRate = catalog.ArrivalRate
+	run.SpikeUsers = catalog.SpikeUsers
+	run.SpikeDuration = catalog.SpikeDuration
+	run.Stages = nil
+	for _, st := range catalog.Stages {
+		run.Stages = append(run.Stages, RunLoadTestStageReq{Target: st.Target, Duration: st.Duration})
+	}
+	if catalog.ThinkTime != nil {
+		run.ThinkTime = &RunLoadTestThinkTimeReq{Type: catalog.ThinkTime.Type, Delay: catalog.ThinkTime.Delay, Range: catalog.ThinkTime.Range}
+	}
+
+	arg, err := toRunLoadTestParam(run)
+	if err != nil {
+		return load.SaveLoadTestScheduleParam{}, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return load.SaveLoadTestScheduleParam{
+		Name:              req.Name,
+		Description:       req.Description,
+		Cron:              req.Cron,
+		RunAt:             req.RunAt,
+		TimeZone:          strings.TrimSpace(req.TimeZone),
+		Enabled:           enabled,
+		ScenarioCatalogId: req.ScenarioCatalogId,
+		NsId:              req.NsId,
+		InfraId:           req.InfraId,
+		NodeId:            req.NodeId,
+		Run:               arg,
+	}, nil
+}
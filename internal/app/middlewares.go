@@ -20,17 +20,23 @@ var (
 	}
 )
 
+// streamSkipper skips the middlewares that hold a response back until it is complete, which
+// a stream of events never is.
+func streamSkipper(c echo.Context) bool {
+	return strings.HasSuffix(c.Request().URL.Path, "/stream")
+}
+
 func setMiddleware(e *echo.Echo) {
 	e.Use(
 		middleware.Secure(),
 		middleware.RequestID(),
 		middleware.Recover(),
-		middleware.Gzip(),
+		middleware.GzipWithConfig(middleware.GzipConfig{Skipper: streamSkipper}),
 		middleware.CORS(),
 		Zerologger(logSkipPattern),
 		middleware.TimeoutWithConfig(
 			middleware.TimeoutConfig{
-				Skipper:      middleware.DefaultSkipper,
+				Skipper:      streamSkipper,
 				ErrorMessage: "request timeout",
 				OnTimeoutRouteErrorHandler: func(err error, c echo.Context) {
 					log.Info().Msg(c.Path())
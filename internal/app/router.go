@@ -40,12 +40,15 @@ func (server *AntServer) InitRouter() error {
 			{
 				// load test execution
 				loadTestRouter.POST("/run", server.runLoadTest)
+				loadTestRouter.POST("/run/jmx", server.runLoadTestWithTestPlan)
 				loadTestRouter.POST("/stop", server.stopLoadTest)
+				loadTestRouter.POST("/:loadTestKey/rerun", server.rerunLoadTest)
 
 				// load test state
 				loadTestRouter.GET("/state", server.getAllLoadTestExecutionState)
 				loadTestRouter.GET("/state/:loadTestKey", server.getLoadTestExecutionState)
 				loadTestRouter.GET("/state/last", server.getLastLoadTestExecutionState)
+				loadTestRouter.GET("/state/:loadTestKey/stream", server.streamLoadTestProgress)
 
 				// load test history
 				loadTestRouter.GET("/infos", server.getAllLoadTestExecutionInfos)
@@ -53,11 +56,41 @@ func (server *AntServer) InitRouter() error {
 
 				// load test result
 				loadTestRouter.GET("/result", server.getLoadTestResult)
+				loadTestRouter.GET("/result/regions", server.getLoadTestRegionResult)
+				loadTestRouter.GET("/result/compare", server.compareLoadTests)
 				loadTestRouter.GET("/result/metrics", server.getLoadTestMetrics)
 				loadTestRouter.GET("/result/last", server.getLastLoadTestResult)
 				loadTestRouter.GET("/result/metrics/last", server.getLastLoadTestMetrics)
 			}
 
+			// CSV data sets runs read ${column} values from
+			dataSetsRouter := loadRouter.Group("/data-sets")
+			{
+				dataSetsRouter.POST("", server.createLoadTestDataSet)
+				dataSetsRouter.GET("", server.getAllLoadTestDataSets)
+				dataSetsRouter.GET("/:dataSetId", server.getLoadTestDataSet)
+				dataSetsRouter.DELETE("/:dataSetId", server.deleteLoadTestDataSet)
+			}
+
+			// runs the later runs of a scenario catalog entry or target node are compared with
+			baselinesRouter := loadRouter.Group("/baselines")
+			{
+				baselinesRouter.POST("", server.pinLoadTestBaseline)
+				baselinesRouter.GET("", server.getAllLoadTestBaselines)
+				baselinesRouter.DELETE("/:baselineId", server.deleteLoadTestBaseline)
+			}
+
+			// load tests run on a cron or once at a given time
+			schedulesRouter := loadRouter.Group("/schedules")
+			{
+				schedulesRouter.POST("", server.createLoadTestSchedule)
+				schedulesRouter.GET("", server.getAllLoadTestSchedules)
+				schedulesRouter.GET("/:scheduleId", server.getLoadTestSchedule)
+				schedulesRouter.PUT("/:scheduleId", server.updateLoadTestSchedule)
+				schedulesRouter.DELETE("/:scheduleId", server.deleteLoadTestSchedule)
+				schedulesRouter.GET("/:scheduleId/runs", server.getLoadTestScheduleRuns)
+			}
+
 			// load test scenario catalog templates
 			templatesRouter := loadRouter.Group("/templates")
 			{
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/cloud-barista/cm-ant/internal/infra/outbound/spider"
 	"github.com/cloud-barista/cm-ant/internal/infra/outbound/tumblebug"
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
@@ -47,8 +50,21 @@ type AntServer struct {
 	db              *gorm.DB
 
 	depCache depCache
+
+	// stopBackground stops the scheduler.
+	stopBackground context.CancelFunc
+	// stopHeartbeats stops the heartbeat of the running load tests, once they are drained.
+	stopHeartbeats context.CancelFunc
 }
 
+const (
+	// defaultShutdownTimeout is how long a shutdown waits for running load tests when
+	// server.shutdownTimeout is unset.
+	defaultShutdownTimeout = 5 * time.Minute
+	// httpShutdownTimeout is how long requests in flight get once the load tests are drained.
+	httpShutdownTimeout = 10 * time.Second
+)
+
 // NewAntServer initializes and returns a new instance of AntServer.
 func NewAntServer() (*AntServer, error) {
 
@@ -75,6 +91,8 @@ func NewAntServer() (*AntServer, error) {
 		spiderClient:    spiderClient,
 		tumblebugClient: tumblebugClient,
 		db:              conn,
+		stopBackground:  func() {},
+		stopHeartbeats:  func() {},
 	}, nil
 }
 
@@ -114,7 +132,57 @@ func initializeServices(repos *antRepositories, tbClient *tumblebug.TumblebugCli
 }
 
 // Start launches the Echo HTTP server on the port specified in the application
-// configuration. It returns an error if the server fails to start.
+// configuration, along with the scheduler that starts scheduled load tests and the runs left
+// unfinished or queued when the server last stopped. It returns an error if the server fails to start; after Shutdown it returns
+// http.ErrServerClosed.
 func (a *AntServer) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopBackground = cancel
+
+	go func() {
+		// runs left unfinished by servers gone hold their slots before the queue moves
+		a.services.loadService.ResumeUnfinishedRuns()
+		a.services.loadService.DispatchQueuedRuns()
+	}()
+	go a.services.loadService.RunSchedules(ctx)
+	heartbeatCtx, stopHeartbeats := context.WithCancel(context.Background())
+	a.stopHeartbeats = stopHeartbeats
+	go a.services.loadService.RunHeartbeats(heartbeatCtx)
 	return a.e.Start(fmt.Sprintf(":%s", config.AppConfig.Server.Port))
 }
+
+// ShutdownTimeout is how long Shutdown may take to drain the running load tests.
+func ShutdownTimeout() time.Duration {
+	if t := config.AppConfig.Server.ShutdownTimeout; t > 0 {
+		return t
+	}
+	return defaultShutdownTimeout
+}
+
+// Shutdown stops the server gracefully. It stops the scheduler and takes no new load tests,
+// waits until ctx is done at the latest for the running ones to reach a point the next
+// server can pick them up from, with /ant/readyz reporting the drain meanwhile, and then
+// stops the HTTP server and closes the database.
+func (a *AntServer) Shutdown(ctx context.Context) error {
+	a.stopBackground()
+
+	var errs []error
+	if err := a.services.loadService.Drain(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	a.stopHeartbeats()
+
+	httpCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := a.e.Shutdown(httpCtx); err != nil {
+		errs = append(errs, fmt.Errorf("http server shutdown error: %w", err))
+	}
+
+	if sqlDB, err := a.db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("database close error: %w", err))
+		}
+	}
+	log.Info().Msg("CM-Ant server shut down")
+	return errors.Join(errs...)
+}
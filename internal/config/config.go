@@ -20,6 +20,10 @@ type AntConfig struct {
 	} `yaml:"root"`
 	Server struct {
 		Port string `yaml:"port"`
+		// ShutdownTimeout is how long a shutdown waits for running load tests to reach a point
+		// the next server can pick them up from. 0 (unset) falls back to 5 minutes; override
+		// via config.yaml or ANT_SERVER_SHUTDOWNTIMEOUT.
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
 	} `yaml:"server"`
 	Spider struct {
 		Host     string `yaml:"host"`
@@ -76,11 +80,41 @@ type AntConfig struct {
 			MaxDuration     int `yaml:"maxDuration"`
 			MaxRampUpTime   int `yaml:"maxRampUpTime"`
 			MaxRampUpSteps  int `yaml:"maxRampUpSteps"`
+			MaxArrivalRate  int `yaml:"maxArrivalRate"` // iterations started per second
 		} `yaml:"limits"`
+		Queue struct {
+			// How many runs may be going at once (FR-MA2-PERF-007-01): in all, on one load
+			// generator and against one target node. A run over any of them waits in the queue
+			// until one ends. A value of 0 (unset) falls back to the built-in default (2/1/1);
+			// override via config.yaml or ANT_LOAD_QUEUE_*.
+			MaxConcurrentRuns   int `yaml:"maxConcurrentRuns"`
+			MaxRunsPerGenerator int `yaml:"maxRunsPerGenerator"`
+			MaxRunsPerTarget    int `yaml:"maxRunsPerTarget"`
+		} `yaml:"queue"`
+		Comparison struct {
+			// How much worse than its baseline a run may do before a comparison flags it, and the
+			// p-value below which a change is not put down to chance. A value of 0 (unset) falls
+			// back to the built-in default (10/1/10/10/0.05); a request may override each.
+			ResponseTimePercent float64 `yaml:"responseTimePercent"` // % slower: average, median, percentiles
+			ErrorPercentPoints  float64 `yaml:"errorPercentPoints"`  // error rate points higher
+			ThroughputPercent   float64 `yaml:"throughputPercent"`   // % fewer requests per second
+			MetricPercent       float64 `yaml:"metricPercent"`       // % worse target metrics
+			Significance        float64 `yaml:"significance"`
+		} `yaml:"comparison"`
+		Result struct {
+			// Every run's result is kept in the database once it is collected, and served from
+			// there. The raw csv files it was read from are kept beside it unless DiscardRawFiles
+			// is set; without them a comparison draws on the stored samples only.
+			DiscardRawFiles bool `yaml:"discardRawFiles"`
+		} `yaml:"result"`
 		JMeter struct {
 			Dir     string `yaml:"dir"`
 			Version string `yaml:"version"`
 		} `yaml:"jmeter"`
+		// K6 is installed next to JMeter, under load.jmeter.dir, the first time a run asks for it.
+		K6 struct {
+			Version string `yaml:"version"`
+		} `yaml:"k6"`
 		Image struct {
 			UseSmartMatching      bool                         `yaml:"useSmartMatching"`
 			UseFallbackImagesOnly bool                         `yaml:"useFallbackImagesOnly"`
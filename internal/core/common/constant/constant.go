@@ -13,17 +13,56 @@ type LoadGeneratorType string
 
 const (
 	Jmeter LoadGeneratorType = "jmeter"
+	K6     LoadGeneratorType = "k6"
+)
+
+// LoadShape is how the load of a run changes over time.
+type LoadShape string
+
+const (
+	// VirtualUsers added over RampUpTime in RampUpSteps steps, then held for Duration.
+	ConcurrencyShape LoadShape = "concurrency"
+	// ArrivalRate iterations started per second, reached over RampUpTime in RampUpSteps steps
+	// and held for Duration, with at most VirtualUsers of them in flight.
+	ArrivalRateShape LoadShape = "arrival_rate"
+	// VirtualUsers ramped up over RampUpTime and held for Duration, jumping to SpikeUsers for
+	// SpikeDuration in the middle of the hold.
+	SpikeShape LoadShape = "spike"
+	// VirtualUsers ramped up over RampUpTime, then lowered in RampUpSteps equal steps spread
+	// over Duration.
+	StepDownShape LoadShape = "step_down"
+	// Stages, each moving the users linearly to its target over its duration.
+	StagesShape LoadShape = "stages"
 )
 
 type ExecutionStatus string
 
 const (
+	// waiting in the run queue for the concurrency limits to let it start
+	Queued       ExecutionStatus = "queued"
 	OnProcessing ExecutionStatus = "on_processing"
 	OnFetching   ExecutionStatus = "on_fetching"
 	Successed    ExecutionStatus = "successed"
 	TestFailed   ExecutionStatus = "test_failed"
 )
 
+// Finished reports whether a run has ended, one way or the other.
+func (s ExecutionStatus) Finished() bool {
+	return s == Successed || s == TestFailed
+}
+
+// ProgressEventType is the kind of update pushed to those watching a run.
+type ProgressEventType string
+
+const (
+	// the run's state; the first event of a stream carries its steps as well
+	StateEvent ProgressEventType = "state"
+	// a step that changed
+	StepEvent ProgressEventType = "step"
+	// rolling figures of the load while it runs
+	FiguresEvent ProgressEventType = "figures"
+)
+
 // ExecutionStep identifies a stage of a load test run (FR-MA2-PERF-007-08).
 type ExecutionStep string
 
@@ -108,11 +147,155 @@ func (s StepStatus) Terminal() bool {
 	return s == StepOk || s == StepFailed || s == StepSkipped
 }
 
+// ExtractorType is how a sampler pulls a value out of its response so that later requests
+// in the same scenario can reference it as ${name}.
+type ExtractorType string
+
+const (
+	JsonPathExtractor ExtractorType = "json_path"
+	RegexExtractor    ExtractorType = "regex"
+)
+
+// HttpBodyType is how a request's body is encoded. Raw sends BodyData as is; form and multipart
+// are built from the request's form fields (and files, for multipart).
+type HttpBodyType string
+
+const (
+	RawBody       HttpBodyType = "raw"
+	FormBody      HttpBodyType = "form"
+	MultipartBody HttpBodyType = "multipart"
+)
+
+// HttpFieldKind tells apart the name/value pairs a request carries.
+type HttpFieldKind string
+
+const (
+	HeaderField HttpFieldKind = "header"
+	QueryField  HttpFieldKind = "query"
+	FormField   HttpFieldKind = "form"
+)
+
+// AssertionType is what a response is checked for. A response that fails an assertion is
+// counted as an error, just like one the server answered with an error status.
+type AssertionType string
+
+const (
+	StatusCodeAssertion   AssertionType = "status_code"   // the status is one of Value's comma separated codes
+	BodyContainsAssertion AssertionType = "body_contains" // the body contains Value
+	JsonPathAssertion     AssertionType = "json_path"     // Expression finds a value, equal to Value when given
+	MaxDurationAssertion  AssertionType = "max_duration"  // the response took at most Value ms
+)
+
+// ThinkTimeType is how long a virtual user pauses before sending a request.
+type ThinkTimeType string
+
+const (
+	ConstantThinkTime ThinkTimeType = "constant" // Delay ms every time
+	UniformThinkTime  ThinkTimeType = "uniform"  // Delay ms plus up to Range ms more, evenly spread
+	GaussianThinkTime ThinkTimeType = "gaussian" // Delay ms on average, deviating by Range ms
+)
+
+// TargetProtocol is what a target that is not load tested over plain HTTP speaks.
+type TargetProtocol string
+
+const (
+	WebSocketTarget TargetProtocol = "websocket" // sends Message and waits for one frame back
+	GrpcTarget      TargetProtocol = "grpc"      // calls Method with Message as its JSON request
+	TcpTarget       TargetProtocol = "tcp"       // sends Message and reads the answer
+	JdbcTarget      TargetProtocol = "jdbc"      // runs Query on Database
+)
+
+// JdbcDriver is the database a jdbc target is.
+type JdbcDriver string
+
+const (
+	PostgresDriver JdbcDriver = "postgresql"
+	MysqlDriver    JdbcDriver = "mysql"
+)
+
+// HttpImplementation is the client library a JMeter sampler sends its request with.
+type HttpImplementation string
+
+const (
+	HttpClient4Implementation HttpImplementation = "HttpClient4"
+	JavaImplementation        HttpImplementation = "Java"
+)
+
+// TlsVersion pins the TLS protocol a run speaks to https targets. Empty leaves it to the engine.
+type TlsVersion string
+
+const (
+	Tls12 TlsVersion = "TLSv1.2"
+	Tls13 TlsVersion = "TLSv1.3"
+)
+
+// DataSetShareMode is which virtual users read through a CSV data set together: with all, every
+// user of the run takes the next line; with group, every thread group keeps its own position;
+// with thread, every user reads the file from the top on its own.
+type DataSetShareMode string
+
+const (
+	ShareAll    DataSetShareMode = "all"
+	ShareGroup  DataSetShareMode = "group"
+	ShareThread DataSetShareMode = "thread"
+)
+
+// SlaVerdict says whether a finished run met its service level thresholds. A run that was not
+// given any has no verdict.
+type SlaVerdict string
+
+const (
+	SlaPass SlaVerdict = "PASS"
+	SlaFail SlaVerdict = "FAIL"
+)
+
+// SlaCriterion names a service level threshold of a run.
+type SlaCriterion string
+
+const (
+	MaxErrorPercentCriterion SlaCriterion = "max_error_percent"
+	MaxP95Criterion          SlaCriterion = "max_p95"
+	MaxP99Criterion          SlaCriterion = "max_p99"
+	MinThroughputCriterion   SlaCriterion = "min_throughput"
+	// the result file could not be read, so none of the thresholds could be checked
+	ResultCriterion SlaCriterion = "result"
+)
+
+// AutoStopRule names a rule that stops a run while its load is going.
+type AutoStopRule string
+
+const (
+	ErrorPercentRule AutoStopRule = "error_percent"
+	P95Rule          AutoStopRule = "p95"
+	AgentCpuRule     AutoStopRule = "agent_cpu"
+)
+
+// BaselineVerdict says how a finished run did against the baseline of its scenario catalog entry
+// or target node. A run without a baseline has none.
+type BaselineVerdict string
+
+const (
+	BaselinePass      BaselineVerdict = "PASS"
+	BaselineRegressed BaselineVerdict = "REGRESSED"
+	// the comparison could not be made, such as when the baseline's result is gone
+	BaselineInconclusive BaselineVerdict = "INCONCLUSIVE"
+)
+
+// ComparisonSide names a side of a run-to-run comparison, for a label or metric only one side
+// has.
+type ComparisonSide string
+
+const (
+	BaselineSide ComparisonSide = "baseline"
+	RunSide      ComparisonSide = "run"
+)
+
 type ResultFormat string
 
 const (
-	Normal    ResultFormat = "normal"
-	Aggregate ResultFormat = "aggregate"
+	Normal     ResultFormat = "normal"
+	Aggregate  ResultFormat = "aggregate"
+	TimeSeries ResultFormat = "timeseries" // figures of each label per time bucket, for charts
 )
 
 type PricePolicy string
@@ -213,3 +396,12 @@ const (
 	Fail    IconCode = "IC0002"
 	Pending IconCode = "IC0003"
 )
+
+// ScheduleRunStatus is what became of a schedule firing: the run it started, or why none was.
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStarted ScheduleRunStatus = "started"
+	// the run was refused, e.g. because the scenario catalog was deleted
+	ScheduleRunFailed ScheduleRunStatus = "failed"
+)
@@ -0,0 +1,157 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// An assertion turns a response that arrived but is wrong into an error sample. JMeter counts
+// only a failed connection or a 4xx/5xx status as an error on its own, so without assertions a
+// target answering every request with an error page in 200 still looks healthy.
+
+type jmxAssertionTemplateData struct {
+	Codes      []string
+	Expression string
+	Value      string
+	Validate   bool
+}
+
+// jmxAssertionTemplate renders the assertions that check a sampler's response. Like the
+// extractors they sit in the sampler's own hashTree.
+//
+// A status code assertion matches the code against every given code (test type 8, equals, or
+// 32, any of them) and is told to ignore the status (assume_success), because otherwise JMeter
+// would still fail a 404 the caller listed as expected. A body assertion is a substring match
+// (test type 16), so the value is not read as a regular expression.
+var jmxAssertionTemplate = map[constant.AssertionType]string{
+	constant.StatusCodeAssertion: `
+		<ResponseAssertion guiclass="AssertionGui" testclass="ResponseAssertion" testname="Status Code Assertion" enabled="true">
+			<collectionProp name="Asserion.test_strings">
+				{{- range $i, $code := .Codes }}
+				<stringProp name="{{ $i }}">{{ $code }}</stringProp>
+				{{- end }}
+			</collectionProp>
+			<stringProp name="Assertion.custom_message"></stringProp>
+			<stringProp name="Assertion.test_field">Assertion.response_code</stringProp>
+			<boolProp name="Assertion.assume_success">true</boolProp>
+			<intProp name="Assertion.test_type">40</intProp>
+		</ResponseAssertion>
+		<hashTree/>
+	`,
+	constant.BodyContainsAssertion: `
+		<ResponseAssertion guiclass="AssertionGui" testclass="ResponseAssertion" testname="Body Assertion" enabled="true">
+			<collectionProp name="Asserion.test_strings">
+				<stringProp name="0">{{.Value}}</stringProp>
+			</collectionProp>
+			<stringProp name="Assertion.custom_message"></stringProp>
+			<stringProp name="Assertion.test_field">Assertion.response_data</stringProp>
+			<boolProp name="Assertion.assume_success">false</boolProp>
+			<intProp name="Assertion.test_type">16</intProp>
+		</ResponseAssertion>
+		<hashTree/>
+	`,
+	constant.JsonPathAssertion: `
+		<JSONPathAssertion guiclass="JSONPathAssertionGui" testclass="JSONPathAssertion" testname="JSON Assertion" enabled="true">
+			<stringProp name="JSON_PATH">{{.Expression}}</stringProp>
+			<stringProp name="EXPECTED_VALUE">{{.Value}}</stringProp>
+			<boolProp name="JSONVALIDATION">{{.Validate}}</boolProp>
+			<boolProp name="EXPECT_NULL">false</boolProp>
+			<boolProp name="INVERT">false</boolProp>
+			<boolProp name="ISREGEX">false</boolProp>
+		</JSONPathAssertion>
+		<hashTree/>
+	`,
+	constant.MaxDurationAssertion: `
+		<DurationAssertion guiclass="DurationAssertionGui" testclass="DurationAssertion" testname="Duration Assertion" enabled="true">
+			<stringProp name="DurationAssertion.duration">{{.Value}}</stringProp>
+		</DurationAssertion>
+		<hashTree/>
+	`,
+}
+
+// ValidateAssertions checks the assertions of one request.
+func ValidateAssertions(assertions []RunLoadTestAssertionParam) error {
+	for _, a := range assertions {
+		switch a.Type {
+		case constant.StatusCodeAssertion:
+			if _, err := statusCodesOf(a.Value); err != nil {
+				return err
+			}
+		case constant.BodyContainsAssertion:
+			if a.Value == "" {
+				return errors.New("body_contains assertion needs the text to look for")
+			}
+		case constant.JsonPathAssertion:
+			if !strings.HasPrefix(strings.TrimSpace(a.Expression), "$") {
+				return fmt.Errorf("json_path assertion expression %q must start with $", a.Expression)
+			}
+		case constant.MaxDurationAssertion:
+			ms, err := strconv.Atoi(strings.TrimSpace(a.Value))
+			if err != nil || ms < 1 {
+				return fmt.Errorf("max_duration assertion value %q must be a positive number of milliseconds", a.Value)
+			}
+		default:
+			return fmt.Errorf("assertion type %q is not supported", a.Type)
+		}
+	}
+	return nil
+}
+
+// statusCodesOf reads a comma separated list of HTTP status codes.
+func statusCodesOf(value string) ([]string, error) {
+	var codes []string
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		code, err := strconv.Atoi(c)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("status_code assertion value %q is not an HTTP status code", c)
+		}
+		codes = append(codes, strconv.Itoa(code))
+	}
+	if len(codes) == 0 {
+		return nil, errors.New("status_code assertion needs at least one status code")
+	}
+	return codes, nil
+}
+
+// assertionParseToJmx renders the assertions of one request into JMeter assertions.
+func assertionParseToJmx(assertions []RunLoadTestAssertionParam) (string, error) {
+	if err := ValidateAssertions(assertions); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for i, a := range assertions {
+		tmpl, err := template.New(fmt.Sprintf("jmxAssertion-%d-%s", i, a.Type)).Parse(jmxAssertionTemplate[a.Type])
+		if err != nil {
+			return "", err
+		}
+
+		data := jmxAssertionTemplateData{
+			Expression: html.EscapeString(strings.TrimSpace(a.Expression)),
+			Value:      html.EscapeString(a.Value),
+			// without an expected value the assertion only checks that the path exists
+			Validate: a.Value != "",
+		}
+		switch a.Type {
+		case constant.StatusCodeAssertion:
+			data.Codes, _ = statusCodesOf(a.Value)
+		case constant.MaxDurationAssertion:
+			data.Value = strings.TrimSpace(a.Value)
+		}
+
+		if err := tmpl.Execute(&builder, data); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
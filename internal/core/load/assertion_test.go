@@ -0,0 +1,67 @@
+package load
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestAssertionsRenderIntoTheSampler(t *testing.T) {
+	param := RunLoadTestParam{
+		HttpReqs: []RunLoadTestHttpParam{{
+			Method: "GET", Protocol: "http", Hostname: "127.0.0.1", Port: "80", Path: "/items",
+			Assertions: []RunLoadTestAssertionParam{
+				{Type: constant.StatusCodeAssertion, Value: "200, 404"},
+				{Type: constant.BodyContainsAssertion, Value: `"ok" & done`},
+				{Type: constant.JsonPathAssertion, Expression: "$.status"},
+				{Type: constant.MaxDurationAssertion, Value: "500"},
+			},
+		}},
+	}
+	out, err := httpReqParseToJmx(param.HttpReqs, "/opt/ant/test_plan/key")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+	for _, want := range []string{
+		`<stringProp name="0">200</stringProp>`,
+		`<stringProp name="1">404</stringProp>`,
+		`<boolProp name="Assertion.assume_success">true</boolProp>`,
+		`&#34;ok&#34; &amp; done`,
+		`<stringProp name="JSON_PATH">$.status</stringProp>`,
+		`<boolProp name="JSONVALIDATION">false</boolProp>`,
+		`<stringProp name="DurationAssertion.duration">500</stringProp>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in the plan", want)
+		}
+	}
+}
+
+func TestAssertionsAreValidated(t *testing.T) {
+	for name, a := range map[string]RunLoadTestAssertionParam{
+		"unknown type":         {Type: "header_contains", Value: "x"},
+		"no status code":       {Type: constant.StatusCodeAssertion, Value: " , "},
+		"bad status code":      {Type: constant.StatusCodeAssertion, Value: "2xx"},
+		"empty body":           {Type: constant.BodyContainsAssertion},
+		"relative json path":   {Type: constant.JsonPathAssertion, Expression: "status"},
+		"zero max duration":    {Type: constant.MaxDurationAssertion, Value: "0"},
+		"unitful max duration": {Type: constant.MaxDurationAssertion, Value: "500ms"},
+	} {
+		if err := ValidateAssertions([]RunLoadTestAssertionParam{a}); err == nil {
+			t.Errorf("%s: expected the assertion to be rejected", name)
+		}
+	}
+
+	if err := (k6Engine{}).Validate(RunLoadTestParam{
+		VirtualUsers: "1", Duration: "1", RampUpTime: "0", RampUpSteps: "1",
+		HttpReqs: []RunLoadTestHttpParam{{
+			Method: "GET", Protocol: "http", Hostname: "127.0.0.1", Port: "80",
+			Assertions: []RunLoadTestAssertionParam{{Type: constant.StatusCodeAssertion, Value: "200"}},
+		}},
+	}); err == nil {
+		t.Error("expected k6 to refuse assertions")
+	}
+}
@@ -0,0 +1,234 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// A run whose target has fallen over carries on hammering it until its duration is up, unless
+// someone is watching and stops it by hand. A run given auto-stop rules has them checked on the
+// rolling figures while its load goes, and is stopped the way a stop request stops it the
+// first time one of them fires. The rule that fired is told in the jmeter_run step, the run
+// fails with it, and the results up to that point are collected as usual.
+
+// agentCpuLabel is the metric of the agent's cpu file the cpu rule reads: the busy share of
+// all cores.
+const agentCpuLabel = "cpu_all_combined"
+
+// ValidateAutoStop checks the auto-stop rules of a run before it is accepted. The cpu rule
+// needs the run to collect the target's metrics.
+func ValidateAutoStop(autoStop *RunLoadTestAutoStopParam, withMetrics bool) error {
+	if autoStop == nil {
+		return nil
+	}
+	limits := []struct {
+		name  string
+		value *float64
+	}{
+		{"maxErrorPercent", autoStop.MaxErrorPercent},
+		{"maxP95", autoStop.MaxP95},
+		{"maxAgentCpuPercent", autoStop.MaxAgentCpuPercent},
+	}
+	for _, t := range limits {
+		if t.value != nil && *t.value < 0 {
+			return fmt.Errorf("autoStop %s must not be negative", t.name)
+		}
+	}
+	if autoStop.MaxErrorPercent != nil && *autoStop.MaxErrorPercent > 100 {
+		return errors.New("autoStop maxErrorPercent must be at most 100")
+	}
+	if autoStop.MaxAgentCpuPercent != nil && *autoStop.MaxAgentCpuPercent > 100 {
+		return errors.New("autoStop maxAgentCpuPercent must be at most 100")
+	}
+	if autoStop.ErrorPercentForSec < 0 {
+		return errors.New("autoStop errorPercentForSec must not be negative")
+	}
+	if autoStop.ErrorPercentForSec > 0 && autoStop.MaxErrorPercent == nil {
+		return errors.New("autoStop errorPercentForSec needs maxErrorPercent")
+	}
+	if autoStop.MaxAgentCpuPercent != nil && !withMetrics {
+		return errors.New("autoStop maxAgentCpuPercent needs collectAdditionalSystemMetrics")
+	}
+	return nil
+}
+
+// autoStopWatch checks the auto-stop rules of one run as its figures come in.
+type autoStopWatch struct {
+	rules *RunLoadTestAutoStopParam
+
+	mu sync.Mutex
+	// errorSince is the time of the figures the error rate was first seen above its limit at,
+	// of the spell it is above it now; zero while it is not.
+	errorSince time.Time
+	// fired tells the rule that stopped the run; "" until one has.
+	fired string
+}
+
+// newAutoStopWatch returns nil for a run without rules.
+func newAutoStopWatch(rules *RunLoadTestAutoStopParam) *autoStopWatch {
+	if rules == nil {
+		return nil
+	}
+	return &autoStopWatch{rules: rules}
+}
+
+// check returns the rule the figures, and the target's cpu when it is known, fire, told for
+// the run's steps; "" when none does, or one already has.
+func (w *autoStopWatch) check(figures *LiveFigures, agentCpu *float64) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fired != "" || figures.Samples == 0 {
+		return ""
+	}
+
+	if v := w.rules.MaxErrorPercent; v != nil {
+		if figures.ErrorPercent <= *v {
+			w.errorSince = time.Time{}
+		} else {
+			if w.errorSince.IsZero() {
+				w.errorSince = figures.At
+			}
+			if above := figures.At.Sub(w.errorSince); above >= time.Duration(w.rules.ErrorPercentForSec)*time.Second {
+				w.fired = fmt.Sprintf("%s: error rate %.2f%% over the last %ds has been above %.2f%% for %ds",
+					constant.ErrorPercentRule, figures.ErrorPercent, figures.WindowSec, *v, int(above/time.Second))
+				return w.fired
+			}
+		}
+	}
+	if v := w.rules.MaxP95; v != nil && figures.P95Elapsed > *v {
+		w.fired = fmt.Sprintf("%s: p95 %.2f ms over the last %ds is above %.2f ms",
+			constant.P95Rule, figures.P95Elapsed, figures.WindowSec, *v)
+		return w.fired
+	}
+	if v := w.rules.MaxAgentCpuPercent; v != nil && agentCpu != nil && *agentCpu > *v {
+		w.fired = fmt.Sprintf("%s: target cpu %.2f%% over the last %ds is above %.2f%%",
+			constant.AgentCpuRule, *agentCpu, figures.WindowSec, *v)
+		return w.fired
+	}
+	return ""
+}
+
+// firedRule tells the rule that stopped the run; "" for a run that was not stopped, or has no
+// rules.
+func (w *autoStopWatch) firedRule() string {
+	if w == nil {
+		return ""
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.fired
+}
+
+// rearm forgets a rule that fired without the run being stopped, so the next check can try
+// again.
+func (w *autoStopWatch) rearm() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.fired = ""
+}
+
+// checkAutoStop stops the run of f when one of its rules fires on figures.
+func (l *LoadService) checkAutoStop(f *fetchDataParam, figures *LiveFigures) {
+	var agentCpu *float64
+	if f.AutoStop.rules.MaxAgentCpuPercent != nil && f.CollectAdditionalSystemMetrics {
+		if f.agentCpu == nil {
+			f.agentCpu = &agentCpuTail{window: liveFiguresWindow}
+		}
+		cpu, err := f.agentCpu.read(fmt.Sprintf("%s/%s_cpu_result.csv", utils.JoinRootPathWith("/result/"+f.LoadTestKey), f.LoadTestKey))
+		if err != nil {
+			log.Debug().Msgf("no target cpu for load test %s yet; %v", f.LoadTestKey, err)
+		} else {
+			agentCpu = cpu
+		}
+	}
+
+	fired := f.AutoStop.check(figures, agentCpu)
+	if fired == "" {
+		return
+	}
+	log.Warn().Msgf("stopping load test %s; auto-stop rule %s", f.LoadTestKey, fired)
+	f.StepRec.progress(constant.StepJmeterRun, 0, "Stopping the load test - an auto-stop rule fired", fired)
+
+	if err := l.StopLoadTest(StopLoadTestParam{LoadTestKey: f.LoadTestKey}); err != nil {
+		log.Error().Msgf("failed to auto-stop load test %s; %v", f.LoadTestKey, err)
+		f.StepRec.progress(constant.StepJmeterRun, 0, "An auto-stop rule fired but the load test could not be stopped",
+			fmt.Sprintf("%s; %v", fired, err))
+		f.AutoStop.rearm()
+	}
+}
+
+// agentCpuTail follows the target's cpu file as it is fetched, holding only the readings within
+// the window of the latest one.
+type agentCpuTail struct {
+	file     fileTail
+	window   time.Duration
+	readings []*MetricsRawData
+}
+
+// read takes in the readings added to the cpu file at path since the last read, and returns
+// their average over the window; nil when there is no reading yet.
+func (t *agentCpuTail) read(path string) (*float64, error) {
+	err := t.file.read(path, func(rows io.Reader) error {
+		return scanMetricsRows(rows, path, func(label string, r *MetricsRawData) {
+			if label == agentCpuLabel && !r.IsError {
+				t.readings = append(t.readings, r)
+			}
+		})
+	})
+	// a part with no reading in it is only an error while none has come in
+	if err != nil && len(t.readings) == 0 {
+		return nil, err
+	}
+
+	var latest time.Time
+	for _, r := range t.readings {
+		if r.Timestamp.After(latest) {
+			latest = r.Timestamp
+		}
+	}
+	kept := t.readings[:0]
+	for _, r := range t.readings {
+		if r.Timestamp.After(latest.Add(-t.window)) {
+			kept = append(kept, r)
+		}
+	}
+	t.readings = kept
+	return agentCpuOf(t.readings, t.window), nil
+}
+
+// agentCpuOf averages the cpu readings in window up to the latest one; nil when there are none.
+func agentCpuOf(readings []*MetricsRawData, window time.Duration) *float64 {
+	var latest time.Time
+	for _, r := range readings {
+		if !r.IsError && r.Timestamp.After(latest) {
+			latest = r.Timestamp
+		}
+	}
+
+	from := latest.Add(-window)
+	sum, n := 0.0, 0
+	for _, r := range readings {
+		if r.IsError || !r.Timestamp.After(from) {
+			continue
+		}
+		v, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil {
+			continue
+		}
+		sum += v
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	avg := sum / float64(n)
+	return &avg
+}
@@ -0,0 +1,131 @@
+package load
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestAutoStopFiresOnAnErrorRateThatStaysUp(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	figures := func(sec int, errorPercent float64) *LiveFigures {
+		return &LiveFigures{At: start.Add(time.Duration(sec) * time.Second), WindowSec: 30, Samples: 100, ErrorPercent: errorPercent}
+	}
+	w := newAutoStopWatch(&RunLoadTestAutoStopParam{MaxErrorPercent: floatPtr(50), ErrorPercentForSec: 20})
+
+	// a spell that ends before its time does not count towards the next one
+	for _, f := range []*LiveFigures{figures(0, 80), figures(10, 90), figures(20, 10), figures(30, 60), figures(40, 70)} {
+		if fired := w.check(f, nil); fired != "" {
+			t.Fatalf("expected no rule to fire at %s, got %s", f.At.Sub(start), fired)
+		}
+	}
+	fired := w.check(figures(50, 75), nil)
+	if !strings.HasPrefix(fired, string(constant.ErrorPercentRule)+":") || !strings.Contains(fired, "for 20s") {
+		t.Fatalf("expected the error rate rule to fire after 20s, got %q", fired)
+	}
+	if w.firedRule() != fired || w.check(figures(60, 99), nil) != "" {
+		t.Errorf("expected the rule to fire once and be kept")
+	}
+
+	w.rearm()
+	if w.check(figures(70, 99), nil) == "" {
+		t.Errorf("expected a rearmed watch to fire again")
+	}
+}
+
+func TestAutoStopFiresOnP95AndTargetCpu(t *testing.T) {
+	f := &LiveFigures{At: time.Now(), WindowSec: 30, Samples: 10, P95Elapsed: 900}
+	rules := &RunLoadTestAutoStopParam{MaxP95: floatPtr(1000), MaxAgentCpuPercent: floatPtr(90)}
+
+	w := newAutoStopWatch(rules)
+	if fired := w.check(f, nil); fired != "" {
+		t.Errorf("expected no rule to fire without the cpu known, got %s", fired)
+	}
+	if fired := w.check(f, floatPtr(95)); !strings.HasPrefix(fired, string(constant.AgentCpuRule)+":") {
+		t.Errorf("expected the cpu rule to fire, got %q", fired)
+	}
+
+	w = newAutoStopWatch(rules)
+	f.P95Elapsed = 1200
+	if fired := w.check(f, floatPtr(10)); !strings.HasPrefix(fired, string(constant.P95Rule)+":") {
+		t.Errorf("expected the p95 rule to fire, got %q", fired)
+	}
+
+	if newAutoStopWatch(nil).firedRule() != "" {
+		t.Errorf("expected a run without rules never to be stopped")
+	}
+}
+
+func TestAgentCpuIsAveragedOverTheLatestWindow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	reading := func(sec int, value string) *MetricsRawData {
+		return &MetricsRawData{Value: value, Timestamp: start.Add(time.Duration(sec) * time.Second)}
+	}
+	readings := []*MetricsRawData{
+		reading(0, "10.000"), reading(40, "80.000"), reading(50, "90.000"), reading(60, "100.000"),
+		{IsError: true, Timestamp: start.Add(70 * time.Second)},
+	}
+
+	got := agentCpuOf(readings, 30*time.Second)
+	if got == nil || *got != 90 {
+		t.Errorf("expected 90, got %v", got)
+	}
+	if agentCpuOf(nil, 30*time.Second) != nil {
+		t.Errorf("expected no cpu without readings")
+	}
+}
+
+func TestAgentCpuTailReadsOnlyTheNewReadings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpu_result.csv")
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC).UnixMilli()
+	row := func(sec int64, milli int) string {
+		return fmt.Sprintf("%d,%d,perfmon 127.0.0.1 cpu_all_combined,,,,text,true,,0,0,1,1,,0,0,0\n", start+sec*1000, milli)
+	}
+	content := "timeStamp,elapsed,label,responseCode,responseMessage,threadName,dataType,success,failureMessage,bytes,sentBytes,grpThreads,allThreads,URL,Latency,IdleTime,Connect\n" +
+		row(0, 10000) + row(40, 80000)
+	if err := os.WriteFile(path, []byte(content+"17"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tail := &agentCpuTail{window: 30 * time.Second}
+	if got, err := tail.read(path); err != nil || got == nil || *got != 80 {
+		t.Fatalf("expected 80, got %v, %v", got, err)
+	}
+	content += row(50, 90000) + row(60, 100000)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := tail.read(path); err != nil || got == nil || *got != 90 {
+		t.Errorf("expected 90 over the latest window, got %v, %v", got, err)
+	}
+	if len(tail.readings) != 3 {
+		t.Errorf("expected only the readings of the window held, got %d", len(tail.readings))
+	}
+}
+
+func TestAutoStopRulesAreValidated(t *testing.T) {
+	cases := []struct {
+		name        string
+		autoStop    *RunLoadTestAutoStopParam
+		withMetrics bool
+		wantErr     bool
+	}{
+		{"none", nil, false, false},
+		{"error rate held", &RunLoadTestAutoStopParam{MaxErrorPercent: floatPtr(30), ErrorPercentForSec: 60}, false, false},
+		{"error rate above 100", &RunLoadTestAutoStopParam{MaxErrorPercent: floatPtr(101)}, false, true},
+		{"hold without a rate", &RunLoadTestAutoStopParam{MaxP95: floatPtr(500), ErrorPercentForSec: 60}, false, true},
+		{"negative p95", &RunLoadTestAutoStopParam{MaxP95: floatPtr(-1)}, false, true},
+		{"cpu without metrics", &RunLoadTestAutoStopParam{MaxAgentCpuPercent: floatPtr(90)}, false, true},
+		{"cpu with metrics", &RunLoadTestAutoStopParam{MaxAgentCpuPercent: floatPtr(90)}, true, false},
+	}
+	for _, c := range cases {
+		if err := ValidateAutoStop(c.autoStop, c.withMetrics); (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
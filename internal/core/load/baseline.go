@@ -0,0 +1,248 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A migration is judged by how the target does against how it did before. A run that finished
+// successfully can be pinned as the baseline of a scenario catalog entry or of a target node;
+// every run of either that finishes successfully afterwards is compared with it the way
+// CompareLoadTests compares runs, and the verdict is kept with the run's state. A run of a
+// catalog entry that has a baseline is held to that one wherever it was sent, which is how a
+// run against the source of a migration becomes the reference of the runs against its target.
+
+// ErrInvalidLoadTestBaseline is returned for a baseline that cannot be pinned as asked, so the
+// handler can answer with the caller's mistake rather than a server error.
+var ErrInvalidLoadTestBaseline = errors.New("load test baseline is not valid")
+
+// ErrLoadTestBaselineNotFound is returned for a baseline id that does not exist (any more).
+var ErrLoadTestBaselineNotFound = errors.New("load test baseline not found")
+
+// ValidateBaselineScope checks what a run is pinned as the baseline of: a scenario catalog
+// entry, a target node named in full, or neither.
+func ValidateBaselineScope(param PinLoadTestBaselineParam) error {
+	node := param.NsId != "" || param.InfraId != "" || param.NodeId != ""
+	if node && (param.NsId == "" || param.InfraId == "" || param.NodeId == "") {
+		return errors.New("a baseline target needs its nsId, infraId and nodeId together")
+	}
+	if node && param.ScenarioCatalogId != 0 {
+		return errors.New("a baseline is either of a scenario catalog entry or of a target node")
+	}
+	return nil
+}
+
+// PinLoadTestBaseline makes the run of param the baseline of its scope, in place of the one
+// the scope had. Runs already compared with the old one keep their verdict.
+func (l *LoadService) PinLoadTestBaseline(ctx context.Context, param PinLoadTestBaselineParam) (LoadTestBaselineResult, error) {
+	log.Info().Str("loadTestKey", param.LoadTestKey).Msg("Starting PinLoadTestBaseline")
+
+	if err := ValidateBaselineScope(param); err != nil {
+		return LoadTestBaselineResult{}, fmt.Errorf("%w; %v", ErrInvalidLoadTestBaseline, err)
+	}
+
+	state, err := l.loadRepo.GetLoadTestExecutionStateTx(ctx, GetLoadTestExecutionStateParam{LoadTestKey: param.LoadTestKey})
+	if err != nil {
+		return LoadTestBaselineResult{}, err
+	}
+	if state.ID == 0 {
+		return LoadTestBaselineResult{}, fmt.Errorf("%w: %s", ErrLoadTestNotFound, param.LoadTestKey)
+	}
+	if state.ExecutionStatus != constant.Successed {
+		return LoadTestBaselineResult{}, fmt.Errorf("%w; load test %s is %s, and only a successful run can be a baseline",
+			ErrInvalidLoadTestBaseline, param.LoadTestKey, state.ExecutionStatus)
+	}
+
+	baseline := LoadTestBaseline{
+		ScenarioCatalogId: param.ScenarioCatalogId,
+		NsId:              param.NsId,
+		InfraId:           param.InfraId,
+		NodeId:            param.NodeId,
+	}
+	if baseline.ScenarioCatalogId == 0 && baseline.NodeId == "" {
+		if state.NsId == "" || state.InfraId == "" || state.NodeId == "" {
+			return LoadTestBaselineResult{}, fmt.Errorf("%w; load test %s was not sent to a target node; give the scenario catalog entry or node it is the baseline of",
+				ErrInvalidLoadTestBaseline, param.LoadTestKey)
+		}
+		baseline.NsId, baseline.InfraId, baseline.NodeId = state.NsId, state.InfraId, state.NodeId
+	}
+	if baseline.ScenarioCatalogId != 0 {
+		if _, err := l.GetLoadTestScenarioCatalog(ctx, baseline.ScenarioCatalogId); err != nil {
+			return LoadTestBaselineResult{}, fmt.Errorf("%w; scenario catalog %d not found", ErrInvalidLoadTestBaseline, baseline.ScenarioCatalogId)
+		}
+	}
+
+	// the row of the scope, deleted or not, is taken over in place, so pins of one scope at
+	// once leave it a single baseline
+	baseline.LoadTestKey = param.LoadTestKey
+	err = l.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scenario_catalog_id"}, {Name: "ns_id"}, {Name: "infra_id"}, {Name: "node_id"}},
+		DoUpdates: clause.Assignments(map[string]any{"load_test_key": baseline.LoadTestKey, "updated_at": time.Now(), "deleted_at": nil}),
+	}).Create(&baseline).Error
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save load test baseline")
+		return LoadTestBaselineResult{}, err
+	}
+	pinned, found, err := l.findLoadTestBaseline(ctx, baseline.ScenarioCatalogId, baseline.NsId, baseline.InfraId, baseline.NodeId)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read the pinned load test baseline")
+		return LoadTestBaselineResult{}, err
+	}
+	if found {
+		baseline = pinned
+	}
+
+	log.Info().Uint("baselineId", baseline.ID).Str("loadTestKey", baseline.LoadTestKey).Msg("Successfully pinned load test baseline")
+	return toLoadTestBaselineResult(baseline), nil
+}
+
+// GetAllLoadTestBaselines lists the pinned baselines.
+func (l *LoadService) GetAllLoadTestBaselines(ctx context.Context, param GetAllLoadTestBaselinesParam) (GetAllLoadTestBaselinesResult, error) {
+	log.Info().Msg("Starting GetAllLoadTestBaselines")
+
+	var baselines []LoadTestBaseline
+	var totalCount int64
+
+	query := l.db.WithContext(ctx).Model(&LoadTestBaseline{}).Where("deleted_at IS NULL")
+	if param.ScenarioCatalogId != 0 {
+		query = query.Where("scenario_catalog_id = ?", param.ScenarioCatalogId)
+	}
+	if param.NsId != "" {
+		query = query.Where("ns_id = ?", param.NsId)
+	}
+	if param.InfraId != "" {
+		query = query.Where("infra_id = ?", param.InfraId)
+	}
+	if param.NodeId != "" {
+		query = query.Where("node_id = ?", param.NodeId)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to count load test baselines")
+		return GetAllLoadTestBaselinesResult{}, err
+	}
+
+	offset := (param.Page - 1) * param.Size
+	if err := query.Offset(offset).Limit(param.Size).Order("created_at DESC").Find(&baselines).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to get load test baselines")
+		return GetAllLoadTestBaselinesResult{}, err
+	}
+
+	results := []LoadTestBaselineResult{}
+	for _, baseline := range baselines {
+		results = append(results, toLoadTestBaselineResult(baseline))
+	}
+
+	log.Info().Int64("totalCount", totalCount).Int("returnedCount", len(results)).Msg("Successfully retrieved load test baselines")
+	return GetAllLoadTestBaselinesResult{LoadTestBaselines: results, TotalRow: totalCount}, nil
+}
+
+// DeleteLoadTestBaseline unpins a baseline. Runs already compared with it keep their verdict.
+func (l *LoadService) DeleteLoadTestBaseline(ctx context.Context, id uint) error {
+	log.Info().Uint("baselineId", id).Msg("Starting DeleteLoadTestBaseline")
+
+	var baseline LoadTestBaseline
+	err := l.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&baseline).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLoadTestBaselineNotFound
+		}
+		log.Error().Err(err).Msg("Failed to check existing load test baseline")
+		return err
+	}
+
+	if err := l.db.WithContext(ctx).Delete(&baseline).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test baseline")
+		return err
+	}
+
+	log.Info().Uint("baselineId", id).Msg("Successfully deleted load test baseline")
+	return nil
+}
+
+// findLoadTestBaseline returns the baseline of a scenario catalog entry, or of a target node
+// when scenarioCatalogId is 0.
+func (l *LoadService) findLoadTestBaseline(ctx context.Context, scenarioCatalogId uint, nsId, infraId, nodeId string) (LoadTestBaseline, bool, error) {
+	var baseline LoadTestBaseline
+	query := l.db.WithContext(ctx).Where("scenario_catalog_id = ? AND deleted_at IS NULL", scenarioCatalogId)
+	if scenarioCatalogId == 0 {
+		query = query.Where("ns_id = ? AND infra_id = ? AND node_id = ?", nsId, infraId, nodeId)
+	}
+	err := query.First(&baseline).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return baseline, false, nil
+		}
+		return baseline, false, err
+	}
+	return baseline, true, nil
+}
+
+// baselineOf is the baseline a run is held to: that of its scenario catalog entry, else that
+// of its target node.
+func (l *LoadService) baselineOf(ctx context.Context, state *LoadTestExecutionState) (LoadTestBaseline, bool, error) {
+	if state.ScenarioCatalogId != 0 {
+		baseline, found, err := l.findLoadTestBaseline(ctx, state.ScenarioCatalogId, "", "", "")
+		if err != nil || found {
+			return baseline, found, err
+		}
+	}
+	if state.NsId == "" || state.InfraId == "" || state.NodeId == "" {
+		return LoadTestBaseline{}, false, nil
+	}
+	return l.findLoadTestBaseline(ctx, 0, state.NsId, state.InfraId, state.NodeId)
+}
+
+// judgeBaseline compares a run that finished successfully with its baseline, and records the
+// verdict on its state. A run without a baseline, or that is its own, is left without one.
+func (l *LoadService) judgeBaseline(ctx context.Context, state *LoadTestExecutionState) {
+	baseline, found, err := l.baselineOf(ctx, state)
+	if err != nil {
+		log.Error().Msgf("could not look up the baseline of %s; %v", state.LoadTestKey, err)
+		return
+	}
+	if !found || baseline.LoadTestKey == state.LoadTestKey {
+		return
+	}
+
+	result, err := l.CompareLoadTests(CompareLoadTestsParam{LoadTestKeys: []string{baseline.LoadTestKey, state.LoadTestKey}})
+	state.BaselineLoadTestKey = baseline.LoadTestKey
+	state.BaselineVerdict, state.BaselineMessage = baselineVerdictOf(result, err)
+	log.Info().Msgf("baseline verdict of %s against %s is %s", state.LoadTestKey, baseline.LoadTestKey, state.BaselineVerdict)
+}
+
+// baselineVerdictOf turns the comparison of a run with its baseline into the verdict kept with
+// the run, and the message that goes with it.
+func baselineVerdictOf(result LoadTestComparisonResult, err error) (constant.BaselineVerdict, string) {
+	if err != nil {
+		return constant.BaselineInconclusive, fmt.Sprintf("comparison with the baseline could not be made; %v", err)
+	}
+	if len(result.Runs) == 0 {
+		return constant.BaselineInconclusive, "comparison with the baseline has no result"
+	}
+	if compared := result.Runs[0]; compared.Regressed {
+		return constant.BaselineRegressed, strings.Join(compared.Regressions, "; ")
+	}
+	return constant.BaselinePass, ""
+}
+
+func toLoadTestBaselineResult(baseline LoadTestBaseline) LoadTestBaselineResult {
+	return LoadTestBaselineResult{
+		ID:                baseline.ID,
+		LoadTestKey:       baseline.LoadTestKey,
+		ScenarioCatalogId: baseline.ScenarioCatalogId,
+		NsId:              baseline.NsId,
+		InfraId:           baseline.InfraId,
+		NodeId:            baseline.NodeId,
+		CreatedAt:         baseline.CreatedAt,
+		UpdatedAt:         baseline.UpdatedAt,
+	}
+}
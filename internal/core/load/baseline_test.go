@@ -0,0 +1,75 @@
+package load
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestBaselineScopeIsValidated(t *testing.T) {
+	cases := []struct {
+		name    string
+		param   PinLoadTestBaselineParam
+		wantErr bool
+	}{
+		{"the run's own node", PinLoadTestBaselineParam{LoadTestKey: "a"}, false},
+		{"a catalog entry", PinLoadTestBaselineParam{LoadTestKey: "a", ScenarioCatalogId: 3}, false},
+		{"a node in full", PinLoadTestBaselineParam{LoadTestKey: "a", NsId: "ns", InfraId: "infra", NodeId: "node"}, false},
+		{"a node in part", PinLoadTestBaselineParam{LoadTestKey: "a", NsId: "ns", NodeId: "node"}, true},
+		{"both", PinLoadTestBaselineParam{LoadTestKey: "a", ScenarioCatalogId: 3, NsId: "ns", InfraId: "infra", NodeId: "node"}, true},
+	}
+	for _, c := range cases {
+		if err := ValidateBaselineScope(c.param); (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
+
+func TestBaselineVerdictOfAComparison(t *testing.T) {
+	verdict, message := baselineVerdictOf(LoadTestComparisonResult{Runs: []ComparedLoadTestResult{{LoadTestKey: "b"}}}, nil)
+	if verdict != constant.BaselinePass || message != "" {
+		t.Errorf("expected a run without regressions to pass, got %s %q", verdict, message)
+	}
+
+	regressed := ComparedLoadTestResult{LoadTestKey: "b", Regressed: true, Regressions: []string{"login average went up", "total throughput went down"}}
+	verdict, message = baselineVerdictOf(LoadTestComparisonResult{Regressed: true, Runs: []ComparedLoadTestResult{regressed}}, nil)
+	if verdict != constant.BaselineRegressed || message != "login average went up; total throughput went down" {
+		t.Errorf("expected the regressions told, got %s %q", verdict, message)
+	}
+
+	verdict, message = baselineVerdictOf(LoadTestComparisonResult{}, errors.New("reading the result of a; gone"))
+	if verdict != constant.BaselineInconclusive || message == "" {
+		t.Errorf("expected a comparison that could not be made to be inconclusive, got %s %q", verdict, message)
+	}
+}
+
+func TestRunOfAScenarioCarriesItsLoad(t *testing.T) {
+	scenario := RunLoadTestParam{VirtualUsers: "10", Duration: "60", RampUpTime: "10", RampUpSteps: "2"}
+	spike := RunLoadTestParam{LoadShape: constant.SpikeShape, VirtualUsers: "10", Duration: "60", RampUpTime: "10", SpikeUsers: "50", SpikeDuration: "5"}
+	stages := RunLoadTestParam{LoadShape: constant.StagesShape, Stages: []RunLoadTestStageParam{{Target: 10, Duration: 30}}}
+	thinking := scenario
+	thinking.ThinkTime = &RunLoadTestThinkTimeParam{Type: constant.ConstantThinkTime, Delay: 500}
+
+	cases := []struct {
+		name     string
+		run      RunLoadTestParam
+		scenario RunLoadTestParam
+		wantErr  bool
+	}{
+		{"the same load", RunLoadTestParam{LoadShape: constant.ConcurrencyShape, VirtualUsers: "10", Duration: "60", RampUpTime: "10", RampUpSteps: "2"}, scenario, false},
+		{"more users", RunLoadTestParam{VirtualUsers: "20", Duration: "60", RampUpTime: "10", RampUpSteps: "2"}, scenario, true},
+		{"another shape", spike, scenario, true},
+		{"steps a spike does not read", func() RunLoadTestParam { r := spike; r.RampUpSteps = "4"; return r }(), spike, false},
+		{"a bigger spike", func() RunLoadTestParam { r := spike; r.SpikeUsers = "80"; return r }(), spike, true},
+		{"the same stages", stages, stages, false},
+		{"other stages", RunLoadTestParam{LoadShape: constant.StagesShape, Stages: []RunLoadTestStageParam{{Target: 20, Duration: 30}}}, stages, true},
+		{"a think time of its own", thinking, scenario, false},
+		{"not the scenario's think time", scenario, thinking, true},
+	}
+	for _, c := range cases {
+		if err := scenarioMismatchOf(c.run, c.scenario); (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
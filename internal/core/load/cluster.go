@@ -0,0 +1,317 @@
+package load
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/infra/outbound/tumblebug"
+)
+
+// A cluster is one generator server that leads - the master - and the workers beside it, all in
+// the generator MCI. A run on a cluster splits its load between them: every server runs the same
+// plan with its share of the users, on its own, and writes its own result file. The fetch step
+// pulls every file and merges them into the one result the run is read from.
+//
+// JMeter's own remote mode is not used for this. It needs the RMI ports open between the
+// generators, and it streams every sample back through the master, which then becomes the
+// bottleneck the workers were added to remove.
+
+// MaxClusterSize bounds the generator servers one run is split across.
+const MaxClusterSize = 10
+
+// clusterServers returns the servers a run on the generator uses, master first. A generator
+// that is not a cluster uses its master alone, even when the MCI holds more VMs. The workers
+// are taken from the master's region only: a generator installed for runs from several regions
+// holds servers in the others too, which send the load of their own region.
+func clusterServers(loadGeneratorInstallInfo *LoadGeneratorInstallInfo) []LoadGeneratorServer {
+	var servers []LoadGeneratorServer
+	for _, s := range serversMasterFirst(loadGeneratorInstallInfo) {
+		if len(servers) == 0 || inRegion(s.Csp, s.Region, servers[0].Csp, servers[0].Region) {
+			servers = append(servers, s)
+		}
+	}
+
+	size := 1
+	if loadGeneratorInstallInfo.IsCluster && loadGeneratorInstallInfo.ClusterSize > 1 {
+		size = int(loadGeneratorInstallInfo.ClusterSize)
+	}
+	if len(servers) > size {
+		servers = servers[:size]
+	}
+	return servers
+}
+
+// serversMasterFirst returns every server of the generator, the master first and the others
+// in the order they were added.
+func serversMasterFirst(loadGeneratorInstallInfo *LoadGeneratorInstallInfo) []LoadGeneratorServer {
+	servers := append([]LoadGeneratorServer{}, loadGeneratorInstallInfo.LoadGeneratorServers...)
+	sort.SliceStable(servers, func(i, j int) bool {
+		if servers[i].IsMaster != servers[j].IsMaster {
+			return servers[i].IsMaster
+		}
+		return servers[i].ID < servers[j].ID
+	})
+	return servers
+}
+
+// runServers returns the servers a run uses, the one that collects the metrics first, and
+// what each of them runs. A run from several regions uses a server in each of them; otherwise
+// the run uses the cluster, master first. A generator without recorded servers is reached as
+// a whole MCI, as it was before clusters.
+func runServers(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) ([]LoadGeneratorServer, []RunLoadTestParam, error) {
+	if len(param.Regions) > 0 {
+		servers, err := regionServers(param.Regions, loadGeneratorInstallInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		return servers, regionShares(param), nil
+	}
+
+	servers := clusterServers(loadGeneratorInstallInfo)
+	if len(servers) == 0 {
+		return []LoadGeneratorServer{{}}, []RunLoadTestParam{param}, nil
+	}
+	shares := clusterShares(param, len(servers))
+	return servers[:len(shares)], shares, nil
+}
+
+// fetchWorkersOf lists the servers of a run whose result files the fetch step pulls beside
+// the first one's.
+func fetchWorkersOf(servers []LoadGeneratorServer) []fetchWorker {
+	var workers []fetchWorker
+	for _, s := range servers[min(1, len(servers)):] {
+		workers = append(workers, fetchWorker{Username: s.Username, PublicIp: s.PublicIp})
+	}
+	return workers
+}
+
+// share is server i's part of total split n ways. What does not divide evenly goes to the
+// first servers, so the master carries the most.
+func share(total, i, n int) int {
+	s := total / n
+	if i < total%n {
+		s++
+	}
+	return s
+}
+
+// clusterShares splits the load of param across at most n servers and returns what each of
+// them runs. A server is only given a share when it gets at least one user, or one iteration a
+// second, so a run smaller than the cluster uses fewer servers. Only the master collects the
+// target's system metrics; they describe the target, not the generator.
+//
+// An uploaded plan decides its own load, which cannot be split from here, so it runs on the
+// master alone.
+func clusterShares(param RunLoadTestParam, n int) []RunLoadTestParam {
+	if n <= 1 || param.TestPlan != nil {
+		return []RunLoadTestParam{param}
+	}
+	n = max(1, min(n, peakOf(param)))
+	return splitLoad(param, n, func(total, i int) int { return share(total, i, n) })
+}
+
+// peakOf is the most users, or iterations a second, param has going at once.
+func peakOf(param RunLoadTestParam) int {
+	users, _ := strconv.Atoi(param.VirtualUsers)
+	switch loadShapeOf(param) {
+	case constant.StagesShape:
+		peak := 0
+		for _, s := range param.Stages {
+			peak = max(peak, s.Target)
+		}
+		return peak
+	case constant.ArrivalRateShape:
+		rate, _ := strconv.Atoi(param.ArrivalRate)
+		return min(users, rate)
+	case constant.SpikeShape:
+		spike, _ := strconv.Atoi(param.SpikeUsers)
+		return max(users, spike)
+	}
+	return users
+}
+
+// splitLoad returns n copies of param, the i-th of which carries part(total, i) of every load
+// figure of param. Only the first collects the target's system metrics.
+func splitLoad(param RunLoadTestParam, n int, part func(total, i int) int) []RunLoadTestParam {
+	shape := loadShapeOf(param)
+	users, _ := strconv.Atoi(param.VirtualUsers)
+	rate, _ := strconv.Atoi(param.ArrivalRate)
+	spikeUsers, _ := strconv.Atoi(param.SpikeUsers)
+
+	shares := make([]RunLoadTestParam, n)
+	for i := range shares {
+		p := param
+		p.VirtualUsers = strconv.Itoa(part(users, i))
+		if shape == constant.ArrivalRateShape {
+			p.ArrivalRate = strconv.Itoa(part(rate, i))
+		}
+		if shape == constant.SpikeShape {
+			p.SpikeUsers = strconv.Itoa(part(spikeUsers, i))
+		}
+		if shape == constant.StagesShape {
+			p.Stages = make([]RunLoadTestStageParam, len(param.Stages))
+			for j, s := range param.Stages {
+				p.Stages[j] = RunLoadTestStageParam{Target: part(s.Target, i), Duration: s.Duration}
+			}
+		}
+		p.CollectAdditionalSystemMetrics = param.CollectAdditionalSystemMetrics && i == 0
+		shares[i] = p
+	}
+	return shares
+}
+
+// commandToServer runs cmd on one generator server. A server recorded without a node id can
+// only be reached through the whole MCI, which is what every command did before clusters.
+func (l *LoadService) commandToServer(ctx context.Context, nsId, mciId string, server LoadGeneratorServer, cmd string) (string, error) {
+	req := tumblebug.SendCommandReq{Command: []string{cmd}}
+	if server.NodeId == "" {
+		return l.tumblebugClient.CommandToMciWithContext(ctx, nsId, mciId, req)
+	}
+	return l.tumblebugClient.CommandToVmWithContext(ctx, nsId, mciId, server.NodeId, req)
+}
+
+// resultPartFileName names the result file of server i of a cluster once it is fetched.
+func resultPartFileName(loadTestKey string, i int) string {
+	return fmt.Sprintf("%s_result_part%d.csv", loadTestKey, i)
+}
+
+// resultMerge is how far the merge of the result parts of a run has got: the bytes of each part
+// taken in, and the length of the merged file they made. It is kept beside the merged file, so
+// every fetch appends only what the parts gained since the last one, even across a restart.
+type resultMerge struct {
+	Merged int64   `json:"merged"`
+	Parts  []int64 `json:"parts"`
+}
+
+func resultMergeFileName(dst string) string {
+	return dst + ".merge"
+}
+
+// readResultMerge returns how far the merge into dst has got; from the start when it has not
+// been saved for as many parts.
+func readResultMerge(dst string, parts int) resultMerge {
+	var m resultMerge
+	content, err := os.ReadFile(resultMergeFileName(dst))
+	if err != nil || json.Unmarshal(content, &m) != nil || len(m.Parts) != parts {
+		return resultMerge{Parts: make([]int64, parts)}
+	}
+	return m
+}
+
+func (m resultMerge) save(dst string) error {
+	content, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	tmp := resultMergeFileName(dst) + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, resultMergeFileName(dst))
+}
+
+// mergeResultParts appends to dst the rows every part gained since the last merge, the header
+// of the first part leading. A part is fetched while its server still writes to it, so a last
+// line without its newline is left for the next merge rather than cut in half. What was
+// appended after the merge was last saved is dropped and taken again.
+//
+// The rows of the parts follow one another, so the merged file is in time order within each
+// part only. Nothing that reads it may take it to be in order, and nothing does: statistics,
+// samples and buckets are added up by the timestamp of each row, and the live figures take in
+// rows out of order by the fetch interval, which is well within their window.
+func mergeResultParts(dst string, parts []string) error {
+	m := readResultMerge(dst, len(parts))
+	for i, part := range parts {
+		info, err := os.Stat(part)
+		if err != nil {
+			return err
+		}
+		if info.Size() < m.Parts[i] {
+			// fetched anew from its start
+			m = resultMerge{Parts: make([]int64, len(parts))}
+			break
+		}
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := out.Truncate(m.Merged); err != nil {
+		return err
+	}
+	if _, err := out.Seek(m.Merged, io.SeekStart); err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		taken, written, err := appendResultPart(out, part, m.Parts[i], i > 0)
+		if err != nil {
+			return fmt.Errorf("reading %s; %w", part, err)
+		}
+		m.Parts[i] = taken
+		m.Merged += written
+	}
+	return m.save(dst)
+}
+
+// appendResultPart copies the complete lines of part from the byte from on to w, without the
+// header when skipHeader is set. It returns how far the part has been taken and how many bytes
+// were written.
+func appendResultPart(w io.Writer, part string, from int64, skipHeader bool) (int64, int64, error) {
+	f, err := os.Open(part)
+	if err != nil {
+		return from, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return from, 0, err
+	}
+
+	end, err := lastLineEnd(f, from, info.Size())
+	if err != nil || end == from {
+		return from, 0, err
+	}
+	start := from
+	if start == 0 && skipHeader {
+		// every part repeats the header
+		header, err := bufio.NewReader(io.NewSectionReader(f, 0, end)).ReadBytes('\n')
+		if err != nil {
+			return from, 0, err
+		}
+		start = int64(len(header))
+	}
+	written, err := io.Copy(w, io.NewSectionReader(f, start, end-start))
+	if err != nil {
+		return from, written, err
+	}
+	return end, written, nil
+}
+
+// lastLineEnd returns the offset just past the last newline of f between from and to; from when
+// there is none. It reads backwards from to, so only the last line is read to find it.
+func lastLineEnd(f *os.File, from, to int64) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for end := to; end > from; {
+		start := max(from, end-int64(len(buf)))
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return from, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return from, nil
+}
@@ -0,0 +1,201 @@
+package load
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestClusterSharesSplitTheLoad(t *testing.T) {
+	param := RunLoadTestParam{VirtualUsers: "10", Duration: "60", RampUpTime: "10", CollectAdditionalSystemMetrics: true}
+	shares := clusterShares(param, 3)
+	if len(shares) != 3 {
+		t.Fatalf("expected 3 shares, got %d", len(shares))
+	}
+	for i, want := range []string{"4", "3", "3"} {
+		if shares[i].VirtualUsers != want {
+			t.Errorf("share %d: expected %s users, got %s", i, want, shares[i].VirtualUsers)
+		}
+		if shares[i].CollectAdditionalSystemMetrics != (i == 0) {
+			t.Errorf("share %d: expected only the master to collect metrics", i)
+		}
+	}
+
+	if shares := clusterShares(RunLoadTestParam{VirtualUsers: "2"}, 5); len(shares) != 2 {
+		t.Errorf("expected a run of 2 users to use 2 servers, got %d", len(shares))
+	}
+
+	staged := RunLoadTestParam{LoadShape: constant.StagesShape, Stages: []RunLoadTestStageParam{
+		{Target: 5, Duration: 10}, {Target: 1, Duration: 20},
+	}}
+	shares = clusterShares(staged, 2)
+	if shares[0].Stages[0].Target != 3 || shares[1].Stages[0].Target != 2 ||
+		shares[0].Stages[1].Target != 1 || shares[1].Stages[1].Target != 0 {
+		t.Errorf("expected every stage target to be split, got %+v and %+v", shares[0].Stages, shares[1].Stages)
+	}
+	if staged.Stages[0].Target != 5 {
+		t.Error("expected the run's own stages to be left as they were")
+	}
+
+	// a spike peaks at its spike users, which are split like the rest
+	spike := RunLoadTestParam{LoadShape: constant.SpikeShape, VirtualUsers: "1", SpikeUsers: "6", Duration: "60", SpikeDuration: "10"}
+	if shares := clusterShares(spike, 3); len(shares) != 3 || shares[0].SpikeUsers != "2" {
+		t.Errorf("expected a spike of 6 users over a single one to use 3 servers, got %+v", shares)
+	}
+
+	if shares := clusterShares(RunLoadTestParam{VirtualUsers: "10", TestPlan: &RunLoadTestPlanParam{}}, 3); len(shares) != 1 {
+		t.Errorf("expected an uploaded plan to run on the master alone, got %d shares", len(shares))
+	}
+}
+
+func TestClusterServersPutTheMasterFirst(t *testing.T) {
+	info := &LoadGeneratorInstallInfo{IsCluster: true, ClusterSize: 2, LoadGeneratorServers: []LoadGeneratorServer{
+		{VmName: "w1"}, {VmName: "m", IsMaster: true}, {VmName: "w2"},
+	}}
+	info.LoadGeneratorServers[0].ID, info.LoadGeneratorServers[1].ID, info.LoadGeneratorServers[2].ID = 2, 3, 1
+
+	servers := clusterServers(info)
+	if len(servers) != 2 || servers[0].VmName != "m" || servers[1].VmName != "w2" {
+		t.Errorf("expected the master and the oldest worker, got %+v", servers)
+	}
+
+	info.IsCluster = false
+	if servers := clusterServers(info); len(servers) != 1 || servers[0].VmName != "m" {
+		t.Errorf("expected a generator that is not a cluster to use its master alone, got %+v", servers)
+	}
+}
+
+func TestClusterServersStayInTheMastersRegion(t *testing.T) {
+	info := &LoadGeneratorInstallInfo{IsCluster: true, ClusterSize: 3, LoadGeneratorServers: []LoadGeneratorServer{
+		{VmName: "m", IsMaster: true, Csp: "aws", Region: "ap-northeast-2"},
+		{VmName: "tokyo", Csp: "aws", Region: "ap-northeast-1"},
+		{VmName: "gcp", Csp: "gcp", Region: "ap-northeast-2"},
+		{VmName: "w1", Csp: "aws", Region: "ap-northeast-2"},
+		{VmName: "w2", Csp: "AWS", Region: "ap-northeast-2"},
+	}}
+	for i := range info.LoadGeneratorServers {
+		info.LoadGeneratorServers[i].ID = uint(i + 1)
+	}
+
+	servers := clusterServers(info)
+	if len(servers) != 3 || servers[0].VmName != "m" || servers[1].VmName != "w1" || servers[2].VmName != "w2" {
+		t.Errorf("expected the master and the workers beside it, not the other regions' servers, got %+v", servers)
+	}
+}
+
+func TestMergeResultPartsKeepsOneHeader(t *testing.T) {
+	dir := t.TempDir()
+	parts := []string{filepath.Join(dir, "p0.csv"), filepath.Join(dir, "p1.csv")}
+	if err := os.WriteFile(parts[0], []byte("timeStamp,elapsed\n1,10\n2,20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the worker was still writing its last line when it was fetched
+	if err := os.WriteFile(parts[1], []byte("timeStamp,elapsed\n3,30\n4,4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "result.csv")
+	if err := mergeResultParts(dst, parts); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "timeStamp,elapsed\n1,10\n2,20\n3,30\n"; string(got) != want {
+		t.Errorf("expected\n%q\ngot\n%q", want, got)
+	}
+}
+
+func TestMergeResultPartsAppendsWhatThePartsGained(t *testing.T) {
+	dir := t.TempDir()
+	parts := []string{filepath.Join(dir, "p0.csv"), filepath.Join(dir, "p1.csv")}
+	write := func(part, content string) {
+		if err := os.WriteFile(part, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	merged := func() string {
+		got, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(got)
+	}
+	merge := func() {
+		if err := mergeResultParts(filepath.Join(dir, "result.csv"), parts); err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+	}
+
+	// the worker has not written its header out yet
+	write(parts[0], "timeStamp,elapsed\n1,10\n")
+	write(parts[1], "timeSt")
+	merge()
+	write(parts[0], "timeStamp,elapsed\n1,10\n2,20\n")
+	write(parts[1], "timeStamp,elapsed\n3,3")
+	merge()
+	write(parts[1], "timeStamp,elapsed\n3,30\n")
+	merge()
+	merge()
+	if want := "timeStamp,elapsed\n1,10\n2,20\n3,30\n"; merged() != want {
+		t.Errorf("expected every row once\n%q\ngot\n%q", want, merged())
+	}
+
+	// a part fetched anew is merged again from the start
+	write(parts[0], "timeStamp,elapsed\n5,50\n")
+	merge()
+	if want := "timeStamp,elapsed\n5,50\n3,30\n"; merged() != want {
+		t.Errorf("expected\n%q\ngot\n%q", want, merged())
+	}
+}
+
+func TestMergedResultReadsAsInTimeOrder(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	// the two servers send at once, so their rows interleave in time
+	first := comparisonSamples(start, repeated(100, 30), 3)
+	second := comparisonSamples(start.Add(50*time.Millisecond), repeated(200, 30), 1)
+	for i, r := range first {
+		r.Bytes, r.ActiveThreads = 100+i, 2
+	}
+
+	dir := t.TempDir()
+	parts := []string{writeResultFile(t, map[string][]*ResultRawData{"login": first}), writeResultFile(t, map[string][]*ResultRawData{"login": second})}
+	merged := filepath.Join(dir, "result.csv")
+	if err := mergeResultParts(merged, parts); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	inOrder := slices.Concat(first, second)
+	slices.SortStableFunc(inOrder, func(a, b *ResultRawData) int { return a.Timestamp.Compare(b.Timestamp) })
+	sorted := writeResultFile(t, map[string][]*ResultRawData{"login": inOrder})
+
+	read := func(path string) ([]*LoadTestStatistics, LoadTestTimeSeries) {
+		digest, err := digestResult(jmeterEngine{}, path, resultSampleIntervalMs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		series := newTimeSeries(timeSeriesWindowOf(TimeSeriesWindow{IntervalMs: 500}))
+		if err := (jmeterEngine{}).ScanResult(path, series.add); err != nil {
+			t.Fatal(err)
+		}
+		result, err := series.result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return digest.statistics(), result
+	}
+	gotStatistics, gotSeries := read(merged)
+	wantStatistics, wantSeries := read(sorted)
+	if !reflect.DeepEqual(gotStatistics, wantStatistics) {
+		t.Errorf("expected the statistics of the merged parts to be those of the rows in order, got %+v, want %+v", gotStatistics[0], wantStatistics[0])
+	}
+	if !reflect.DeepEqual(gotSeries, wantSeries) {
+		t.Errorf("expected the time series of the merged parts to be that of the rows in order, got %+v, want %+v", gotSeries, wantSeries)
+	}
+}
@@ -0,0 +1,605 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/config"
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// The statistics of a run are fetched one run at a time, so telling whether a target got worse
+// after a migration meant setting two results side by side by hand. A comparison takes the
+// first run it names as the baseline and sets every other run against it, label by label and
+// target metric by target metric. A change is flagged as a regression only when it goes past
+// its tolerance in the worse direction and is unlikely to be chance: response times by a
+// Mann-Whitney U test on those of every request, the error rate by a two-proportion test,
+// throughput by a test on the two request rates, and target metrics by Welch's test on the
+// readings. A run whose raw result was discarded has only its stored statistics, which leave
+// its response times untested.
+
+// comparisonTotalLabel is the row that takes all of the requests of a run together.
+const comparisonTotalLabel = "total"
+
+// comparisonDefaults are the tolerances used when neither the request nor the config sets one.
+var comparisonDefaults = LoadTestComparisonTolerance{
+	ResponseTimePercent: 10,
+	ErrorPercentPoints:  1,
+	ThroughputPercent:   10,
+	MetricPercent:       10,
+	Significance:        0.05,
+}
+
+// comparisonJudge is how a statistic is judged; the others are reported only.
+type comparisonJudge int
+
+const (
+	notJudged comparisonJudge = iota
+	responseTimeJudged
+	errorRateJudged
+	throughputJudged
+)
+
+// comparedStatistics are the statistics of LoadTestStatistics a comparison reports, by their
+// json names.
+var comparedStatistics = []struct {
+	name  string
+	of    func(s *LoadTestStatistics) float64
+	judge comparisonJudge
+}{
+	{"requestCount", func(s *LoadTestStatistics) float64 { return float64(s.RequestCount) }, notJudged},
+	{"average", func(s *LoadTestStatistics) float64 { return s.Average }, responseTimeJudged},
+	{"median", func(s *LoadTestStatistics) float64 { return s.Median }, responseTimeJudged},
+	{"ninetyPercent", func(s *LoadTestStatistics) float64 { return s.NinetyPercent }, responseTimeJudged},
+	{"ninetyFive", func(s *LoadTestStatistics) float64 { return s.NinetyFive }, responseTimeJudged},
+	{"ninetyNine", func(s *LoadTestStatistics) float64 { return s.NinetyNine }, responseTimeJudged},
+	{"minTime", func(s *LoadTestStatistics) float64 { return s.MinTime }, notJudged},
+	{"maxTime", func(s *LoadTestStatistics) float64 { return s.MaxTime }, notJudged},
+	{"errorPercent", func(s *LoadTestStatistics) float64 { return s.ErrorPercent }, errorRateJudged},
+	{"throughput", func(s *LoadTestStatistics) float64 { return s.Throughput }, throughputJudged},
+	{"receivedKB", func(s *LoadTestStatistics) float64 { return s.ReceivedKB }, notJudged},
+	{"sentKB", func(s *LoadTestStatistics) float64 { return s.SentKB }, notJudged},
+}
+
+// metricWorse tells the direction a target metric gets worse in: 1 when it goes up, -1 when it
+// goes down. The metrics not in it, such as disk and network traffic, follow the load and are
+// reported only.
+var metricWorse = map[string]float64{
+	"cpu_all_combined":   1,
+	"cpu_all_idle":       -1,
+	"memory_all_used":    1,
+	"memory_all_used_kb": 1,
+	"memory_all_free":    -1,
+	"memory_all_free_kb": -1,
+	"disk_use":           1,
+}
+
+// ValidateComparison checks a comparison before its runs are read. The tolerances are checked
+// as given; the ones left out are the config's.
+func ValidateComparison(param CompareLoadTestsParam) error {
+	if len(param.LoadTestKeys) < 2 {
+		return errors.New("at least two load test keys are needed to compare")
+	}
+	seen := make(map[string]bool, len(param.LoadTestKeys))
+	for _, k := range param.LoadTestKeys {
+		if k == "" {
+			return errors.New("load test keys must not be empty")
+		}
+		if seen[k] {
+			return fmt.Errorf("load test %s is named more than once", k)
+		}
+		seen[k] = true
+	}
+
+	tolerances := []struct {
+		name  string
+		value *float64
+	}{
+		{"responseTimePercent", param.ResponseTimePercent},
+		{"errorPercentPoints", param.ErrorPercentPoints},
+		{"throughputPercent", param.ThroughputPercent},
+		{"metricPercent", param.MetricPercent},
+	}
+	for _, t := range tolerances {
+		if t.value != nil && *t.value < 0 {
+			return fmt.Errorf("comparison %s must not be negative", t.name)
+		}
+	}
+	if v := param.Significance; v != nil && (*v <= 0 || *v >= 1) {
+		return errors.New("comparison significance must be between 0 and 1")
+	}
+	return nil
+}
+
+// comparisonTolerance is the tolerance of param: each one given, else the config's, else the
+// built-in default.
+func comparisonTolerance(param CompareLoadTestsParam) LoadTestComparisonTolerance {
+	c := config.AppConfig.Load.Comparison
+	pick := func(given *float64, configured, fallback float64) float64 {
+		if given != nil {
+			return *given
+		}
+		if configured > 0 {
+			return configured
+		}
+		return fallback
+	}
+	d := comparisonDefaults
+	return LoadTestComparisonTolerance{
+		ResponseTimePercent: pick(param.ResponseTimePercent, c.ResponseTimePercent, d.ResponseTimePercent),
+		ErrorPercentPoints:  pick(param.ErrorPercentPoints, c.ErrorPercentPoints, d.ErrorPercentPoints),
+		ThroughputPercent:   pick(param.ThroughputPercent, c.ThroughputPercent, d.ThroughputPercent),
+		MetricPercent:       pick(param.MetricPercent, c.MetricPercent, d.MetricPercent),
+		Significance:        pick(param.Significance, c.Significance, d.Significance),
+	}
+}
+
+// comparisonRun is what a comparison reads of one run.
+type comparisonRun struct {
+	loadTestKey string
+	labels      map[string]*comparedLabel
+	total       *comparedLabel
+	// metrics are the target's readings by label; nil for a run that did not collect them.
+	metrics map[string][]*MetricsRawData
+	// fromStatistics is set for a run read from its stored statistics, its raw result having
+	// been discarded.
+	fromStatistics bool
+}
+
+// comparedLabel is what a comparison reads of one label of a run, or of all of them together.
+type comparedLabel struct {
+	statistics   *LoadTestStatistics
+	requestCount int
+	errorCount   int
+	spanSeconds  float64
+	// elapsed are the response times of every request; nil for a run read from its stored
+	// statistics, whose response times then cannot be tested for a change.
+	elapsed *elapsedHistogram
+}
+
+// comparisonRunOf returns a run read from its raw result.
+func comparisonRunOf(loadTestKey string, results *resultDigest) *comparisonRun {
+	labelOf := func(label string, a *statisticsAccumulator) *comparedLabel {
+		if a.requestCount == 0 {
+			return nil
+		}
+		return &comparedLabel{
+			statistics:   a.statistics(label),
+			requestCount: a.requestCount,
+			errorCount:   a.errorCount,
+			spanSeconds:  a.spanSeconds(),
+			elapsed:      &a.elapsed,
+		}
+	}
+
+	run := &comparisonRun{loadTestKey: loadTestKey, labels: make(map[string]*comparedLabel, len(results.labels))}
+	for label, ld := range results.labels {
+		if l := labelOf(label, &ld.statisticsAccumulator); l != nil {
+			run.labels[label] = l
+		}
+	}
+	run.total = labelOf(comparisonTotalLabel, &results.total)
+	return run
+}
+
+// storedComparisonRunOf returns a run read from the stored statistics of the run as a whole;
+// false for a result stored without the row of all of its labels together.
+func storedComparisonRunOf(loadTestKey string, rows []LoadTestResultStatistic) (*comparisonRun, bool) {
+	run := &comparisonRun{loadTestKey: loadTestKey, labels: make(map[string]*comparedLabel), fromStatistics: true}
+	for _, row := range rows {
+		if row.Provider != "" || row.Region != "" || row.RequestCount == 0 {
+			continue
+		}
+		l := &comparedLabel{
+			statistics:   toLoadTestStatistics(row),
+			requestCount: row.RequestCount,
+			errorCount:   row.ErrorCount,
+			spanSeconds:  row.SpanSeconds,
+		}
+		if row.Total {
+			run.total = l
+		} else {
+			run.labels[row.Label] = l
+		}
+	}
+	return run, run.total != nil
+}
+
+// CompareLoadTests sets every run of param after the first against the first.
+func (l *LoadService) CompareLoadTests(param CompareLoadTestsParam) (LoadTestComparisonResult, error) {
+	if err := ValidateComparison(param); err != nil {
+		return LoadTestComparisonResult{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	runs := make([]*comparisonRun, 0, len(param.LoadTestKeys))
+	for _, k := range param.LoadTestKeys {
+		run, err := l.readComparisonRun(ctx, k)
+		if err != nil {
+			return LoadTestComparisonResult{}, err
+		}
+		runs = append(runs, run)
+	}
+
+	tol := comparisonTolerance(param)
+	result := LoadTestComparisonResult{
+		BaselineLoadTestKey: runs[0].loadTestKey,
+		Tolerance:           tol,
+		Runs:                make([]ComparedLoadTestResult, 0, len(runs)-1),
+	}
+	for _, run := range runs[1:] {
+		compared := compareRuns(runs[0], run, tol)
+		result.Regressed = result.Regressed || compared.Regressed
+		result.Runs = append(result.Runs, compared)
+	}
+	return result, nil
+}
+
+// readComparisonRun reads the result of a run, and its target metrics when it collected them.
+// Metrics that cannot be read leave the run without them rather than fail the comparison.
+func (l *LoadService) readComparisonRun(ctx context.Context, loadTestKey string) (*comparisonRun, error) {
+	state, err := l.loadRepo.GetLoadTestExecutionStateTx(ctx, GetLoadTestExecutionStateParam{LoadTestKey: loadTestKey})
+	if err != nil {
+		return nil, err
+	}
+	if state.ID == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrLoadTestNotFound, loadTestKey)
+	}
+
+	engine, err := engineFor(state.Engine)
+	if err != nil {
+		engine, _ = engineFor(constant.Jmeter)
+	}
+	resultPath := fmt.Sprintf("%s/%s_result.csv", utils.JoinRootPathWith("/result/"+loadTestKey), loadTestKey)
+	if !utils.ExistCheck(resultPath) {
+		// the files of a stored result may have been discarded; its statistics stand in for them
+		if run, found := l.storedComparisonRun(ctx, loadTestKey); found {
+			return run, nil
+		}
+	}
+	results, err := digestResult(engine, resultPath, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reading the result of %s; %w", loadTestKey, err)
+	}
+
+	run := comparisonRunOf(loadTestKey, results)
+	if state.WithMetrics {
+		metrics, err := readLoadTestMetrics(loadTestKey)
+		if err != nil {
+			log.Warn().Msgf("comparing load test %s without its target metrics; %v", loadTestKey, err)
+		} else {
+			run.metrics = metrics
+		}
+	}
+	return run, nil
+}
+
+// storedComparisonRun reads a run for comparison from its stored statistics, which keep the
+// counts and the span of each label and of the run as a whole. The response times of every
+// request are not kept, so a change in them is reported but not tested.
+func (l *LoadService) storedComparisonRun(ctx context.Context, loadTestKey string) (*comparisonRun, bool) {
+	stored, found, err := l.storedLoadTestResult(ctx, loadTestKey)
+	if err != nil || !found {
+		return nil, false
+	}
+	rows, err := l.storedStatistics(ctx, stored.ID)
+	if err != nil {
+		log.Warn().Msgf("could not read the stored statistics of %s; %v", loadTestKey, err)
+		return nil, false
+	}
+
+	run, ok := storedComparisonRunOf(loadTestKey, rows)
+	if !ok {
+		return nil, false
+	}
+	if stored.WithMetrics {
+		if metrics, err := l.storedMetrics(ctx, stored.ID); err == nil {
+			run.metrics = metrics
+		}
+	}
+	return run, true
+}
+
+// compareRuns sets run against base. Labels are taken in order with the total last; metrics are
+// compared only when both runs have them.
+func compareRuns(base, run *comparisonRun, tol LoadTestComparisonTolerance) ComparedLoadTestResult {
+	compared := ComparedLoadTestResult{LoadTestKey: run.loadTestKey}
+
+	labels := make(map[string]bool)
+	for label := range base.labels {
+		labels[label] = true
+	}
+	for label := range run.labels {
+		labels[label] = true
+	}
+	ordered := make([]string, 0, len(labels))
+	for label := range labels {
+		ordered = append(ordered, label)
+	}
+	sort.Strings(ordered)
+
+	for _, label := range ordered {
+		lc, regressions := compareLabel(label, base.labels[label], run.labels[label], tol)
+		compared.Labels = append(compared.Labels, lc)
+		compared.Regressions = append(compared.Regressions, regressions...)
+	}
+	lc, regressions := compareLabel(comparisonTotalLabel, base.total, run.total, tol)
+	compared.Labels = append(compared.Labels, lc)
+	compared.Regressions = append(compared.Regressions, regressions...)
+
+	for _, r := range []*comparisonRun{base, run} {
+		if r.fromStatistics {
+			compared.Untested = append(compared.Untested,
+				fmt.Sprintf("response times were not tested for a change: the raw result of %s was discarded", r.loadTestKey))
+		}
+	}
+
+	if base.metrics != nil && run.metrics != nil {
+		metrics, regressions := compareMetrics(base.metrics, run.metrics, tol)
+		compared.Metrics = metrics
+		compared.Regressions = append(compared.Regressions, regressions...)
+	}
+
+	compared.Regressed = len(compared.Regressions) > 0
+	return compared
+}
+
+// compareLabel sets one label of both runs against each other, and tells the statistics that
+// regressed. A label a run did not send is nil. Response times are judged only when both runs
+// have them all.
+func compareLabel(label string, base, run *comparedLabel, tol LoadTestComparisonTolerance) (LabelComparisonResult, []string) {
+	lc := LabelComparisonResult{Label: label}
+	switch {
+	case base == nil && run == nil:
+		return lc, nil
+	case base == nil:
+		lc.MissingIn = constant.BaselineSide
+		return lc, nil
+	case run == nil:
+		lc.MissingIn = constant.RunSide
+		return lc, nil
+	}
+
+	var slower *float64
+	if base.elapsed != nil && run.elapsed != nil {
+		p := mannWhitneyGreaterOf(base.elapsed, run.elapsed)
+		slower = &p
+	}
+	moreErrors := twoProportionGreater(base.errorCount, base.requestCount, run.errorCount, run.requestCount)
+	lowerRate := rateLower(base.requestCount, base.spanSeconds, run.requestCount, run.spanSeconds)
+
+	var regressions []string
+	for _, s := range comparedStatistics {
+		c := StatisticComparisonResult{Name: s.name, Baseline: s.of(base.statistics), Value: s.of(run.statistics)}
+		// a rate over a label sent within one millisecond has no value
+		if !isFinite(c.Baseline) || !isFinite(c.Value) {
+			continue
+		}
+		c.Delta = c.Value - c.Baseline
+		c.DeltaPercent = deltaPercentOf(c.Baseline, c.Delta)
+
+		switch s.judge {
+		case responseTimeJudged:
+			c.PValue = slower
+			c.Regression = slower != nil && c.DeltaPercent != nil && *c.DeltaPercent > tol.ResponseTimePercent && *slower < tol.Significance
+		case errorRateJudged:
+			c.PValue = &moreErrors
+			c.Regression = c.Delta > tol.ErrorPercentPoints && moreErrors < tol.Significance
+		case throughputJudged:
+			c.PValue = &lowerRate
+			c.Regression = c.DeltaPercent != nil && -*c.DeltaPercent > tol.ThroughputPercent && lowerRate < tol.Significance
+		}
+		if c.Regression {
+			regressions = append(regressions, fmt.Sprintf("%s %s went from %.2f to %.2f (p=%.4f)", label, s.name, c.Baseline, c.Value, *c.PValue))
+		}
+		lc.Statistics = append(lc.Statistics, c)
+	}
+	return lc, regressions
+}
+
+// compareMetrics sets the average of every target metric in both runs against each other, and
+// tells the metrics that regressed.
+func compareMetrics(base, run map[string][]*MetricsRawData, tol LoadTestComparisonTolerance) ([]MetricComparisonResult, []string) {
+	labels := make(map[string]bool)
+	for label := range base {
+		labels[label] = true
+	}
+	for label := range run {
+		labels[label] = true
+	}
+	ordered := make([]string, 0, len(labels))
+	for label := range labels {
+		ordered = append(ordered, label)
+	}
+	sort.Strings(ordered)
+
+	var compared []MetricComparisonResult
+	var regressions []string
+	for _, label := range ordered {
+		bv, bUnit := readingsOf(base[label])
+		rv, rUnit := readingsOf(run[label])
+		m := MetricComparisonResult{Label: label, Unit: bUnit}
+		switch {
+		case len(bv) == 0 && len(rv) == 0:
+			// failed readings only
+			continue
+		case len(bv) == 0:
+			m.Unit, m.MissingIn = rUnit, constant.BaselineSide
+			compared = append(compared, m)
+			continue
+		case len(rv) == 0:
+			m.MissingIn = constant.RunSide
+			compared = append(compared, m)
+			continue
+		}
+
+		m.Baseline, m.Value = meanOf(bv), meanOf(rv)
+		m.Delta = m.Value - m.Baseline
+		m.DeltaPercent = deltaPercentOf(m.Baseline, m.Delta)
+		if worse, ok := metricWorse[label]; ok {
+			p := welchGreater(bv, rv)
+			if worse < 0 {
+				p = welchGreater(rv, bv)
+			}
+			m.PValue = &p
+			m.Regression = m.DeltaPercent != nil && *m.DeltaPercent*worse > tol.MetricPercent && p < tol.Significance
+		}
+		if m.Regression {
+			regressions = append(regressions, fmt.Sprintf("target %s went from %.2f to %.2f %s (p=%.4f)", label, m.Baseline, m.Value, m.Unit, *m.PValue))
+		}
+		compared = append(compared, m)
+	}
+	return compared, regressions
+}
+
+// deltaPercentOf is delta as a percentage of base; nil when base is 0.
+func deltaPercentOf(base, delta float64) *float64 {
+	if base == 0 {
+		return nil
+	}
+	p := delta / math.Abs(base) * 100
+	return &p
+}
+
+func isFinite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+// readingsOf returns the values of the readings that did not fail, and their unit.
+func readingsOf(readings []*MetricsRawData) ([]float64, string) {
+	var values []float64
+	var unit string
+	for _, r := range readings {
+		if r.IsError {
+			continue
+		}
+		v, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil {
+			continue
+		}
+		values = append(values, v)
+		unit = r.Unit
+	}
+	return values, unit
+}
+
+func meanOf(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// normalUpperTail is the chance a standard normal value is above z.
+func normalUpperTail(z float64) float64 {
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+// mannWhitneyGreaterOf is the one-sided p-value of the response times counted in b tending to
+// be larger than those in a, by the Mann-Whitney U test in its normal approximation, corrected
+// for ties and continuity. Response times are far from normal, so their ranks are compared
+// rather than their means. The values of a bucket are ranked as ties, which they are below
+// elapsedExactLimit.
+func mannWhitneyGreaterOf(a, b *elapsedHistogram) float64 {
+	buckets := make(map[int]bool)
+	for _, bucket := range a.buckets() {
+		buckets[bucket] = true
+	}
+	for _, bucket := range b.buckets() {
+		buckets[bucket] = true
+	}
+	ordered := make([]int, 0, len(buckets))
+	for bucket := range buckets {
+		ordered = append(ordered, bucket)
+	}
+	sort.Ints(ordered)
+
+	// tied values share the average of their ranks
+	var rankSumB, ties float64
+	i := 0
+	for _, bucket := range ordered {
+		inB := b.countOf(bucket)
+		j := i + a.countOf(bucket) + inB
+		rank := float64(i+j+1) / 2
+		t := float64(j - i)
+		ties += t*t*t - t
+		rankSumB += rank * float64(inB)
+		i = j
+	}
+
+	na, nb, n := float64(a.total), float64(b.total), float64(a.total+b.total)
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	u := rankSumB - nb*(nb+1)/2
+	variance := na * nb / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	return normalUpperTail((u - na*nb/2 - 0.5) / math.Sqrt(variance))
+}
+
+// twoProportionGreater is the one-sided p-value of the second proportion, x2 of n2, being
+// larger than the first, by the pooled two-proportion z-test.
+func twoProportionGreater(x1, n1, x2, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	p1, p2 := float64(x1)/float64(n1), float64(x2)/float64(n2)
+	pooled := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 1
+	}
+	return normalUpperTail((p2 - p1) / se)
+}
+
+// rateLower is the one-sided p-value of the second rate, c2 events over t2 seconds, being
+// lower than the first, taking the counts as Poisson.
+func rateLower(c1 int, t1 float64, c2 int, t2 float64) float64 {
+	if t1 <= 0 || t2 <= 0 {
+		return 1
+	}
+	r1, r2 := float64(c1)/t1, float64(c2)/t2
+	se := math.Sqrt(float64(c1)/(t1*t1) + float64(c2)/(t2*t2))
+	if se == 0 {
+		return 1
+	}
+	return normalUpperTail((r1 - r2) / se)
+}
+
+// welchGreater is the one-sided p-value of b's mean being larger than a's, by Welch's test in
+// its normal approximation; there are plenty of readings in a run for it. Readings that do not
+// vary at all leave no doubt either way.
+func welchGreater(a, b []float64) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 1
+	}
+	ma, mb := meanOf(a), meanOf(b)
+	se := math.Sqrt(varianceOf(a, ma)/float64(len(a)) + varianceOf(b, mb)/float64(len(b)))
+	if se == 0 {
+		if mb > ma {
+			return 0
+		}
+		return 1
+	}
+	return normalUpperTail((mb - ma) / se)
+}
+
+// varianceOf is the sample variance of values around their mean.
+func varianceOf(values []float64, mean float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values)-1)
+}
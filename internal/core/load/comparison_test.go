@@ -0,0 +1,215 @@
+package load
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func comparisonSamples(start time.Time, elapsed []int, errors int) []*ResultRawData {
+	var results []*ResultRawData
+	for i, e := range elapsed {
+		results = append(results, &ResultRawData{
+			Elapsed:   e,
+			IsError:   i < errors,
+			Timestamp: start.Add(time.Duration(i) * 100 * time.Millisecond),
+		})
+	}
+	return results
+}
+
+func repeated(value, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = value + i%10
+	}
+	return values
+}
+
+func histogramOf(values ...int) *elapsedHistogram {
+	var h elapsedHistogram
+	for _, v := range values {
+		h.add(v)
+	}
+	return &h
+}
+
+func statisticOf(lc LabelComparisonResult, name string) StatisticComparisonResult {
+	for _, s := range lc.Statistics {
+		if s.Name == name {
+			return s
+		}
+	}
+	return StatisticComparisonResult{}
+}
+
+func TestComparisonFlagsASignificantSlowdown(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	base := comparisonRunOf("before", digestOf(t, map[string][]*ResultRawData{
+		"login": comparisonSamples(start, repeated(100, 200), 0),
+		"list":  comparisonSamples(start, repeated(50, 200), 0),
+		"old":   comparisonSamples(start, repeated(50, 10), 0),
+	}, 0))
+	run := comparisonRunOf("after", digestOf(t, map[string][]*ResultRawData{
+		"login": comparisonSamples(start, repeated(150, 200), 0),
+		"list":  comparisonSamples(start, repeated(51, 200), 0),
+		"new":   comparisonSamples(start, repeated(50, 10), 0),
+	}, 0))
+
+	got := compareRuns(base, run, comparisonDefaults)
+	var labels []string
+	for _, lc := range got.Labels {
+		labels = append(labels, lc.Label)
+	}
+	if strings.Join(labels, ",") != "list,login,new,old,total" {
+		t.Fatalf("expected the labels of both runs in order with the total last, got %v", labels)
+	}
+	if got.Labels[2].MissingIn != constant.BaselineSide || got.Labels[3].MissingIn != constant.RunSide || len(got.Labels[3].Statistics) != 0 {
+		t.Errorf("expected the labels of one run only to be marked, got %+v %+v", got.Labels[2], got.Labels[3])
+	}
+
+	login := statisticOf(got.Labels[1], "average")
+	if !login.Regression || login.Delta != 50 || login.DeltaPercent == nil || *login.DeltaPercent < 45 || *login.PValue > 0.001 {
+		t.Errorf("expected login to be flagged 50 ms slower, got %+v", login)
+	}
+	// 2% slower is within the tolerance, however sure the test is of it
+	if list := statisticOf(got.Labels[0], "average"); list.Regression {
+		t.Errorf("expected list not to be flagged, got %+v", list)
+	}
+	if count := statisticOf(got.Labels[1], "requestCount"); count.PValue != nil || count.Regression {
+		t.Errorf("expected the request count to be reported only, got %+v", count)
+	}
+	if !got.Regressed || len(got.Regressions) == 0 || !strings.HasPrefix(got.Regressions[0], "login ") {
+		t.Errorf("expected the regressions of login told, got %v", got.Regressions)
+	}
+}
+
+func TestComparisonNeedsMoreThanTheTolerance(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	// a few samples that are slower are not enough to tell
+	base := comparisonRunOf("a", digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, []int{100, 300}, 0)}, 0))
+	run := comparisonRunOf("b", digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, []int{110, 400}, 0)}, 0))
+	if got := compareRuns(base, run, comparisonDefaults); got.Regressed {
+		t.Errorf("expected two samples not to be significant, got %v", got.Regressions)
+	}
+
+	// errors going from 1% to 20% of many requests are
+	base = comparisonRunOf("a", digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, repeated(100, 500), 5)}, 0))
+	run = comparisonRunOf("b", digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, repeated(100, 500), 100)}, 0))
+	got := compareRuns(base, run, comparisonDefaults)
+	if e := statisticOf(got.Labels[0], "errorPercent"); !e.Regression || e.Delta != 19 {
+		t.Errorf("expected the error rate to be flagged, got %+v", e)
+	}
+}
+
+func TestComparisonOfADiscardedRunIsFromItsStoredStatistics(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	base := comparisonRunOf("before", digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, repeated(100, 500), 5)}, 0))
+	// samples are one per bucket, so a run read back from them would count a tenth of these
+	digest := digestOf(t, map[string][]*ResultRawData{"x": comparisonSamples(start, repeated(150, 500), 100)}, resultSampleIntervalMs)
+	run, ok := storedComparisonRunOf("after", loadTestResultOf("after", digest).Statistics)
+	if !ok {
+		t.Fatal("expected a stored result with its total to be comparable")
+	}
+
+	got := compareRuns(base, run, comparisonDefaults)
+	x := got.Labels[0]
+	if count := statisticOf(x, "requestCount"); count.Value != 500 {
+		t.Errorf("expected every request counted, got %+v", count)
+	}
+	if e := statisticOf(x, "errorPercent"); !e.Regression || e.Delta != 19 {
+		t.Errorf("expected the error rate judged from the stored counts, got %+v", e)
+	}
+	if avg := statisticOf(x, "average"); avg.Regression || avg.PValue != nil || avg.Delta <= 0 {
+		t.Errorf("expected the slowdown reported but not tested, got %+v", avg)
+	}
+	if got.Labels[1].Label != comparisonTotalLabel || statisticOf(got.Labels[1], "requestCount").Value != 500 {
+		t.Errorf("expected the stored total last, got %+v", got.Labels[1])
+	}
+	if len(got.Untested) != 1 || !strings.Contains(got.Untested[0], "after") {
+		t.Errorf("expected the untested response times told, got %v", got.Untested)
+	}
+
+	if _, ok := storedComparisonRunOf("old", loadTestResultOf("old", digest).Statistics[:1]); ok {
+		t.Error("expected a result stored without its total not to be comparable")
+	}
+}
+
+func TestComparisonOfTargetMetrics(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	readings := func(unit string, values ...string) []*MetricsRawData {
+		var r []*MetricsRawData
+		for i, v := range values {
+			r = append(r, &MetricsRawData{Value: v, Unit: unit, Timestamp: start.Add(time.Duration(i) * time.Second)})
+		}
+		return append(r, &MetricsRawData{IsError: true})
+	}
+	base := map[string][]*MetricsRawData{
+		"cpu_all_combined": readings("%", "40", "42", "41", "39"),
+		"cpu_all_idle":     readings("%", "60", "58", "59", "61"),
+		"disk_read_kb":     readings("kb", "10", "20"),
+	}
+	run := map[string][]*MetricsRawData{
+		"cpu_all_combined": readings("%", "80", "82", "81", "79"),
+		"cpu_all_idle":     readings("%", "20", "18", "19", "21"),
+		"disk_read_kb":     readings("kb", "100", "200"),
+		"disk_use":         readings("%", "50"),
+	}
+
+	got, regressions := compareMetrics(base, run, comparisonDefaults)
+	if len(got) != 4 || len(regressions) != 2 {
+		t.Fatalf("expected four metrics with cpu busy and idle regressed, got %+v %v", got, regressions)
+	}
+	if got[0].Label != "cpu_all_combined" || !got[0].Regression || got[0].Baseline != 40.5 || got[0].Value != 80.5 {
+		t.Errorf("expected the busy cpu to be flagged, got %+v", got[0])
+	}
+	if got[1].Label != "cpu_all_idle" || !got[1].Regression {
+		t.Errorf("expected less idle cpu to be flagged, got %+v", got[1])
+	}
+	if got[2].Label != "disk_read_kb" || got[2].Regression || got[2].PValue != nil || got[2].Unit != "kb" {
+		t.Errorf("expected disk reads to be reported only, got %+v", got[2])
+	}
+	if got[3].MissingIn != constant.BaselineSide {
+		t.Errorf("expected the disk use to be missing in the baseline, got %+v", got[3])
+	}
+}
+
+func TestComparisonIsValidated(t *testing.T) {
+	cases := []struct {
+		name    string
+		param   CompareLoadTestsParam
+		wantErr bool
+	}{
+		{"two runs", CompareLoadTestsParam{LoadTestKeys: []string{"a", "b"}}, false},
+		{"one run", CompareLoadTestsParam{LoadTestKeys: []string{"a"}}, true},
+		{"same run twice", CompareLoadTestsParam{LoadTestKeys: []string{"a", "b", "a"}}, true},
+		{"negative tolerance", CompareLoadTestsParam{LoadTestKeys: []string{"a", "b"}, ThroughputPercent: floatPtr(-1)}, true},
+		{"zero tolerance", CompareLoadTestsParam{LoadTestKeys: []string{"a", "b"}, ResponseTimePercent: floatPtr(0)}, false},
+		{"significance of 1", CompareLoadTestsParam{LoadTestKeys: []string{"a", "b"}, Significance: floatPtr(1)}, true},
+	}
+	for _, c := range cases {
+		if err := ValidateComparison(c.param); (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
+
+func TestSignificanceTests(t *testing.T) {
+	if p := mannWhitneyGreaterOf(histogramOf(1, 2, 3, 4, 5, 6, 7, 8), histogramOf(11, 12, 13, 14, 15, 16, 17, 18)); p > 0.001 {
+		t.Errorf("expected clearly larger samples to be significant, got %v", p)
+	}
+	if p := mannWhitneyGreaterOf(histogramOf(5, 5, 5), histogramOf(5, 5, 5)); p != 1 {
+		t.Errorf("expected equal samples not to be, got %v", p)
+	}
+	if p := twoProportionGreater(10, 100, 10, 100); p < 0.5 {
+		t.Errorf("expected the same proportion not to be significant, got %v", p)
+	}
+	if p := rateLower(1000, 10, 500, 10); p > 0.001 {
+		t.Errorf("expected half the rate to be significant, got %v", p)
+	}
+	if p := welchGreater([]float64{1, 1}, []float64{2, 2}); p != 0 {
+		t.Errorf("expected steady readings that went up to leave no doubt, got %v", p)
+	}
+}
@@ -0,0 +1,163 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a standard five field cron expression: minute, hour, day of month, month and
+// day of week. Each field is a set of the values it matches, as bits.
+//
+// A field is *, a value, a range a-b or a list of them, each optionally stepped by /n. Days of
+// the week are 0 to 7, both 0 and 7 being Sunday. As in cron, when both day fields are
+// restricted a day matches either of them. The @yearly, @monthly, @weekly, @daily and @hourly
+// shorthands are understood; names of months and days are not.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record a * day field, which then leaves the choice to the other one
+	domAny, dowAny bool
+}
+
+var cronShorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron reads a cron expression.
+func parseCron(spec string) (cronSchedule, error) {
+	var c cronSchedule
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := cronShorthands[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return c, fmt.Errorf("cron expression %q must have 5 fields, has %d", spec, len(fields))
+	}
+
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return c, fmt.Errorf("minute; %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return c, fmt.Errorf("hour; %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return c, fmt.Errorf("day of month; %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return c, fmt.Errorf("month; %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return c, fmt.Errorf("day of week; %w", err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return c, nil
+}
+
+// parseCronField reads one field whose values run from lo to hi.
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("step %q is not a positive number", part[i+1:])
+			}
+			rangePart, step = part[:i], n
+		}
+
+		from, to := lo, hi
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			from, err1 = strconv.Atoi(bounds[0])
+			to, err2 = strconv.Atoi(bounds[1])
+			if err := errors.Join(err1, err2); err != nil {
+				return 0, fmt.Errorf("range %q is not two numbers", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("%q is not a number", rangePart)
+			}
+			from, to = n, n
+			if step > 1 {
+				to = hi // n/step runs from n to the end
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return 0, fmt.Errorf("%q is out of %d to %d", rangePart, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func cronHas(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
+
+// dayMatches reports whether the day of t is one the schedule fires on.
+func (c cronSchedule) dayMatches(t time.Time) bool {
+	dom := cronHas(c.dom, t.Day())
+	dow := cronHas(c.dow, int(t.Weekday()))
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next returns the first minute after t the schedule fires at, in the location of t. It is the
+// zero time when the schedule never fires, such as on the 30th of February.
+func (c cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+
+	// every month and weekday combination comes round within a few years
+	limit := t.Year() + 5
+	for t.Year() <= limit {
+		if !cronHas(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cronHas(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !cronHas(c.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,45 @@
+package load
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNextFiresOnTheFollowingMatch(t *testing.T) {
+	from := time.Date(2026, 3, 31, 22, 30, 15, 0, time.UTC) // a Tuesday
+	for spec, want := range map[string]time.Time{
+		"*/15 * * * *":     time.Date(2026, 3, 31, 22, 45, 0, 0, time.UTC),
+		"@daily":           time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		"0 2 * * 1-5":      time.Date(2026, 4, 1, 2, 0, 0, 0, time.UTC),
+		"0 9 * * 7":        time.Date(2026, 4, 5, 9, 0, 0, 0, time.UTC),
+		"30 22 31 * *":     time.Date(2026, 5, 31, 22, 30, 0, 0, time.UTC),
+		"0 0 13 * 5":       time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), // the 13th or any Friday
+		"0 12 29 2 *":      time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		"5,10 8-9/1 * * *": time.Date(2026, 4, 1, 8, 5, 0, 0, time.UTC),
+	} {
+		c, err := parseCron(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if got := c.next(from); !got.Equal(want) {
+			t.Errorf("%s: expected %s, got %s", spec, want, got)
+		}
+	}
+}
+
+func TestCronRejectsWhatItCannotRead(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * JAN *"} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+
+	c, err := parseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := c.next(time.Now()); !next.IsZero() {
+		t.Errorf("expected the 30th of February never to come, got %s", next)
+	}
+}
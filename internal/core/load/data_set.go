@@ -0,0 +1,180 @@
+package load
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// A data set feeds a run the values a fixed scenario cannot make up - users to log in as, ids
+// to look up, payloads to send. It is a CSV file whose header line names its columns, and it
+// travels to the generator with the plan like any other attachment.
+
+// dataSetColumn is what a column name must look like to be used as ${name}.
+var dataSetColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DataSetColumns reads the header line of a data set and counts the lines under it. Every
+// line must have as many fields as the header, which is also what keeps JMeter from silently
+// leaving the last columns of a short line unset.
+func DataSetColumns(content []byte) ([]string, int, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, 0, errors.New("data set is empty")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("data set is not valid csv; %w", err)
+	}
+
+	seen := make(map[string]bool, len(header))
+	columns := make([]string, 0, len(header))
+	for _, h := range header {
+		name := strings.TrimSpace(h)
+		if !dataSetColumn.MatchString(name) {
+			return nil, 0, fmt.Errorf("data set column %q cannot be used as a variable name", h)
+		}
+		if seen[name] {
+			return nil, 0, fmt.Errorf("data set column %s appears twice", name)
+		}
+		seen[name] = true
+		columns = append(columns, name)
+	}
+
+	rows := 0
+	for {
+		_, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("data set is not valid csv; %w", err)
+		}
+		rows++
+	}
+	if rows == 0 {
+		return nil, 0, errors.New("data set has no lines under its header")
+	}
+	return columns, rows, nil
+}
+
+// IsSupportedShareMode reports whether data sets can be shared the given way.
+func IsSupportedShareMode(mode constant.DataSetShareMode) bool {
+	switch mode {
+	case constant.ShareAll, constant.ShareGroup, constant.ShareThread:
+		return true
+	}
+	return false
+}
+
+// ValidateDataSets checks the data sets of a run once their content is known. A column that
+// two data sets share would be overwritten by whichever JMeter reads last, so it is refused,
+// and together they must fit what a test can carry to the generator.
+func ValidateDataSets(dataSets []RunLoadTestDataSetParam) error {
+	owner := map[string]string{}
+	size := 0
+	for _, ds := range dataSets {
+		size += len(ds.Content)
+		name := strings.TrimSpace(ds.FileName)
+		if name == "" || strings.ContainsAny(name, "/\\") {
+			return fmt.Errorf("data set file name %q is not valid", ds.FileName)
+		}
+		if !IsSupportedShareMode(shareModeOf(ds)) {
+			return fmt.Errorf("data set share mode %q is not supported", ds.ShareMode)
+		}
+		columns, _, err := DataSetColumns(ds.Content)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, c := range columns {
+			if other, ok := owner[c]; ok {
+				return fmt.Errorf("data set column %s is in both %s and %s", c, other, name)
+			}
+			owner[c] = name
+		}
+	}
+	if size > MaxPlanAttachmentBytes {
+		return fmt.Errorf("data sets are larger than %d bytes", MaxPlanAttachmentBytes)
+	}
+	return nil
+}
+
+// shareModeOf returns how a data set is shared; all users share one when it does not say.
+func shareModeOf(ds RunLoadTestDataSetParam) constant.DataSetShareMode {
+	if ds.ShareMode == "" {
+		return constant.ShareAll
+	}
+	return ds.ShareMode
+}
+
+// dataSetFileName is where a data set is written next to the plan. The prefix keeps it apart
+// from the multipart files of the requests, which may have the same name.
+func dataSetFileName(index int, fileName string) string {
+	return fmt.Sprintf("data_%d_%s", index, filepath.Base(fileName))
+}
+
+// jmxDataSetTemplate reads a data set a line per iteration. The header line is skipped and its
+// names are given as the variable names instead, so they are the ones that were validated.
+var jmxDataSetTemplate = `
+		<CSVDataSet guiclass="TestBeanGUI" testclass="CSVDataSet" testname="{{.FileName}} Data Set" enabled="true">
+			<stringProp name="filename">{{.Path}}</stringProp>
+			<stringProp name="fileEncoding">UTF-8</stringProp>
+			<stringProp name="variableNames">{{.VariableNames}}</stringProp>
+			<boolProp name="ignoreFirstLine">true</boolProp>
+			<stringProp name="delimiter">,</stringProp>
+			<boolProp name="quotedData">true</boolProp>
+			<boolProp name="recycle">{{.Recycle}}</boolProp>
+			<boolProp name="stopThread">{{.StopThread}}</boolProp>
+			<stringProp name="shareMode">shareMode.{{.ShareMode}}</stringProp>
+		</CSVDataSet>
+		<hashTree/>
+	`
+
+type jmxDataSetTemplateData struct {
+	FileName      string
+	Path          string
+	VariableNames string
+	Recycle       bool
+	StopThread    bool
+	ShareMode     constant.DataSetShareMode
+}
+
+// dataSetParseToJmx renders the data sets of a run as CSV Data Set Configs, which go in the
+// thread group next to the samplers that read them.
+func dataSetParseToJmx(dataSets []RunLoadTestDataSetParam, attachmentDir string) (string, error) {
+	if len(dataSets) == 0 {
+		return "", nil
+	}
+	tmpl, err := template.New("jmxDataSet").Parse(jmxDataSetTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for i, ds := range dataSets {
+		columns, _, err := DataSetColumns(ds.Content)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ds.FileName, err)
+		}
+		data := jmxDataSetTemplateData{
+			FileName:      html.EscapeString(ds.FileName),
+			Path:          html.EscapeString(fmt.Sprintf("%s/%s", attachmentDir, dataSetFileName(i, ds.FileName))),
+			VariableNames: strings.Join(columns, ","),
+			Recycle:       ds.Recycle,
+			StopThread:    !ds.Recycle && ds.StopThread,
+			ShareMode:     shareModeOf(ds),
+		}
+		if err := tmpl.Execute(&builder, data); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,84 @@
+package load
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestDataSetColumnsReadTheHeader(t *testing.T) {
+	columns, rows, err := DataSetColumns([]byte("\xef\xbb\xbfuser, password\nalice,\"p,1\"\nbob,p2\n"))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if strings.Join(columns, ",") != "user,password" || rows != 2 {
+		t.Errorf("got columns %v and %d rows", columns, rows)
+	}
+
+	for name, content := range map[string]string{
+		"empty":           "",
+		"header only":     "user,password\n",
+		"unusable name":   "user,pass word\na,b\n",
+		"repeated column": "user,user\na,b\n",
+		"short line":      "user,password\nalice\n",
+	} {
+		if _, _, err := DataSetColumns([]byte(content)); err == nil {
+			t.Errorf("%s: expected the data set to be rejected", name)
+		}
+	}
+}
+
+func TestDataSetsMustNotShareAColumn(t *testing.T) {
+	err := ValidateDataSets([]RunLoadTestDataSetParam{
+		{FileName: "users.csv", Content: []byte("user,password\na,b\n")},
+		{FileName: "orders.csv", Content: []byte("order,user\n1,a\n")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "user") {
+		t.Errorf("expected the shared user column to be rejected, got %v", err)
+	}
+
+	err = ValidateDataSets([]RunLoadTestDataSetParam{
+		{FileName: "users.csv", Content: []byte("user\na\n"), ShareMode: "node"},
+	})
+	if err == nil {
+		t.Error("expected an unknown share mode to be rejected")
+	}
+}
+
+func TestDataSetsRenderCsvDataSetConfigs(t *testing.T) {
+	dataSets := []RunLoadTestDataSetParam{
+		{FileName: "users.csv", Content: []byte("user,password\na,b\n"), Recycle: true},
+		{FileName: "a&b.csv", Content: []byte("order\n1\n"), ShareMode: constant.ShareThread, StopThread: true},
+	}
+	out, err := dataSetParseToJmx(dataSets, "/opt/ant/test_plan/key")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+
+	for _, want := range []string{
+		`<stringProp name="filename">/opt/ant/test_plan/key/data_0_users.csv</stringProp>`,
+		`<stringProp name="variableNames">user,password</stringProp>`,
+		`<stringProp name="shareMode">shareMode.all</stringProp>`,
+		`<stringProp name="filename">/opt/ant/test_plan/key/data_1_a&amp;b.csv</stringProp>`,
+		`<stringProp name="shareMode">shareMode.thread</stringProp>`,
+		`<boolProp name="stopThread">true</boolProp>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %s\n%s", want, out)
+		}
+	}
+
+	files := planAttachments(RunLoadTestParam{DataSets: dataSets})
+	if len(files) != 2 || files[0].Name != "data_0_users.csv" || string(files[1].Content) != "order\n1\n" {
+		t.Errorf("expected both data sets to travel with the plan, got %+v", files)
+	}
+
+	if err := (k6Engine{}).Validate(RunLoadTestParam{
+		VirtualUsers: "1", Duration: "1", RampUpTime: "1", RampUpSteps: "1", DataSets: dataSets,
+	}); err == nil {
+		t.Error("expected data sets to be rejected for k6")
+	}
+}
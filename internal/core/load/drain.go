@@ -0,0 +1,224 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+)
+
+// A server that is shutting down drains: it takes no new runs and starts none from the queue,
+// and waits for the runs it is driving to reach a point the next server can pick them up from
+// (see ResumeUnfinishedRuns). A run whose load is going on the generator is at such a point;
+// one still being prepared, or whose results are being transferred, is not.
+
+// ErrShuttingDown is returned on a run asked for while the server is shutting down.
+var ErrShuttingDown = errors.New("cm-ant is shutting down and takes no new load tests; try again once it is back")
+
+// drainPollInterval is how often a drain looks whether the runs have settled.
+const drainPollInterval = time.Second
+
+var (
+	drainMu  sync.Mutex
+	draining bool
+	// runPhases are the phases the runs this server drives are in, by load test key; "" for
+	// a run that has not begun one.
+	runPhases = make(map[string]constant.ExecutionStep)
+	// rsyncs is how many result transfers are in progress.
+	rsyncs int
+)
+
+// DrainStatus is how far a shutdown has got.
+type DrainStatus struct {
+	Draining    bool `json:"draining"`
+	RunningRuns int  `json:"runningRuns"`
+	// UnsettledRuns are running runs not yet at a point the next server can pick them up
+	// from; Rsyncs are result transfers in progress.
+	UnsettledRuns int `json:"unsettledRuns"`
+	Rsyncs        int `json:"rsyncs"`
+}
+
+func isDraining() bool {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	return draining
+}
+
+// trackRun counts a run this server drives from the phase it is in.
+func trackRun(loadTestKey string, phase constant.ExecutionStep) {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	runPhases[loadTestKey] = phase
+}
+
+// trackRunPhase records the phase a tracked run has moved to.
+func trackRunPhase(loadTestKey string, phase constant.ExecutionStep) {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	if _, ok := runPhases[loadTestKey]; ok {
+		runPhases[loadTestKey] = phase
+	}
+}
+
+// phaseOf is the phase a tracked run is in; "" for one that is not tracked.
+func phaseOf(loadTestKey string) constant.ExecutionStep {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	return runPhases[loadTestKey]
+}
+
+// trackedRuns returns the keys of the runs this server drives, and of those of them that can
+// be left for the next server.
+func trackedRuns() (keys, settledKeys []string) {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	for key, phase := range runPhases {
+		keys = append(keys, key)
+		if settled(phase) {
+			settledKeys = append(settledKeys, key)
+		}
+	}
+	return keys, settledKeys
+}
+
+// isTracked tells whether this server drives the run.
+func isTracked(loadTestKey string) bool {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	_, ok := runPhases[loadTestKey]
+	return ok
+}
+
+func untrackRun(loadTestKey string) {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	delete(runPhases, loadTestKey)
+}
+
+// beginRsync counts a result transfer until the returned func is called.
+func beginRsync() func() {
+	drainMu.Lock()
+	rsyncs++
+	drainMu.Unlock()
+	return func() {
+		drainMu.Lock()
+		rsyncs--
+		drainMu.Unlock()
+	}
+}
+
+// settled reports whether a run in phase can be left for the next server to pick up.
+func settled(phase constant.ExecutionStep) bool {
+	return phase == constant.StepJmeterRun
+}
+
+// DrainStatus reports how far a shutdown has got; Draining is false until one begins.
+func (l *LoadService) DrainStatus() DrainStatus {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	status := DrainStatus{Draining: draining, RunningRuns: len(runPhases), Rsyncs: rsyncs}
+	for _, phase := range runPhases {
+		if !settled(phase) {
+			status.UnsettledRuns++
+		}
+	}
+	return status
+}
+
+// Drain stops the server taking and starting runs, then waits until every run it drives can
+// be left for the next server and no result transfer is in progress, or until ctx is done.
+// Either way the step each remaining run is in is told that the server went down, and an
+// error names the runs that had not settled when ctx was done.
+func (l *LoadService) Drain(ctx context.Context) error {
+	drainMu.Lock()
+	draining = true
+	drainMu.Unlock()
+	log.Info().Msg("Draining load tests")
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var err error
+wait:
+	for {
+		status := l.DrainStatus()
+		if status.UnsettledRuns == 0 && status.Rsyncs == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("shutdown deadline passed with load tests [%s] unsettled and %d result transfers in progress",
+				strings.Join(l.unsettledRuns(), ", "), status.Rsyncs)
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	l.recordShutdown()
+	l.disownSettledRuns()
+	progress.closeAll()
+	if err == nil {
+		log.Info().Msg("Successfully drained load tests")
+	}
+	return err
+}
+
+// disownSettledRuns leaves the runs that can be picked up to the next server at once, rather
+// than once their heartbeat has expired.
+func (l *LoadService) disownSettledRuns() {
+	_, keys := trackedRuns()
+	if len(keys) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := l.db.WithContext(ctx).Model(&LoadTestQueuedRun{}).
+		Where("owner_id = ? AND load_test_key IN ?", serverId, keys).
+		Updates(map[string]any{"owner_id": "", "heartbeat_at": nil}).Error
+	if err != nil {
+		log.Warn().Msgf("failed to leave load tests [%s] to the next server; %v", strings.Join(keys, ", "), err)
+	}
+}
+
+func (l *LoadService) unsettledRuns() []string {
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	var keys []string
+	for key, phase := range runPhases {
+		if !settled(phase) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// recordShutdown tells the running steps of the runs still going that the server went down,
+// so their progress does not read as work carrying on until the next server takes them up.
+func (l *LoadService) recordShutdown() {
+	drainMu.Lock()
+	phases := make(map[string]constant.ExecutionStep, len(runPhases))
+	for key, phase := range runPhases {
+		phases[key] = phase
+	}
+	drainMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for key, phase := range phases {
+		message := "cm-ant shut down before this step finished; the run is settled once it is back"
+		if settled(phase) {
+			message = "cm-ant is restarting; the load carries on and is picked up again once it is back"
+		}
+		err := l.db.WithContext(ctx).Model(&LoadTestExecutionStep{}).
+			Where("load_test_key = ? AND status = ?", key, constant.StepRunning).
+			Update("message", message).Error
+		if err != nil {
+			log.Warn().Msgf("failed to record the shutdown on load test %s; %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package load
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestDrainWaitsOnlyForRunsThatCannotBePickedUp(t *testing.T) {
+	l := &LoadService{}
+	trackRun("preparing", constant.StepJmxPrepare)
+	trackRun("loading", "")
+	trackRunPhase("loading", constant.StepJmeterRun)
+	trackRunPhase("not-tracked", constant.StepPrecheck)
+	endRsync := beginRsync()
+	defer func() {
+		untrackRun("preparing")
+		untrackRun("loading")
+	}()
+
+	want := DrainStatus{RunningRuns: 2, UnsettledRuns: 1, Rsyncs: 1}
+	if got := l.DrainStatus(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	endRsync()
+	untrackRun("preparing")
+	if got := l.DrainStatus(); got.UnsettledRuns != 0 || got.Rsyncs != 0 {
+		t.Errorf("expected a run under load to be left for the next server, got %+v", got)
+	}
+}
@@ -47,6 +47,20 @@ type InstallLoadGeneratorParam struct {
 	NsId    string `json:"nsId,omitempty"`
 	InfraId string `json:"infraId,omitempty"`
 	NodeId  string `json:"nodeId,omitempty"`
+
+	// ClusterSize is how many generator servers the load is split across; 0 and 1 are one.
+	ClusterSize int `json:"clusterSize,omitempty"`
+
+	// Regions are the places beside the generator's own that need a generator server of their
+	// own. One is added to the generator MCI for every region it has none in yet.
+	Regions []LoadGeneratorRegionParam `json:"regions,omitempty"`
+}
+
+// LoadGeneratorRegionParam is a region of a CSP, named the way cb-tumblebug names them, e.g.
+// aws and ap-northeast-2.
+type LoadGeneratorRegionParam struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region"`
 }
 
 type LoadGeneratorServerResult struct {
@@ -67,6 +81,8 @@ type LoadGeneratorServerResult struct {
 	StartTime       string    `json:"startTime,omitempty"`
 	AdditionalVmKey string    `json:"additionalVmKey,omitempty"`
 	Label           string    `json:"label,omitempty"`
+	IsCluster       bool      `json:"isCluster,omitempty"`
+	IsMaster        bool      `json:"isMaster,omitempty"`
 	CreatedAt       time.Time `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time `json:"updatedAt,omitempty"`
 }
@@ -78,6 +94,9 @@ type LoadGeneratorInstallInfoResult struct {
 	InstallPath     string                   `json:"installPath,omitempty"`
 	InstallVersion  string                   `json:"installVersion,omitempty"`
 	Status          string                   `json:"status,omitempty"`
+	IsCluster       bool                     `json:"isCluster,omitempty"`
+	MasterId        uint                     `json:"masterId,omitempty"`
+	ClusterSize     uint64                   `json:"clusterSize,omitempty"`
 	CreatedAt       time.Time                `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time                `json:"updatedAt,omitempty"`
 
@@ -106,6 +125,13 @@ type RunLoadTestParam struct {
 	InstallLoadGenerator       InstallLoadGeneratorParam `json:"installLoadGenerator"`
 	LoadGeneratorInstallInfoId uint                      `json:"loadGeneratorInstallInfoId"`
 
+	// ParentLoadTestKey is the run this one is made again from; see RerunLoadTest.
+	ParentLoadTestKey string `json:"parentLoadTestKey,omitempty"`
+
+	// ScenarioCatalogId is the scenario catalog entry the run is of, whose baseline it is held
+	// to; see judgeBaseline.
+	ScenarioCatalogId uint `json:"scenarioCatalogId,omitempty"`
+
 	// test scenario
 	TestName     string `json:"testName"`
 	VirtualUsers string `json:"virtualUsers"`
@@ -113,6 +139,42 @@ type RunLoadTestParam struct {
 	RampUpTime   string `json:"rampUpTime"`
 	RampUpSteps  string `json:"rampUpSteps"`
 
+	// LoadShape is how the load changes over time; concurrency when empty. See
+	// constant.LoadShape for which of the fields below each shape reads.
+	LoadShape     constant.LoadShape      `json:"loadShape,omitempty"`
+	ArrivalRate   string                  `json:"arrivalRate,omitempty"`
+	SpikeUsers    string                  `json:"spikeUsers,omitempty"`
+	SpikeDuration string                  `json:"spikeDuration,omitempty"`
+	Stages        []RunLoadTestStageParam `json:"stages,omitempty"`
+
+	// ThinkTime is the pause before every request that does not set its own.
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime,omitempty"`
+
+	// DataSets are CSV files read a line at a time while the test runs; every column is a
+	// variable requests can reference as ${column}.
+	DataSets []RunLoadTestDataSetParam `json:"dataSets,omitempty"`
+
+	// Sla are the thresholds the whole run is held to once it finishes. A run given none is
+	// not judged and gets no verdict.
+	Sla *RunLoadTestSlaParam `json:"sla,omitempty"`
+
+	// AutoStop are the rules that stop the run while its load is going, when the target is
+	// plainly falling over. The results up to that point are still collected.
+	AutoStop *RunLoadTestAutoStopParam `json:"autoStop,omitempty"`
+
+	// HttpClient are the client options every request is sent with unless it sets its own.
+	// Once the run is accepted they hold the engine's defaults for whatever was not given.
+	HttpClient *RunLoadTestHttpClientParam `json:"httpClient,omitempty"`
+
+	// Targets are requests to targets that do not speak plain HTTP. They run after HttpReqs
+	// in every iteration.
+	Targets []RunLoadTestTargetParam `json:"targets,omitempty"`
+
+	// Regions are the places the load is sent from at the same time, each by a generator
+	// server of its own. The load is split between them by weight. A run given none sends
+	// all of it from the generator's master.
+	Regions []RunLoadTestRegionParam `json:"regions,omitempty"`
+
 	// related tumblebug
 	NsId    string `json:"nsId"`
 	InfraId string `json:"infraId"`
@@ -122,6 +184,155 @@ type RunLoadTestParam struct {
 	AgentHostname                  string
 
 	HttpReqs []RunLoadTestHttpParam `json:"httpReqs,omitempty"`
+
+	// Engine is the tool that generates the load; JMeter when empty.
+	Engine constant.LoadGeneratorType `json:"engine,omitempty"`
+
+	// TestPlan is a user supplied JMeter plan. When set it is run instead of the plan built
+	// from HttpReqs, and the scenario fields above only describe the run (Duration and
+	// RampUpTime still give its expected length).
+	TestPlan *RunLoadTestPlanParam `json:"testPlan,omitempty"`
+}
+
+// RerunLoadTestParam makes the run of LoadTestKey again. Every other field overrides what the
+// run was stored with when given. NsId, InfraId and NodeId move the run to another target node
+// and go together; Hostname replaces the host of every request and target. InstallLoadGenerator
+// is only read when the run's load generator is gone.
+type RerunLoadTestParam struct {
+	LoadTestKey          string                    `json:"loadTestKey"`
+	InstallLoadGenerator InstallLoadGeneratorParam `json:"installLoadGenerator"`
+
+	TestName     string `json:"testName,omitempty"`
+	VirtualUsers string `json:"virtualUsers,omitempty"`
+	Duration     string `json:"duration,omitempty"`
+
+	NsId     string `json:"nsId,omitempty"`
+	InfraId  string `json:"infraId,omitempty"`
+	NodeId   string `json:"nodeId,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// RunLoadTestStageParam moves the number of virtual users linearly from where the previous
+// stage left it (0 before the first) to Target over Duration seconds. A Duration of 0 sets
+// it at once.
+type RunLoadTestStageParam struct {
+	Target   int `json:"target"`
+	Duration int `json:"duration"`
+}
+
+// RunLoadTestRegionParam is a region a run sends load from. Weight is the region's part of the
+// load against the weights of the other regions; 0 counts as 1.
+type RunLoadTestRegionParam struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region"`
+	Weight   int    `json:"weight,omitempty"`
+}
+
+// RunLoadTestThinkTimeParam is the pause a virtual user takes before sending a request, the
+// way a JMeter timer delays the sampler it belongs to. Delay and Range are milliseconds; what
+// Range means depends on Type.
+type RunLoadTestThinkTimeParam struct {
+	Type  constant.ThinkTimeType `json:"type"`
+	Delay int                    `json:"delay"`
+	Range int                    `json:"range,omitempty"`
+}
+
+// RunLoadTestDataSetParam is one CSV data set of a run. Its first line names the columns.
+// DataSetId refers to a stored data set, whose file the run uses when Content is empty.
+// Recycle starts over at the top of the file once the last line is read; without it a virtual
+// user past the end stops when StopThread is set and otherwise reads <EOF> for every column.
+type RunLoadTestDataSetParam struct {
+	DataSetId  uint                      `json:"dataSetId,omitempty"`
+	FileName   string                    `json:"fileName"`
+	Content    []byte                    `json:"content,omitempty"`
+	ShareMode  constant.DataSetShareMode `json:"shareMode"`
+	Recycle    bool                      `json:"recycle"`
+	StopThread bool                      `json:"stopThread"`
+}
+
+// RunLoadTestSlaParam are the service level thresholds of a run, checked against the statistics
+// of all of its requests together. A nil field is not checked. The response times are
+// milliseconds and the throughput requests per second.
+type RunLoadTestSlaParam struct {
+	MaxErrorPercent *float64 `json:"maxErrorPercent,omitempty"`
+	MaxP95          *float64 `json:"maxP95,omitempty"`
+	MaxP99          *float64 `json:"maxP99,omitempty"`
+	MinThroughput   *float64 `json:"minThroughput,omitempty"`
+}
+
+// RunLoadTestAutoStopParam are the rules that stop a run early. They are checked every 10
+// seconds on the requests of the last 30 seconds, and on the target's CPU over the same span.
+// A nil field is not checked. The error rate has to stay above MaxErrorPercent for
+// ErrorPercentForSec seconds; 0 stops the run the first time it is seen above. MaxP95 is
+// milliseconds, and MaxAgentCpuPercent needs the run's additional system metrics.
+type RunLoadTestAutoStopParam struct {
+	MaxErrorPercent    *float64 `json:"maxErrorPercent,omitempty"`
+	ErrorPercentForSec int      `json:"errorPercentForSec,omitempty"`
+	MaxP95             *float64 `json:"maxP95,omitempty"`
+	MaxAgentCpuPercent *float64 `json:"maxAgentCpuPercent,omitempty"`
+}
+
+// RunLoadTestHttpClientParam are the options of the HTTP client a request is sent with. A zero
+// or nil field takes the value the run has, or the engine's default. The timeouts are
+// milliseconds. TlsVersion and InsecureSkipVerify belong to the whole run, since both engines
+// set up TLS once for all requests.
+type RunLoadTestHttpClientParam struct {
+	ConnectTimeout            int                         `json:"connectTimeout,omitempty"`
+	ResponseTimeout           int                         `json:"responseTimeout,omitempty"`
+	KeepAlive                 *bool                       `json:"keepAlive,omitempty"`
+	FollowRedirects           *bool                       `json:"followRedirects,omitempty"`
+	Implementation            constant.HttpImplementation `json:"implementation,omitempty"`
+	DownloadEmbeddedResources *bool                       `json:"downloadEmbeddedResources,omitempty"`
+	TlsVersion                constant.TlsVersion         `json:"tlsVersion,omitempty"`
+	InsecureSkipVerify        *bool                       `json:"insecureSkipVerify,omitempty"`
+}
+
+// RunLoadTestTargetParam is a request to a target that does not speak plain HTTP. Protocol
+// decides which of the other fields are read:
+//
+//   - websocket: Path, Secure and Message, the text frame sent on a new connection every
+//     iteration. The exchange ends with the first frame that comes back.
+//   - grpc: Method (package.Service/Method), ProtoFileName and ProtoFile, the .proto that
+//     defines it, Message, the request as JSON, and Secure.
+//   - tcp: Message, sent as text, and EolByte, the byte an answer ends with. Without it the
+//     answer ends when the target closes the connection.
+//   - jdbc: Driver, Database, Username, Password and Query.
+//
+// Timeout is the most milliseconds one exchange may take; 0 is 10 seconds.
+type RunLoadTestTargetParam struct {
+	Protocol constant.TargetProtocol `json:"protocol"`
+	Hostname string                  `json:"hostname"`
+	Port     string                  `json:"port"`
+	Timeout  int                     `json:"timeout,omitempty"`
+
+	Path    string `json:"path,omitempty"`
+	Secure  bool   `json:"secure,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	Method        string `json:"method,omitempty"`
+	ProtoFileName string `json:"protoFileName,omitempty"`
+	ProtoFile     []byte `json:"protoFile,omitempty"`
+
+	EolByte *int `json:"eolByte,omitempty"`
+
+	Driver   constant.JdbcDriver `json:"driver,omitempty"`
+	Database string              `json:"database,omitempty"`
+	Username string              `json:"username,omitempty"`
+	Password string              `json:"password,omitempty"`
+	Query    string              `json:"query,omitempty"`
+}
+
+// RunLoadTestPlanParam is an uploaded .jmx and the CSV data files it reads. A CSV Data Set
+// Config in the plan is pointed at the uploaded file with the same base name.
+type RunLoadTestPlanParam struct {
+	FileName  string                     `json:"fileName"`
+	Content   []byte                     `json:"content"`
+	DataFiles []RunLoadTestDataFileParam `json:"dataFiles,omitempty"`
+}
+
+type RunLoadTestDataFileParam struct {
+	FileName string `json:"fileName"`
+	Content  []byte `json:"content"`
 }
 
 type RunLoadTestHttpParam struct {
@@ -131,6 +342,73 @@ type RunLoadTestHttpParam struct {
 	Port     string `json:"port"`
 	Path     string `json:"path,omitempty"`
 	BodyData string `json:"bodyData,omitempty"`
+
+	// Headers apply to this request only, on top of the plan-wide ones. QueryParams are added
+	// to whatever query Path already has. BodyType decides whether the body is BodyData as
+	// given or is built from FormParams (and Files, for multipart).
+	Headers     []RunLoadTestNameValueParam `json:"headers,omitempty"`
+	QueryParams []RunLoadTestNameValueParam `json:"queryParams,omitempty"`
+	BodyType    constant.HttpBodyType       `json:"bodyType,omitempty"`
+	FormParams  []RunLoadTestNameValueParam `json:"formParams,omitempty"`
+	Files       []RunLoadTestFileParam      `json:"files,omitempty"`
+
+	// Extractors pull values out of this request's response. Requests run in order within an
+	// iteration, so a later request can use an extracted value as ${varName} in its path, body
+	// or headers - which is what a login -> token -> API flow needs.
+	Extractors []RunLoadTestExtractorParam `json:"extractors,omitempty"`
+
+	// Weight makes the request part of a traffic mix: it is sent in its share of iterations,
+	// weight / sum of all weights, instead of in every one. 0 sends it every iteration.
+	// Exactly one weighted request is picked an iteration, so a chained request that reads a
+	// value extracted by a weighted one may run without it.
+	Weight int `json:"weight,omitempty"`
+
+	// ThinkTime overrides the run's think time for this request.
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime,omitempty"`
+
+	// Assertions are what a response must satisfy to count as a success. Without them only
+	// a 4xx/5xx status or a connection failure is an error.
+	Assertions []RunLoadTestAssertionParam `json:"assertions,omitempty"`
+
+	// HttpClient overrides the run's client options for this request, field by field.
+	HttpClient *RunLoadTestHttpClientParam `json:"httpClient,omitempty"`
+}
+
+type RunLoadTestNameValueParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RunLoadTestFileParam is a file sent as one part of a multipart request. Content travels with
+// the test so the generator can read it from disk; it is copied next to the test plan.
+type RunLoadTestFileParam struct {
+	ParamName string `json:"paramName"`
+	FileName  string `json:"fileName"`
+	MimeType  string `json:"mimeType,omitempty"`
+	Content   []byte `json:"content,omitempty"`
+}
+
+// RunLoadTestExtractorParam describes one value to extract from a response.
+//
+// MatchNo follows JMeter: 1 keeps the first match, 0 a random one and -1 all of them as
+// varName_1..varName_n. DefaultValue is assigned when nothing matches, so a failed login shows
+// up as requests carrying the default rather than a literal "${token}".
+type RunLoadTestExtractorParam struct {
+	Type         constant.ExtractorType `json:"type"`
+	VarName      string                 `json:"varName"`
+	Expression   string                 `json:"expression"`
+	MatchNo      int                    `json:"matchNo"`
+	DefaultValue string                 `json:"defaultValue,omitempty"`
+}
+
+// RunLoadTestAssertionParam describes one check of a response. What Value holds depends on
+// Type: the accepted status codes separated by commas, the text the body must contain, the
+// value Expression must find (any value when empty) or the most milliseconds a response may
+// take. Expression is only read by json_path assertions.
+type RunLoadTestAssertionParam struct {
+	Type       constant.AssertionType `json:"type"`
+	Expression string                 `json:"expression,omitempty"`
+	Value      string                 `json:"value,omitempty"`
 }
 
 type GetAllLoadTestExecutionStateParam struct {
@@ -138,6 +416,10 @@ type GetAllLoadTestExecutionStateParam struct {
 	Size            int                      `json:"size"`
 	LoadTestKey     string                   `json:"loadTestKey"`
 	ExecutionStatus constant.ExecutionStatus `json:"executionStatus"`
+	// ParentLoadTestKey lists the runs made again from that run.
+	ParentLoadTestKey string `json:"parentLoadTestKey"`
+	// BaselineVerdict lists the runs that came out so against their baseline.
+	BaselineVerdict constant.BaselineVerdict `json:"baselineVerdict"`
 }
 
 type GetAllLoadTestExecutionStateResult struct {
@@ -150,23 +432,49 @@ type LoadTestExecutionStateResult struct {
 	LoadGeneratorInstallInfoId uint                           `json:"loadGeneratorInstallInfoId,omitempty"`
 	LoadGeneratorInstallInfo   LoadGeneratorInstallInfoResult `json:"loadGeneratorInstallInfo,omitempty"`
 	LoadTestKey                string                         `json:"loadTestKey,omitempty"`
+	// ParentLoadTestKey is the run this one was made again from.
+	ParentLoadTestKey string `json:"parentLoadTestKey,omitempty"`
 	// NodeUid identifies which VM this run belongs to. Node ids are names and get reused,
 	// so a caller that keeps showing "the last run for this node" needs the uid to notice
 	// that the answer belongs to a VM that has since been replaced.
-	NodeUid                     string                   `json:"nodeUid,omitempty"`
-	ExecutionStatus             constant.ExecutionStatus `json:"executionStatus,omitempty"`
-	StartAt                     time.Time                `json:"startAt,omitempty"`
-	FinishAt                    *time.Time               `json:"finishAt,omitempty"`
-	ExpectedFinishAt            time.Time                `json:"expectedFinishAt,omitempty"`
-	IconCode                    constant.IconCode        `json:"iconCode"`
-	TotalExpectedExcutionSecond uint64                   `json:"totalExpectedExecutionSecond,omitempty"`
-	FailureMessage              string                   `json:"failureMessage,omitempty"`
-	CompileDuration             string                   `json:"compileDuration,omitempty"`
-	ExecutionDuration           string                   `json:"executionDuration,omitempty"`
-	CreatedAt                   time.Time                `json:"createdAt,omitempty"`
-	UpdatedAt                   time.Time                `json:"updatedAt,omitempty"`
+	NodeUid                     string                     `json:"nodeUid,omitempty"`
+	ExecutionStatus             constant.ExecutionStatus   `json:"executionStatus,omitempty"`
+	QueuePosition               int                        `json:"queuePosition,omitempty"` // of a queued run, from 1
+	StartAt                     time.Time                  `json:"startAt,omitempty"`
+	FinishAt                    *time.Time                 `json:"finishAt,omitempty"`
+	ExpectedFinishAt            time.Time                  `json:"expectedFinishAt,omitempty"`
+	IconCode                    constant.IconCode          `json:"iconCode"`
+	TotalExpectedExcutionSecond uint64                     `json:"totalExpectedExecutionSecond,omitempty"`
+	FailureMessage              string                     `json:"failureMessage,omitempty"`
+	CompileDuration             string                     `json:"compileDuration,omitempty"`
+	ExecutionDuration           string                     `json:"executionDuration,omitempty"`
+	Engine                      constant.LoadGeneratorType `json:"engine,omitempty"`
+	CreatedAt                   time.Time                  `json:"createdAt,omitempty"`
+	UpdatedAt                   time.Time                  `json:"updatedAt,omitempty"`
 	// Steps is the per-stage progress of the run (FR-MA2-PERF-007-08), ordered by seq.
 	Steps []LoadTestExecutionStepResult `json:"steps,omitempty"`
+
+	// Verdict is PASS or FAIL once a run that was given SLA thresholds has finished, and
+	// SlaViolations lists the thresholds a failed one missed.
+	Verdict       constant.SlaVerdict                   `json:"verdict,omitempty"`
+	SlaViolations []LoadTestExecutionSlaViolationResult `json:"slaViolations,omitempty"`
+
+	// ScenarioCatalogId is the scenario catalog entry the run is of.
+	ScenarioCatalogId uint `json:"scenarioCatalogId,omitempty"`
+	// BaselineVerdict is PASS, REGRESSED or INCONCLUSIVE once a run that has a baseline has
+	// finished successfully; BaselineMessage tells what regressed, or why it is inconclusive.
+	BaselineLoadTestKey string                   `json:"baselineLoadTestKey,omitempty"`
+	BaselineVerdict     constant.BaselineVerdict `json:"baselineVerdict,omitempty"`
+	BaselineMessage     string                   `json:"baselineMessage,omitempty"`
+}
+
+// LoadTestExecutionSlaViolationResult is one SLA threshold a run missed. Threshold and Actual
+// are in the unit of the criterion and are both 0 when the result could not be read at all.
+type LoadTestExecutionSlaViolationResult struct {
+	Criterion constant.SlaCriterion `json:"criterion"`
+	Threshold float64               `json:"threshold"`
+	Actual    float64               `json:"actual"`
+	Message   string                `json:"message"`
 }
 
 // LoadTestExecutionStepResult exposes one execution stage for the web console
@@ -192,6 +500,30 @@ type LoadTestExecutionStepResult struct {
 	Children []LoadTestExecutionStepResult `json:"children,omitempty"`
 }
 
+// LoadTestProgressEvent is one update pushed to those watching a run: its state, a step that
+// changed, or rolling figures of the load while it runs. Only the field of its type is set.
+type LoadTestProgressEvent struct {
+	Type    constant.ProgressEventType    `json:"type"`
+	State   *LoadTestExecutionStateResult `json:"state,omitempty"`
+	Step    *LoadTestExecutionStepResult  `json:"step,omitempty"`
+	Figures *LiveFigures                  `json:"figures,omitempty"`
+}
+
+// LiveFigures are rolling figures of a run under way, over the samples of its result file
+// so far that fall in the WindowSec seconds up to At, the time of the latest one. Elapsed
+// times are in milliseconds.
+type LiveFigures struct {
+	At           time.Time `json:"at"`
+	WindowSec    int       `json:"windowSec"`
+	Samples      int       `json:"samples"`
+	Throughput   float64   `json:"throughput"` // requests a second
+	AvgElapsed   float64   `json:"avgElapsed"`
+	P95Elapsed   float64   `json:"p95Elapsed"`
+	ErrorPercent float64   `json:"errorPercent"`
+	TotalSamples int       `json:"totalSamples"`
+	TotalErrors  int       `json:"totalErrors"`
+}
+
 type GetLoadTestExecutionStateParam struct {
 	LoadTestKey string `json:"loadTestKey"`
 	NsId        string `json:"nsId"`
@@ -210,20 +542,34 @@ type GetAllLoadTestExecutionInfosResult struct {
 }
 
 type LoadTestExecutionInfoResult struct {
-	ID                         uint                              `json:"id"`
-	LoadTestKey                string                            `json:"loadTestKey,omitempty"`
-	TestName                   string                            `json:"testName,omitempty"`
-	VirtualUsers               string                            `json:"virtualUsers,omitempty"`
-	Duration                   string                            `json:"duration,omitempty"`
-	RampUpTime                 string                            `json:"rampUpTime,omitempty"`
-	RampUpSteps                string                            `json:"rampUpSteps,omitempty"`
-	AgentHostname              string                            `json:"agentHostname,omitempty"`
-	AgentInstalled             bool                              `json:"agentInstalled,omitempty"`
-	CompileDuration            string                            `json:"compileDuration,omitempty"`
-	ExecutionDuration          string                            `json:"executionDuration,omitempty"`
-	LoadTestExecutionHttpInfos []LoadTestExecutionHttpInfoResult `json:"loadTestExecutionHttpInfos,omitempty"`
-	LoadTestExecutionState     LoadTestExecutionStateResult      `json:"loadTestExecutionState,omitempty"`
-	LoadGeneratorInstallInfo   LoadGeneratorInstallInfoResult    `json:"loadGeneratorInstallInfo,omitempty"`
+	ID                         uint                                  `json:"id"`
+	LoadTestKey                string                                `json:"loadTestKey,omitempty"`
+	TestName                   string                                `json:"testName,omitempty"`
+	VirtualUsers               string                                `json:"virtualUsers,omitempty"`
+	Duration                   string                                `json:"duration,omitempty"`
+	RampUpTime                 string                                `json:"rampUpTime,omitempty"`
+	RampUpSteps                string                                `json:"rampUpSteps,omitempty"`
+	LoadShape                  constant.LoadShape                    `json:"loadShape,omitempty"`
+	ArrivalRate                string                                `json:"arrivalRate,omitempty"`
+	SpikeUsers                 string                                `json:"spikeUsers,omitempty"`
+	SpikeDuration              string                                `json:"spikeDuration,omitempty"`
+	Stages                     []RunLoadTestStageParam               `json:"stages,omitempty"`
+	ThinkTime                  *RunLoadTestThinkTimeParam            `json:"thinkTime,omitempty"`
+	AgentHostname              string                                `json:"agentHostname,omitempty"`
+	AgentInstalled             bool                                  `json:"agentInstalled,omitempty"`
+	Engine                     constant.LoadGeneratorType            `json:"engine,omitempty"`
+	CompileDuration            string                                `json:"compileDuration,omitempty"`
+	ExecutionDuration          string                                `json:"executionDuration,omitempty"`
+	LoadTestExecutionHttpInfos []LoadTestExecutionHttpInfoResult     `json:"loadTestExecutionHttpInfos,omitempty"`
+	UploadedFiles              []LoadTestExecutionUploadedFileResult `json:"uploadedFiles,omitempty"`
+	DataSets                   []LoadTestExecutionDataSetResult      `json:"dataSets,omitempty"`
+	Sla                        *RunLoadTestSlaParam                  `json:"sla,omitempty"`
+	AutoStop                   *RunLoadTestAutoStopParam             `json:"autoStop,omitempty"`
+	HttpClient                 *RunLoadTestHttpClientParam           `json:"httpClient,omitempty"`
+	Targets                    []LoadTestExecutionTargetResult       `json:"targets,omitempty"`
+	Regions                    []LoadTestExecutionRegionResult       `json:"regions,omitempty"`
+	LoadTestExecutionState     LoadTestExecutionStateResult          `json:"loadTestExecutionState,omitempty"`
+	LoadGeneratorInstallInfo   LoadGeneratorInstallInfoResult        `json:"loadGeneratorInstallInfo,omitempty"`
 }
 
 type LoadTestExecutionHttpInfoResult struct {
@@ -234,6 +580,94 @@ type LoadTestExecutionHttpInfoResult struct {
 	Port     string `json:"port,omitempty"`
 	Path     string `json:"path,omitempty"`
 	BodyData string `json:"bodyData,omitempty"`
+
+	Headers     []RunLoadTestNameValueParam            `json:"headers,omitempty"`
+	QueryParams []RunLoadTestNameValueParam            `json:"queryParams,omitempty"`
+	BodyType    constant.HttpBodyType                  `json:"bodyType,omitempty"`
+	FormParams  []RunLoadTestNameValueParam            `json:"formParams,omitempty"`
+	Files       []LoadTestExecutionHttpFileResult      `json:"files,omitempty"`
+	Extractors  []LoadTestExecutionHttpExtractorResult `json:"extractors,omitempty"`
+	Weight      int                                    `json:"weight,omitempty"`
+	ThinkTime   *RunLoadTestThinkTimeParam             `json:"thinkTime,omitempty"`
+	Assertions  []LoadTestExecutionHttpAssertionResult `json:"assertions,omitempty"`
+	HttpClient  *RunLoadTestHttpClientParam            `json:"httpClient,omitempty"`
+}
+
+// LoadTestExecutionHttpFileResult describes an uploaded file without its content, which can be
+// large and is only needed again to re-run the test.
+type LoadTestExecutionHttpFileResult struct {
+	ID        uint   `json:"id"`
+	ParamName string `json:"paramName"`
+	FileName  string `json:"fileName"`
+	MimeType  string `json:"mimeType,omitempty"`
+	Size      int    `json:"size"`
+}
+
+type LoadTestExecutionHttpExtractorResult struct {
+	ID           uint                   `json:"id"`
+	Type         constant.ExtractorType `json:"type"`
+	VarName      string                 `json:"varName"`
+	Expression   string                 `json:"expression"`
+	MatchNo      int                    `json:"matchNo"`
+	DefaultValue string                 `json:"defaultValue,omitempty"`
+}
+
+type LoadTestExecutionHttpAssertionResult struct {
+	ID         uint                   `json:"id"`
+	Type       constant.AssertionType `json:"type"`
+	Expression string                 `json:"expression,omitempty"`
+	Value      string                 `json:"value,omitempty"`
+}
+
+// LoadTestExecutionTargetResult describes a non-HTTP target a run sent load to. The password of
+// a database and the content of a .proto are not given back.
+type LoadTestExecutionTargetResult struct {
+	ID       uint                    `json:"id"`
+	Protocol constant.TargetProtocol `json:"protocol"`
+	Hostname string                  `json:"hostname"`
+	Port     string                  `json:"port"`
+	Timeout  int                     `json:"timeout,omitempty"`
+
+	Path          string              `json:"path,omitempty"`
+	Secure        bool                `json:"secure,omitempty"`
+	Message       string              `json:"message,omitempty"`
+	Method        string              `json:"method,omitempty"`
+	ProtoFileName string              `json:"protoFileName,omitempty"`
+	EolByte       *int                `json:"eolByte,omitempty"`
+	Driver        constant.JdbcDriver `json:"driver,omitempty"`
+	Database      string              `json:"database,omitempty"`
+	Username      string              `json:"username,omitempty"`
+	Query         string              `json:"query,omitempty"`
+}
+
+// LoadTestExecutionRegionResult describes a region a run sent load from and the virtual users
+// it was given.
+type LoadTestExecutionRegionResult struct {
+	ID           uint   `json:"id"`
+	Provider     string `json:"provider"`
+	Region       string `json:"region"`
+	Weight       int    `json:"weight"`
+	VirtualUsers string `json:"virtualUsers"`
+}
+
+// LoadTestExecutionDataSetResult describes a data set a run read, without its content.
+type LoadTestExecutionDataSetResult struct {
+	ID         uint                      `json:"id"`
+	DataSetId  uint                      `json:"dataSetId,omitempty"`
+	FileName   string                    `json:"fileName"`
+	Columns    []string                  `json:"columns"`
+	Size       int                       `json:"size"`
+	ShareMode  constant.DataSetShareMode `json:"shareMode"`
+	Recycle    bool                      `json:"recycle"`
+	StopThread bool                      `json:"stopThread"`
+}
+
+// LoadTestExecutionUploadedFileResult describes an uploaded test plan or data file.
+type LoadTestExecutionUploadedFileResult struct {
+	ID         uint   `json:"id"`
+	FileName   string `json:"fileName"`
+	Size       int    `json:"size"`
+	IsTestPlan bool   `json:"isTestPlan"`
 }
 
 type GetLoadTestExecutionInfoParam struct {
@@ -268,6 +702,9 @@ type ResultRawData struct {
 	Connection int // time to establish connection
 	IsError    bool
 	Timestamp  time.Time
+	// ActiveThreads are the threads (virtual users) running when the request was sent; 0 when
+	// the engine does not tell.
+	ActiveThreads int
 }
 
 type MetricsRawData struct {
@@ -296,6 +733,131 @@ type LoadTestStatistics struct {
 type GetLoadTestResultParam struct {
 	LoadTestKey string
 	Format      constant.ResultFormat
+	Window      TimeSeriesWindow // of the timeseries format
+}
+
+// TimeSeriesWindow is the part of a run a time series covers and the width of its buckets. A
+// zero From or To leaves that end open, at the first or the last request of the run.
+type TimeSeriesWindow struct {
+	IntervalMs int
+	From       time.Time
+	To         time.Time
+}
+
+// LoadTestTimeSeries is the figures of a run per time bucket, label by label with all of them
+// together last, ready to be drawn as throughput and latency over time.
+type LoadTestTimeSeries struct {
+	IntervalMs int                     `json:"intervalMs"`
+	From       time.Time               `json:"from"`
+	To         time.Time               `json:"to"`
+	Labels     []LabelTimeSeriesResult `json:"labels"`
+}
+
+// LabelTimeSeriesResult is the buckets of one label, every bucket of the window in time order;
+// a bucket without requests has only its time.
+type LabelTimeSeriesResult struct {
+	Label   string             `json:"label"`
+	Buckets []TimeSeriesBucket `json:"buckets"`
+}
+
+// TimeSeriesBucket is the figures of the requests sent within one bucket. Response times are
+// in ms over every request, failed ones included; percentiles are within 1% of the exact ones.
+type TimeSeriesBucket struct {
+	At               time.Time `json:"at"` // start of the bucket
+	Requests         int       `json:"requests"`
+	Errors           int       `json:"errors"`
+	RequestsPerSec   float64   `json:"requestsPerSec"`
+	ErrorsPerSec     float64   `json:"errorsPerSec"`
+	MeanElapsed      float64   `json:"meanElapsed"`
+	P50Elapsed       float64   `json:"p50Elapsed"`
+	P95Elapsed       float64   `json:"p95Elapsed"`
+	P99Elapsed       float64   `json:"p99Elapsed"`
+	ActiveThreads    int       `json:"activeThreads"` // the most seen within the bucket
+	ReceivedKBPerSec float64   `json:"receivedKBPerSec"`
+	SentKBPerSec     float64   `json:"sentKBPerSec"`
+}
+
+// LoadTestRegionResult is the part of a run's result that was sent from one region. Latency
+// seen from there includes the way to the target, which is what a user in that region waits.
+type LoadTestRegionResult struct {
+	Provider     string                `json:"provider"`
+	Region       string                `json:"region"`
+	Weight       int                   `json:"weight"`
+	VirtualUsers string                `json:"virtualUsers"`
+	Statistics   []*LoadTestStatistics `json:"statistics"`
+}
+
+// CompareLoadTestsParam names the runs to compare, the first being the baseline the others are
+// held to. A tolerance left nil is the configured one.
+type CompareLoadTestsParam struct {
+	LoadTestKeys        []string
+	ResponseTimePercent *float64
+	ErrorPercentPoints  *float64
+	ThroughputPercent   *float64
+	MetricPercent       *float64
+	Significance        *float64
+}
+
+// LoadTestComparisonTolerance is how much worse than the baseline a run may do before it is
+// flagged. A change beyond a tolerance is flagged only when it is also significant.
+type LoadTestComparisonTolerance struct {
+	ResponseTimePercent float64 `json:"responseTimePercent"` // % slower; average, median and percentiles
+	ErrorPercentPoints  float64 `json:"errorPercentPoints"`  // error rate points higher
+	ThroughputPercent   float64 `json:"throughputPercent"`   // % fewer requests per second
+	MetricPercent       float64 `json:"metricPercent"`       // % worse target metrics
+	Significance        float64 `json:"significance"`        // p-value a change must be below
+}
+
+// LoadTestComparisonResult compares every run after the first with the first, its baseline.
+type LoadTestComparisonResult struct {
+	BaselineLoadTestKey string                      `json:"baselineLoadTestKey"`
+	Tolerance           LoadTestComparisonTolerance `json:"tolerance"`
+	Regressed           bool                        `json:"regressed"` // any of the runs regressed
+	Runs                []ComparedLoadTestResult    `json:"runs"`
+}
+
+// ComparedLoadTestResult is one run set against the baseline, label by label and metric by
+// metric. Metrics are compared only when both runs collected them.
+type ComparedLoadTestResult struct {
+	LoadTestKey string                   `json:"loadTestKey"`
+	Regressed   bool                     `json:"regressed"`
+	Regressions []string                 `json:"regressions,omitempty"` // what regressed, in words
+	Untested    []string                 `json:"untested,omitempty"`    // what could not be tested, in words
+	Labels      []LabelComparisonResult  `json:"labels"`
+	Metrics     []MetricComparisonResult `json:"metrics,omitempty"`
+}
+
+// LabelComparisonResult sets the statistics of one label against the baseline's; the total row
+// takes all of the requests together. A label only one of the runs sent has no statistics.
+type LabelComparisonResult struct {
+	Label      string                      `json:"label"`
+	MissingIn  constant.ComparisonSide     `json:"missingIn,omitempty"`
+	Statistics []StatisticComparisonResult `json:"statistics,omitempty"`
+}
+
+// StatisticComparisonResult is one statistic of LoadTestStatistics in both runs. Statistics
+// that are not judged carry no p-value.
+type StatisticComparisonResult struct {
+	Name         string   `json:"name"`
+	Baseline     float64  `json:"baseline"`
+	Value        float64  `json:"value"`
+	Delta        float64  `json:"delta"`
+	DeltaPercent *float64 `json:"deltaPercent,omitempty"` // none when the baseline is 0
+	PValue       *float64 `json:"pValue,omitempty"`
+	Regression   bool     `json:"regression"`
+}
+
+// MetricComparisonResult is the average of one target metric in both runs.
+type MetricComparisonResult struct {
+	Label        string                  `json:"label"`
+	Unit         string                  `json:"unit"`
+	MissingIn    constant.ComparisonSide `json:"missingIn,omitempty"`
+	Baseline     float64                 `json:"baseline"`
+	Value        float64                 `json:"value"`
+	Delta        float64                 `json:"delta"`
+	DeltaPercent *float64                `json:"deltaPercent,omitempty"`
+	PValue       *float64                `json:"pValue,omitempty"`
+	Regression   bool                    `json:"regression"`
 }
 
 type GetLastLoadTestResultParam struct {
@@ -303,6 +865,42 @@ type GetLastLoadTestResultParam struct {
 	InfraId string
 	NodeId  string
 	Format  constant.ResultFormat
+	Window  TimeSeriesWindow // of the timeseries format
+}
+
+// PinLoadTestBaselineParam pins a run as the baseline of a scenario catalog entry, or of a target
+// node; with neither given, of the node the run was sent to.
+type PinLoadTestBaselineParam struct {
+	LoadTestKey       string
+	ScenarioCatalogId uint
+	NsId              string
+	InfraId           string
+	NodeId            string
+}
+
+type LoadTestBaselineResult struct {
+	ID                uint      `json:"id"`
+	LoadTestKey       string    `json:"loadTestKey"`
+	ScenarioCatalogId uint      `json:"scenarioCatalogId,omitempty"`
+	NsId              string    `json:"nsId,omitempty"`
+	InfraId           string    `json:"infraId,omitempty"`
+	NodeId            string    `json:"nodeId,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+type GetAllLoadTestBaselinesParam struct {
+	Page              int    `json:"page" example:"1"`
+	Size              int    `json:"size" example:"10"`
+	ScenarioCatalogId uint   `json:"scenarioCatalogId,omitempty"`
+	NsId              string `json:"nsId,omitempty"`
+	InfraId           string `json:"infraId,omitempty"`
+	NodeId            string `json:"nodeId,omitempty"`
+}
+
+type GetAllLoadTestBaselinesResult struct {
+	LoadTestBaselines []LoadTestBaselineResult `json:"loadTestBaselines"`
+	TotalRow          int64                    `json:"totalRow"`
 }
 
 // LoadTestScenarioCatalog DTOs
@@ -313,6 +911,15 @@ type CreateLoadTestScenarioCatalogReq struct {
 	Duration     string `json:"duration" binding:"required" example:"300"`
 	RampUpTime   string `json:"rampUpTime" binding:"required" example:"60"`
 	RampUpSteps  string `json:"rampUpSteps" binding:"required" example:"5"`
+
+	LoadShape     constant.LoadShape      `json:"loadShape,omitempty" example:"concurrency"`
+	ArrivalRate   string                  `json:"arrivalRate,omitempty" example:"50"`
+	SpikeUsers    string                  `json:"spikeUsers,omitempty" example:"80"`
+	SpikeDuration string                  `json:"spikeDuration,omitempty" example:"30"`
+	Stages        []RunLoadTestStageParam `json:"stages,omitempty"`
+
+	// think time of the requests run with this scenario
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime,omitempty"`
 }
 
 type UpdateLoadTestScenarioCatalogReq struct {
@@ -322,18 +929,41 @@ type UpdateLoadTestScenarioCatalogReq struct {
 	Duration     string `json:"duration" example:"600"`
 	RampUpTime   string `json:"rampUpTime" example:"120"`
 	RampUpSteps  string `json:"rampUpSteps" example:"10"`
+
+	LoadShape     constant.LoadShape `json:"loadShape,omitempty" example:"spike"`
+	ArrivalRate   string             `json:"arrivalRate,omitempty" example:"50"`
+	SpikeUsers    string             `json:"spikeUsers,omitempty" example:"80"`
+	SpikeDuration string             `json:"spikeDuration,omitempty" example:"30"`
+	// Stages replaces the stored stages when present; an empty list removes them.
+	Stages []RunLoadTestStageParam `json:"stages"`
+	// ThinkTime replaces the stored think time when present; a constant one of 0 ms stops the
+	// pauses.
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime,omitempty"`
 }
 
 type LoadTestScenarioCatalogResult struct {
-	ID           uint      `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	VirtualUsers string    `json:"virtualUsers"`
-	Duration     string    `json:"duration"`
-	RampUpTime   string    `json:"rampUpTime"`
-	RampUpSteps  string    `json:"rampUpSteps"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	VirtualUsers string `json:"virtualUsers"`
+	Duration     string `json:"duration"`
+	RampUpTime   string `json:"rampUpTime"`
+	RampUpSteps  string `json:"rampUpSteps"`
+
+	LoadShape     constant.LoadShape      `json:"loadShape"`
+	ArrivalRate   string                  `json:"arrivalRate,omitempty"`
+	SpikeUsers    string                  `json:"spikeUsers,omitempty"`
+	SpikeDuration string                  `json:"spikeDuration,omitempty"`
+	Stages        []RunLoadTestStageParam `json:"stages,omitempty"`
+
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime,omitempty"`
+
+	// TotalExpectedExecutionSecond is how long a run of this scenario lasts, over its whole
+	// load profile.
+	TotalExpectedExecutionSecond uint64 `json:"totalExpectedExecutionSecond"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type GetAllLoadTestScenarioCatalogsParam struct {
@@ -346,3 +976,104 @@ type GetAllLoadTestScenarioCatalogsResult struct {
 	LoadTestScenarioCatalogs []LoadTestScenarioCatalogResult `json:"loadTestScenarioCatalogs"`
 	TotalRow                 int64                           `json:"totalRow"`
 }
+
+// LoadTestDataSet DTOs
+type LoadTestDataSetResult struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	FileName    string   `json:"fileName"`
+	Columns     []string `json:"columns"`
+	Rows        int      `json:"rows"`
+	Size        int      `json:"size"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type GetAllLoadTestDataSetsParam struct {
+	Page int    `json:"page" example:"1"`
+	Size int    `json:"size" example:"10"`
+	Name string `json:"name,omitempty" example:"users"`
+}
+
+type GetAllLoadTestDataSetsResult struct {
+	LoadTestDataSets []LoadTestDataSetResult `json:"loadTestDataSets"`
+	TotalRow         int64                   `json:"totalRow"`
+}
+
+// LoadTestSchedule DTOs
+
+// SaveLoadTestScheduleParam is a schedule as created, or as it replaces a stored one. Run is
+// the run it starts, already checked; its load profile comes from the scenario catalog.
+type SaveLoadTestScheduleParam struct {
+	Name        string
+	Description string
+	Cron        string
+	RunAt       *time.Time
+	TimeZone    string
+	Enabled     bool
+
+	ScenarioCatalogId uint
+	NsId              string
+	InfraId           string
+	NodeId            string
+	Run               RunLoadTestParam
+}
+
+type LoadTestScheduleResult struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Cron        string     `json:"cron,omitempty"`
+	RunAt       *time.Time `json:"runAt,omitempty"`
+	TimeZone    string     `json:"timeZone"`
+	Enabled     bool       `json:"enabled"`
+
+	ScenarioCatalogId uint             `json:"scenarioCatalogId"`
+	NsId              string           `json:"nsId"`
+	InfraId           string           `json:"infraId"`
+	NodeId            string           `json:"nodeId"`
+	Run               RunLoadTestParam `json:"run"`
+
+	// NextRunAt is when the schedule fires next; absent once it has nothing left to fire.
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type GetAllLoadTestSchedulesParam struct {
+	Page int    `json:"page" example:"1"`
+	Size int    `json:"size" example:"10"`
+	Name string `json:"name,omitempty" example:"nightly"`
+}
+
+type GetAllLoadTestSchedulesResult struct {
+	LoadTestSchedules []LoadTestScheduleResult `json:"loadTestSchedules"`
+	TotalRow          int64                    `json:"totalRow"`
+}
+
+type LoadTestScheduleRunResult struct {
+	ID          uint                       `json:"id"`
+	ScheduledAt time.Time                  `json:"scheduledAt"`
+	StartedAt   time.Time                  `json:"startedAt"`
+	Status      constant.ScheduleRunStatus `json:"status"`
+	LoadTestKey string                     `json:"loadTestKey,omitempty"`
+	Message     string                     `json:"message,omitempty"`
+
+	// ExecutionStatus is where the started run is now; absent when none was started.
+	ExecutionStatus constant.ExecutionStatus `json:"executionStatus,omitempty"`
+}
+
+type GetLoadTestScheduleRunsParam struct {
+	ScheduleId uint
+	Page       int
+	Size       int
+}
+
+type GetLoadTestScheduleRunsResult struct {
+	LoadTestScheduleRuns []LoadTestScheduleRunResult `json:"loadTestScheduleRuns"`
+	TotalRow             int64                       `json:"totalRow"`
+}
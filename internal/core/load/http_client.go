@@ -0,0 +1,117 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// The HTTP client options decide what a run measures as much as the load does: with a one
+// second response timeout every slow response is an error. They are resolved once, when the
+// run is accepted, so the stored run says what its requests were really sent with.
+
+func boolPtr(b bool) *bool { return &b }
+
+// httpClientDefaults are what each engine does when it is not told otherwise. JMeter trusts
+// every certificate it is shown; k6 has no connect timeout of its own, its response timeout
+// covers the whole request.
+var httpClientDefaults = map[constant.LoadGeneratorType]RunLoadTestHttpClientParam{
+	constant.Jmeter: {
+		ConnectTimeout:            5000,
+		ResponseTimeout:           60000,
+		KeepAlive:                 boolPtr(true),
+		FollowRedirects:           boolPtr(true),
+		Implementation:            constant.HttpClient4Implementation,
+		DownloadEmbeddedResources: boolPtr(false),
+		InsecureSkipVerify:        boolPtr(true),
+	},
+	constant.K6: {
+		ResponseTimeout:           60000,
+		KeepAlive:                 boolPtr(true),
+		FollowRedirects:           boolPtr(true),
+		DownloadEmbeddedResources: boolPtr(false),
+		InsecureSkipVerify:        boolPtr(false),
+	},
+}
+
+// resolveHttpClient fills in what a run leaves open with the engine's defaults.
+func resolveHttpClient(engine constant.LoadGeneratorType, client *RunLoadTestHttpClientParam) *RunLoadTestHttpClientParam {
+	if engine == "" {
+		engine = constant.Jmeter
+	}
+	defaults := httpClientDefaults[engine]
+	return mergeHttpClient(&defaults, client)
+}
+
+// mergeHttpClient returns base with every field over sets replaced. Neither is changed.
+func mergeHttpClient(base, over *RunLoadTestHttpClientParam) *RunLoadTestHttpClientParam {
+	var merged RunLoadTestHttpClientParam
+	if base != nil {
+		merged = *base
+	}
+	if over == nil {
+		return &merged
+	}
+	if over.ConnectTimeout != 0 {
+		merged.ConnectTimeout = over.ConnectTimeout
+	}
+	if over.ResponseTimeout != 0 {
+		merged.ResponseTimeout = over.ResponseTimeout
+	}
+	if over.KeepAlive != nil {
+		merged.KeepAlive = over.KeepAlive
+	}
+	if over.FollowRedirects != nil {
+		merged.FollowRedirects = over.FollowRedirects
+	}
+	if over.Implementation != "" {
+		merged.Implementation = over.Implementation
+	}
+	if over.DownloadEmbeddedResources != nil {
+		merged.DownloadEmbeddedResources = over.DownloadEmbeddedResources
+	}
+	if over.TlsVersion != "" {
+		merged.TlsVersion = over.TlsVersion
+	}
+	if over.InsecureSkipVerify != nil {
+		merged.InsecureSkipVerify = over.InsecureSkipVerify
+	}
+	return &merged
+}
+
+// withHttpClient gives every request the options it is sent with, its own over the run's.
+// The requests are copied, so the run's parameters keep saying what was asked for.
+func withHttpClient(httpReqs []RunLoadTestHttpParam, client *RunLoadTestHttpClientParam) []RunLoadTestHttpParam {
+	reqs := make([]RunLoadTestHttpParam, len(httpReqs))
+	copy(reqs, httpReqs)
+	for i := range reqs {
+		reqs[i].HttpClient = mergeHttpClient(client, reqs[i].HttpClient)
+	}
+	return reqs
+}
+
+// ValidateHttpClient rejects client options neither engine can apply. A request's options may
+// not touch TLS, which is set up once for the whole run.
+func ValidateHttpClient(client *RunLoadTestHttpClientParam, perRequest bool) error {
+	if client == nil {
+		return nil
+	}
+	if client.ConnectTimeout < 0 || client.ResponseTimeout < 0 {
+		return errors.New("http client timeouts must not be negative")
+	}
+	switch client.Implementation {
+	case "", constant.HttpClient4Implementation, constant.JavaImplementation:
+	default:
+		return fmt.Errorf("http client implementation %q is not supported", client.Implementation)
+	}
+	switch client.TlsVersion {
+	case "", constant.Tls12, constant.Tls13:
+	default:
+		return fmt.Errorf("tls version %q is not supported", client.TlsVersion)
+	}
+	if perRequest && (client.TlsVersion != "" || client.InsecureSkipVerify != nil) {
+		return errors.New("tlsVersion and insecureSkipVerify apply to the whole run, not to one request")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package load
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestHttpClientOptionsRenderIntoTheSamplers(t *testing.T) {
+	run := &RunLoadTestHttpClientParam{ResponseTimeout: 3000, KeepAlive: boolPtr(false)}
+	out, err := httpReqParseToJmx(withHttpClient([]RunLoadTestHttpParam{
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/a"},
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/b",
+			HttpClient: &RunLoadTestHttpClientParam{ConnectTimeout: 1000, FollowRedirects: boolPtr(false),
+				Implementation: constant.JavaImplementation, DownloadEmbeddedResources: boolPtr(true)}},
+	}, resolveHttpClient(constant.Jmeter, run)), "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	wellFormed(t, out)
+
+	samplers := strings.Split(out, "</HTTPSamplerProxy>")
+	first, second := samplers[0], samplers[1]
+	for _, want := range []string{
+		`<stringProp name="HTTPSampler.connect_timeout">5000</stringProp>`,
+		`<stringProp name="HTTPSampler.response_timeout">3000</stringProp>`,
+		`<boolProp name="HTTPSampler.use_keepalive">false</boolProp>`,
+		`<boolProp name="HTTPSampler.follow_redirects">true</boolProp>`,
+		`<stringProp name="HTTPSampler.implementation">HttpClient4</stringProp>`,
+	} {
+		if !strings.Contains(first, want) {
+			t.Errorf("expected the first sampler to contain %s", want)
+		}
+	}
+	if strings.Contains(first, "image_parser") {
+		t.Error("expected the first sampler not to download embedded resources")
+	}
+	for _, want := range []string{
+		`<stringProp name="HTTPSampler.connect_timeout">1000</stringProp>`,
+		`<stringProp name="HTTPSampler.response_timeout">3000</stringProp>`,
+		`<boolProp name="HTTPSampler.use_keepalive">false</boolProp>`,
+		`<boolProp name="HTTPSampler.follow_redirects">false</boolProp>`,
+		`<stringProp name="HTTPSampler.implementation">Java</stringProp>`,
+		`<boolProp name="HTTPSampler.image_parser">true</boolProp>`,
+	} {
+		if !strings.Contains(second, want) {
+			t.Errorf("expected the second sampler to contain %s", want)
+		}
+	}
+}
+
+func TestTlsVersionIsPassedToJmeter(t *testing.T) {
+	param := RunLoadTestParam{LoadTestKey: "key", HttpClient: &RunLoadTestHttpClientParam{TlsVersion: constant.Tls13}}
+	cmd := (jmeterEngine{}).RunCmd(param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant", InstallVersion: "5.6"})
+	for _, want := range []string{" -Jhttps.default.protocol=TLSv1.3", " -Jhttps.socket.protocols=TLSv1.3"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("expected command to contain %q, got:\n%s", want, cmd)
+		}
+	}
+
+	param.HttpClient.InsecureSkipVerify = boolPtr(false)
+	if err := (jmeterEngine{}).Validate(param); err == nil {
+		t.Error("expected JMeter to refuse to verify certificates")
+	}
+}
+
+func TestK6ScriptCarriesHttpClientOptions(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadTestKey: "key", VirtualUsers: "1", Duration: "1", RampUpTime: "1", RampUpSteps: "1", Engine: constant.K6,
+		HttpClient: &RunLoadTestHttpClientParam{ResponseTimeout: 2500, KeepAlive: boolPtr(false), TlsVersion: constant.Tls12},
+		HttpReqs: []RunLoadTestHttpParam{
+			{Method: "GET", Protocol: "https", Hostname: "h", Port: "443", Path: "/",
+				HttpClient: &RunLoadTestHttpClientParam{FollowRedirects: boolPtr(false)}},
+		},
+	}
+	if err := (k6Engine{}).Validate(param); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (k6Engine{}).RenderPlan(&buf, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`"keepAlive": false`,
+		`"insecureSkipVerify": false`,
+		`"tlsVersion": "tls1.2"`,
+		`"timeout": 2500`,
+		`"followRedirects": false`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected script to contain %s\n%s", want, out)
+		}
+	}
+
+	for name, client := range map[string]*RunLoadTestHttpClientParam{
+		"connect timeout":    {ConnectTimeout: 1000},
+		"implementation":     {Implementation: constant.JavaImplementation},
+		"embedded resources": {DownloadEmbeddedResources: boolPtr(true)},
+	} {
+		param.HttpClient = client
+		if err := (k6Engine{}).Validate(param); err == nil {
+			t.Errorf("%s: expected k6 to refuse it", name)
+		}
+	}
+}
+
+func TestHttpClientIsValidated(t *testing.T) {
+	for name, c := range map[string]*RunLoadTestHttpClientParam{
+		"negative timeout":   {ResponseTimeout: -1},
+		"unknown client":     {Implementation: "OkHttp"},
+		"unknown tls":        {TlsVersion: "SSLv3"},
+		"tls on one request": {TlsVersion: constant.Tls13},
+	} {
+		if err := ValidateHttpClient(c, name == "tls on one request"); err == nil {
+			t.Errorf("%s: expected the options to be rejected", name)
+		}
+	}
+
+	resolved := resolveHttpClient(constant.Jmeter, &RunLoadTestHttpClientParam{ResponseTimeout: 1000})
+	if resolved.ConnectTimeout != 5000 || resolved.ResponseTimeout != 1000 || !*resolved.KeepAlive || !*resolved.InsecureSkipVerify {
+		t.Errorf("expected the JMeter defaults under the given timeout, got %+v", resolved)
+	}
+}
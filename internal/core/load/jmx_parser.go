@@ -1,16 +1,16 @@
 package load
 
 import (
-	"bytes"
 	"fmt"
 	"html"
 	"io"
 	"net/url"
+	"regexp"
 	"strings"
 	"text/template"
 
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
 	"github.com/cloud-barista/cm-ant/internal/utils"
-	"github.com/rs/zerolog/log"
 )
 
 type jmxTemplateData struct {
@@ -20,6 +20,7 @@ type jmxTemplateData struct {
 	RampUpTime        string
 	VirtualUsers      string
 	HttpRequests      string
+	ThreadGroup       string // replaces the concurrency thread group of the template when set
 	AgentHost         string
 	AgentPort         string
 	CpuResultPath     string
@@ -31,28 +32,115 @@ type jmxTemplateData struct {
 }
 
 type jmxHttpTemplateData struct {
-	Method   string                 `json:"method"`
-	Protocol string                 `json:"protocol"`
-	Hostname string                 `json:"hostname"`
-	Port     string                 `json:"port"`
-	Path     string                 `json:"path,omitempty"`
-	Params   []jmxTemplateDataParam `json:"params,omitempty"`
-	BodyData string                 `json:"bodyData,omitempty"`
+	Method    string                 `json:"method"`
+	Protocol  string                 `json:"protocol"`
+	Hostname  string                 `json:"hostname"`
+	Port      string                 `json:"port"`
+	Path      string                 `json:"path,omitempty"`
+	Params    []jmxTemplateDataParam `json:"params,omitempty"`
+	BodyData  string                 `json:"bodyData,omitempty"`
+	RawBody   bool                   `json:"rawBody,omitempty"`
+	Multipart bool                   `json:"multipart,omitempty"`
+	Files     []jmxTemplateDataFile  `json:"files,omitempty"`
+	Headers   []jmxTemplateDataParam `json:"headers,omitempty"`
+
+	// the HTTP client options; embedded resources are fetched six at a time, like a browser
+	ConnectTimeout    int    `json:"connectTimeout"`
+	ResponseTimeout   int    `json:"responseTimeout"`
+	KeepAlive         bool   `json:"keepAlive"`
+	FollowRedirects   bool   `json:"followRedirects"`
+	Implementation    string `json:"implementation"`
+	EmbeddedResources bool   `json:"embeddedResources,omitempty"`
 }
 
 type jmxTemplateDataParam struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Encode bool   `json:"encode,omitempty"`
+}
+
+type jmxTemplateDataFile struct {
+	Path      string `json:"path"`
+	ParamName string `json:"paramName"`
+	MimeType  string `json:"mimeType,omitempty"`
+}
+
+type jmxExtractorTemplateData struct {
+	VarName      string
+	Expression   string
+	MatchNo      int
+	DefaultValue string
+	Template     string
+}
+
+// jmxExtractorTemplate renders the post-processors that run after a sampler. They sit in the
+// sampler's own hashTree, so each one only sees the response of the request it belongs to.
+var jmxExtractorTemplate = map[constant.ExtractorType]string{
+	constant.JsonPathExtractor: `
+		<JSONPostProcessor guiclass="JSONPostProcessorGui" testclass="JSONPostProcessor" testname="{{.VarName}} Extractor" enabled="true">
+			<stringProp name="JSONPostProcessor.referenceNames">{{.VarName}}</stringProp>
+			<stringProp name="JSONPostProcessor.jsonPathExprs">{{.Expression}}</stringProp>
+			<stringProp name="JSONPostProcessor.match_numbers">{{.MatchNo}}</stringProp>
+			<stringProp name="JSONPostProcessor.defaultValues">{{.DefaultValue}}</stringProp>
+		</JSONPostProcessor>
+		<hashTree/>
+	`,
+	constant.RegexExtractor: `
+		<RegexExtractor guiclass="RegexExtractorGui" testclass="RegexExtractor" testname="{{.VarName}} Extractor" enabled="true">
+			<stringProp name="RegexExtractor.useHeaders">false</stringProp>
+			<stringProp name="RegexExtractor.refname">{{.VarName}}</stringProp>
+			<stringProp name="RegexExtractor.regex">{{.Expression}}</stringProp>
+			<stringProp name="RegexExtractor.template">{{.Template}}</stringProp>
+			<stringProp name="RegexExtractor.default">{{.DefaultValue}}</stringProp>
+			<stringProp name="RegexExtractor.match_number">{{.MatchNo}}</stringProp>
+		</RegexExtractor>
+		<hashTree/>
+	`,
+}
+
+// jmxHttpMethods are the methods the sampler can render, mapped to whether the request carries
+// a raw body. A body method keeps its query string in the path: JMeter sends the arguments of a
+// body method as a form body, which would turn ?id=1 into a payload the target never expected.
+var jmxHttpMethods = map[string]bool{
+	"GET":     false,
+	"HEAD":    false,
+	"OPTIONS": false,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+}
+
+// HttpMethodHasBody reports whether requests with this method are rendered with a body.
+func HttpMethodHasBody(method string) bool {
+	return jmxHttpMethods[strings.ToUpper(strings.TrimSpace(method))]
+}
+
+// IsRenderableHttpMethod reports whether a request with this method can be put in a test plan.
+func IsRenderableHttpMethod(method string) bool {
+	_, ok := jmxHttpMethods[strings.ToUpper(strings.TrimSpace(method))]
+	return ok
 }
 
-var jmxHttpSamplerTemplate = map[string]string{
-	"GET": `
+var jmxHttpSamplerTemplate = `
 	<HTTPSamplerProxy guiclass="HttpTestSampleGui" testclass="HTTPSamplerProxy" testname="{{.Method}} Request" enabled="true">
+		{{- if .RawBody }}
+		<boolProp name="HTTPSampler.postBodyRaw">true</boolProp>
+		<elementProp name="HTTPsampler.Arguments" elementType="Arguments">
+			<collectionProp name="Arguments.arguments">
+				<elementProp name="" elementType="HTTPArgument">
+					<boolProp name="HTTPArgument.always_encode">false</boolProp>
+					<stringProp name="Argument.value">{{.BodyData}}</stringProp>
+					<stringProp name="Argument.metadata">=</stringProp>
+				</elementProp>
+			</collectionProp>
+		</elementProp>
+		{{- else }}
 		<elementProp name="HTTPsampler.Arguments" elementType="Arguments" guiclass="HTTPArgumentsPanel" testclass="Arguments" testname="User Defined Variables" enabled="true">
 			<collectionProp name="Arguments.arguments">
 				{{ range .Params }}
 				<elementProp name="{{ .Name }}" elementType="HTTPArgument">
-					<boolProp name="HTTPArgument.always_encode">false</boolProp>
+					<boolProp name="HTTPArgument.always_encode">{{ .Encode }}</boolProp>
 					<stringProp name="Argument.value">{{ .Value }}</stringProp>
 					<stringProp name="Argument.metadata">=</stringProp>
 					<boolProp name="HTTPArgument.use_equals">true</boolProp>
@@ -61,54 +149,231 @@ var jmxHttpSamplerTemplate = map[string]string{
 				{{ end }}
 			</collectionProp>
 		</elementProp>
-		<stringProp name="HTTPSampler.domain">{{.Hostname}}</stringProp>
-		<stringProp name="HTTPSampler.port">{{.Port}}</stringProp>
-		<stringProp name="HTTPSampler.protocol">{{.Protocol}}</stringProp>
-		<stringProp name="HTTPSampler.path">{{.Path}}</stringProp>
-		<stringProp name="HTTPSampler.method">{{.Method}}</stringProp>
-		<stringProp name="HTTPSampler.contentEncoding">UTF-8</stringProp>
-		<boolProp name="HTTPSampler.follow_redirects">true</boolProp>
-		<boolProp name="HTTPSampler.auto_redirects">false</boolProp>
-		<boolProp name="HTTPSampler.use_keepalive">true</boolProp>
-		<boolProp name="HTTPSampler.DO_MULTIPART_POST">false</boolProp>
-		<stringProp name="HTTPSampler.embedded_url_re"></stringProp>
-		<stringProp name="HTTPSampler.connect_timeout">5000</stringProp>
-		<stringProp name="HTTPSampler.response_timeout">60000</stringProp>
-	</HTTPSamplerProxy>
-	<hashTree/>
-	`,
-	"POST": `
-	<HTTPSamplerProxy guiclass="HttpTestSampleGui" testclass="HTTPSamplerProxy" testname="{{.Method}} Request" enabled="true">
-		<boolProp name="HTTPSampler.postBodyRaw">true</boolProp>
-		<elementProp name="HTTPsampler.Arguments" elementType="Arguments">
-			<collectionProp name="Arguments.arguments">
-				<elementProp name="" elementType="HTTPArgument">
-					<boolProp name="HTTPArgument.always_encode">false</boolProp>
-					<stringProp name="Argument.value">{{.BodyData}}</stringProp>
-					<stringProp name="Argument.metadata">=</stringProp>
+		{{- end }}
+		{{- if .Files }}
+		<elementProp name="HTTPsampler.Files" elementType="HTTPFileArgs">
+			<collectionProp name="HTTPFileArgs.files">
+				{{ range .Files }}
+				<elementProp name="{{ .Path }}" elementType="HTTPFileArg">
+					<stringProp name="File.path">{{ .Path }}</stringProp>
+					<stringProp name="File.paramname">{{ .ParamName }}</stringProp>
+					<stringProp name="File.mimetype">{{ .MimeType }}</stringProp>
 				</elementProp>
+				{{ end }}
 			</collectionProp>
 		</elementProp>
+		{{- end }}
 		<stringProp name="HTTPSampler.domain">{{.Hostname}}</stringProp>
 		<stringProp name="HTTPSampler.port">{{.Port}}</stringProp>
 		<stringProp name="HTTPSampler.protocol">{{.Protocol}}</stringProp>
 		<stringProp name="HTTPSampler.path">{{.Path}}</stringProp>
 		<stringProp name="HTTPSampler.method">{{.Method}}</stringProp>
 		<stringProp name="HTTPSampler.contentEncoding">UTF-8</stringProp>
-		<boolProp name="HTTPSampler.follow_redirects">true</boolProp>
+		<boolProp name="HTTPSampler.follow_redirects">{{.FollowRedirects}}</boolProp>
 		<boolProp name="HTTPSampler.auto_redirects">false</boolProp>
-		<boolProp name="HTTPSampler.use_keepalive">true</boolProp>
-		<boolProp name="HTTPSampler.DO_MULTIPART_POST">false</boolProp>
+		<boolProp name="HTTPSampler.use_keepalive">{{.KeepAlive}}</boolProp>
+		<boolProp name="HTTPSampler.DO_MULTIPART_POST">{{.Multipart}}</boolProp>
+		{{- if .EmbeddedResources }}
+		<boolProp name="HTTPSampler.image_parser">true</boolProp>
+		<boolProp name="HTTPSampler.concurrentDwn">true</boolProp>
+		<stringProp name="HTTPSampler.concurrentPool">6</stringProp>
+		{{- end }}
 		<stringProp name="HTTPSampler.embedded_url_re"></stringProp>
-		<stringProp name="HTTPSampler.connect_timeout">5000</stringProp>
-		<stringProp name="HTTPSampler.response_timeout">60000</stringProp>
+		<stringProp name="HTTPSampler.implementation">{{.Implementation}}</stringProp>
+		<stringProp name="HTTPSampler.connect_timeout">{{.ConnectTimeout}}</stringProp>
+		<stringProp name="HTTPSampler.response_timeout">{{.ResponseTimeout}}</stringProp>
 	</HTTPSamplerProxy>
+	`
+
+// jmxHeaderManagerTemplate holds a request's own headers. Placed in the sampler's hashTree it
+// applies to that sampler only, and JMeter lets it win over a plan-wide header of the same name.
+var jmxHeaderManagerTemplate = `
+		<HeaderManager guiclass="HeaderPanel" testclass="HeaderManager" testname="{{.Method}} Request Headers" enabled="true">
+			<collectionProp name="HeaderManager.headers">
+				{{ range .Headers }}
+				<elementProp name="" elementType="Header">
+					<stringProp name="Header.name">{{ .Name }}</stringProp>
+					<stringProp name="Header.value">{{ .Value }}</stringProp>
+				</elementProp>
+				{{ end }}
+			</collectionProp>
+		</HeaderManager>
+		<hashTree/>
+	`
+
+type jmxThreadGroupTemplateData struct {
+	TestName         string
+	TargetLevel      int
+	RampUp           string
+	Steps            string
+	Hold             string
+	ConcurrencyLimit string
+	Rows             []utgRow
+}
+
+// jmxThreadGroupTemplate renders the thread groups of the shapes the concurrency thread group
+// in default.jmx cannot express. Both come with jpgc-casutg, which the generator install puts
+// in place.
+var jmxThreadGroupTemplate = map[constant.LoadShape]string{
+	// Unit S makes TargetLevel iterations started per second.
+	constant.ArrivalRateShape: `<com.blazemeter.jmeter.threads.arrivals.ArrivalsThreadGroup guiclass="com.blazemeter.jmeter.threads.arrivals.ArrivalsThreadGroupGui" testclass="com.blazemeter.jmeter.threads.arrivals.ArrivalsThreadGroup" testname="{{.TestName}} Thread Group" enabled="true">
+        <elementProp name="ThreadGroup.main_controller" elementType="com.blazemeter.jmeter.control.VirtualUserController"/>
+        <stringProp name="ThreadGroup.on_sample_error">continue</stringProp>
+        <stringProp name="TargetLevel">{{.TargetLevel}}</stringProp>
+        <stringProp name="RampUp">{{.RampUp}}</stringProp>
+        <stringProp name="Steps">{{.Steps}}</stringProp>
+        <stringProp name="Hold">{{.Hold}}</stringProp>
+        <stringProp name="LogFilename"></stringProp>
+        <stringProp name="Iterations"></stringProp>
+        <stringProp name="Unit">S</stringProp>
+        <stringProp name="ConcurrencyLimit">{{.ConcurrencyLimit}}</stringProp>
+      </com.blazemeter.jmeter.threads.arrivals.ArrivalsThreadGroup>`,
+	constant.StagesShape: `<kg.apc.jmeter.threads.UltimateThreadGroup guiclass="kg.apc.jmeter.threads.UltimateThreadGroupGui" testclass="kg.apc.jmeter.threads.UltimateThreadGroup" testname="{{.TestName}} Thread Group" enabled="true">
+        <collectionProp name="ultimatethreadgroupdata">
+          {{- range $i, $row := .Rows }}
+          <collectionProp name="{{$i}}">
+            <stringProp name="threads">{{$row.Threads}}</stringProp>
+            <stringProp name="initial_delay">{{$row.InitialDelay}}</stringProp>
+            <stringProp name="startup_time">{{$row.StartupTime}}</stringProp>
+            <stringProp name="hold_load">{{$row.HoldLoad}}</stringProp>
+            <stringProp name="shutdown_time">{{$row.ShutdownTime}}</stringProp>
+          </collectionProp>
+          {{- end }}
+        </collectionProp>
+        <elementProp name="ThreadGroup.main_controller" elementType="LoopController" guiclass="LoopControlPanel" testclass="LoopController" testname="Loop Controller" enabled="true">
+          <boolProp name="LoopController.continue_forever">false</boolProp>
+          <intProp name="LoopController.loops">-1</intProp>
+        </elementProp>
+        <stringProp name="ThreadGroup.on_sample_error">continue</stringProp>
+      </kg.apc.jmeter.threads.UltimateThreadGroup>`,
+}
+
+// threadGroupParseToJmx renders the thread group for the run's load shape, or "" to keep the
+// template's concurrency thread group. The spike, step down and stage shapes all become an
+// Ultimate Thread Group.
+func threadGroupParseToJmx(param RunLoadTestParam) (string, error) {
+	data := jmxThreadGroupTemplateData{TestName: html.EscapeString(param.TestName)}
+
+	shape := loadShapeOf(param)
+	switch shape {
+	case constant.ConcurrencyShape:
+		return "", nil
+	case constant.ArrivalRateShape:
+		rate, err := scenarioNumber("arrival rate", param.ArrivalRate)
+		if err != nil {
+			return "", err
+		}
+		data.TargetLevel = rate
+		data.RampUp = html.EscapeString(param.RampUpTime)
+		data.Steps = html.EscapeString(param.RampUpSteps)
+		data.Hold = html.EscapeString(param.Duration)
+		data.ConcurrencyLimit = html.EscapeString(param.VirtualUsers)
+	case constant.SpikeShape, constant.StepDownShape, constant.StagesShape:
+		stages, err := loadStagesOf(param)
+		if err != nil {
+			return "", err
+		}
+		data.Rows = ultimateThreadGroupRows(stages)
+		shape = constant.StagesShape
+	default:
+		return "", fmt.Errorf("load shape %q cannot be rendered into a test plan", shape)
+	}
+
+	tmpl, err := template.New(fmt.Sprintf("jmxThreadGroup-%s", shape)).Parse(jmxThreadGroupTemplate[shape])
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// jmxThinkTimeTemplate renders a request's think time. A timer in the sampler's hashTree
+// delays that sampler only, before it is sent.
+var jmxThinkTimeTemplate = map[constant.ThinkTimeType]string{
+	constant.ConstantThinkTime: `
+		<ConstantTimer guiclass="ConstantTimerGui" testclass="ConstantTimer" testname="{{.Method}} Request Think Time" enabled="true">
+			<stringProp name="ConstantTimer.delay">{{.ThinkTime.Delay}}</stringProp>
+		</ConstantTimer>
+		<hashTree/>
+	`,
+	constant.UniformThinkTime: `
+		<UniformRandomTimer guiclass="UniformRandomTimerGui" testclass="UniformRandomTimer" testname="{{.Method}} Request Think Time" enabled="true">
+			<stringProp name="ConstantTimer.delay">{{.ThinkTime.Delay}}</stringProp>
+			<stringProp name="RandomTimer.range">{{.ThinkTime.Range}}</stringProp>
+		</UniformRandomTimer>
+		<hashTree/>
+	`,
+	constant.GaussianThinkTime: `
+		<GaussianRandomTimer guiclass="GaussianRandomTimerGui" testclass="GaussianRandomTimer" testname="{{.Method}} Request Think Time" enabled="true">
+			<stringProp name="ConstantTimer.delay">{{.ThinkTime.Delay}}</stringProp>
+			<stringProp name="RandomTimer.range">{{.ThinkTime.Range}}</stringProp>
+		</GaussianRandomTimer>
+		<hashTree/>
 	`,
 }
 
+// jmxMixTemplate holds the weighted requests of a plan in one switch controller, which runs
+// exactly one of its children an iteration: the one at the index its value comes to. The value
+// draws that index afresh every iteration, each child as often as its weight asks for.
+var jmxMixTemplate = `
+	<SwitchController guiclass="SwitchControllerGui" testclass="SwitchController" testname="Request Mix" enabled="true">
+		<stringProp name="SwitchController.value">{{.}}</stringProp>
+	</SwitchController>
+	`
+
+// jmxMixChoice is the switch value that draws the index of one of the weighted requests, each
+// in proportion to its weight: 16/3/1 gives ${__groovy(def r = ...nextInt(20); r < 16 ? 0 :
+// r < 19 ? 1 : 2)}. A function argument ends at a comma, so the script has none. It is escaped
+// for the XML it is written into.
+func jmxMixChoice(weights []int) string {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	var choice strings.Builder
+	fmt.Fprintf(&choice, "def r = java.util.concurrent.ThreadLocalRandom.current().nextInt(%d); ", total)
+	upTo := 0
+	for i, w := range weights[:len(weights)-1] {
+		upTo += w
+		fmt.Fprintf(&choice, "r < %d ? %d : ", upTo, i)
+	}
+	fmt.Fprintf(&choice, "%d", len(weights)-1)
+	return "${__groovy(" + html.EscapeString(choice.String()) + ")}"
+}
+
+type jmxRequestTemplateData struct {
+	Method    string
+	ThinkTime *RunLoadTestThinkTimeParam
+}
+
+// jmeterVarRef matches a ${name} reference, which must reach JMeter exactly as written.
+var jmeterVarRef = regexp.MustCompile(`\$\{[^}]*\}`)
+
 func parseTestPlanStructToString(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error {
+	if param.TestPlan != nil {
+		return parseUploadedTestPlan(w, param, loadGeneratorInstallInfo)
+	}
+
 	resultPath := fmt.Sprintf("%s/result", loadGeneratorInstallInfo.InstallPath)
-	httpRequests, err := httpReqParseToJmx(param.HttpReqs)
+	attachmentDir := planAttachmentDir(loadGeneratorInstallInfo.InstallPath, param.LoadTestKey)
+	httpReqs := withHttpClient(withDefaultThinkTime(param.HttpReqs, param.ThinkTime), resolveHttpClient(constant.Jmeter, param.HttpClient))
+	httpRequests, err := httpReqParseToJmx(httpReqs, attachmentDir)
+	if err != nil {
+		return err
+	}
+	targets, err := targetParseToJmx(param.Targets, attachmentDir)
+	if err != nil {
+		return err
+	}
+	dataSets, err := dataSetParseToJmx(param.DataSets, attachmentDir)
+	if err != nil {
+		return err
+	}
+
+	threadGroup, err := threadGroupParseToJmx(param)
 	if err != nil {
 		return err
 	}
@@ -123,7 +388,8 @@ func parseTestPlanStructToString(w io.Writer, param RunLoadTestParam, loadGenera
 		RampUpSteps:  param.RampUpSteps,
 		RampUpTime:   param.RampUpTime,
 		VirtualUsers: param.VirtualUsers,
-		HttpRequests: httpRequests,
+		HttpRequests: dataSets + httpRequests + targets,
+		ThreadGroup:  threadGroup,
 	}
 
 	if agentHost != "" {
@@ -134,7 +400,10 @@ func parseTestPlanStructToString(w io.Writer, param RunLoadTestParam, loadGenera
 		jmxTemplateData.DiskResultPath = fmt.Sprintf("%s/%s_disk_result.csv", resultPath, param.LoadTestKey)
 		jmxTemplateData.NetworkResultPath = fmt.Sprintf("%s/%s_network_result.csv", resultPath, param.LoadTestKey)
 
-		tmpl, err = template.ParseFiles(utils.JoinRootPathWith("/test_plan/default_perfmon.jmx"))
+		tmpl, err = template.ParseFiles(
+			utils.JoinRootPathWith("/test_plan/default_perfmon.jmx"),
+			utils.JoinRootPathWith("/test_plan/perfmon_collectors.jmx"),
+		)
 	} else {
 		tmpl, err = template.ParseFiles(utils.JoinRootPathWith("/test_plan/default.jmx"))
 	}
@@ -151,69 +420,251 @@ func parseTestPlanStructToString(w io.Writer, param RunLoadTestParam, loadGenera
 	return nil
 }
 
-func httpReqParseToJmx(httpReqs []RunLoadTestHttpParam) (string, error) {
-	var builder strings.Builder
+func httpReqParseToJmx(httpReqs []RunLoadTestHttpParam, attachmentDir string) (string, error) {
+	samplerTmpl, err := template.New("jmxHttpSampler").Parse(jmxHttpSamplerTemplate)
+	if err != nil {
+		return "", err
+	}
+	headerTmpl, err := template.New("jmxHeaderManager").Parse(jmxHeaderManagerTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	mixTmpl, err := template.New("jmxMix").Parse(jmxMixTemplate)
+	if err != nil {
+		return "", err
+	}
+	timerTmpls := make(map[constant.ThinkTimeType]*template.Template, len(jmxThinkTimeTemplate))
+	for t, text := range jmxThinkTimeTemplate {
+		timerTmpls[t], err = template.New("jmxThinkTime-" + string(t)).Parse(text)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// the weighted requests go together where the first of them is, the others stay in order
+	var builder, mix strings.Builder
+	var weights []int
+	mixAt := -1
 	for i, req := range httpReqs {
-		method := strings.ToUpper(req.Method)
-		jmxTemplate, ok := jmxHttpSamplerTemplate[method]
+		if strings.TrimSpace(req.Hostname) == "" || strings.TrimSpace(req.Port) == "" {
+			continue
+		}
 
-		if ok {
-			tmpl, err := template.New(fmt.Sprintf("jmxTemplate-%d-%s", i, method)).Parse(jmxTemplate)
-			if err != nil {
-				return "", err
+		data, err := httpSamplerTemplateData(i, req, attachmentDir)
+		if err != nil {
+			return "", err
+		}
+		client := resolveHttpClient(constant.Jmeter, req.HttpClient)
+		data.ConnectTimeout = client.ConnectTimeout
+		data.ResponseTimeout = client.ResponseTimeout
+		data.KeepAlive = *client.KeepAlive
+		data.FollowRedirects = *client.FollowRedirects
+		data.Implementation = string(client.Implementation)
+		data.EmbeddedResources = *client.DownloadEmbeddedResources
+		timed := jmxRequestTemplateData{Method: data.Method, ThinkTime: req.ThinkTime}
+
+		out := &builder
+		if req.Weight > 0 {
+			if mixAt < 0 {
+				mixAt = builder.Len()
 			}
+			weights = append(weights, req.Weight)
+			out = &mix
+		}
 
-			parsedUrl, err := url.Parse(req.Path)
-			if err != nil {
+		if err := samplerTmpl.Execute(out, data); err != nil {
+			return "", fmt.Errorf("request %d cannot be rendered into a test plan; %w", i+1, err)
+		}
+
+		out.WriteString("<hashTree>")
+		if len(data.Headers) > 0 {
+			if err := headerTmpl.Execute(out, data); err != nil {
 				return "", err
 			}
-
-			h := strings.TrimSpace(req.Hostname)
-			if h == "" {
-				continue
+		}
+		if timed.ThinkTime != nil {
+			timerTmpl, ok := timerTmpls[timed.ThinkTime.Type]
+			if !ok {
+				return "", fmt.Errorf("think time type %q cannot be rendered into a test plan", timed.ThinkTime.Type)
 			}
-
-			p := strings.TrimSpace(req.Port)
-			if p == "" {
-				continue
+			if err := timerTmpl.Execute(out, timed); err != nil {
+				return "", err
 			}
+		}
+		children, err := extractorParseToJmx(req.Extractors)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(children)
+		assertions, err := assertionParseToJmx(req.Assertions)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(assertions)
+		out.WriteString("</hashTree>")
 
-			jmxHttpTemplateData := jmxHttpTemplateData{
-				Method:   method,
-				Protocol: req.Protocol,
-				Hostname: h,
-				Port:     p,
-				Path:     parsedUrl.Path,
-			}
+		out.WriteString("\n")
+	}
+	if mixAt < 0 {
+		return builder.String(), nil
+	}
 
-			if strings.ToUpper(method) == "GET" {
-				params := make([]jmxTemplateDataParam, 0)
-				parsedParams := parsedUrl.Query()
-				for key, values := range parsedParams {
-					param := jmxTemplateDataParam{
-						Name:  key,
-						Value: values[0],
-					}
-
-					params = append(params, param)
-				}
-				jmxHttpTemplateData.Params = params
-			} else if method == "POST" {
-				jmxHttpTemplateData.BodyData = ConvertToHTMLEntities(req.BodyData)
-			}
+	var switchController strings.Builder
+	if err := mixTmpl.Execute(&switchController, jmxMixChoice(weights)); err != nil {
+		return "", err
+	}
+	result := builder.String()
+	return result[:mixAt] + switchController.String() + "<hashTree>" + mix.String() + "</hashTree>\n" + result[mixAt:], nil
+}
+
+// httpSamplerTemplateData turns one request into what the sampler template needs. The template
+// writes values verbatim, so anything that can carry user text is escaped for XML here; ${var}
+// references pass through untouched and are resolved by JMeter at run time.
+func httpSamplerTemplateData(index int, req RunLoadTestHttpParam, attachmentDir string) (jmxHttpTemplateData, error) {
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	bodyMethod, ok := jmxHttpMethods[method]
+
+	// An unknown method used to be skipped, which left a plan with nothing in it and a run
+	// that "succeeded" without sending a single request. Say so instead.
+	if !ok {
+		return jmxHttpTemplateData{}, fmt.Errorf("http method %q cannot be rendered into a test plan", req.Method)
+	}
+
+	parsedUrl, err := url.Parse(req.Path)
+	if err != nil {
+		return jmxHttpTemplateData{}, err
+	}
+
+	data := jmxHttpTemplateData{
+		Method:   method,
+		Protocol: html.EscapeString(strings.TrimSpace(req.Protocol)),
+		Hostname: html.EscapeString(strings.TrimSpace(req.Hostname)),
+		Port:     html.EscapeString(strings.TrimSpace(req.Port)),
+		Path:     html.EscapeString(parsedUrl.Path),
+	}
+
+	for _, h := range req.Headers {
+		data.Headers = append(data.Headers, jmxTemplateDataParam{
+			Name:  html.EscapeString(h.Name),
+			Value: html.EscapeString(h.Value),
+		})
+	}
+
+	if !bodyMethod {
+		// Without a body, the query is sent as sampler arguments: the ones written into the
+		// path first, then the explicit ones.
+		params := make([]jmxTemplateDataParam, 0)
+		for key, values := range parsedUrl.Query() {
+			params = append(params, jmxTemplateDataParam{
+				Name:  html.EscapeString(key),
+				Value: html.EscapeString(values[0]),
+			})
+		}
+		for _, q := range req.QueryParams {
+			params = append(params, jmxTemplateDataParam{
+				Name:   html.EscapeString(q.Name),
+				Value:  html.EscapeString(q.Value),
+				Encode: true,
+			})
+		}
+		data.Params = params
+		return data, nil
+	}
+
+	// A body method's arguments are its body, so the query has to stay in the path.
+	query := parsedUrl.RawQuery
+	for _, q := range req.QueryParams {
+		if query != "" {
+			query += "&"
+		}
+		query += queryEscapeKeepingVars(q.Name) + "=" + queryEscapeKeepingVars(q.Value)
+	}
+	if query != "" {
+		data.Path = html.EscapeString(parsedUrl.Path + "?" + query)
+	}
 
-			var buf bytes.Buffer
-			err = tmpl.Execute(&buf, jmxHttpTemplateData)
-			if err != nil {
-				log.Error().Msgf("Error executing template: %s", err)
+	switch req.BodyType {
+	case constant.FormBody, constant.MultipartBody:
+		for _, f := range req.FormParams {
+			data.Params = append(data.Params, jmxTemplateDataParam{
+				Name:   html.EscapeString(f.Name),
+				Value:  html.EscapeString(f.Value),
+				Encode: true,
+			})
+		}
+		if req.BodyType == constant.MultipartBody {
+			data.Multipart = true
+			for _, f := range req.Files {
+				data.Files = append(data.Files, jmxTemplateDataFile{
+					Path:      html.EscapeString(fmt.Sprintf("%s/%s", attachmentDir, attachmentName(index, f.FileName))),
+					ParamName: html.EscapeString(f.ParamName),
+					MimeType:  html.EscapeString(f.MimeType),
+				})
 			}
-			builder.WriteString(buf.String())
 		}
+	case constant.RawBody, "":
+		data.RawBody = true
+		data.BodyData = ConvertToHTMLEntities(req.BodyData)
+	default:
+		return jmxHttpTemplateData{}, fmt.Errorf("body type %q cannot be rendered into a test plan", req.BodyType)
+	}
 
-		builder.WriteString("\n")
+	return data, nil
+}
+
+// queryEscapeKeepingVars escapes a query component but leaves ${name} references alone, since
+// an escaped reference would be sent literally instead of being resolved.
+func queryEscapeKeepingVars(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range jmeterVarRef.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
 	}
-	result := builder.String()
-	return result, nil
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
+
+// extractorParseToJmx renders the extractors of one request into JMeter post-processors.
+func extractorParseToJmx(extractors []RunLoadTestExtractorParam) (string, error) {
+	var builder strings.Builder
+	for i, e := range extractors {
+		jmxTemplate, ok := jmxExtractorTemplate[e.Type]
+		if !ok {
+			return "", fmt.Errorf("unsupported extractor type %q for %s", e.Type, e.VarName)
+		}
+
+		tmpl, err := template.New(fmt.Sprintf("jmxExtractor-%d-%s", i, e.Type)).Parse(jmxTemplate)
+		if err != nil {
+			return "", err
+		}
+
+		data := jmxExtractorTemplateData{
+			VarName:      html.EscapeString(e.VarName),
+			Expression:   html.EscapeString(e.Expression),
+			MatchNo:      e.MatchNo,
+			DefaultValue: html.EscapeString(e.DefaultValue),
+			Template:     regexTemplateOf(e.Expression),
+		}
+
+		if err := tmpl.Execute(&builder, data); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// regexTemplateOf keeps the first capture group when the expression has one and the whole
+// match otherwise, so `"token":"(.+?)"` yields the token and a bare pattern still yields
+// something. JMeter regexes are Java's; an expression Go cannot parse is assumed to group.
+func regexTemplateOf(expression string) string {
+	re, err := regexp.Compile(expression)
+	if err == nil && re.NumSubexp() == 0 {
+		return "$0$"
+	}
+	return "$1$"
 }
 
 func ConvertToHTMLEntities(jsonStr string) string {
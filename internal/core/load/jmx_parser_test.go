@@ -0,0 +1,329 @@
+package load
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// wellFormed wraps a rendered fragment in a root element and checks it parses as XML. JMeter
+// refuses the whole plan over one bad character, and it only finds out on the generator.
+func wellFormed(t *testing.T, fragment string) {
+	t.Helper()
+	d := xml.NewDecoder(strings.NewReader("<root>" + fragment + "</root>"))
+	for {
+		_, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			t.Fatalf("rendered plan is not well-formed: %v\n%s", err, fragment)
+		}
+	}
+}
+
+// A login -> token -> API flow: the first request extracts, the second references it.
+func TestChainedRequestsRenderExtractorsAndKeepVariables(t *testing.T) {
+	out, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{
+			Method: "POST", Protocol: "http", Hostname: "api.example.com", Port: "80", Path: "/login",
+			BodyData: `{"user":"a","password":"<b>"}`,
+			Extractors: []RunLoadTestExtractorParam{
+				{Type: constant.JsonPathExtractor, VarName: "token", Expression: "$.data[?(@.kind<2)].token", MatchNo: 1, DefaultValue: "NOT_FOUND"},
+				{Type: constant.RegexExtractor, VarName: "session", Expression: `"session":"(.+?)"`, MatchNo: 1},
+			},
+		},
+		{
+			Method: "GET", Protocol: "http", Hostname: "api.example.com", Port: "80", Path: "/orders/${session}?auth=${token}",
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+
+	for _, want := range []string{
+		`<stringProp name="JSONPostProcessor.referenceNames">token</stringProp>`,
+		`<stringProp name="JSONPostProcessor.jsonPathExprs">$.data[?(@.kind&lt;2)].token</stringProp>`,
+		`<stringProp name="JSONPostProcessor.defaultValues">NOT_FOUND</stringProp>`,
+		`<stringProp name="RegexExtractor.refname">session</stringProp>`,
+		`<stringProp name="RegexExtractor.template">$1$</stringProp>`,
+		`<stringProp name="HTTPSampler.path">/orders/${session}</stringProp>`,
+		`<stringProp name="Argument.value">${token}</stringProp>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %s", want)
+		}
+	}
+
+	// the extractors must belong to the login request, not to the one that uses the values
+	login := out[:strings.Index(out, "/orders/")]
+	if !strings.Contains(login, "JSONPostProcessor") || !strings.Contains(login, "RegexExtractor") {
+		t.Errorf("extractors were not rendered under the request that produces the values")
+	}
+}
+
+func TestRegexWithoutGroupKeepsWholeMatch(t *testing.T) {
+	if got := regexTemplateOf(`[0-9a-f]{32}`); got != "$0$" {
+		t.Errorf("expected whole match for a pattern without a group, got %s", got)
+	}
+	if got := regexTemplateOf(`id=(\d+)`); got != "$1$" {
+		t.Errorf("expected first group, got %s", got)
+	}
+}
+
+func TestUnknownExtractorTypeIsRejected(t *testing.T) {
+	_, err := httpReqParseToJmx([]RunLoadTestHttpParam{{
+		Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/",
+		Extractors: []RunLoadTestExtractorParam{{Type: "xpath", VarName: "v", Expression: "//a"}},
+	}}, "")
+	if err == nil {
+		t.Fatal("expected an unknown extractor type to fail rendering")
+	}
+}
+
+func TestEveryMethodRendersItsOwnSampler(t *testing.T) {
+	out, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{Method: "put", Protocol: "http", Hostname: "h", Port: "80", Path: "/items/1?version=2", BodyData: `{"name":"x"}`},
+		{Method: "PATCH", Protocol: "http", Hostname: "h", Port: "80", Path: "/items/1", BodyData: `{"name":"y"}`},
+		{Method: "DELETE", Protocol: "http", Hostname: "h", Port: "80", Path: "/items/1"},
+		{Method: "HEAD", Protocol: "http", Hostname: "h", Port: "80", Path: "/items?page=1"},
+		{Method: "OPTIONS", Protocol: "http", Hostname: "h", Port: "80", Path: "/items"},
+	}, "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+
+	if n := strings.Count(out, "<HTTPSamplerProxy "); n != 5 {
+		t.Fatalf("expected 5 samplers, got %d", n)
+	}
+	for _, m := range []string{"PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"} {
+		if !strings.Contains(out, `<stringProp name="HTTPSampler.method">`+m+`</stringProp>`) {
+			t.Errorf("expected a %s sampler", m)
+		}
+	}
+
+	// a body method keeps its query in the path rather than sending it as a form body
+	if !strings.Contains(out, `<stringProp name="HTTPSampler.path">/items/1?version=2</stringProp>`) {
+		t.Errorf("expected the PUT query string to stay in the path")
+	}
+	if !strings.Contains(out, `<stringProp name="Argument.value">{&#34;name&#34;:&#34;x&#34;}</stringProp>`) {
+		t.Errorf("expected the PUT body to be rendered raw")
+	}
+	if !strings.Contains(out, `<stringProp name="Argument.name">page</stringProp>`) {
+		t.Errorf("expected the HEAD query to be rendered as arguments")
+	}
+}
+
+func TestUnrenderableMethodIsAnError(t *testing.T) {
+	_, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/"},
+		{Method: "TRACE", Protocol: "http", Hostname: "h", Port: "80", Path: "/"},
+	}, "")
+	if err == nil {
+		t.Fatal("expected TRACE to fail rather than be dropped from the plan")
+	}
+}
+
+func TestRequestHeadersQueryAndFormBodies(t *testing.T) {
+	out, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{
+			Method: "POST", Protocol: "http", Hostname: "h", Port: "80", Path: "/login?lang=ko",
+			Headers:     []RunLoadTestNameValueParam{{Name: "X-Tenant", Value: "acme"}},
+			QueryParams: []RunLoadTestNameValueParam{{Name: "redirect", Value: "/home page"}, {Name: "sid", Value: "${sid}"}},
+			BodyType:    constant.FormBody,
+			FormParams:  []RunLoadTestNameValueParam{{Name: "user", Value: "a&b"}},
+		},
+		{
+			Method: "POST", Protocol: "http", Hostname: "h", Port: "80", Path: "/upload",
+			Headers:    []RunLoadTestNameValueParam{{Name: "Authorization", Value: "Bearer ${token}"}},
+			BodyType:   constant.MultipartBody,
+			FormParams: []RunLoadTestNameValueParam{{Name: "title", Value: "report"}},
+			Files:      []RunLoadTestFileParam{{ParamName: "file", FileName: "report.pdf", MimeType: "application/pdf", Content: []byte("%PDF")}},
+		},
+	}, "/opt/ant/test_plan/key")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+
+	for _, want := range []string{
+		`<stringProp name="Header.name">X-Tenant</stringProp>`,
+		`<stringProp name="Header.value">Bearer ${token}</stringProp>`,
+		`<stringProp name="HTTPSampler.path">/login?lang=ko&amp;redirect=%2Fhome+page&amp;sid=${sid}</stringProp>`,
+		`<stringProp name="Argument.value">a&amp;b</stringProp>`,
+		`<boolProp name="HTTPSampler.DO_MULTIPART_POST">true</boolProp>`,
+		`<stringProp name="File.path">/opt/ant/test_plan/key/1_report.pdf</stringProp>`,
+		`<stringProp name="File.paramname">file</stringProp>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %s", want)
+		}
+	}
+
+	// each request's headers stay with that request
+	first := out[:strings.Index(out, "/upload")]
+	if strings.Contains(first, "Authorization") {
+		t.Errorf("headers of the second request leaked into the first")
+	}
+	if strings.Contains(first, "postBodyRaw") {
+		t.Errorf("a form body must not be sent raw")
+	}
+}
+
+func TestProtocolIsEscaped(t *testing.T) {
+	out, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{Method: "GET", Protocol: " https</stringProp><x>&", Hostname: "h", Port: "443", Path: "/"},
+	}, "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	wellFormed(t, out)
+	if want := `<stringProp name="HTTPSampler.protocol">https&lt;/stringProp&gt;&lt;x&gt;&amp;</stringProp>`; !strings.Contains(out, want) {
+		t.Errorf("expected plan to contain %s", want)
+	}
+}
+
+func TestAttachmentsAreWrittenNextToThePlan(t *testing.T) {
+	files := planAttachments(RunLoadTestParam{HttpReqs: []RunLoadTestHttpParam{
+		{Files: []RunLoadTestFileParam{{FileName: "a.bin", Content: []byte{0, 1, 2}}}},
+		{Files: []RunLoadTestFileParam{{FileName: "../a.bin", Content: []byte{3}}}},
+	}})
+	if len(files) != 2 || files[0].Name != "0_a.bin" || files[1].Name != "1_a.bin" {
+		t.Fatalf("unexpected attachment names: %+v", files)
+	}
+
+	cmd := writeAttachmentsCmd("/opt/ant/test_plan/key", files)
+	for _, want := range []string{
+		"mkdir -p /opt/ant/test_plan/key\n",
+		"base64 -d > /opt/ant/test_plan/key/0_a.bin << 'EOF'\nAAEC\nEOF\n",
+		"base64 -d > /opt/ant/test_plan/key/1_a.bin << 'EOF'\nAw==\nEOF\n",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("expected command to contain %q, got:\n%s", want, cmd)
+		}
+	}
+}
+
+// jmxElement is a rendered plan element with its children, to check how a plan nests.
+type jmxElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []jmxElement `xml:",any"`
+}
+
+// names returns the names of the elements, in order.
+func names(elements []jmxElement) []string {
+	var ns []string
+	for _, e := range elements {
+		ns = append(ns, e.XMLName.Local)
+	}
+	return ns
+}
+
+func jmxTree(t *testing.T, fragment string) []jmxElement {
+	t.Helper()
+	var root jmxElement
+	if err := xml.Unmarshal([]byte("<root>"+fragment+"</root>"), &root); err != nil {
+		t.Fatalf("rendered plan is not well-formed: %v\n%s", err, fragment)
+	}
+	return root.Children
+}
+
+// 16/3/1 is the same mix as 80/15/5: the weighted requests sit under one switch controller,
+// which sends exactly one of them an iteration, and an unweighted request stays outside it.
+func TestWeightedRequestsRunInOneSwitchController(t *testing.T) {
+	out, err := httpReqParseToJmx(withDefaultThinkTime([]RunLoadTestHttpParam{
+		{Method: "POST", Protocol: "http", Hostname: "h", Port: "80", Path: "/login"},
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/browse", Weight: 16},
+		{Method: "POST", Protocol: "http", Hostname: "h", Port: "80", Path: "/cart", Weight: 3,
+			ThinkTime: &RunLoadTestThinkTimeParam{Type: constant.UniformThinkTime, Delay: 100, Range: 400}},
+		{Method: "DELETE", Protocol: "http", Hostname: "h", Port: "80", Path: "/session"},
+		{Method: "PUT", Protocol: "http", Hostname: "h", Port: "80", Path: "/checkout", Weight: 1},
+	}, &RunLoadTestThinkTimeParam{Type: constant.GaussianThinkTime, Delay: 1000, Range: 300}), "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	tree := jmxTree(t, out)
+	want := []string{"HTTPSamplerProxy", "hashTree", "SwitchController", "hashTree", "HTTPSamplerProxy", "hashTree"}
+	if got := names(tree); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected the mix where its first request is, between login and session, got %v", got)
+	}
+	if strings.Contains(out, "ThroughputController") {
+		t.Error("expected no throughput controllers, each of which would pick on its own")
+	}
+
+	choice := tree[2].Children[0]
+	if choice.XMLName.Local != "stringProp" || choice.Text != "${__groovy(def r = java.util.concurrent.ThreadLocalRandom.current().nextInt(20); r < 16 ? 0 : r < 19 ? 1 : 2)}" {
+		t.Errorf("expected the switch to draw one of three children by weight, got %+v", choice)
+	}
+	if strings.Contains(choice.Text, ",") {
+		t.Errorf("expected no comma to cut the function argument short, got %s", choice.Text)
+	}
+
+	// the switch counts its children, so the samplers are its only ones
+	mix := tree[3].Children
+	if got := names(mix); strings.Join(got, " ") != "HTTPSamplerProxy hashTree HTTPSamplerProxy hashTree HTTPSamplerProxy hashTree" {
+		t.Fatalf("expected the three weighted samplers in the switch, got %v", got)
+	}
+	for i, method := range []string{"GET", "POST", "PUT"} {
+		if name := attrOf(mix[2*i], "testname"); name != method+" Request" {
+			t.Errorf("expected child %d of the switch to be the %s request, got %s", i, method, name)
+		}
+	}
+	if timers := names(mix[3].Children); !strings.Contains(strings.Join(timers, " "), "UniformRandomTimer") {
+		t.Errorf("expected the cart request to keep its own think time, got %v", timers)
+	}
+	if got := strings.Count(out, `testclass="GaussianRandomTimer"`); got != 4 {
+		t.Errorf("expected the run's think time on the 4 requests without their own, got %d", got)
+	}
+}
+
+func attrOf(e jmxElement, name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func TestMixChoiceOfOneWeightedRequest(t *testing.T) {
+	if got := jmxMixChoice([]int{5}); got != "${__groovy(def r = java.util.concurrent.ThreadLocalRandom.current().nextInt(5); 0)}" {
+		t.Errorf("expected a lone weighted request to be picked every time, got %s", got)
+	}
+}
+
+func TestConstantThinkTimeRendersATimer(t *testing.T) {
+	out, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/",
+			ThinkTime: &RunLoadTestThinkTimeParam{Type: constant.ConstantThinkTime, Delay: 250}},
+	}, "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	wellFormed(t, out)
+	if !strings.Contains(out, `<stringProp name="ConstantTimer.delay">250</stringProp>`) || strings.Contains(out, "ThroughputController") {
+		t.Errorf("expected a constant timer and no throughput controller\n%s", out)
+	}
+}
+
+func TestUnrenderableThinkTimeIsAnError(t *testing.T) {
+	_, err := httpReqParseToJmx([]RunLoadTestHttpParam{
+		{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/",
+			ThinkTime: &RunLoadTestThinkTimeParam{Type: "poisson", Delay: 250}},
+	}, "")
+	if err == nil {
+		t.Fatal("expected a think time with no timer to fail rather than be dropped from the plan")
+	}
+}
@@ -0,0 +1,191 @@
+package load
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// k6's csv output is read into the rows the JMeter result parser produces.
+//
+// k6 writes one row per metric rather than one per request: a request shows up as http_reqs
+// followed by its http_req_* timings, written together. A request therefore starts at its
+// http_reqs row and takes the timings that follow it. The timings are added up to the figures
+// JMeter reports, whose elapsed, latency and connect times all include setting up the
+// connection.
+//
+// k6 counts transferred bytes per iteration, not per request, so Bytes and SentBytes stay 0
+// and the KB/s figures of a k6 run are 0 as well.
+
+// scanK6ResultRawData reads k6's csv output a row at a time, handing on each request once its
+// timings are in.
+func scanK6ResultRawData(filePath string, fn func(label string, r *ResultRawData)) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Error().Msgf("can not open file: %s; %s", filePath, err)
+		return err
+	}
+	defer file.Close()
+
+	return scanK6ResultRows(file, filePath, fn)
+}
+
+// scanK6ResultRows reads k6 result rows, the header first, from r; source names it in the log.
+func scanK6ResultRows(r io.Reader, source string, fn func(label string, r *ResultRawData)) error {
+	reader := csv.NewReader(bufio.NewReaderSize(r, 1<<20))
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return errors.New("result data file is empty")
+		}
+		return err
+	}
+
+	// columns are looked up by name; k6 has added columns between releases
+	column := map[string]int{}
+	for i, name := range header {
+		column[name] = i
+	}
+	for _, name := range []string{"metric_name", "timestamp", "metric_value", "name", "url"} {
+		if _, ok := column[name]; !ok {
+			return errors.New("result data file is not k6 csv output; missing column " + name)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := column[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	type pending struct {
+		label                                       string
+		raw                                         *ResultRawData
+		duration, connecting, tls, sending, waiting float64
+	}
+	var current *pending
+	no, rows := 0, 0
+	// k6 reports its running virtual users every second, apart from the requests
+	vus := 0
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		connect := current.connecting + current.tls
+		current.raw.Elapsed = int(math.Round(connect + current.duration))
+		current.raw.Latency = int(math.Round(connect + current.sending + current.waiting))
+		current.raw.Connection = int(math.Round(connect))
+		fn(current.label, current.raw)
+		current = nil
+	}
+
+	for i := 0; ; i++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to read CSV file from path %s; %v", source, err)
+			return err
+		}
+		rows++
+
+		metric := field(row, "metric_name")
+		if metric == "vus" {
+			if v, err := strconv.ParseFloat(field(row, "metric_value"), 64); err == nil {
+				vus = int(v)
+			}
+			continue
+		}
+		if metric == "http_reqs" {
+			flush()
+			t, err := k6Timestamp(field(row, "timestamp"))
+			if err != nil {
+				log.Printf("[%d] time has error %s\n", i, err)
+				continue
+			}
+			current = &pending{
+				label: field(row, "name"),
+				raw: &ResultRawData{
+					No:        no,
+					URL:       field(row, "url"),
+					Timestamp: t,
+					// until http_req_failed says otherwise, judge by the status k6 expected
+					IsError:       field(row, "expected_response") == "false",
+					ActiveThreads: vus,
+				},
+			}
+			no++
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		var target *float64
+		switch metric {
+		case "http_req_duration":
+			target = &current.duration
+		case "http_req_connecting":
+			target = &current.connecting
+		case "http_req_tls_handshaking":
+			target = &current.tls
+		case "http_req_sending":
+			target = &current.sending
+		case "http_req_waiting":
+			target = &current.waiting
+		case "http_req_failed":
+		default:
+			continue
+		}
+
+		value, err := strconv.ParseFloat(field(row, "metric_value"), 64)
+		if err != nil {
+			log.Printf("[%d] %s has error %s\n", i, metric, err)
+			continue
+		}
+		if target == nil {
+			current.raw.IsError = value != 0
+			continue
+		}
+		*target = value
+	}
+	flush()
+
+	switch {
+	case rows == 0:
+		return errors.New("result data file is empty")
+	case no == 0:
+		return errors.New("result data file has no http requests")
+	}
+	return nil
+}
+
+// k6Timestamp reads k6's timestamp column, which is unix seconds by default and finer when
+// the output is configured for it.
+func k6Timestamp(value string) (time.Time, error) {
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch {
+	case len(value) >= 19:
+		return time.Unix(0, v), nil
+	case len(value) >= 16:
+		return time.UnixMicro(v), nil
+	case len(value) >= 13:
+		return time.UnixMilli(v), nil
+	}
+	return time.Unix(v, 0), nil
+}
@@ -0,0 +1,473 @@
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// A k6 script is a fixed runtime plus the test as JSON. Keeping the user's text in data rather
+// than splicing it into code means nothing they send can change what the script does, and
+// json.Marshal already produces valid JavaScript literals.
+//
+// The runtime follows the JMeter plan's semantics where the two differ: ${name} references
+// resolve against values extracted earlier in the same iteration, a reference to nothing is
+// sent as written, and an extractor that finds nothing leaves its default value.
+var k6ScriptTemplate = template.Must(template.New("k6Script").Parse(`import http from 'k6/http';
+import { sleep } from 'k6';
+
+const plan = {{.}};
+
+// files can only be opened while the script is initialised
+const files = {};
+for (const req of plan.requests) {
+  for (const f of req.files) {
+    files[f.path] = open(f.path, 'b');
+  }
+}
+
+export const options = {
+  scenarios: {
+    ant: plan.arrivalRate
+      ? {
+          executor: 'ramping-arrival-rate',
+          startRate: 0,
+          timeUnit: '1s',
+          preAllocatedVUs: plan.maxVUs,
+          maxVUs: plan.maxVUs,
+          stages: plan.stages,
+        }
+      : {
+          executor: 'ramping-vus',
+          startVUs: 0,
+          stages: plan.stages,
+          gracefulRampDown: '0s',
+        },
+  },
+  noConnectionReuse: !plan.keepAlive,
+  insecureSkipTLSVerify: plan.insecureSkipVerify,
+  tlsVersion: plan.tlsVersion || undefined,
+};
+
+// the JMeter timers: uniform adds up to range ms, gaussian deviates by range ms around delay
+function pause(t) {
+  if (!t) {
+    return;
+  }
+  let ms = t.delay;
+  if (t.type === 'uniform') {
+    ms += Math.random() * t.range;
+  } else if (t.type === 'gaussian') {
+    const g = Math.sqrt(-2 * Math.log(1 - Math.random())) * Math.cos(2 * Math.PI * Math.random());
+    ms = Math.abs(ms + g * t.range);
+  }
+  sleep(ms / 1000);
+}
+
+function resolve(s, vars) {
+  return s.replace(/\$\{([^}]*)\}/g, (ref, name) => (Object.prototype.hasOwnProperty.call(vars, name) ? vars[name] : ref));
+}
+
+function matches(res, e) {
+  if (e.type === 'json_path') {
+    let v;
+    try {
+      v = res.json(e.expression);
+    } catch (err) {
+      return [];
+    }
+    if (v === undefined || v === null) {
+      return [];
+    }
+    const values = e.multi && Array.isArray(v) ? v : [v];
+    return values.map((x) => (typeof x === 'object' ? JSON.stringify(x) : String(x)));
+  }
+  const values = [];
+  const re = new RegExp(e.expression, 'g');
+  const body = typeof res.body === 'string' ? res.body : '';
+  let m;
+  while ((m = re.exec(body)) !== null) {
+    values.push(m[e.group] === undefined ? '' : m[e.group]);
+    if (m[0] === '') {
+      re.lastIndex++;
+    }
+  }
+  return values;
+}
+
+function extract(res, e, vars) {
+  const values = matches(res, e);
+  if (e.matchNo < 0) {
+    values.forEach((v, i) => {
+      vars[e.varName + '_' + (i + 1)] = v;
+    });
+    vars[e.varName + '_matchNr'] = String(values.length);
+    return;
+  }
+  const i = e.matchNo === 0 ? Math.floor(Math.random() * values.length) : e.matchNo - 1;
+  vars[e.varName] = i < values.length ? values[i] : e.defaultValue;
+}
+
+// the weighted requests are a mix, of which one is sent an iteration
+const mixTotal = plan.requests.reduce((total, req) => total + req.percent, 0);
+
+export default function () {
+  const vars = {};
+  let pick = Math.random() * mixTotal;
+  for (const req of plan.requests) {
+    if (req.percent > 0) {
+      pick -= req.percent;
+      // picked when the draw fell within this request's share
+      if (pick >= 0 || pick < -req.percent) {
+        continue;
+      }
+    }
+    pause(req.thinkTime);
+
+    const params = { headers: {}, tags: { name: req.label }, timeout: req.timeout };
+    if (!req.followRedirects) {
+      params.redirects = 0;
+    }
+    for (const h of req.headers) {
+      params.headers[resolve(h.name, vars)] = resolve(h.value, vars);
+    }
+
+    let body = null;
+    if (req.form) {
+      body = {};
+      for (const f of req.form) {
+        body[resolve(f.name, vars)] = resolve(f.value, vars);
+      }
+      for (const f of req.files) {
+        body[f.paramName] = http.file(files[f.path], f.fileName, f.mimeType || undefined);
+      }
+    } else if (req.body !== '') {
+      body = resolve(req.body, vars);
+    }
+
+    const res = http.request(req.method, resolve(req.url, vars), body, params);
+    for (const e of req.extractors) {
+      extract(res, e, vars);
+    }
+  }
+}
+`))
+
+type k6ScriptData struct {
+	// ArrivalRate makes the stage targets iterations started per second instead of users.
+	ArrivalRate bool        `json:"arrivalRate"`
+	MaxVUs      int         `json:"maxVUs"`
+	Stages      []k6Stage   `json:"stages"`
+	Requests    []k6Request `json:"requests"`
+
+	// KeepAlive reuses connections across requests; TlsVersion is k6's name for the version.
+	KeepAlive          bool   `json:"keepAlive"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	TlsVersion         string `json:"tlsVersion"`
+}
+
+// k6TlsVersions maps a TLS version to the name k6 gives it.
+var k6TlsVersions = map[constant.TlsVersion]string{
+	constant.Tls12: "tls1.2",
+	constant.Tls13: "tls1.3",
+}
+
+type k6Stage struct {
+	Duration string `json:"duration"`
+	Target   int    `json:"target"`
+}
+
+type k6Request struct {
+	Label      string                      `json:"label"`
+	Method     string                      `json:"method"`
+	Url        string                      `json:"url"`
+	Headers    []RunLoadTestNameValueParam `json:"headers"`
+	Body       string                      `json:"body"`
+	Form       []RunLoadTestNameValueParam `json:"form"`
+	Files      []k6File                    `json:"files"`
+	Extractors []k6Extractor               `json:"extractors"`
+
+	// Percent is the share of iterations a weighted request is sent in, as the one of the mix
+	// picked; 0 means all of them.
+	Percent   float64                    `json:"percent"`
+	ThinkTime *RunLoadTestThinkTimeParam `json:"thinkTime"`
+
+	// Timeout is the most milliseconds the request may take, connecting included.
+	Timeout         int  `json:"timeout"`
+	FollowRedirects bool `json:"followRedirects"`
+}
+
+type k6File struct {
+	Path      string `json:"path"`
+	ParamName string `json:"paramName"`
+	FileName  string `json:"fileName"`
+	MimeType  string `json:"mimeType"`
+}
+
+type k6Extractor struct {
+	Type         constant.ExtractorType `json:"type"`
+	VarName      string                 `json:"varName"`
+	Expression   string                 `json:"expression"`
+	Multi        bool                   `json:"multi"`
+	Group        int                    `json:"group"`
+	MatchNo      int                    `json:"matchNo"`
+	DefaultValue string                 `json:"defaultValue"`
+}
+
+func parseK6Script(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error {
+	stages, err := k6Stages(param)
+	if err != nil {
+		return err
+	}
+
+	data := k6ScriptData{Stages: stages, Requests: []k6Request{}}
+	if loadShapeOf(param) == constant.ArrivalRateShape {
+		// the users are what the rate is served with; like the JMeter group's concurrency
+		// limit, no more than VirtualUsers iterations run at once
+		data.ArrivalRate = true
+		if data.MaxVUs, err = scenarioNumber("virtual users", param.VirtualUsers); err != nil {
+			return err
+		}
+	}
+	client := resolveHttpClient(constant.K6, param.HttpClient)
+	data.KeepAlive = *client.KeepAlive
+	data.InsecureSkipVerify = *client.InsecureSkipVerify
+	data.TlsVersion = k6TlsVersions[client.TlsVersion]
+
+	attachmentDir := planAttachmentDir(loadGeneratorInstallInfo.InstallPath, param.LoadTestKey)
+	httpReqs := withHttpClient(withDefaultThinkTime(param.HttpReqs, param.ThinkTime), client)
+	percents := weightPercents(httpReqs)
+	for i, req := range httpReqs {
+		if strings.TrimSpace(req.Hostname) == "" || strings.TrimSpace(req.Port) == "" {
+			continue
+		}
+		r, err := k6RequestOf(i, req, attachmentDir)
+		if err != nil {
+			return err
+		}
+		if err := ValidateThinkTime(req.ThinkTime); err != nil {
+			return err
+		}
+		r.Percent = percents[i]
+		r.ThinkTime = req.ThinkTime
+		r.Timeout = req.HttpClient.ResponseTimeout
+		r.FollowRedirects = *req.HttpClient.FollowRedirects
+		data.Requests = append(data.Requests, r)
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return k6ScriptTemplate.Execute(w, string(encoded))
+}
+
+// k6Stages reproduces the thread group of the JMeter plan. The staged shapes map one stage to
+// one stage. The concurrency and arrival rate groups add their users, or their rate, over the
+// ramp up time - in RampUpSteps equal steps, each held until the next, or evenly when there is
+// one step - and then hold it for the duration.
+func k6Stages(param RunLoadTestParam) ([]k6Stage, error) {
+	shape := loadShapeOf(param)
+	switch shape {
+	case constant.SpikeShape, constant.StepDownShape, constant.StagesShape:
+		stages, err := loadStagesOf(param)
+		if err != nil {
+			return nil, err
+		}
+		var k6 []k6Stage
+		for _, s := range stages {
+			k6 = append(k6, k6Stage{Duration: fmt.Sprintf("%ds", s.Duration), Target: s.Target})
+		}
+		return k6, nil
+	case constant.ConcurrencyShape, constant.ArrivalRateShape:
+	default:
+		return nil, fmt.Errorf("load shape %q is not supported", shape)
+	}
+
+	target, err := scenarioNumber("virtual users", param.VirtualUsers)
+	if err != nil {
+		return nil, err
+	}
+	if shape == constant.ArrivalRateShape {
+		if target, err = scenarioNumber("arrival rate", param.ArrivalRate); err != nil {
+			return nil, err
+		}
+	}
+	duration, err := scenarioNumber("duration", param.Duration)
+	if err != nil {
+		return nil, err
+	}
+	rampUp, err := scenarioNumber("ramp up time", param.RampUpTime)
+	if err != nil {
+		return nil, err
+	}
+	steps := 1
+	if strings.TrimSpace(param.RampUpSteps) != "" {
+		if steps, err = scenarioNumber("ramp up steps", param.RampUpSteps); err != nil {
+			return nil, err
+		}
+	}
+
+	var stages []k6Stage
+	switch {
+	case rampUp == 0:
+		stages = append(stages, k6Stage{Duration: "0s", Target: target})
+	case steps <= 1:
+		stages = append(stages, k6Stage{Duration: fmt.Sprintf("%ds", rampUp), Target: target})
+	default:
+		stepMs := rampUp * 1000 / steps
+		for i := 1; i <= steps; i++ {
+			level := target * i / steps
+			stages = append(stages,
+				k6Stage{Duration: "0s", Target: level},
+				k6Stage{Duration: fmt.Sprintf("%dms", stepMs), Target: level},
+			)
+		}
+	}
+	stages = append(stages, k6Stage{Duration: fmt.Sprintf("%ds", duration), Target: target})
+	return stages, nil
+}
+
+// k6RequestOf turns one request into the script's form of it. The url is built the way the
+// JMeter sampler sends it, so both engines hit the same address.
+func k6RequestOf(index int, req RunLoadTestHttpParam, attachmentDir string) (k6Request, error) {
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	bodyMethod, ok := jmxHttpMethods[method]
+	if !ok {
+		return k6Request{}, fmt.Errorf("http method %q cannot be rendered into a test plan", req.Method)
+	}
+
+	parsedUrl, err := url.Parse(req.Path)
+	if err != nil {
+		return k6Request{}, err
+	}
+	query := parsedUrl.RawQuery
+	for _, q := range req.QueryParams {
+		if query != "" {
+			query += "&"
+		}
+		query += queryEscapeKeepingVars(q.Name) + "=" + queryEscapeKeepingVars(q.Value)
+	}
+	target := fmt.Sprintf("%s://%s:%s%s", req.Protocol, strings.TrimSpace(req.Hostname), strings.TrimSpace(req.Port), parsedUrl.Path)
+	if query != "" {
+		target += "?" + query
+	}
+
+	r := k6Request{
+		// the JMeter sampler's name, so results group the same way whichever engine ran
+		Label:      fmt.Sprintf("%s Request", method),
+		Method:     method,
+		Url:        target,
+		Headers:    append([]RunLoadTestNameValueParam{}, req.Headers...),
+		Files:      []k6File{},
+		Extractors: []k6Extractor{},
+	}
+
+	if bodyMethod {
+		switch req.BodyType {
+		case constant.FormBody, constant.MultipartBody:
+			r.Form = append([]RunLoadTestNameValueParam{}, req.FormParams...)
+			if req.BodyType == constant.MultipartBody {
+				for _, f := range req.Files {
+					r.Files = append(r.Files, k6File{
+						Path:      fmt.Sprintf("%s/%s", attachmentDir, attachmentName(index, f.FileName)),
+						ParamName: f.ParamName,
+						FileName:  f.FileName,
+						MimeType:  f.MimeType,
+					})
+				}
+			}
+		case constant.RawBody, "":
+			r.Body = req.BodyData
+		default:
+			return k6Request{}, fmt.Errorf("body type %q cannot be rendered into a test plan", req.BodyType)
+		}
+	}
+
+	for _, e := range req.Extractors {
+		x := k6Extractor{
+			Type:         e.Type,
+			VarName:      e.VarName,
+			Expression:   e.Expression,
+			MatchNo:      e.MatchNo,
+			DefaultValue: e.DefaultValue,
+		}
+		switch e.Type {
+		case constant.JsonPathExtractor:
+			x.Expression, x.Multi, err = jsonPathToGjson(e.Expression)
+			if err != nil {
+				return k6Request{}, fmt.Errorf("extractor %s: %w", e.VarName, err)
+			}
+		case constant.RegexExtractor:
+			if regexTemplateOf(e.Expression) == "$1$" {
+				x.Group = 1
+			}
+		default:
+			return k6Request{}, fmt.Errorf("unsupported extractor type %q for %s", e.Type, e.VarName)
+		}
+		r.Extractors = append(r.Extractors, x)
+	}
+
+	return r, nil
+}
+
+var jsonPathToken = regexp.MustCompile(`^(?:\.([A-Za-z_$][\w$-]*)|\.\*|\[\*\]|\[(\d+)\]|\['([^']*)'\]|\["([^"]*)"\])`)
+
+// jsonPathToGjson translates a JSONPath into the GJSON path k6's Response.json() takes. Only
+// member access, indexes and wildcards translate; filters, slices and recursive descent have no
+// GJSON form, so they are rejected rather than silently extracting something else. multi is
+// true when the path can match more than one value.
+func jsonPathToGjson(expression string) (string, bool, error) {
+	rest := strings.TrimSpace(expression)
+	if !strings.HasPrefix(rest, "$") {
+		return "", false, fmt.Errorf("json path %q must start with $", expression)
+	}
+	rest = rest[1:]
+
+	var parts []string
+	multi := false
+	for rest != "" {
+		m := jsonPathToken.FindStringSubmatch(rest)
+		if m == nil {
+			return "", false, fmt.Errorf("json path %q cannot be run with %s; only .name, ['name'], [n] and [*] are supported", expression, constant.K6)
+		}
+		rest = rest[len(m[0]):]
+		switch {
+		case m[1] != "":
+			parts = append(parts, gjsonEscape(m[1]))
+		case m[2] != "":
+			parts = append(parts, m[2])
+		case m[3] != "" || m[4] != "":
+			parts = append(parts, gjsonEscape(m[3]+m[4]))
+		default:
+			parts = append(parts, "#")
+			multi = true
+		}
+	}
+	// a trailing # would ask GJSON for the array's length; the array itself is what is meant
+	if len(parts) > 0 && parts[len(parts)-1] == "#" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return "@this", multi, nil
+	}
+	return strings.Join(parts, "."), multi, nil
+}
+
+// gjsonEscape escapes the characters GJSON gives a meaning to inside a key.
+func gjsonEscape(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if strings.ContainsRune(`.*?#|@!=<>%"\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package load
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloud-barista/cm-ant/internal/config"
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+)
+
+// loadEngine is the tool that generates the load on the generator. Everything that differs
+// between tools lives behind it; the rest of a run - pre-check, generator install, transfer
+// of the plan and its attachments, result fetch, statistics - is shared.
+//
+// Every engine writes its results to <installPath>/result/<loadTestKey>_result.csv, which is
+// where the fetch step looks, and parses that file into the same ResultRawData rows, so a run
+// reads the same whatever produced it.
+type loadEngine interface {
+	Type() constant.LoadGeneratorType
+
+	// InstallCmd makes the engine able to run param on the generator. It is run before every
+	// test and must do nothing when what it installs is already there. "" means the
+	// generator install already provides everything.
+	InstallCmd(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string
+
+	// PlanFileName names the rendered plan under <installPath>/test_plan.
+	PlanFileName(loadTestKey string) string
+
+	// RenderPlan writes the plan for param to w.
+	RenderPlan(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error
+
+	// RunCmd runs the rendered plan to completion and removes it afterwards, together with
+	// the files it read.
+	RunCmd(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string
+
+	// KillCmd stops a running test.
+	KillCmd(loadTestKey string) string
+
+	// RunningCmd prints whether the test is still running on the generator; see
+	// processRunningCmd.
+	RunningCmd(loadTestKey string) string
+
+	// ScanResult reads the result file a row at a time, handing each to fn with its request
+	// label, so a file of any size is read in bounded memory.
+	ScanResult(filePath string, fn func(label string, r *ResultRawData)) error
+
+	// ScanRows reads rows, the header first, from r as ScanResult does from a file, so a file
+	// that is still being written can be read a part at a time. The file is named in the log.
+	ScanRows(r io.Reader, filePath string, fn func(label string, r *ResultRawData)) error
+
+	// Validate rejects a run the engine cannot carry out, before anything is provisioned for it.
+	Validate(param RunLoadTestParam) error
+}
+
+var loadEngines = map[constant.LoadGeneratorType]loadEngine{
+	constant.Jmeter: jmeterEngine{},
+	constant.K6:     k6Engine{},
+}
+
+// engineFor returns the engine of the given type. An empty type is JMeter, the engine every
+// run used before there was a choice, so stored runs without one keep working.
+func engineFor(t constant.LoadGeneratorType) (loadEngine, error) {
+	if t == "" {
+		t = constant.Jmeter
+	}
+	e, ok := loadEngines[t]
+	if !ok {
+		return nil, fmt.Errorf("load generator engine %q is not supported", t)
+	}
+	return e, nil
+}
+
+// IsSupportedEngine reports whether runs can be made with the given engine.
+func IsSupportedEngine(t constant.LoadGeneratorType) bool {
+	_, ok := loadEngines[t]
+	return ok
+}
+
+// jmeterEngine runs plans built from default.jmx / default_perfmon.jmx, or the plan the user
+// uploaded, with the JMeter the generator install puts in place.
+type jmeterEngine struct{}
+
+func (jmeterEngine) Type() constant.LoadGeneratorType { return constant.Jmeter }
+
+// InstallCmd adds the plugins and drivers the run's targets need; plain HTTP needs nothing
+// the generator install does not already provide.
+func (jmeterEngine) InstallCmd(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string {
+	return targetInstallCmd(param.Targets, loadGeneratorInstallInfo)
+}
+
+func (jmeterEngine) PlanFileName(loadTestKey string) string {
+	return fmt.Sprintf("%s.jmx", loadTestKey)
+}
+
+func (jmeterEngine) RenderPlan(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error {
+	return parseTestPlanStructToString(w, param, loadGeneratorInstallInfo)
+}
+
+func (e jmeterEngine) RunCmd(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string {
+	resultFileName := fmt.Sprintf("%s_result.csv", param.LoadTestKey)
+	if param.TestPlan != nil {
+		// an uploaded plan carries cm-ant's result writer itself; -l would record every sample twice
+		resultFileName = ""
+	}
+	var properties []string
+	if param.HttpClient != nil && param.HttpClient.TlsVersion != "" {
+		// JMeter sets up TLS once per run, from its properties rather than from the plan
+		properties = append(properties,
+			fmt.Sprintf("https.default.protocol=%s", param.HttpClient.TlsVersion),
+			fmt.Sprintf("https.socket.protocols=%s", param.HttpClient.TlsVersion))
+	}
+	if eolByte := tcpEolByte(param.Targets); eolByte != nil {
+		properties = append(properties, fmt.Sprintf("tcp.eolByte=%d", *eolByte))
+	}
+	return generateJmeterExecutionCmd(loadGeneratorInstallInfo.InstallPath, loadGeneratorInstallInfo.InstallVersion, e.PlanFileName(param.LoadTestKey), resultFileName, properties...)
+}
+
+func (jmeterEngine) KillCmd(loadTestKey string) string {
+	return killCmdGen(loadTestKey)
+}
+
+func (jmeterEngine) RunningCmd(loadTestKey string) string {
+	return processRunningCmd(fmt.Sprintf("\\/bin\\/ApacheJMeter\\.jar.*%s", loadTestKey))
+}
+
+func (jmeterEngine) ScanResult(filePath string, fn func(label string, r *ResultRawData)) error {
+	return scanResultRawData(filePath, fn)
+}
+
+func (jmeterEngine) ScanRows(r io.Reader, filePath string, fn func(label string, r *ResultRawData)) error {
+	return scanResultRows(r, filePath, fn)
+}
+
+// Validate turns away certificate verification, which JMeter has no switch for: it trusts
+// every certificate. Requests and uploaded plans are otherwise checked as they come in.
+func (jmeterEngine) Validate(param RunLoadTestParam) error {
+	if c := param.HttpClient; c != nil && c.InsecureSkipVerify != nil && !*c.InsecureSkipVerify {
+		return fmt.Errorf("%s does not verify certificates; insecureSkipVerify cannot be false", constant.Jmeter)
+	}
+	return nil
+}
+
+// k6Engine runs a script generated from the same requests. k6 is a single binary, so it is
+// fetched on first use rather than being part of the generator install.
+type k6Engine struct{}
+
+func (k6Engine) Type() constant.LoadGeneratorType { return constant.K6 }
+
+func k6Version() string {
+	v := strings.TrimPrefix(strings.TrimSpace(config.AppConfig.Load.K6.Version), "v")
+	if v == "" {
+		v = "0.54.0"
+	}
+	return v
+}
+
+func k6Binary(installPath string) string {
+	return fmt.Sprintf("%s/k6-v%s/k6", installPath, k6Version())
+}
+
+func (k6Engine) InstallCmd(_ RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string {
+	installPath := loadGeneratorInstallInfo.InstallPath
+	version := k6Version()
+	dir := fmt.Sprintf("%s/k6-v%s", installPath, version)
+	// the release archives unpack into k6-v<version>-linux-<arch>/
+	return fmt.Sprintf(`set -e
+if [ ! -x %[1]s/k6 ]; then
+  case "$(uname -m)" in aarch64|arm64) arch=arm64 ;; *) arch=amd64 ;; esac
+  sudo mkdir -p %[1]s
+  curl -fsSL https://github.com/grafana/k6/releases/download/v%[2]s/k6-v%[2]s-linux-${arch}.tar.gz | sudo tar -xz -C %[1]s --strip-components=1
+fi
+%[1]s/k6 version
+`, dir, version)
+}
+
+func (k6Engine) PlanFileName(loadTestKey string) string {
+	return fmt.Sprintf("%s.js", loadTestKey)
+}
+
+func (k6Engine) RenderPlan(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error {
+	return parseK6Script(w, param, loadGeneratorInstallInfo)
+}
+
+func (e k6Engine) RunCmd(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string {
+	installPath := loadGeneratorInstallInfo.InstallPath
+	scriptPath := fmt.Sprintf("%s/test_plan/%s", installPath, e.PlanFileName(param.LoadTestKey))
+	resultPath := fmt.Sprintf("%s/result/%s_result.csv", installPath, param.LoadTestKey)
+	attachmentDir := planAttachmentDir(installPath, param.LoadTestKey)
+
+	// The key is passed as a tag so the process can be told apart from other runs by KillCmd.
+	cmd := fmt.Sprintf("%s run --quiet --no-color --no-summary --tag ant_test=%s --out csv=%s %s && sudo rm -rf %s %s",
+		k6Binary(installPath), param.LoadTestKey, resultPath, scriptPath, scriptPath, attachmentDir)
+	log.Info().Msgf("k6 execution command generated: %s", cmd)
+	return cmd
+}
+
+func (k6Engine) KillCmd(loadTestKey string) string {
+	// k6 finishes the iterations in flight on SIGTERM and still flushes its output
+	return fmt.Sprintf("kill -15 $(ps -ef | grep -E 'k6 run.*ant_test=%s' | grep -v grep | awk '{print $2}')", loadTestKey)
+}
+
+func (k6Engine) RunningCmd(loadTestKey string) string {
+	return processRunningCmd(fmt.Sprintf("k6 run.*ant_test=%s", loadTestKey))
+}
+
+func (k6Engine) ScanResult(filePath string, fn func(label string, r *ResultRawData)) error {
+	return scanK6ResultRawData(filePath, fn)
+}
+
+func (k6Engine) ScanRows(r io.Reader, filePath string, fn func(label string, r *ResultRawData)) error {
+	return scanK6ResultRows(r, filePath, fn)
+}
+
+// Validate turns away what only JMeter can do. The metric agent speaks the PerfMon protocol,
+// which k6 has no client for, and an uploaded plan is a JMeter plan. Rendering the script
+// catches the extractors k6 cannot express.
+func (k6Engine) Validate(param RunLoadTestParam) error {
+	if param.CollectAdditionalSystemMetrics {
+		return fmt.Errorf("additional system metrics cannot be collected with %s", constant.K6)
+	}
+	if param.TestPlan != nil {
+		return fmt.Errorf("an uploaded JMeter test plan cannot be run with %s", constant.K6)
+	}
+	if len(param.DataSets) > 0 {
+		return fmt.Errorf("CSV data sets cannot be read with %s", constant.K6)
+	}
+	if len(param.Targets) > 0 {
+		return fmt.Errorf("only http requests can be sent with %s", constant.K6)
+	}
+	if err := validateK6HttpClient(param.HttpClient, false); err != nil {
+		return err
+	}
+	for _, h := range param.HttpReqs {
+		if len(h.Assertions) > 0 {
+			return fmt.Errorf("response assertions cannot be checked with %s", constant.K6)
+		}
+		if err := validateK6HttpClient(h.HttpClient, true); err != nil {
+			return err
+		}
+	}
+	return parseK6Script(io.Discard, param, &LoadGeneratorInstallInfo{})
+}
+
+// validateK6HttpClient turns away the client options k6 has no equivalent for. Its response
+// timeout covers connecting as well, and connections are reused, or not, for the whole run.
+func validateK6HttpClient(client *RunLoadTestHttpClientParam, perRequest bool) error {
+	if client == nil {
+		return nil
+	}
+	if client.ConnectTimeout != 0 {
+		return fmt.Errorf("a connect timeout cannot be set with %s; its response timeout covers connecting", constant.K6)
+	}
+	if client.Implementation != "" {
+		return fmt.Errorf("an http client implementation cannot be chosen with %s", constant.K6)
+	}
+	if client.DownloadEmbeddedResources != nil && *client.DownloadEmbeddedResources {
+		return fmt.Errorf("embedded resources cannot be downloaded with %s", constant.K6)
+	}
+	if perRequest && client.KeepAlive != nil {
+		return fmt.Errorf("keepAlive applies to the whole run with %s", constant.K6)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package load
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestK6ScriptCarriesRequestsAsData(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadTestKey:  "key",
+		VirtualUsers: "10",
+		Duration:     "60",
+		RampUpTime:   "10",
+		RampUpSteps:  "2",
+		Engine:       constant.K6,
+		HttpReqs: []RunLoadTestHttpParam{
+			{
+				Method: "post", Protocol: "http", Hostname: "api.example.com", Port: "8080", Path: "/login?lang=ko",
+				QueryParams: []RunLoadTestNameValueParam{{Name: "sid", Value: "${sid}"}},
+				BodyData:    `{"user":"a'); http.get('evil"}`,
+				Extractors: []RunLoadTestExtractorParam{
+					{Type: constant.JsonPathExtractor, VarName: "token", Expression: "$.data['access.token']", MatchNo: 1},
+					{Type: constant.RegexExtractor, VarName: "session", Expression: `id=(\d+)`, MatchNo: 1},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (k6Engine{}).RenderPlan(&buf, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`"url": "http://api.example.com:8080/login?lang=ko\u0026sid=${sid}"`,
+		`"label": "POST Request"`,
+		// user text only ever appears inside a JSON string
+		`"body": "{\"user\":\"a'); http.get('evil\"}"`,
+		`"expression": "data.access\\.token"`,
+		`"group": 1`,
+		`{
+      "duration": "0s",
+      "target": 5
+    },
+    {
+      "duration": "5000ms",
+      "target": 5
+    }`,
+		`{
+      "duration": "60s",
+      "target": 10
+    }`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected script to contain %s\n%s", want, out)
+		}
+	}
+}
+
+func TestK6ScriptCarriesWeightsAndThinkTimes(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadTestKey: "key", VirtualUsers: "1", Duration: "1", RampUpTime: "1", RampUpSteps: "1", Engine: constant.K6,
+		ThinkTime: &RunLoadTestThinkTimeParam{Type: constant.ConstantThinkTime, Delay: 500},
+		HttpReqs: []RunLoadTestHttpParam{
+			{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/a", Weight: 4},
+			{Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/b", Weight: 1,
+				ThinkTime: &RunLoadTestThinkTimeParam{Type: constant.GaussianThinkTime, Delay: 1000, Range: 200}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (k6Engine{}).RenderPlan(&buf, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`"percent": 80`,
+		`"percent": 20`,
+		"let pick = Math.random() * mixTotal;",
+		`"type": "constant"`,
+		`"type": "gaussian"`,
+		"pause(req.thinkTime)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected script to contain %s\n%s", want, out)
+		}
+	}
+
+	param.ThinkTime = &RunLoadTestThinkTimeParam{Type: "poisson"}
+	if err := (k6Engine{}).RenderPlan(&bytes.Buffer{}, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err == nil {
+		t.Error("expected an unknown think time type to be rejected")
+	}
+}
+
+func TestJsonPathToGjson(t *testing.T) {
+	cases := []struct {
+		in    string
+		want  string
+		multi bool
+		err   bool
+	}{
+		{in: "$.token", want: "token"},
+		{in: "$.data[0].id", want: "data.0.id"},
+		{in: `$["a.b"].c`, want: `a\.b.c`},
+		{in: "$.items[*].id", want: "items.#.id", multi: true},
+		{in: "$.items[*]", want: "items", multi: true},
+		{in: "$", want: "@this"},
+		{in: "$.data[?(@.kind<2)].token", err: true},
+		{in: "$..token", err: true},
+		{in: "token", err: true},
+	}
+	for _, tc := range cases {
+		got, multi, err := jsonPathToGjson(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", tc.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want || multi != tc.multi {
+			t.Errorf("%s: got %q multi=%v err=%v, want %q multi=%v", tc.in, got, multi, err, tc.want, tc.multi)
+		}
+	}
+}
+
+func TestK6RejectsWhatOnlyJMeterCanDo(t *testing.T) {
+	base := RunLoadTestParam{VirtualUsers: "1", Duration: "1", RampUpTime: "1", RampUpSteps: "1"}
+
+	withMetrics := base
+	withMetrics.CollectAdditionalSystemMetrics = true
+	if err := (k6Engine{}).Validate(withMetrics); err == nil {
+		t.Error("expected system metrics to be rejected for k6")
+	}
+
+	withPlan := base
+	withPlan.TestPlan = &RunLoadTestPlanParam{FileName: "a.jmx"}
+	if err := (k6Engine{}).Validate(withPlan); err == nil {
+		t.Error("expected an uploaded plan to be rejected for k6")
+	}
+
+	withFilter := base
+	withFilter.HttpReqs = []RunLoadTestHttpParam{{
+		Method: "GET", Protocol: "http", Hostname: "h", Port: "80", Path: "/",
+		Extractors: []RunLoadTestExtractorParam{{Type: constant.JsonPathExtractor, VarName: "v", Expression: "$..v"}},
+	}}
+	if err := (k6Engine{}).Validate(withFilter); err == nil {
+		t.Error("expected a json path k6 cannot express to be rejected")
+	}
+
+	if _, err := engineFor("gatling"); err == nil {
+		t.Error("expected an unknown engine to be rejected")
+	}
+	if e, err := engineFor(""); err != nil || e.Type() != constant.Jmeter {
+		t.Errorf("expected an unset engine to be JMeter, got %v %v", e, err)
+	}
+}
+
+const k6Csv = `metric_name,timestamp,metric_value,check,error,error_code,expected_response,group,method,name,proto,scenario,service,status,subproto,tls_version,url,extra_tags,metadata
+http_reqs,1718000000,1.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_duration,1718000000,12.500000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_blocked,1718000000,3.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_connecting,1718000000,2.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_tls_handshaking,1718000000,0.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_sending,1718000000,0.500000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_waiting,1718000000,10.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_receiving,1718000000,2.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_req_failed,1718000000,0.000000,,,,true,,GET,GET Request,HTTP/1.1,ant,,200,,,http://h:80/,ant_test=key,
+http_reqs,1718000001,1.000000,,,,false,,POST,POST Request,HTTP/1.1,ant,,500,,,http://h:80/login,ant_test=key,
+http_req_duration,1718000001,30.000000,,,,false,,POST,POST Request,HTTP/1.1,ant,,500,,,http://h:80/login,ant_test=key,
+http_req_failed,1718000001,1.000000,,,,false,,POST,POST Request,HTTP/1.1,ant,,500,,,http://h:80/login,ant_test=key,
+data_received,1718000001,812.000000,,,,,,,,,ant,,,,,,ant_test=key,
+iterations,1718000001,1.000000,,,,,,,,,ant,,,,,,ant_test=key,
+`
+
+func TestK6ResultReadsAsJMeterRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key_result.csv")
+	if err := os.WriteFile(path, []byte(k6Csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := make(map[string][]*ResultRawData)
+	err := (k6Engine{}).ScanResult(path, func(label string, r *ResultRawData) {
+		rows[label] = append(rows[label], r)
+	})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	get := rows["GET Request"]
+	if len(get) != 1 {
+		t.Fatalf("expected one GET row, got %+v", rows)
+	}
+	if g := get[0]; g.Elapsed != 15 || g.Latency != 13 || g.Connection != 2 || g.IsError || g.URL != "http://h:80/" {
+		t.Errorf("unexpected GET row %+v", *g)
+	}
+
+	post := rows["POST Request"]
+	if len(post) != 1 || !post[0].IsError || post[0].Elapsed != 30 {
+		t.Errorf("unexpected POST rows %+v", post)
+	}
+
+	digest, err := digestResult(k6Engine{}, path, 0)
+	if err != nil {
+		t.Fatalf("digest failed: %v", err)
+	}
+	if stats := digest.statistics(); len(stats) != 2 || stats[0].Label != "GET Request" || stats[0].RequestCount != 1 || stats[0].Average != 15 {
+		t.Errorf("expected k6 rows to aggregate like JMeter ones, got %+v", stats)
+	}
+}
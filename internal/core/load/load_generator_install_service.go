@@ -107,6 +107,19 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 	installLocation := param.InstallLocation
 	installScriptPath := utils.JoinRootPathWith("/script/install-jmeter.sh")
 
+	clusterSize := max(1, param.ClusterSize)
+	if clusterSize > MaxClusterSize {
+		return result, fmt.Errorf("a load generator cluster can have at most %d servers", MaxClusterSize)
+	}
+	if clusterSize > 1 && installLocation != constant.Remote {
+		return result, errors.New("a load generator cluster can only be installed remotely")
+	}
+	if len(param.Regions) > 0 && installLocation != constant.Remote {
+		return result, errors.New("generator servers in other regions can only be installed remotely")
+	}
+	loadGeneratorInstallInfo.IsCluster = clusterSize > 1
+	loadGeneratorInstallInfo.ClusterSize = uint64(clusterSize)
+
 	switch installLocation {
 	case constant.Local:
 		log.Info().Msgf("Starting local installation of JMeter")
@@ -219,16 +232,23 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 
 		var antMci tumblebug.MciRes
 		for attempt := 1; attempt <= maxAttempts; attempt++ {
-			antMci, err = l.getAndDefaultMci(ctx, antVmCommonSpec, antVmCommonImage, existingConnectionName, activeMci, baseVm)
+			antMci, err = l.getAndDefaultMci(ctx, antVmCommonSpec, antVmCommonImage, existingConnectionName, activeMci, baseVm, clusterSize)
 			if err != nil {
 				log.Error().Msgf("Error getting or creating default mci; %v", err)
 				return result, err
 			}
+			if len(param.Regions) > 0 {
+				antMci, err = l.addRegionVms(ctx, nsId, activeMci, baseVm, param.Regions, antMci)
+				if err != nil {
+					log.Error().Msgf("Error adding generator VMs in the requested regions; %v", err)
+					return result, err
+				}
+			}
 
 			// if server is not running state, try to resume and get mci information
 			retryCount := config.AppConfig.Load.Retry
 			var prepareErr error
-			for retryCount > 0 && antMci.StatusCount.CountRunning < 1 {
+			for retryCount > 0 && (antMci.StatusCount.CountRunning < clusterSize || len(missingRegions(antMci.Vm, param.Regions)) > 0) {
 				log.Info().Msgf("Attempting to resume MCI, retry count: %d", retryCount)
 
 				prepareErr = l.tumblebugClient.ControlLifecycleWithContext(ctx, nsId, antMci.Id, "resume")
@@ -237,7 +257,7 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 					break
 				}
 				time.Sleep(defaultDelay)
-				antMci, err = l.getAndDefaultMci(ctx, antVmCommonSpec, antVmCommonImage, existingConnectionName, activeMci, baseVm)
+				antMci, err = l.getAndDefaultMci(ctx, antVmCommonSpec, antVmCommonImage, existingConnectionName, activeMci, baseVm, clusterSize)
 				if err != nil {
 					log.Error().Msgf("Error getting MCI after resume attempt; %v", err)
 					return result, err
@@ -246,8 +266,12 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 				retryCount = retryCount - 1
 			}
 
-			if prepareErr == nil && antMci.StatusCount.CountRunning < 1 {
-				prepareErr = errors.New("generator VM is not in a running state (likely deleted externally)")
+			if prepareErr == nil && antMci.StatusCount.CountRunning < clusterSize {
+				prepareErr = fmt.Errorf("%d of the %d generator VMs are in a running state (the others likely deleted externally)",
+					antMci.StatusCount.CountRunning, clusterSize)
+			}
+			if missing := missingRegions(antMci.Vm, param.Regions); prepareErr == nil && len(missing) > 0 {
+				prepareErr = fmt.Errorf("no generator VM is running in %s", strings.Join(missing, ", "))
 			}
 
 			// Remote install command — reads (key + script) are hard errors (not a recovery
@@ -362,7 +386,7 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 				loadGeneratorServer.StartTime = vm.CreatedTime
 				loadGeneratorServer.AdditionalVmKey = vm.CspResourceId
 				loadGeneratorServer.Label = "temp-label"
-				loadGeneratorServer.IsCluster = false
+				loadGeneratorServer.IsCluster = clusterSize > 1 && i < clusterSize
 				loadGeneratorServer.IsMaster = i == 0
 				loadGeneratorServer.ClusterSize = uint64(len(antMci.Vm))
 			} else {
@@ -386,7 +410,7 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 					StartTime:       vm.CreatedTime,
 					AdditionalVmKey: vm.CspResourceId,
 					Label:           "temp-label",
-					IsCluster:       false,
+					IsCluster:       clusterSize > 1 && i < clusterSize,
 					IsMaster:        i == 0,
 					ClusterSize:     uint64(len(antMci.Vm)),
 				}
@@ -421,6 +445,18 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 		return result, err
 	}
 
+	// the servers have their ids only now that they are saved
+	for _, s := range loadGeneratorInstallInfo.LoadGeneratorServers {
+		if s.IsMaster && s.ID != loadGeneratorInstallInfo.MasterId {
+			loadGeneratorInstallInfo.MasterId = s.ID
+			err = l.loadRepo.UpdateLoadGeneratorInstallInfoTx(ctx, loadGeneratorInstallInfo)
+			if err != nil {
+				log.Error().Msgf("Error recording the master of the load generator; %v", err)
+				return result, err
+			}
+		}
+	}
+
 	log.Info().Msg("LoadGeneratorInstallInfo updated successfully")
 
 	loadGeneratorServerResults := make([]LoadGeneratorServerResult, 0)
@@ -443,6 +479,8 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 			StartTime:       l.StartTime,
 			AdditionalVmKey: l.AdditionalVmKey,
 			Label:           l.Label,
+			IsCluster:       l.IsCluster,
+			IsMaster:        l.IsMaster,
 			CreatedAt:       l.CreatedAt,
 			UpdatedAt:       l.UpdatedAt,
 		}
@@ -455,6 +493,9 @@ func (l *LoadService) InstallLoadGenerator(param InstallLoadGeneratorParam, prog
 	result.InstallPath = loadGeneratorInstallInfo.InstallPath
 	result.InstallVersion = loadGeneratorInstallInfo.InstallVersion
 	result.Status = loadGeneratorInstallInfo.Status
+	result.IsCluster = loadGeneratorInstallInfo.IsCluster
+	result.MasterId = loadGeneratorInstallInfo.MasterId
+	result.ClusterSize = loadGeneratorInstallInfo.ClusterSize
 	result.PublicKeyName = loadGeneratorInstallInfo.PublicKeyName
 	result.PrivateKeyName = loadGeneratorInstallInfo.PrivateKeyName
 	result.CreatedAt = loadGeneratorInstallInfo.CreatedAt
@@ -508,7 +549,8 @@ func nextGeneratorMciName(current, base string) string {
 // getAndDefaultMci retrieves or creates the generator MCI named mciId (with VM vmName).
 // mciId/vmName let the caller target a rotated name for the newInstall recovery mode
 // (FR-MA2-PERF-007-09); pass the config base names for the default generator.
-func (l *LoadService) getAndDefaultMci(ctx context.Context, antVmCommonSpec, antVmCommonImage, antVmConnectionName, mciId, vmName string) (tumblebug.MciRes, error) {
+// An MCI with fewer than size VMs is grown to size; one with more is left as it is.
+func (l *LoadService) getAndDefaultMci(ctx context.Context, antVmCommonSpec, antVmCommonImage, antVmConnectionName, mciId, vmName string, size int) (tumblebug.MciRes, error) {
 	var antMci tumblebug.MciRes
 	var err error
 	nsId, _, _, _ := getResourceNames()
@@ -534,7 +576,7 @@ func (l *LoadService) getAndDefaultMci(ctx context.Context, antVmCommonSpec, ant
 						Name:           vmName,
 						RootDiskSize:   antVmRootDiskSize,
 						RootDiskType:   antVmRootDiskType,
-						SubGroupSize:   antVmSubGroupSize,
+						SubGroupSize:   max(antVmSubGroupSize, size),
 						VMUserPassword: antVmUserPassword,
 						// SSH key, VNet, Security Group will be auto-created by CB-Tumblebug
 					},
@@ -548,9 +590,15 @@ func (l *LoadService) getAndDefaultMci(ctx context.Context, antVmCommonSpec, ant
 		} else {
 			return antMci, err
 		}
-	} else if antMci.Vm != nil && len(antMci.Vm) == 0 {
+	} else if antMci.Vm != nil && len(antMci.Vm) < size {
 		// CB-Tumblebug will automatically create SSH key, VNet, Security Group, etc.
-		log.Info().Msg("MCI exists but no VMs, adding VM with dynamic resource provisioning")
+		log.Info().Msgf("MCI has %d of %d VMs, adding VMs with dynamic resource provisioning", len(antMci.Vm), size)
+
+		// a subgroup name is taken once per MCI, so the ones added to grow it are numbered
+		subGroupName := vmName
+		if len(antMci.Vm) > 0 {
+			subGroupName = fmt.Sprintf("%s-%d", vmName, len(antMci.Vm))
+		}
 
 		dynamicVmArg := tumblebug.DynamicVmReq{
 			ImageId:        antVmCommonImage,
@@ -558,10 +606,10 @@ func (l *LoadService) getAndDefaultMci(ctx context.Context, antVmCommonSpec, ant
 			ConnectionName: antVmConnectionName,
 			Description:    antVmDescription,
 			Label:          map[string]string{antLabelKey: antVmLabel},
-			Name:           vmName,
+			Name:           subGroupName,
 			RootDiskSize:   antVmRootDiskSize,
 			RootDiskType:   antVmRootDiskType,
-			SubGroupSize:   antVmSubGroupSize,
+			SubGroupSize:   size - len(antMci.Vm),
 			VMUserPassword: antVmUserPassword,
 			// SSH key, VNet, Security Group will be auto-created by CB-Tumblebug
 		}
@@ -1149,6 +1197,8 @@ func (l *LoadService) GetAllLoadGeneratorInstallInfo(param GetAllLoadGeneratorIn
 				StartTime:       s.StartTime,
 				AdditionalVmKey: s.AdditionalVmKey,
 				Label:           s.Label,
+				IsCluster:       s.IsCluster,
+				IsMaster:        s.IsMaster,
 				CreatedAt:       s.CreatedAt,
 				UpdatedAt:       s.UpdatedAt,
 			}
@@ -1161,6 +1211,9 @@ func (l *LoadService) GetAllLoadGeneratorInstallInfo(param GetAllLoadGeneratorIn
 			InstallPath:          l.InstallPath,
 			InstallVersion:       l.InstallVersion,
 			Status:               l.Status,
+			IsCluster:            l.IsCluster,
+			MasterId:             l.MasterId,
+			ClusterSize:          l.ClusterSize,
 			PublicKeyName:        l.PublicKeyName,
 			PrivateKeyName:       l.PrivateKeyName,
 			CreatedAt:            l.CreatedAt,
@@ -0,0 +1,263 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// loadShapeOf returns the shape of a run. Runs made before there was a choice, and runs that
+// do not say, use the concurrency thread group every plan was built with until then.
+func loadShapeOf(param RunLoadTestParam) constant.LoadShape {
+	if param.LoadShape == "" {
+		return constant.ConcurrencyShape
+	}
+	return param.LoadShape
+}
+
+// IsSupportedLoadShape reports whether runs can be made with the given shape.
+func IsSupportedLoadShape(shape constant.LoadShape) bool {
+	switch shape {
+	case constant.ConcurrencyShape, constant.ArrivalRateShape, constant.SpikeShape,
+		constant.StepDownShape, constant.StagesShape:
+		return true
+	}
+	return false
+}
+
+// ValidateLoadShape checks that param carries what its load shape needs, so a scenario that
+// cannot be rendered is turned away before anything is stored or provisioned for it.
+func ValidateLoadShape(param RunLoadTestParam) error {
+	shape := loadShapeOf(param)
+	if !IsSupportedLoadShape(shape) {
+		return fmt.Errorf("load shape %q is not supported", shape)
+	}
+	switch shape {
+	case constant.ArrivalRateShape:
+		if rate, err := scenarioNumber("arrival rate", param.ArrivalRate); err != nil {
+			return err
+		} else if rate < 1 {
+			return errors.New("arrival rate must be at least 1")
+		}
+	case constant.SpikeShape:
+		if users, err := scenarioNumber("spike users", param.SpikeUsers); err != nil {
+			return err
+		} else if users < 1 {
+			return errors.New("spike users must be at least 1")
+		}
+	}
+	_, err := ExpectedExecutionSecond(param)
+	return err
+}
+
+// scenarioNumber reads one of the scenario fields, which are kept as strings.
+func scenarioNumber(name, value string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s %q is not a valid number", name, value)
+	}
+	return v, nil
+}
+
+// ExpectedExecutionSecond is how long the load of a run lasts: the sum of its stages for the
+// staged shapes, ramp up plus duration for the others.
+func ExpectedExecutionSecond(param RunLoadTestParam) (uint64, error) {
+	switch loadShapeOf(param) {
+	case constant.SpikeShape, constant.StepDownShape, constant.StagesShape:
+		stages, err := loadStagesOf(param)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		for _, s := range stages {
+			total += s.Duration
+		}
+		return uint64(total), nil
+	}
+
+	duration, err := scenarioNumber("duration", param.Duration)
+	if err != nil {
+		return 0, err
+	}
+	rampUp, err := scenarioNumber("ramp up time", param.RampUpTime)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(duration + rampUp), nil
+}
+
+// loadStagesOf spells out the staged shapes - spike, step down and custom stages - as stages,
+// the one form both engines render. Stage durations are whole seconds because that is what
+// JMeter's Ultimate Thread Group schedules in; the shapes split their time accordingly.
+func loadStagesOf(param RunLoadTestParam) ([]RunLoadTestStageParam, error) {
+	shape := loadShapeOf(param)
+	if shape == constant.StagesShape {
+		if len(param.Stages) == 0 {
+			return nil, errors.New("stages load shape needs at least one stage")
+		}
+		total := 0
+		for i, s := range param.Stages {
+			if s.Target < 0 || s.Duration < 0 {
+				return nil, fmt.Errorf("stage %d must not have a negative target or duration", i+1)
+			}
+			total += s.Duration
+		}
+		if total == 0 {
+			return nil, errors.New("stages load shape must last at least one second")
+		}
+		return append([]RunLoadTestStageParam{}, param.Stages...), nil
+	}
+
+	users, err := scenarioNumber("virtual users", param.VirtualUsers)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := scenarioNumber("duration", param.Duration)
+	if err != nil {
+		return nil, err
+	}
+	rampUp, err := scenarioNumber("ramp up time", param.RampUpTime)
+	if err != nil {
+		return nil, err
+	}
+	stages := []RunLoadTestStageParam{{Target: users, Duration: rampUp}}
+
+	switch shape {
+	case constant.SpikeShape:
+		spikeUsers, err := scenarioNumber("spike users", param.SpikeUsers)
+		if err != nil {
+			return nil, err
+		}
+		spikeDuration, err := scenarioNumber("spike duration", param.SpikeDuration)
+		if err != nil {
+			return nil, err
+		}
+		if spikeDuration > duration {
+			return nil, fmt.Errorf("spike duration %d is longer than the duration %d it is part of", spikeDuration, duration)
+		}
+		// the spike sits in the middle of the hold, so the load is steady on both sides of it
+		before := (duration - spikeDuration) / 2
+		after := duration - spikeDuration - before
+		stages = append(stages,
+			RunLoadTestStageParam{Target: users, Duration: before},
+			RunLoadTestStageParam{Target: spikeUsers, Duration: 0},
+			RunLoadTestStageParam{Target: spikeUsers, Duration: spikeDuration},
+			RunLoadTestStageParam{Target: users, Duration: 0},
+			RunLoadTestStageParam{Target: users, Duration: after},
+		)
+	case constant.StepDownShape:
+		steps := 1
+		if strings.TrimSpace(param.RampUpSteps) != "" {
+			if steps, err = scenarioNumber("ramp up steps", param.RampUpSteps); err != nil {
+				return nil, err
+			}
+		}
+		if steps < 1 {
+			steps = 1
+		}
+		// the first step holds every user; each one after it drops an equal share. Seconds
+		// that do not divide evenly go to the earliest steps.
+		for i := 0; i < steps; i++ {
+			target := users * (steps - i) / steps
+			hold := duration / steps
+			if i < duration%steps {
+				hold++
+			}
+			stages = append(stages,
+				RunLoadTestStageParam{Target: target, Duration: 0},
+				RunLoadTestStageParam{Target: target, Duration: hold},
+			)
+		}
+	default:
+		return nil, fmt.Errorf("load shape %q is not made of stages", shape)
+	}
+	return stages, nil
+}
+
+// utgRow is one row of JMeter's Ultimate Thread Group: Threads users start InitialDelay
+// seconds into the test, ramp up over StartupTime, stay for HoldLoad and stop over
+// ShutdownTime.
+type utgRow struct {
+	Threads      int
+	InitialDelay int
+	StartupTime  int
+	HoldLoad     int
+	ShutdownTime int
+}
+
+// ultimateThreadGroupRows turns stages into Ultimate Thread Group rows. The thread group adds
+// up its rows, so a profile is cut into horizontal bands at every level a stage aims for: a
+// band is filled while the profile climbs through it and emptied while it falls through it,
+// and each fill-hold-empty is one row. Every stage boundary is a band edge, so a stage either
+// crosses a band completely or not at all, and each crossing is linear like the stage itself.
+func ultimateThreadGroupRows(stages []RunLoadTestStageParam) []utgRow {
+	levelSet := map[int]struct{}{0: {}}
+	for _, s := range stages {
+		levelSet[s.Target] = struct{}{}
+	}
+	levels := make([]int, 0, len(levelSet))
+	for l := range levelSet {
+		levels = append(levels, l)
+	}
+	sort.Ints(levels)
+
+	total := 0
+	for _, s := range stages {
+		total += s.Duration
+	}
+
+	// at returns the second a stage from `from` to `to` starting at `start` passes level,
+	// rounded to the whole seconds the thread group works in.
+	at := func(start, duration, from, to, level int) int {
+		if duration == 0 || from == to {
+			return start
+		}
+		return start + (duration*(level-from)+(to-from)/2)/(to-from)
+	}
+
+	var rows []utgRow
+	for b := 1; b < len(levels); b++ {
+		low, high := levels[b-1], levels[b]
+		filled := false
+		var fillStart, fillEnd int
+
+		start, from := 0, 0
+		for _, s := range stages {
+			to := s.Target
+			switch {
+			case !filled && from <= low && to >= high:
+				fillStart = at(start, s.Duration, from, to, low)
+				fillEnd = at(start, s.Duration, from, to, high)
+				filled = true
+			case filled && from >= high && to <= low:
+				// falling, high is passed first and low last
+				emptyStart := at(start, s.Duration, from, to, high)
+				emptyEnd := at(start, s.Duration, from, to, low)
+				rows = append(rows, utgRow{
+					Threads:      high - low,
+					InitialDelay: fillStart,
+					StartupTime:  fillEnd - fillStart,
+					HoldLoad:     emptyStart - fillEnd,
+					ShutdownTime: emptyEnd - emptyStart,
+				})
+				filled = false
+			}
+			start += s.Duration
+			from = to
+		}
+		if filled {
+			// still running when the last stage ends; the test ends them
+			rows = append(rows, utgRow{
+				Threads:      high - low,
+				InitialDelay: fillStart,
+				StartupTime:  fillEnd - fillStart,
+				HoldLoad:     total - fillEnd,
+			})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,166 @@
+package load
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestSpikeIsCentredInTheHold(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadShape: constant.SpikeShape, VirtualUsers: "10", Duration: "60", RampUpTime: "10", RampUpSteps: "1",
+		SpikeUsers: "50", SpikeDuration: "20",
+	}
+
+	stages, err := loadStagesOf(param)
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+	want := []RunLoadTestStageParam{
+		{Target: 10, Duration: 10},
+		{Target: 10, Duration: 20},
+		{Target: 50, Duration: 0},
+		{Target: 50, Duration: 20},
+		{Target: 10, Duration: 0},
+		{Target: 10, Duration: 20},
+	}
+	if !reflect.DeepEqual(stages, want) {
+		t.Errorf("got %+v, want %+v", stages, want)
+	}
+
+	if total, err := ExpectedExecutionSecond(param); err != nil || total != 70 {
+		t.Errorf("expected the spike to fall inside the 70s run, got %d %v", total, err)
+	}
+
+	rows := ultimateThreadGroupRows(stages)
+	wantRows := []utgRow{
+		{Threads: 10, InitialDelay: 0, StartupTime: 10, HoldLoad: 60},
+		{Threads: 40, InitialDelay: 30, StartupTime: 0, HoldLoad: 20},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("got rows %+v, want %+v", rows, wantRows)
+	}
+}
+
+func TestStepDownRowsLeaveOneBandAtATime(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadShape: constant.StepDownShape, VirtualUsers: "9", Duration: "30", RampUpTime: "6", RampUpSteps: "3",
+	}
+
+	stages, err := loadStagesOf(param)
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+	if total, _ := ExpectedExecutionSecond(param); total != 36 {
+		t.Errorf("expected 36s, got %d", total)
+	}
+
+	rows := ultimateThreadGroupRows(stages)
+	wantRows := []utgRow{
+		{Threads: 3, InitialDelay: 0, StartupTime: 2, HoldLoad: 34},
+		{Threads: 3, InitialDelay: 2, StartupTime: 2, HoldLoad: 22},
+		{Threads: 3, InitialDelay: 4, StartupTime: 2, HoldLoad: 10},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("got rows %+v, want %+v", rows, wantRows)
+	}
+}
+
+func TestStagesRampDownBecomesShutdownTime(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadShape: constant.StagesShape,
+		Stages:    []RunLoadTestStageParam{{Target: 20, Duration: 10}, {Target: 0, Duration: 5}},
+	}
+
+	stages, err := loadStagesOf(param)
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+	if total, _ := ExpectedExecutionSecond(param); total != 15 {
+		t.Errorf("expected 15s, got %d", total)
+	}
+	rows := ultimateThreadGroupRows(stages)
+	want := []utgRow{{Threads: 20, InitialDelay: 0, StartupTime: 10, HoldLoad: 0, ShutdownTime: 5}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got rows %+v, want %+v", rows, want)
+	}
+
+	for _, bad := range [][]RunLoadTestStageParam{
+		nil,
+		{{Target: 10, Duration: 0}},
+		{{Target: -1, Duration: 10}},
+	} {
+		if err := ValidateLoadShape(RunLoadTestParam{LoadShape: constant.StagesShape, Stages: bad}); err == nil {
+			t.Errorf("expected stages %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestThreadGroupFollowsTheShape(t *testing.T) {
+	base := RunLoadTestParam{TestName: "a&b", VirtualUsers: "10", Duration: "60", RampUpTime: "10", RampUpSteps: "2"}
+
+	if tg, err := threadGroupParseToJmx(base); err != nil || tg != "" {
+		t.Errorf("expected the template's concurrency group for the default shape, got %q %v", tg, err)
+	}
+
+	arrivals := base
+	arrivals.LoadShape = constant.ArrivalRateShape
+	arrivals.ArrivalRate = "50"
+	tg, err := threadGroupParseToJmx(arrivals)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	for _, want := range []string{
+		"com.blazemeter.jmeter.threads.arrivals.ArrivalsThreadGroup",
+		`<stringProp name="TargetLevel">50</stringProp>`,
+		`<stringProp name="ConcurrencyLimit">10</stringProp>`,
+		`testname="a&amp;b Thread Group"`,
+	} {
+		if !strings.Contains(tg, want) {
+			t.Errorf("expected arrivals group to contain %s\n%s", want, tg)
+		}
+	}
+	wellFormed(t, tg)
+
+	spike := base
+	spike.LoadShape = constant.SpikeShape
+	spike.SpikeUsers = "50"
+	spike.SpikeDuration = "20"
+	tg, err = threadGroupParseToJmx(spike)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(tg, "kg.apc.jmeter.threads.UltimateThreadGroup") || strings.Count(tg, `<stringProp name="threads">`) != 2 {
+		t.Errorf("expected an ultimate thread group with two rows\n%s", tg)
+	}
+	wellFormed(t, tg)
+}
+
+func TestK6ArrivalRateUsesTheRateAsTarget(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadTestKey: "key", VirtualUsers: "10", Duration: "60", RampUpTime: "10", RampUpSteps: "1",
+		LoadShape: constant.ArrivalRateShape, ArrivalRate: "50", Engine: constant.K6,
+	}
+
+	var buf bytes.Buffer
+	if err := (k6Engine{}).RenderPlan(&buf, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`"arrivalRate": true`,
+		`"maxVUs": 10`,
+		`{
+      "duration": "10s",
+      "target": 50
+    }`,
+		"ramping-arrival-rate",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected script to contain %s\n%s", want, out)
+		}
+	}
+}
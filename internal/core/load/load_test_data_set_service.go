@@ -0,0 +1,164 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidLoadTestDataSet is returned when an uploaded file cannot be read as a data set, so
+// the handler can answer with the caller's mistake rather than a server error.
+var ErrInvalidLoadTestDataSet = errors.New("load test data set is not valid")
+
+// ErrLoadTestDataSetNotFound is returned for a data set id that does not exist (any more).
+var ErrLoadTestDataSetNotFound = errors.New("load test data set not found")
+
+// CreateLoadTestDataSet stores a CSV file for runs to read.
+func (l *LoadService) CreateLoadTestDataSet(ctx context.Context, dataSet LoadTestDataSet) (LoadTestDataSetResult, error) {
+	log.Info().Msg("Starting CreateLoadTestDataSet")
+
+	var existing LoadTestDataSet
+	err := l.db.WithContext(ctx).Where("name = ? AND deleted_at IS NULL", dataSet.Name).First(&existing).Error
+	if err == nil {
+		return LoadTestDataSetResult{}, fmt.Errorf("%w; data set with this name already exists", ErrInvalidLoadTestDataSet)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Msg("Failed to check existing data set")
+		return LoadTestDataSetResult{}, err
+	}
+
+	columns, rows, err := DataSetColumns(dataSet.Content)
+	if err != nil {
+		return LoadTestDataSetResult{}, fmt.Errorf("%w; %v", ErrInvalidLoadTestDataSet, err)
+	}
+	dataSet.Columns = strings.Join(columns, ",")
+	dataSet.Rows = rows
+	dataSet.Size = len(dataSet.Content)
+
+	if err := l.db.WithContext(ctx).Create(&dataSet).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to create load test data set")
+		return LoadTestDataSetResult{}, err
+	}
+
+	log.Info().Uint("dataSetId", dataSet.ID).Msg("Successfully created load test data set")
+	return toLoadTestDataSetResult(dataSet), nil
+}
+
+// GetAllLoadTestDataSets lists the stored data sets, without their content.
+func (l *LoadService) GetAllLoadTestDataSets(ctx context.Context, param GetAllLoadTestDataSetsParam) (GetAllLoadTestDataSetsResult, error) {
+	log.Info().Msg("Starting GetAllLoadTestDataSets")
+
+	var dataSets []LoadTestDataSet
+	var totalCount int64
+
+	query := l.db.WithContext(ctx).Model(&LoadTestDataSet{}).Where("deleted_at IS NULL")
+	if param.Name != "" {
+		query = query.Where("name LIKE ?", "%"+param.Name+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to count load test data sets")
+		return GetAllLoadTestDataSetsResult{}, err
+	}
+
+	offset := (param.Page - 1) * param.Size
+	err := query.Omit("content").Offset(offset).Limit(param.Size).Order("created_at DESC").Find(&dataSets).Error
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get load test data sets")
+		return GetAllLoadTestDataSetsResult{}, err
+	}
+
+	results := []LoadTestDataSetResult{}
+	for _, dataSet := range dataSets {
+		results = append(results, toLoadTestDataSetResult(dataSet))
+	}
+
+	log.Info().Int64("totalCount", totalCount).Int("returnedCount", len(results)).Msg("Successfully retrieved load test data sets")
+	return GetAllLoadTestDataSetsResult{LoadTestDataSets: results, TotalRow: totalCount}, nil
+}
+
+// GetLoadTestDataSet describes one stored data set.
+func (l *LoadService) GetLoadTestDataSet(ctx context.Context, id uint) (LoadTestDataSetResult, error) {
+	log.Info().Uint("dataSetId", id).Msg("Starting GetLoadTestDataSet")
+
+	var dataSet LoadTestDataSet
+	err := l.db.WithContext(ctx).Omit("content").Where("id = ? AND deleted_at IS NULL", id).First(&dataSet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return LoadTestDataSetResult{}, ErrLoadTestDataSetNotFound
+		}
+		log.Error().Err(err).Msg("Failed to get load test data set")
+		return LoadTestDataSetResult{}, err
+	}
+
+	return toLoadTestDataSetResult(dataSet), nil
+}
+
+// DeleteLoadTestDataSet soft deletes a stored data set. Runs that read it keep their own copy.
+func (l *LoadService) DeleteLoadTestDataSet(ctx context.Context, id uint) error {
+	log.Info().Uint("dataSetId", id).Msg("Starting DeleteLoadTestDataSet")
+
+	var dataSet LoadTestDataSet
+	err := l.db.WithContext(ctx).Omit("content").Where("id = ? AND deleted_at IS NULL", id).First(&dataSet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLoadTestDataSetNotFound
+		}
+		log.Error().Err(err).Msg("Failed to check existing data set")
+		return err
+	}
+
+	if err := l.db.WithContext(ctx).Delete(&dataSet).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test data set")
+		return err
+	}
+
+	log.Info().Uint("dataSetId", id).Msg("Successfully deleted load test data set")
+	return nil
+}
+
+// resolveDataSets fills in the file of every data set of a run that refers to a stored one.
+// The content is copied into the run so it no longer depends on the stored data set.
+func (l *LoadService) resolveDataSets(ctx context.Context, dataSets []RunLoadTestDataSetParam) error {
+	for i := range dataSets {
+		if dataSets[i].DataSetId == 0 || len(dataSets[i].Content) > 0 {
+			continue
+		}
+		var stored LoadTestDataSet
+		err := l.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", dataSets[i].DataSetId).First(&stored).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("data set %d not found", dataSets[i].DataSetId)
+			}
+			return err
+		}
+		dataSets[i].FileName = stored.FileName
+		dataSets[i].Content = stored.Content
+	}
+	return nil
+}
+
+func toLoadTestDataSetResult(dataSet LoadTestDataSet) LoadTestDataSetResult {
+	return LoadTestDataSetResult{
+		ID:          dataSet.ID,
+		Name:        dataSet.Name,
+		Description: dataSet.Description,
+		FileName:    dataSet.FileName,
+		Columns:     dataSetColumnsOf(dataSet.Columns),
+		Rows:        dataSet.Rows,
+		Size:        dataSet.Size,
+		CreatedAt:   dataSet.CreatedAt,
+		UpdatedAt:   dataSet.UpdatedAt,
+	}
+}
+
+// dataSetColumnsOf splits the stored column list; column names never contain a comma.
+func dataSetColumnsOf(columns string) []string {
+	if columns == "" {
+		return []string{}
+	}
+	return strings.Split(columns, ",")
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -79,7 +78,9 @@ func (s *stepRecorder) upsert(step *LoadTestExecutionStep) {
 	}
 	if err := s.l.loadRepo.UpsertLoadTestExecutionStepTx(context.Background(), step); err != nil {
 		log.Warn().Msgf("failed to record execution step %s; %v", step.Name, err)
+		return
 	}
+	publishStep(s.state.LoadTestKey, *step)
 }
 
 // seed creates the full pipeline as pending so the web can render every step upfront.
@@ -93,6 +94,9 @@ func (s *stepRecorder) seed() {
 }
 
 func (s *stepRecorder) begin(name constant.ExecutionStep, message string) {
+	if _, ok := stepSeq[name]; ok && s != nil && s.state != nil {
+		trackRunPhase(s.state.LoadTestKey, name)
+	}
 	now := time.Now()
 	s.upsert(&LoadTestExecutionStep{Name: name, Status: constant.StepRunning, StartAt: &now, Message: message})
 }
@@ -121,12 +125,6 @@ func (s *stepRecorder) skip(name constant.ExecutionStep, message string) {
 	s.upsert(&LoadTestExecutionStep{Name: name, Status: constant.StepSkipped, FinishAt: &now, Message: message})
 }
 
-// generatorRunMu serializes load test runs. The load generator is a shared singleton
-// (one ant-default MCI reused across runs), so concurrent runs would contend on the same
-// VM (JMeter CPU contention, install/reset races). Runs are therefore serialized and a
-// concurrent request is rejected rather than corrupting the shared generator (BAR-1414).
-var generatorRunMu sync.Mutex
-
 // RunLoadTest initiates the load test and performs necessary initializations.
 // Generates a load test key, installs the load generator or retrieves existing installation information,
 // saves the load test execution state, and then asynchronously runs the load test.
@@ -149,6 +147,9 @@ func (l *LoadService) resolveNodeUid(ctx context.Context, nsId, infraId, nodeId
 }
 
 func (l *LoadService) RunLoadTest(param RunLoadTestParam) (string, error) {
+	if isDraining() {
+		return "", ErrShuttingDown
+	}
 	timeout, err := time.ParseDuration(config.AppConfig.Load.Timeout.CommandExecution)
 	if err != nil {
 		log.Warn().Msgf("Failed to parse commandExecution timeout, using default 50 minutes: %v", err)
@@ -157,34 +158,68 @@ func (l *LoadService) RunLoadTest(param RunLoadTestParam) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	// BAR-1414: only one load test may run at a time on the shared generator.
-	if !generatorRunMu.TryLock() {
-		return "", errors.New("a load test is already running; the shared load generator supports one run at a time")
+	engine, err := engineFor(param.Engine)
+	if err != nil {
+		return "", err
 	}
-	locked := true
-	defer func() {
-		if locked {
-			generatorRunMu.Unlock()
+	if err := ValidateLoadShape(param); err != nil {
+		return "", err
+	}
+	if err := ValidateThinkTime(param.ThinkTime); err != nil {
+		return "", err
+	}
+	for _, h := range param.HttpReqs {
+		if err := ValidateThinkTime(h.ThinkTime); err != nil {
+			return "", err
 		}
-	}()
+		if err := ValidateAssertions(h.Assertions); err != nil {
+			return "", err
+		}
+		if err := ValidateHttpClient(h.HttpClient, true); err != nil {
+			return "", err
+		}
+	}
+	if err := ValidateSla(param.Sla); err != nil {
+		return "", err
+	}
+	if err := ValidateAutoStop(param.AutoStop, param.CollectAdditionalSystemMetrics); err != nil {
+		return "", err
+	}
+	if err := ValidateHttpClient(param.HttpClient, false); err != nil {
+		return "", err
+	}
+	if err := ValidateTargets(param.Targets); err != nil {
+		return "", err
+	}
+	if err := ValidateRegions(param); err != nil {
+		return "", err
+	}
+	// stored data sets are read now, so the run carries its own copy of every file
+	if err := l.resolveDataSets(ctx, param.DataSets); err != nil {
+		return "", err
+	}
+	if err := ValidateDataSets(param.DataSets); err != nil {
+		return "", err
+	}
+	if err := engine.Validate(param); err != nil {
+		return "", err
+	}
+	param.Engine = engine.Type()
+	// an uploaded plan brings its own samplers; a generated one is stored with what it sends
+	if param.TestPlan == nil {
+		param.HttpClient = resolveHttpClient(param.Engine, param.HttpClient)
+	}
 
 	loadTestKey := utils.CreateUniqIdBaseOnUnixTime()
 	param.LoadTestKey = loadTestKey
 	log.Info().Msgf("Starting load test with key: %s", loadTestKey)
 
-	duration, err := strconv.Atoi(param.Duration)
-	if err != nil {
-		log.Error().Msgf("error while type convert; %s", err.Error())
-		return loadTestKey, err
-	}
-
-	rampUpTime, err := strconv.Atoi(param.RampUpTime)
-
+	// the whole profile counts: a spike or a stage added after the hold lengthens the run
+	totalExecutionSecond, err := ExpectedExecutionSecond(param)
 	if err != nil {
-		return loadTestKey, err
+		log.Error().Msgf("error while computing the expected execution time; %s", err.Error())
+		return "", err
 	}
-
-	totalExecutionSecond := uint64(duration + rampUpTime)
 	startAt := time.Now()
 	e := startAt.Add(time.Duration(totalExecutionSecond) * time.Second)
 
@@ -199,9 +234,13 @@ func (l *LoadService) RunLoadTest(param RunLoadTestParam) (string, error) {
 	// correctly for the guarantee to hold, and cb-tumblebug already answers the question.
 	nodeUid := l.resolveNodeUid(ctx, param.NsId, param.InfraId, param.NodeId)
 
+	// BAR-1414: the run waits in the queue until the shared generator and the target are free;
+	// StartAt becomes the time it leaves the queue.
 	stateArg := LoadTestExecutionState{
 		LoadTestKey:                 loadTestKey,
-		ExecutionStatus:             constant.OnProcessing,
+		ParentLoadTestKey:           param.ParentLoadTestKey,
+		ScenarioCatalogId:           param.ScenarioCatalogId,
+		ExecutionStatus:             constant.Queued,
 		StartAt:                     startAt,
 		ExpectedFinishAt:            e,
 		TotalExpectedExcutionSecond: totalExecutionSecond,
@@ -210,6 +249,7 @@ func (l *LoadService) RunLoadTest(param RunLoadTestParam) (string, error) {
 		NodeId:                      param.NodeId,
 		NodeUid:                     nodeUid,
 		WithMetrics:                 param.CollectAdditionalSystemMetrics,
+		Engine:                      param.Engine,
 	}
 
 	err = l.loadRepo.InsertLoadTestExecutionStateTx(ctx, &stateArg)
@@ -219,12 +259,11 @@ func (l *LoadService) RunLoadTest(param RunLoadTestParam) (string, error) {
 	}
 	log.Info().Msgf("Initial load test execution state saved for key: %s", loadTestKey)
 
-	locked = false // ownership transferred to the async run; it releases the lock when done
-	go func() {
-		defer generatorRunMu.Unlock()
-		l.processLoadTestAsync(param, &stateArg)
-		l.applyGeneratorIdlePolicy(param) // BAR-1413: keep / suspend / terminate the generator when idle
-	}()
+	if err := l.enqueueRun(ctx, param); err != nil {
+		log.Error().Msgf("Error queueing load test %s: %v", loadTestKey, err)
+		_ = l.failQueuedRun(ctx, &stateArg, fmt.Sprintf("load test could not be queued; %v", err))
+		return "", err
+	}
 
 	return loadTestKey, nil
 
@@ -260,15 +299,130 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 	// record them now; the generator id is not known yet and is linked in after install.
 	var hs []LoadTestExecutionHttpInfo
 	for _, h := range param.HttpReqs {
+		var es []LoadTestExecutionHttpExtractor
+		for _, e := range h.Extractors {
+			es = append(es, LoadTestExecutionHttpExtractor{
+				Type:         e.Type,
+				VarName:      e.VarName,
+				Expression:   e.Expression,
+				MatchNo:      e.MatchNo,
+				DefaultValue: e.DefaultValue,
+			})
+		}
+		var as []LoadTestExecutionHttpAssertion
+		for _, a := range h.Assertions {
+			as = append(as, LoadTestExecutionHttpAssertion{
+				Type:       a.Type,
+				Expression: a.Expression,
+				Value:      a.Value,
+			})
+		}
+		var fs []LoadTestExecutionHttpField
+		for _, f := range h.Headers {
+			fs = append(fs, LoadTestExecutionHttpField{Kind: constant.HeaderField, Name: f.Name, Value: f.Value})
+		}
+		for _, f := range h.QueryParams {
+			fs = append(fs, LoadTestExecutionHttpField{Kind: constant.QueryField, Name: f.Name, Value: f.Value})
+		}
+		for _, f := range h.FormParams {
+			fs = append(fs, LoadTestExecutionHttpField{Kind: constant.FormField, Name: f.Name, Value: f.Value})
+		}
+		var files []LoadTestExecutionHttpFile
+		for _, f := range h.Files {
+			files = append(files, LoadTestExecutionHttpFile{
+				ParamName: f.ParamName,
+				FileName:  f.FileName,
+				MimeType:  f.MimeType,
+				Size:      len(f.Content),
+				Content:   f.Content,
+			})
+		}
 		hs = append(hs, LoadTestExecutionHttpInfo{
-			Method:   h.Method,
-			Protocol: h.Protocol,
-			Hostname: h.Hostname,
-			Port:     h.Port,
-			Path:     h.Path,
-			BodyData: h.BodyData,
+			Method:     h.Method,
+			Protocol:   h.Protocol,
+			Hostname:   h.Hostname,
+			Port:       h.Port,
+			Path:       h.Path,
+			BodyData:   h.BodyData,
+			BodyType:   h.BodyType,
+			Fields:     fs,
+			Files:      files,
+			Extractors: es,
+			Assertions: as,
+			Weight:     h.Weight,
+			ThinkTime:  thinkTimeModelOf(h.ThinkTime),
+			HttpClient: httpClientModelOf(mergeHttpClient(param.HttpClient, h.HttpClient)),
 		})
 	}
+	var uploaded []LoadTestExecutionUploadedFile
+	if param.TestPlan != nil {
+		uploaded = append(uploaded, LoadTestExecutionUploadedFile{
+			FileName:   param.TestPlan.FileName,
+			IsTestPlan: true,
+			Size:       len(param.TestPlan.Content),
+			Content:    param.TestPlan.Content,
+		})
+		for _, f := range param.TestPlan.DataFiles {
+			uploaded = append(uploaded, LoadTestExecutionUploadedFile{
+				FileName: f.FileName,
+				Size:     len(f.Content),
+				Content:  f.Content,
+			})
+		}
+	}
+	var dataSets []LoadTestExecutionDataSet
+	for _, ds := range param.DataSets {
+		columns, _, _ := DataSetColumns(ds.Content) // validated before the run was accepted
+		dataSets = append(dataSets, LoadTestExecutionDataSet{
+			DataSetId:  ds.DataSetId,
+			FileName:   ds.FileName,
+			Columns:    strings.Join(columns, ","),
+			ShareMode:  shareModeOf(ds),
+			Recycle:    ds.Recycle,
+			StopThread: ds.StopThread,
+			Size:       len(ds.Content),
+			Content:    ds.Content,
+		})
+	}
+	var targets []LoadTestExecutionTarget
+	for _, t := range param.Targets {
+		targets = append(targets, LoadTestExecutionTarget{
+			Protocol:      t.Protocol,
+			Hostname:      t.Hostname,
+			Port:          t.Port,
+			Timeout:       t.Timeout,
+			Path:          t.Path,
+			Secure:        t.Secure,
+			Message:       t.Message,
+			Method:        t.Method,
+			ProtoFileName: t.ProtoFileName,
+			ProtoFile:     t.ProtoFile,
+			EolByte:       t.EolByte,
+			Driver:        t.Driver,
+			Database:      t.Database,
+			Username:      t.Username,
+			Password:      t.Password,
+			Query:         t.Query,
+		})
+	}
+	var stages []LoadTestExecutionLoadStage
+	for _, st := range param.Stages {
+		stages = append(stages, LoadTestExecutionLoadStage{Target: st.Target, Duration: st.Duration})
+	}
+	var regions []LoadTestExecutionRegion
+	if len(param.Regions) > 0 {
+		shares := regionShares(param)
+		for i, r := range param.Regions {
+			regions = append(regions, LoadTestExecutionRegion{
+				Provider:     r.Provider,
+				Region:       r.Region,
+				Weight:       regionWeight(r),
+				VirtualUsers: usersOf(shares[i]),
+				Part:         i,
+			})
+		}
+	}
+
 	loadTestExecutionInfoParam := LoadTestExecutionInfo{
 		LoadTestKey:  param.LoadTestKey,
 		TestName:     param.TestName,
@@ -277,6 +431,13 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 		RampUpTime:   param.RampUpTime,
 		RampUpSteps:  param.RampUpSteps,
 
+		LoadShape:     loadShapeOf(param),
+		ArrivalRate:   param.ArrivalRate,
+		SpikeUsers:    param.SpikeUsers,
+		SpikeDuration: param.SpikeDuration,
+		LoadStages:    stages,
+		ThinkTime:     thinkTimeModelOf(param.ThinkTime),
+
 		NsId:    param.NsId,
 		InfraId: param.InfraId,
 		NodeId:  param.NodeId,
@@ -284,7 +445,16 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 		AgentInstalled: param.CollectAdditionalSystemMetrics,
 		AgentHostname:  param.AgentHostname,
 
+		Engine: param.Engine,
+
 		LoadTestExecutionHttpInfos: hs,
+		UploadedFiles:              uploaded,
+		DataSets:                   dataSets,
+		Sla:                        slaModelOf(param.Sla),
+		AutoStop:                   autoStopModelOf(param.AutoStop),
+		HttpClient:                 httpClientModelOf(param.HttpClient),
+		Targets:                    targets,
+		Regions:                    regions,
 		LoadTestExecutionStateId:   loadTestExecutionState.ID,
 	}
 	if err := l.loadRepo.SaveForLoadTestExecutionTx(context.Background(), &loadTestExecutionInfoParam); err != nil {
@@ -311,6 +481,7 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 		installParam.NsId = param.NsId
 		installParam.InfraId = param.InfraId
 		installParam.NodeId = param.NodeId
+		installParam.Regions = installRegionsOf(param.Regions)
 
 		result, err := l.InstallLoadGenerator(installParam, func(attempt int, message, detail string) {
 			rec.progress(constant.StepGeneratorInstall, attempt, message, detail)
@@ -351,6 +522,13 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 		return
 	}
 
+	// a generator given as it is may have no server in a region the run is sent from
+	if _, _, err := runServers(param, &loadGeneratorInstallInfo); err != nil {
+		rec.fail(constant.StepGeneratorInstall, "Load generator missing a region", err.Error())
+		failed(fmt.Sprintf("load generator cannot run the test; %v", err), err)
+		return
+	}
+
 	// The generator exists now; link the info row saved earlier to it. TestExecutionInfoId was
 	// already set from the early save above.
 	if err = l.loadRepo.UpdateLoadTestExecutionInfoGeneratorTx(context.Background(), param.LoadTestKey, loadGeneratorInstallInfo.ID); err != nil {
@@ -438,38 +616,12 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 
 	loadTestDone := make(chan bool)
 
-	var username string
-	var publicIp string
-	var port string
-	for _, s := range loadGeneratorInstallInfo.LoadGeneratorServers {
-		if s.IsMaster {
-			username = s.Username
-			publicIp = s.PublicIp
-			port = s.SshPort
-		}
-	}
-
-	home, err := os.UserHomeDir()
+	dataParam, err := newFetchDataParam(param, &loadGeneratorInstallInfo, rec, loadTestDone)
 	if err != nil {
 		failed(fmt.Sprintf("user home dir is not valid; %s", err), err)
 		return
 	}
 
-	dataParam := &fetchDataParam{
-		LoadTestDone:                   loadTestDone,
-		LoadTestKey:                    param.LoadTestKey,
-		InstallLocation:                loadGeneratorInstallInfo.InstallLocation,
-		InstallPath:                    loadGeneratorInstallInfo.InstallPath,
-		PublicKeyName:                  loadGeneratorInstallInfo.PublicKeyName,
-		PrivateKeyName:                 loadGeneratorInstallInfo.PrivateKeyName,
-		Username:                       username,
-		PublicIp:                       publicIp,
-		Port:                           port,
-		CollectAdditionalSystemMetrics: param.CollectAdditionalSystemMetrics,
-		Home:                           home,
-		StepRec:                        rec,
-	}
-
 	dataParam.Finished = make(chan struct{})
 	go l.fetchData(dataParam)
 
@@ -481,11 +633,19 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 			// the generator idle policy, so suspend/terminate can't interrupt it (BAR-1413).
 			<-dataParam.Finished
 		}
+		// the result file is complete only now, so this is the first point the sla can be checked
+		if loadTestExecutionState.ExecutionStatus == constant.Successed && param.Sla != nil {
+			l.judgeSla(context.Background(), param.LoadTestKey, param.Sla, loadTestExecutionState)
+		}
+		if loadTestExecutionState.ExecutionStatus == constant.Successed {
+			l.judgeBaseline(context.Background(), loadTestExecutionState)
+		}
 		updateErr := l.loadRepo.UpdateLoadTestExecutionStateTx(context.Background(), loadTestExecutionState)
 		if updateErr != nil {
 			failed(fmt.Sprintf("Error updating load test execution state: %v", updateErr), updateErr)
 			return
 		}
+		l.discardRawResults(context.Background(), dataParam.LoadTestKey)
 
 		log.Info().Msgf("successfully done load test for %s", dataParam.LoadTestKey)
 	}()
@@ -495,6 +655,14 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 	loadTestExecutionState.CompileDuration = compileDuration
 	loadTestExecutionState.ExecutionDuration = executionDuration
 
+	// the engine was killed on purpose, whatever it made of that; the results up to the stop
+	// are collected all the same
+	if fired := dataParam.AutoStop.firedRule(); fired != "" {
+		rec.fail(constant.StepJmeterRun, "Load test stopped by an auto-stop rule", fired)
+		failed(fmt.Sprintf("load test stopped by an auto-stop rule; %s", fired), errors.New(fired))
+		return
+	}
+
 	if loadTestErr != nil {
 		failed(fmt.Sprintf("Error while load testing: %v", loadTestErr), loadTestErr)
 		return
@@ -509,6 +677,48 @@ func (l *LoadService) processLoadTestAsync(param RunLoadTestParam, loadTestExecu
 	loadTestExecutionState.ExecutionStatus = constant.Successed
 }
 
+// newFetchDataParam describes where the results of param are fetched from. The run's first
+// server collects the metrics, so the fetch is led from it.
+func newFetchDataParam(param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo, rec *stepRecorder, loadTestDone <-chan bool) (*fetchDataParam, error) {
+	var username string
+	var publicIp string
+	var port string
+	for _, s := range loadGeneratorInstallInfo.LoadGeneratorServers {
+		if s.IsMaster {
+			username = s.Username
+			publicIp = s.PublicIp
+			port = s.SshPort
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	servers, _, _ := runServers(param, loadGeneratorInstallInfo)
+	if lead := servers[0]; lead.PublicIp != "" {
+		username, publicIp, port = lead.Username, lead.PublicIp, lead.SshPort
+	}
+
+	return &fetchDataParam{
+		LoadTestDone:                   loadTestDone,
+		LoadTestKey:                    param.LoadTestKey,
+		InstallLocation:                loadGeneratorInstallInfo.InstallLocation,
+		InstallPath:                    loadGeneratorInstallInfo.InstallPath,
+		PublicKeyName:                  loadGeneratorInstallInfo.PublicKeyName,
+		PrivateKeyName:                 loadGeneratorInstallInfo.PrivateKeyName,
+		Username:                       username,
+		PublicIp:                       publicIp,
+		Port:                           port,
+		Workers:                        fetchWorkersOf(servers),
+		CollectAdditionalSystemMetrics: param.CollectAdditionalSystemMetrics,
+		Home:                           home,
+		StepRec:                        rec,
+		Engine:                         param.Engine,
+		AutoStop:                       newAutoStopWatch(param.AutoStop),
+	}, nil
+}
+
 // processLoadTest executes the load test.
 // Depending on whether the installation location is local or remote, it creates the test plan and runs test commands.
 // Fetches and saves test results from the local or remote system.
@@ -578,6 +788,11 @@ func (l *LoadService) processLoadTest(param RunLoadTestParam, loadGeneratorInsta
 	if err != nil {
 		return
 	}
+	// the first server of the run collects the metrics, so the fetch is led from it
+	servers, _, _ := runServers(param, loadGeneratorInstallInfo)
+	if lead := servers[0]; lead.PublicIp != "" {
+		username, publicIp, port = lead.Username, lead.PublicIp, lead.SshPort
+	}
 
 	dataParam := &fetchDataParam{
 		LoadTestDone:                   loadTestDone,
@@ -589,6 +804,7 @@ func (l *LoadService) processLoadTest(param RunLoadTestParam, loadGeneratorInsta
 		Username:                       username,
 		PublicIp:                       publicIp,
 		Port:                           port,
+		Workers:                        fetchWorkersOf(servers),
 		CollectAdditionalSystemMetrics: param.CollectAdditionalSystemMetrics,
 		Home:                           home,
 	}
@@ -636,60 +852,109 @@ func (l *LoadService) executeLoadTest(param RunLoadTestParam, loadGeneratorInsta
 	installLocation := loadGeneratorInstallInfo.InstallLocation
 	loadTestKey := param.LoadTestKey
 	loadGeneratorInstallPath := loadGeneratorInstallInfo.InstallPath
-	testPlanName := fmt.Sprintf("%s.jmx", loadTestKey)
-	resultFileName := fmt.Sprintf("%s_result.csv", loadTestKey)
-	loadGeneratorInstallVersion := loadGeneratorInstallInfo.InstallVersion
 
 	log.Info().Msgf("Running load test with key: %s", loadTestKey)
 	compileDuration := "0"
 	executionDuration := "0"
 	start := time.Now()
 
+	engine, err := engineFor(param.Engine)
+	if err != nil {
+		rec.fail(constant.StepJmxPrepare, "Test plan generation failed", err.Error())
+		return compileDuration, executionDuration, err
+	}
+	testPlanName := engine.PlanFileName(loadTestKey)
+
 	if installLocation == constant.Remote {
 		log.Info().Msg("Remote execute detected.")
 		rec.begin(constant.StepJmxPrepare, "Preparing and sending test plan")
-		var buf bytes.Buffer
-		err := parseTestPlanStructToString(&buf, param, loadGeneratorInstallInfo)
+		nsId, mciId, _, _ := getResourceNames()
+
+		if installCmd := engine.InstallCmd(param, loadGeneratorInstallInfo); installCmd != "" {
+			rec.progress(constant.StepJmxPrepare, 0, fmt.Sprintf("Installing %s", engine.Type()), "")
+			stdout, err := l.tumblebugClient.CommandToMciWithContext(context.Background(), nsId, mciId, tumblebug.SendCommandReq{
+				Command: []string{installCmd},
+			})
+			if err == nil && strings.Contains(stdout, "exited with status") {
+				err = fmt.Errorf("%s install did not complete; %s", engine.Type(), stdout)
+			}
+			if err != nil {
+				rec.fail(constant.StepJmxPrepare, fmt.Sprintf("%s install failed", engine.Type()), err.Error())
+				return compileDuration, executionDuration, err
+			}
+		}
+
+		// every server of the run gets a plan of its own, for its share of the load
+		servers, shares, err := runServers(param, loadGeneratorInstallInfo)
 		if err != nil {
 			rec.fail(constant.StepJmxPrepare, "Test plan generation failed", err.Error())
 			return compileDuration, executionDuration, err
 		}
+		if len(param.Regions) > 1 {
+			rec.progress(constant.StepJmxPrepare, 0, fmt.Sprintf("Splitting the load across %d regions", len(servers)), "")
+		} else if len(servers) > 1 {
+			rec.progress(constant.StepJmxPrepare, 0, fmt.Sprintf("Splitting the load across %d generators", len(servers)), "")
+		}
 
-		testPlan := buf.String()
+		var attachmentsCmd string
+		if attachments := planAttachments(param); len(attachments) > 0 {
+			attachmentsCmd = writeAttachmentsCmd(planAttachmentDir(loadGeneratorInstallPath, loadTestKey), attachments)
+		}
 
-		createFileCmd := fmt.Sprintf("cat << 'EOF' > %s/test_plan/%s \n%s\nEOF", loadGeneratorInstallPath, testPlanName, testPlan)
+		for i, server := range servers {
+			var buf bytes.Buffer
+			err := engine.RenderPlan(&buf, shares[i], loadGeneratorInstallInfo)
+			if err != nil {
+				rec.fail(constant.StepJmxPrepare, "Test plan generation failed", err.Error())
+				return compileDuration, executionDuration, err
+			}
 
-		commandReq := tumblebug.SendCommandReq{
-			Command: []string{createFileCmd},
-		}
+			testPlan := buf.String()
 
-		compileDuration = utils.DurationString(start)
-		nsId, mciId, _, _ := getResourceNames()
-		_, err = l.tumblebugClient.CommandToMciWithContext(context.Background(), nsId, mciId, commandReq)
-		if err != nil {
-			rec.fail(constant.StepJmxPrepare, "Test plan transfer failed", err.Error())
-			return compileDuration, executionDuration, err
+			createFileCmd := fmt.Sprintf("cat << 'EOF' > %s/test_plan/%s \n%s\nEOF", loadGeneratorInstallPath, testPlanName, testPlan)
+
+			compileDuration = utils.DurationString(start)
+			_, err = l.commandToServer(context.Background(), nsId, mciId, server, createFileCmd)
+			if err != nil {
+				rec.fail(constant.StepJmxPrepare, "Test plan transfer failed", err.Error())
+				return compileDuration, executionDuration, err
+			}
+
+			if attachmentsCmd != "" {
+				_, err = l.commandToServer(context.Background(), nsId, mciId, server, attachmentsCmd)
+				if err != nil {
+					rec.fail(constant.StepJmxPrepare, "Test file transfer failed", err.Error())
+					return compileDuration, executionDuration, err
+				}
+			}
 		}
 		rec.ok(constant.StepJmxPrepare, "Test plan ready")
 
+		// the servers start together and the run lasts until the last of them is done
 		rec.begin(constant.StepJmeterRun, "Running load test")
-		jmeterTestCommand := generateJmeterExecutionCmd(loadGeneratorInstallPath, loadGeneratorInstallVersion, testPlanName, resultFileName)
-
-		commandReq = tumblebug.SendCommandReq{
-			Command: []string{jmeterTestCommand},
+		runErrs := make([]error, len(servers))
+		var wg sync.WaitGroup
+		for i, server := range servers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stdout, err := l.commandToServer(context.Background(), nsId, mciId, server, engine.RunCmd(shares[i], loadGeneratorInstallInfo))
+				if err == nil && strings.Contains(stdout, "exited with status 1") {
+					err = fmt.Errorf("%s test stopped unexpectedly", engine.Type())
+				}
+				if err != nil && len(servers) > 1 {
+					err = fmt.Errorf("generator %s; %w", server.VmName, err)
+				}
+				runErrs[i] = err
+			}()
 		}
+		wg.Wait()
+		executionDuration = utils.DurationString(start)
 
-		stdout, err := l.tumblebugClient.CommandToMciWithContext(context.Background(), nsId, mciId, commandReq)
-		if err != nil {
+		if err := errors.Join(runErrs...); err != nil {
 			rec.fail(constant.StepJmeterRun, "Load test failed", err.Error())
 			return compileDuration, executionDuration, err
 		}
-		executionDuration = utils.DurationString(start)
-
-		if strings.Contains(stdout, "exited with status 1") {
-			rec.fail(constant.StepJmeterRun, "Load test failed", "jmeter test stopped unexpectedly")
-			return compileDuration, executionDuration, errors.New("jmeter test stopped unexpectedly")
-		}
 		rec.ok(constant.StepJmeterRun, "Load test finished")
 
 	} else if installLocation == constant.Local {
@@ -707,29 +972,45 @@ func (l *LoadService) executeLoadTest(param RunLoadTestParam, loadGeneratorInsta
 			return compileDuration, executionDuration, errors.New("load generator installaion is not validated")
 		}
 
-		outputFile, err := os.Create(fmt.Sprintf("%s/test_plan/%s.jmx", loadGeneratorInstallPath, loadTestKey))
+		if installCmd := engine.InstallCmd(param, loadGeneratorInstallInfo); installCmd != "" {
+			rec.progress(constant.StepJmxPrepare, 0, fmt.Sprintf("Installing %s", engine.Type()), "")
+			if err := utils.InlineCmd(installCmd); err != nil {
+				rec.fail(constant.StepJmxPrepare, fmt.Sprintf("%s install failed", engine.Type()), err.Error())
+				return compileDuration, executionDuration, err
+			}
+		}
+
+		outputFile, err := os.Create(fmt.Sprintf("%s/test_plan/%s", loadGeneratorInstallPath, testPlanName))
 		if err != nil {
 			rec.fail(constant.StepJmxPrepare, "Test plan write failed", err.Error())
 			return compileDuration, executionDuration, err
 		}
+		defer outputFile.Close()
 
-		err = parseTestPlanStructToString(outputFile, param, loadGeneratorInstallInfo)
+		err = engine.RenderPlan(outputFile, param, loadGeneratorInstallInfo)
 
 		if err != nil {
 			rec.fail(constant.StepJmxPrepare, "Test plan generation failed", err.Error())
 			return compileDuration, executionDuration, err
 		}
+
+		if attachments := planAttachments(param); len(attachments) > 0 {
+			err = writeAttachmentsLocal(planAttachmentDir(loadGeneratorInstallPath, loadTestKey), attachments)
+			if err != nil {
+				rec.fail(constant.StepJmxPrepare, "Test file write failed", err.Error())
+				return compileDuration, executionDuration, err
+			}
+		}
 		rec.ok(constant.StepJmxPrepare, "Test plan ready")
 
 		rec.begin(constant.StepJmeterRun, "Running load test")
-		jmeterTestCommand := generateJmeterExecutionCmd(loadGeneratorInstallPath, loadGeneratorInstallVersion, testPlanName, resultFileName)
 		compileDuration = utils.DurationString(start)
 
-		err = utils.InlineCmd(jmeterTestCommand)
+		err = utils.InlineCmd(engine.RunCmd(param, loadGeneratorInstallInfo))
 		executionDuration = utils.DurationString(start)
 		if err != nil {
-			rec.fail(constant.StepJmeterRun, "Load test failed", fmt.Sprintf("jmeter stopped unexpectedly: %v", err))
-			return compileDuration, executionDuration, fmt.Errorf("jmeter test stopped unexpectedly; %w", err)
+			rec.fail(constant.StepJmeterRun, "Load test failed", fmt.Sprintf("%s stopped unexpectedly: %v", engine.Type(), err))
+			return compileDuration, executionDuration, fmt.Errorf("%s test stopped unexpectedly; %w", engine.Type(), err)
 		}
 		rec.ok(constant.StepJmeterRun, "Load test finished")
 	}
@@ -755,7 +1036,8 @@ func (l *LoadService) updateLoadTestExecution(loadTestExecutionState *LoadTestEx
 
 // generateJmeterExecutionCmd generates the JMeter execution command.
 // Constructs a JMeter command string that includes the test plan path and result file path.
-func generateJmeterExecutionCmd(loadGeneratorInstallPath, loadGeneratorInstallVersion, testPlanName, resultFileName string) string {
+// Every property is passed as -J<name>=<value>, overriding jmeter.properties for this run only.
+func generateJmeterExecutionCmd(loadGeneratorInstallPath, loadGeneratorInstallVersion, testPlanName, resultFileName string, properties ...string) string {
 	log.Info().Msgf("Generating JMeter execution command for test plan: %s, result file: %s", testPlanName, resultFileName)
 
 	var builder strings.Builder
@@ -765,9 +1047,16 @@ func generateJmeterExecutionCmd(loadGeneratorInstallPath, loadGeneratorInstallVe
 	builder.WriteString(fmt.Sprintf("%s/apache-jmeter-%s/bin/jmeter.sh", loadGeneratorInstallPath, loadGeneratorInstallVersion))
 	builder.WriteString(" -n -f")
 	builder.WriteString(fmt.Sprintf(" -t=%s", testPath))
-	builder.WriteString(fmt.Sprintf(" -l=%s", resultPath))
+	if resultFileName != "" {
+		builder.WriteString(fmt.Sprintf(" -l=%s", resultPath))
+	}
+	for _, p := range properties {
+		builder.WriteString(fmt.Sprintf(" -J%s", p))
+	}
 
-	builder.WriteString(fmt.Sprintf(" && sudo rm %s", testPath))
+	// the files the plan read at run time go with it
+	attachmentDir := planAttachmentDir(loadGeneratorInstallPath, strings.TrimSuffix(testPlanName, ".jmx"))
+	builder.WriteString(fmt.Sprintf(" && sudo rm -rf %s %s", testPath, attachmentDir))
 	log.Info().Msgf("JMeter execution command generated: %s", builder.String())
 	return builder.String()
 }
@@ -837,13 +1126,29 @@ func (l *LoadService) StopLoadTest(param StopLoadTestParam) error {
 		return errors.New("load test is already completed")
 	}
 
+	// a run that has not left the queue has nothing to kill yet
+	if state.ExecutionStatus == constant.Queued {
+		dequeued, err := l.dequeueRun(ctx, &state)
+		if err != nil {
+			return fmt.Errorf("error occurred while removing the load test from the queue: %w", err)
+		}
+		if !dequeued {
+			return errRunNotQueued
+		}
+		return nil
+	}
+
 	installInfo, err := l.loadRepo.GetValidLoadGeneratorInstallInfoByIdTx(ctx, state.GeneratorInstallInfoId)
 
 	if err != nil {
 		return fmt.Errorf("error occurred while retrieve load install info: %w", err)
 	}
 
-	killCmd := killCmdGen(param.LoadTestKey)
+	engine, err := engineFor(state.Engine)
+	if err != nil {
+		return err
+	}
+	killCmd := engine.KillCmd(param.LoadTestKey)
 
 	if installInfo.InstallLocation == constant.Remote {
 
@@ -3,11 +3,24 @@ package load
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
+// ErrInvalidLoadTestScenario is returned when a catalog does not describe a load profile that
+// can be run, so the handler can answer with the caller's mistake rather than a server error.
+var ErrInvalidLoadTestScenario = errors.New("load test scenario is not valid")
+
+// orderById keeps a catalog's stages in the order they were given.
+func orderById(db *gorm.DB) *gorm.DB {
+	return db.Order("id asc")
+}
+
 // CreateLoadTestScenarioCatalog creates a new load test scenario catalog.
 func (l *LoadService) CreateLoadTestScenarioCatalog(ctx context.Context, catalog LoadTestScenarioCatalog) (LoadTestScenarioCatalogResult, error) {
 	log.Info().Msg("Starting CreateLoadTestScenarioCatalog")
@@ -22,6 +35,10 @@ func (l *LoadService) CreateLoadTestScenarioCatalog(ctx context.Context, catalog
 		return LoadTestScenarioCatalogResult{}, err
 	}
 
+	if err := validateScenario(catalog); err != nil {
+		return LoadTestScenarioCatalogResult{}, fmt.Errorf("%w; %v", ErrInvalidLoadTestScenario, err)
+	}
+
 	// Create new catalog
 	err = l.db.WithContext(ctx).Create(&catalog).Error
 	if err != nil {
@@ -29,17 +46,7 @@ func (l *LoadService) CreateLoadTestScenarioCatalog(ctx context.Context, catalog
 		return LoadTestScenarioCatalogResult{}, err
 	}
 
-	result := LoadTestScenarioCatalogResult{
-		ID:           catalog.ID,
-		Name:         catalog.Name,
-		Description:  catalog.Description,
-		VirtualUsers: catalog.VirtualUsers,
-		Duration:     catalog.Duration,
-		RampUpTime:   catalog.RampUpTime,
-		RampUpSteps:  catalog.RampUpSteps,
-		CreatedAt:    catalog.CreatedAt,
-		UpdatedAt:    catalog.UpdatedAt,
-	}
+	result := toLoadTestScenarioCatalogResult(catalog)
 
 	log.Info().Uint("catalogId", catalog.ID).Msg("Successfully created load test scenario catalog")
 	return result, nil
@@ -68,7 +75,7 @@ func (l *LoadService) GetAllLoadTestScenarioCatalogs(ctx context.Context, param
 
 	// Apply pagination and get results
 	offset := (param.Page - 1) * param.Size
-	err = query.Offset(offset).Limit(param.Size).Order("created_at DESC").Find(&catalogs).Error
+	err = query.Offset(offset).Limit(param.Size).Order("created_at DESC").Preload("Stages", orderById).Find(&catalogs).Error
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get load test scenario catalogs")
 		return GetAllLoadTestScenarioCatalogsResult{}, err
@@ -77,17 +84,7 @@ func (l *LoadService) GetAllLoadTestScenarioCatalogs(ctx context.Context, param
 	// Convert to result format
 	var catalogResults []LoadTestScenarioCatalogResult
 	for _, catalog := range catalogs {
-		catalogResults = append(catalogResults, LoadTestScenarioCatalogResult{
-			ID:           catalog.ID,
-			Name:         catalog.Name,
-			Description:  catalog.Description,
-			VirtualUsers: catalog.VirtualUsers,
-			Duration:     catalog.Duration,
-			RampUpTime:   catalog.RampUpTime,
-			RampUpSteps:  catalog.RampUpSteps,
-			CreatedAt:    catalog.CreatedAt,
-			UpdatedAt:    catalog.UpdatedAt,
-		})
+		catalogResults = append(catalogResults, toLoadTestScenarioCatalogResult(catalog))
 	}
 
 	result := GetAllLoadTestScenarioCatalogsResult{
@@ -104,7 +101,7 @@ func (l *LoadService) GetLoadTestScenarioCatalog(ctx context.Context, id uint) (
 	log.Info().Uint("catalogId", id).Msg("Starting GetLoadTestScenarioCatalog")
 
 	var catalog LoadTestScenarioCatalog
-	err := l.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&catalog).Error
+	err := l.db.WithContext(ctx).Preload("Stages", orderById).Where("id = ? AND deleted_at IS NULL", id).First(&catalog).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return LoadTestScenarioCatalogResult{}, errors.New("load test scenario catalog not found")
@@ -113,17 +110,7 @@ func (l *LoadService) GetLoadTestScenarioCatalog(ctx context.Context, id uint) (
 		return LoadTestScenarioCatalogResult{}, err
 	}
 
-	result := LoadTestScenarioCatalogResult{
-		ID:           catalog.ID,
-		Name:         catalog.Name,
-		Description:  catalog.Description,
-		VirtualUsers: catalog.VirtualUsers,
-		Duration:     catalog.Duration,
-		RampUpTime:   catalog.RampUpTime,
-		RampUpSteps:  catalog.RampUpSteps,
-		CreatedAt:    catalog.CreatedAt,
-		UpdatedAt:    catalog.UpdatedAt,
-	}
+	result := toLoadTestScenarioCatalogResult(catalog)
 
 	log.Info().Uint("catalogId", id).Msg("Successfully retrieved load test scenario catalog")
 	return result, nil
@@ -135,7 +122,7 @@ func (l *LoadService) UpdateLoadTestScenarioCatalog(ctx context.Context, id uint
 
 	// Check if catalog exists
 	var existingCatalog LoadTestScenarioCatalog
-	err := l.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&existingCatalog).Error
+	err := l.db.WithContext(ctx).Preload("Stages", orderById).Where("id = ? AND deleted_at IS NULL", id).First(&existingCatalog).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return LoadTestScenarioCatalogResult{}, errors.New("load test scenario catalog not found")
@@ -176,31 +163,90 @@ func (l *LoadService) UpdateLoadTestScenarioCatalog(ctx context.Context, id uint
 	if catalog.RampUpSteps != "" {
 		updateData["ramp_up_steps"] = catalog.RampUpSteps
 	}
+	if catalog.LoadShape != "" {
+		updateData["load_shape"] = catalog.LoadShape
+	}
+	if catalog.ArrivalRate != "" {
+		updateData["arrival_rate"] = catalog.ArrivalRate
+	}
+	if catalog.SpikeUsers != "" {
+		updateData["spike_users"] = catalog.SpikeUsers
+	}
+	if catalog.SpikeDuration != "" {
+		updateData["spike_duration"] = catalog.SpikeDuration
+	}
+	if catalog.ThinkTime.Type != "" {
+		updateData["think_time_type"] = catalog.ThinkTime.Type
+		updateData["think_time_delay"] = catalog.ThinkTime.Delay
+		updateData["think_time_range"] = catalog.ThinkTime.Range
+	}
+
+	// The fields are updated one by one, so it is the catalog they add up to that has to make a
+	// valid profile: changing the shape alone can leave it without what the new shape reads.
+	merged := existingCatalog
+	for column, value := range updateData {
+		switch column {
+		case "virtual_users":
+			merged.VirtualUsers = value.(string)
+		case "duration":
+			merged.Duration = value.(string)
+		case "ramp_up_time":
+			merged.RampUpTime = value.(string)
+		case "ramp_up_steps":
+			merged.RampUpSteps = value.(string)
+		case "load_shape":
+			merged.LoadShape = value.(constant.LoadShape)
+		case "arrival_rate":
+			merged.ArrivalRate = value.(string)
+		case "spike_users":
+			merged.SpikeUsers = value.(string)
+		case "spike_duration":
+			merged.SpikeDuration = value.(string)
+		}
+	}
+	if catalog.ThinkTime.Type != "" {
+		merged.ThinkTime = catalog.ThinkTime
+	}
+	// stages are replaced as a whole when given; an empty list clears them
+	if catalog.Stages != nil {
+		merged.Stages = catalog.Stages
+	}
+	if err := validateScenario(merged); err != nil {
+		return LoadTestScenarioCatalogResult{}, fmt.Errorf("%w; %v", ErrInvalidLoadTestScenario, err)
+	}
 
-	err = l.db.WithContext(ctx).Model(&existingCatalog).Updates(updateData).Error
+	err = l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingCatalog).Updates(updateData).Error; err != nil {
+			return err
+		}
+		if catalog.Stages == nil {
+			return nil
+		}
+		if err := tx.Where("load_test_scenario_catalog_id = ?", id).Delete(&LoadTestScenarioCatalogStage{}).Error; err != nil {
+			return err
+		}
+		for i := range catalog.Stages {
+			catalog.Stages[i].ID = 0
+			catalog.Stages[i].LoadTestScenarioCatalogId = existingCatalog.ID
+		}
+		if len(catalog.Stages) == 0 {
+			return nil
+		}
+		return tx.Create(&catalog.Stages).Error
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to update load test scenario catalog")
 		return LoadTestScenarioCatalogResult{}, err
 	}
 
 	// Get updated catalog
-	err = l.db.WithContext(ctx).Where("id = ?", id).First(&existingCatalog).Error
+	err = l.db.WithContext(ctx).Preload("Stages", orderById).Where("id = ?", id).First(&existingCatalog).Error
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get updated catalog")
 		return LoadTestScenarioCatalogResult{}, err
 	}
 
-	result := LoadTestScenarioCatalogResult{
-		ID:           existingCatalog.ID,
-		Name:         existingCatalog.Name,
-		Description:  existingCatalog.Description,
-		VirtualUsers: existingCatalog.VirtualUsers,
-		Duration:     existingCatalog.Duration,
-		RampUpTime:   existingCatalog.RampUpTime,
-		RampUpSteps:  existingCatalog.RampUpSteps,
-		CreatedAt:    existingCatalog.CreatedAt,
-		UpdatedAt:    existingCatalog.UpdatedAt,
-	}
+	result := toLoadTestScenarioCatalogResult(existingCatalog)
 
 	log.Info().Uint("catalogId", id).Msg("Successfully updated load test scenario catalog")
 	return result, nil
@@ -231,3 +277,118 @@ func (l *LoadService) DeleteLoadTestScenarioCatalog(ctx context.Context, id uint
 	log.Info().Uint("catalogId", id).Msg("Successfully deleted load test scenario catalog")
 	return nil
 }
+
+// validateScenario checks that a catalog describes a run that can be rendered.
+func validateScenario(catalog LoadTestScenarioCatalog) error {
+	param := scenarioParamOf(catalog)
+	if err := ValidateLoadShape(param); err != nil {
+		return err
+	}
+	return ValidateThinkTime(param.ThinkTime)
+}
+
+// ValidateRunOfScenarioCatalog checks that a run said to be of a scenario catalog entry carries
+// the entry's load profile. A run with a load of its own would otherwise be held to the entry's
+// baseline, which it has nothing in common with.
+func (l *LoadService) ValidateRunOfScenarioCatalog(ctx context.Context, param RunLoadTestParam) error {
+	if param.ScenarioCatalogId == 0 {
+		return nil
+	}
+	var catalog LoadTestScenarioCatalog
+	err := l.db.WithContext(ctx).Preload("Stages", orderById).Where("id = ? AND deleted_at IS NULL", param.ScenarioCatalogId).First(&catalog).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w; scenario catalog %d not found", ErrInvalidLoadTestScenario, param.ScenarioCatalogId)
+		}
+		return err
+	}
+	if err := scenarioMismatchOf(param, scenarioParamOf(catalog)); err != nil {
+		return fmt.Errorf("%w; the run is not of scenario catalog %d: %v", ErrInvalidLoadTestScenario, param.ScenarioCatalogId, err)
+	}
+	return nil
+}
+
+// scenarioMismatchOf tells what of the load profile of run differs from that of scenario, of
+// the fields its shape reads; nil when nothing does. A scenario without a think time leaves the
+// run its own, as a schedule does.
+func scenarioMismatchOf(run, scenario RunLoadTestParam) error {
+	shape := loadShapeOf(run)
+	if want := loadShapeOf(scenario); shape != want {
+		return fmt.Errorf("its load shape is %s, the scenario's %s", shape, want)
+	}
+
+	fields := [][3]string{
+		{"virtualUsers", run.VirtualUsers, scenario.VirtualUsers},
+		{"duration", run.Duration, scenario.Duration},
+		{"rampUpTime", run.RampUpTime, scenario.RampUpTime},
+	}
+	switch shape {
+	case constant.ConcurrencyShape, constant.StepDownShape:
+		fields = append(fields, [3]string{"rampUpSteps", run.RampUpSteps, scenario.RampUpSteps})
+	case constant.ArrivalRateShape:
+		fields = append(fields, [3]string{"rampUpSteps", run.RampUpSteps, scenario.RampUpSteps},
+			[3]string{"arrivalRate", run.ArrivalRate, scenario.ArrivalRate})
+	case constant.SpikeShape:
+		fields = append(fields, [3]string{"spikeUsers", run.SpikeUsers, scenario.SpikeUsers},
+			[3]string{"spikeDuration", run.SpikeDuration, scenario.SpikeDuration})
+	case constant.StagesShape:
+		fields = nil
+		if !slices.Equal(run.Stages, scenario.Stages) {
+			return fmt.Errorf("its stages are %v, the scenario's %v", run.Stages, scenario.Stages)
+		}
+	}
+	for _, f := range fields {
+		if strings.TrimSpace(f[1]) != strings.TrimSpace(f[2]) {
+			return fmt.Errorf("its %s is %q, the scenario's %q", f[0], f[1], f[2])
+		}
+	}
+
+	if scenario.ThinkTime != nil && thinkTimeModelOf(run.ThinkTime) != thinkTimeModelOf(scenario.ThinkTime) {
+		return fmt.Errorf("its think time is %+v, the scenario's %+v", thinkTimeModelOf(run.ThinkTime), thinkTimeModelOf(scenario.ThinkTime))
+	}
+	return nil
+}
+
+// scenarioParamOf is the run a catalog describes, as far as its load profile goes.
+func scenarioParamOf(catalog LoadTestScenarioCatalog) RunLoadTestParam {
+	param := RunLoadTestParam{
+		VirtualUsers:  catalog.VirtualUsers,
+		Duration:      catalog.Duration,
+		RampUpTime:    catalog.RampUpTime,
+		RampUpSteps:   catalog.RampUpSteps,
+		LoadShape:     catalog.LoadShape,
+		ArrivalRate:   catalog.ArrivalRate,
+		SpikeUsers:    catalog.SpikeUsers,
+		SpikeDuration: catalog.SpikeDuration,
+		ThinkTime:     catalog.ThinkTime.param(),
+	}
+	for _, s := range catalog.Stages {
+		param.Stages = append(param.Stages, RunLoadTestStageParam{Target: s.Target, Duration: s.Duration})
+	}
+	return param
+}
+
+func toLoadTestScenarioCatalogResult(catalog LoadTestScenarioCatalog) LoadTestScenarioCatalogResult {
+	param := scenarioParamOf(catalog)
+	// stored catalogs were validated, but ones from before load shapes may not parse; 0 then
+	total, _ := ExpectedExecutionSecond(param)
+
+	return LoadTestScenarioCatalogResult{
+		ID:                           catalog.ID,
+		Name:                         catalog.Name,
+		Description:                  catalog.Description,
+		VirtualUsers:                 catalog.VirtualUsers,
+		Duration:                     catalog.Duration,
+		RampUpTime:                   catalog.RampUpTime,
+		RampUpSteps:                  catalog.RampUpSteps,
+		LoadShape:                    loadShapeOf(param),
+		ArrivalRate:                  catalog.ArrivalRate,
+		SpikeUsers:                   catalog.SpikeUsers,
+		SpikeDuration:                catalog.SpikeDuration,
+		Stages:                       param.Stages,
+		ThinkTime:                    param.ThinkTime,
+		TotalExpectedExecutionSecond: total,
+		CreatedAt:                    catalog.CreatedAt,
+		UpdatedAt:                    catalog.UpdatedAt,
+	}
+}
@@ -0,0 +1,462 @@
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // schedules name their time zone; the image may not ship a zoneinfo database
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidLoadTestSchedule is returned when a schedule cannot fire as given, so the handler
+// can answer with the caller's mistake rather than a server error.
+var ErrInvalidLoadTestSchedule = errors.New("load test schedule is not valid")
+
+// ErrLoadTestScheduleNotFound is returned for a schedule id that does not exist (any more).
+var ErrLoadTestScheduleNotFound = errors.New("load test schedule not found")
+
+// scheduleTickInterval is how often the scheduler looks for schedules that are due. Cron
+// fires on whole minutes, so a run starts within half a minute of its time.
+const scheduleTickInterval = 30 * time.Second
+
+// CreateLoadTestSchedule stores a schedule and works out when it fires first.
+func (l *LoadService) CreateLoadTestSchedule(ctx context.Context, param SaveLoadTestScheduleParam) (LoadTestScheduleResult, error) {
+	log.Info().Msg("Starting CreateLoadTestSchedule")
+
+	schedule, err := scheduleOf(param, time.Now())
+	if err != nil {
+		return LoadTestScheduleResult{}, err
+	}
+
+	if err := l.db.WithContext(ctx).Create(&schedule).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to create load test schedule")
+		return LoadTestScheduleResult{}, err
+	}
+
+	log.Info().Uint("scheduleId", schedule.ID).Msg("Successfully created load test schedule")
+	return toLoadTestScheduleResult(schedule), nil
+}
+
+// GetAllLoadTestSchedules lists the stored schedules.
+func (l *LoadService) GetAllLoadTestSchedules(ctx context.Context, param GetAllLoadTestSchedulesParam) (GetAllLoadTestSchedulesResult, error) {
+	log.Info().Msg("Starting GetAllLoadTestSchedules")
+
+	var schedules []LoadTestSchedule
+	var totalCount int64
+
+	query := l.db.WithContext(ctx).Model(&LoadTestSchedule{}).Where("deleted_at IS NULL")
+	if param.Name != "" {
+		query = query.Where("name LIKE ?", "%"+param.Name+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to count load test schedules")
+		return GetAllLoadTestSchedulesResult{}, err
+	}
+
+	offset := (param.Page - 1) * param.Size
+	err := query.Offset(offset).Limit(param.Size).Order("created_at DESC").Find(&schedules).Error
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get load test schedules")
+		return GetAllLoadTestSchedulesResult{}, err
+	}
+
+	results := []LoadTestScheduleResult{}
+	for _, schedule := range schedules {
+		results = append(results, toLoadTestScheduleResult(schedule))
+	}
+
+	log.Info().Int64("totalCount", totalCount).Int("returnedCount", len(results)).Msg("Successfully retrieved load test schedules")
+	return GetAllLoadTestSchedulesResult{LoadTestSchedules: results, TotalRow: totalCount}, nil
+}
+
+// GetLoadTestSchedule returns a schedule by id.
+func (l *LoadService) GetLoadTestSchedule(ctx context.Context, id uint) (LoadTestScheduleResult, error) {
+	log.Info().Uint("scheduleId", id).Msg("Starting GetLoadTestSchedule")
+
+	schedule, err := l.findLoadTestSchedule(ctx, id)
+	if err != nil {
+		return LoadTestScheduleResult{}, err
+	}
+
+	log.Info().Uint("scheduleId", id).Msg("Successfully retrieved load test schedule")
+	return toLoadTestScheduleResult(schedule), nil
+}
+
+// UpdateLoadTestSchedule replaces a schedule as a whole. When it fires next is worked out again
+// from now, so re-enabling a schedule does not make up for the times it was off.
+func (l *LoadService) UpdateLoadTestSchedule(ctx context.Context, id uint, param SaveLoadTestScheduleParam) (LoadTestScheduleResult, error) {
+	log.Info().Uint("scheduleId", id).Msg("Starting UpdateLoadTestSchedule")
+
+	existing, err := l.findLoadTestSchedule(ctx, id)
+	if err != nil {
+		return LoadTestScheduleResult{}, err
+	}
+
+	schedule, err := scheduleOf(param, time.Now())
+	if err != nil {
+		return LoadTestScheduleResult{}, err
+	}
+	schedule.Model = existing.Model
+	schedule.LastRunAt = existing.LastRunAt
+
+	// Save writes every column, so a nil RunAt or NextRunAt clears the stored one
+	if err := l.db.WithContext(ctx).Save(&schedule).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to update load test schedule")
+		return LoadTestScheduleResult{}, err
+	}
+
+	log.Info().Uint("scheduleId", id).Msg("Successfully updated load test schedule")
+	return toLoadTestScheduleResult(schedule), nil
+}
+
+// DeleteLoadTestSchedule soft deletes a schedule. Its run history is kept with it.
+func (l *LoadService) DeleteLoadTestSchedule(ctx context.Context, id uint) error {
+	log.Info().Uint("scheduleId", id).Msg("Starting DeleteLoadTestSchedule")
+
+	schedule, err := l.findLoadTestSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := l.db.WithContext(ctx).Delete(&schedule).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to delete load test schedule")
+		return err
+	}
+
+	log.Info().Uint("scheduleId", id).Msg("Successfully deleted load test schedule")
+	return nil
+}
+
+// GetLoadTestScheduleRuns lists the times a schedule fired, the latest first, with where each
+// run it started is now.
+func (l *LoadService) GetLoadTestScheduleRuns(ctx context.Context, param GetLoadTestScheduleRunsParam) (GetLoadTestScheduleRunsResult, error) {
+	log.Info().Uint("scheduleId", param.ScheduleId).Msg("Starting GetLoadTestScheduleRuns")
+
+	if _, err := l.findLoadTestSchedule(ctx, param.ScheduleId); err != nil {
+		return GetLoadTestScheduleRunsResult{}, err
+	}
+
+	var runs []LoadTestScheduleRun
+	var totalCount int64
+
+	query := l.db.WithContext(ctx).Model(&LoadTestScheduleRun{}).Where("load_test_schedule_id = ?", param.ScheduleId)
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to count load test schedule runs")
+		return GetLoadTestScheduleRunsResult{}, err
+	}
+
+	offset := (param.Page - 1) * param.Size
+	if err := query.Offset(offset).Limit(param.Size).Order("scheduled_at DESC, id DESC").Find(&runs).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to get load test schedule runs")
+		return GetLoadTestScheduleRunsResult{}, err
+	}
+
+	var keys []string
+	for _, r := range runs {
+		if r.LoadTestKey != "" {
+			keys = append(keys, r.LoadTestKey)
+		}
+	}
+	statuses := make(map[string]constant.ExecutionStatus)
+	if len(keys) > 0 {
+		var states []LoadTestExecutionState
+		if err := l.db.WithContext(ctx).Select("load_test_key", "execution_status").Where("load_test_key IN ?", keys).Find(&states).Error; err != nil {
+			log.Error().Err(err).Msg("Failed to get execution states of load test schedule runs")
+			return GetLoadTestScheduleRunsResult{}, err
+		}
+		for _, s := range states {
+			statuses[s.LoadTestKey] = s.ExecutionStatus
+		}
+	}
+
+	results := []LoadTestScheduleRunResult{}
+	for _, r := range runs {
+		results = append(results, LoadTestScheduleRunResult{
+			ID:              r.ID,
+			ScheduledAt:     r.ScheduledAt,
+			StartedAt:       r.CreatedAt,
+			Status:          r.Status,
+			LoadTestKey:     r.LoadTestKey,
+			Message:         r.Message,
+			ExecutionStatus: statuses[r.LoadTestKey],
+		})
+	}
+
+	log.Info().Int64("totalCount", totalCount).Int("returnedCount", len(results)).Msg("Successfully retrieved load test schedule runs")
+	return GetLoadTestScheduleRunsResult{LoadTestScheduleRuns: results, TotalRow: totalCount}, nil
+}
+
+// RunSchedules starts the runs of schedules as they come due, until ctx is done. Schedules
+// live in the database, so one that came due while the server was down fires once as soon
+// as it is back, however many of its times were missed.
+func (l *LoadService) RunSchedules(ctx context.Context) {
+	log.Info().Msg("Load test scheduler started")
+	ticker := time.NewTicker(scheduleTickInterval)
+	defer ticker.Stop()
+
+	for {
+		l.fireDueSchedules(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Load test scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fireDueSchedules starts a run for every enabled schedule that is due at now, one after the
+// other. A schedule is moved on to its next time before its run starts, and only if nobody
+// else moved it first, so a time is never fired twice.
+func (l *LoadService) fireDueSchedules(ctx context.Context, now time.Time) {
+	now = now.UTC().Truncate(time.Second)
+
+	var due []LoadTestSchedule
+	err := l.db.WithContext(ctx).
+		Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ? AND deleted_at IS NULL", true, now).
+		Order("next_run_at asc").
+		Find(&due).Error
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get due load test schedules")
+		return
+	}
+
+	for _, schedule := range due {
+		scheduledAt := *schedule.NextRunAt
+
+		next, err := nextRunAt(schedule, now)
+		if err != nil {
+			// it was checked when stored; a schedule that cannot be read any more is stopped
+			log.Error().Err(err).Uint("scheduleId", schedule.ID).Msg("Stopping load test schedule")
+		}
+		updates := map[string]interface{}{"next_run_at": next, "last_run_at": now}
+		if next == nil {
+			updates["enabled"] = false
+		}
+
+		res := l.db.WithContext(ctx).Model(&LoadTestSchedule{}).
+			Where("id = ? AND next_run_at = ?", schedule.ID, scheduledAt).
+			Updates(updates)
+		if res.Error != nil {
+			log.Error().Err(res.Error).Uint("scheduleId", schedule.ID).Msg("Failed to move load test schedule on")
+			continue
+		}
+		if res.RowsAffected == 0 || err != nil {
+			continue
+		}
+
+		l.fireSchedule(ctx, schedule, scheduledAt)
+	}
+}
+
+// fireSchedule starts the run of schedule that was due at scheduledAt and records it.
+func (l *LoadService) fireSchedule(ctx context.Context, schedule LoadTestSchedule, scheduledAt time.Time) {
+	log.Info().Uint("scheduleId", schedule.ID).Time("scheduledAt", scheduledAt).Msg("Firing load test schedule")
+
+	record := LoadTestScheduleRun{
+		ScheduledAt:        scheduledAt,
+		Status:             constant.ScheduleRunStarted,
+		LoadTestScheduleId: schedule.ID,
+	}
+
+	param, err := l.scheduledRunOf(ctx, schedule, scheduledAt)
+	if err == nil {
+		record.LoadTestKey, err = l.RunLoadTest(param)
+	}
+	if err != nil {
+		log.Error().Err(err).Uint("scheduleId", schedule.ID).Msg("Scheduled load test did not start")
+		record.Status = constant.ScheduleRunFailed
+		record.Message = err.Error()
+	}
+
+	if err := l.db.WithContext(ctx).Create(&record).Error; err != nil {
+		log.Error().Err(err).Uint("scheduleId", schedule.ID).Msg("Failed to record load test schedule run")
+	}
+}
+
+// scheduledRunOf is the run schedule starts: the stored run, with the load profile its
+// scenario catalog has now and the name of the schedule and the time it was due.
+func (l *LoadService) scheduledRunOf(ctx context.Context, schedule LoadTestSchedule, scheduledAt time.Time) (RunLoadTestParam, error) {
+	var param RunLoadTestParam
+	if err := json.Unmarshal(schedule.Run, &param); err != nil {
+		return param, fmt.Errorf("stored run could not be read; %w", err)
+	}
+
+	var catalog LoadTestScenarioCatalog
+	err := l.db.WithContext(ctx).Preload("Stages", orderById).Where("id = ? AND deleted_at IS NULL", schedule.ScenarioCatalogId).First(&catalog).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return param, fmt.Errorf("scenario catalog %d not found", schedule.ScenarioCatalogId)
+		}
+		return param, err
+	}
+
+	scenario := scenarioParamOf(catalog)
+	param.VirtualUsers = scenario.VirtualUsers
+	param.Duration = scenario.Duration
+	param.RampUpTime = scenario.RampUpTime
+	param.RampUpSteps = scenario.RampUpSteps
+	param.LoadShape = scenario.LoadShape
+	param.ArrivalRate = scenario.ArrivalRate
+	param.SpikeUsers = scenario.SpikeUsers
+	param.SpikeDuration = scenario.SpikeDuration
+	param.Stages = scenario.Stages
+	if scenario.ThinkTime != nil {
+		param.ThinkTime = scenario.ThinkTime
+	}
+
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	param.TestName = fmt.Sprintf("%s %s", schedule.Name, scheduledAt.In(loc).Format("2006-01-02 15:04"))
+	param.NsId = schedule.NsId
+	param.InfraId = schedule.InfraId
+	param.NodeId = schedule.NodeId
+	param.ScenarioCatalogId = schedule.ScenarioCatalogId
+	return param, nil
+}
+
+func (l *LoadService) findLoadTestSchedule(ctx context.Context, id uint) (LoadTestSchedule, error) {
+	var schedule LoadTestSchedule
+	err := l.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return schedule, ErrLoadTestScheduleNotFound
+		}
+		log.Error().Err(err).Msg("Failed to get load test schedule")
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+// scheduleOf checks param and turns it into the schedule it stores, due next after now.
+func scheduleOf(param SaveLoadTestScheduleParam, now time.Time) (LoadTestSchedule, error) {
+	if param.TimeZone == "" {
+		param.TimeZone = "UTC"
+	}
+	param.Cron = strings.TrimSpace(param.Cron)
+	if param.RunAt != nil {
+		runAt := param.RunAt.UTC().Truncate(time.Second)
+		param.RunAt = &runAt
+	}
+
+	run, err := json.Marshal(param.Run)
+	if err != nil {
+		return LoadTestSchedule{}, err
+	}
+	schedule := LoadTestSchedule{
+		Name:              param.Name,
+		Description:       param.Description,
+		Cron:              param.Cron,
+		RunAt:             param.RunAt,
+		TimeZone:          param.TimeZone,
+		Enabled:           param.Enabled,
+		ScenarioCatalogId: param.ScenarioCatalogId,
+		NsId:              param.NsId,
+		InfraId:           param.InfraId,
+		NodeId:            param.NodeId,
+		Run:               run,
+	}
+
+	if err := validateSchedule(schedule, now); err != nil {
+		return LoadTestSchedule{}, fmt.Errorf("%w; %v", ErrInvalidLoadTestSchedule, err)
+	}
+	if schedule.Enabled {
+		// validated above, so it fires again
+		schedule.NextRunAt, _ = nextRunAt(schedule, now)
+	}
+	return schedule, nil
+}
+
+// validateSchedule checks that a schedule says when to fire and fires at least once after now.
+func validateSchedule(schedule LoadTestSchedule, now time.Time) error {
+	if schedule.ScenarioCatalogId == 0 {
+		return errors.New("a schedule needs a scenario catalog")
+	}
+	if (schedule.Cron == "") == (schedule.RunAt == nil) {
+		return errors.New("give either a cron expression or a time to run once, not both")
+	}
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		return fmt.Errorf("time zone %q is unknown", schedule.TimeZone)
+	}
+	if schedule.Cron != "" {
+		if _, err := parseCron(schedule.Cron); err != nil {
+			return err
+		}
+	}
+
+	next, err := nextRunAt(schedule, now)
+	if err != nil {
+		return err
+	}
+	if next == nil && schedule.Enabled {
+		if schedule.RunAt != nil {
+			return fmt.Errorf("run time %s has already passed", schedule.RunAt.Format(time.RFC3339))
+		}
+		return fmt.Errorf("cron expression %q never fires", schedule.Cron)
+	}
+	return nil
+}
+
+// nextRunAt is the first time after t the schedule fires at, in UTC, or nil when it does not
+// fire again. Cron fields are read in the schedule's time zone, so 0 4 * * * stays at 4 am
+// there across daylight saving changes; a time the clocks skip is skipped too.
+func nextRunAt(schedule LoadTestSchedule, t time.Time) (*time.Time, error) {
+	if schedule.Cron == "" {
+		if schedule.RunAt == nil || !schedule.RunAt.After(t) {
+			return nil, nil
+		}
+		runAt := schedule.RunAt.UTC()
+		return &runAt, nil
+	}
+
+	c, err := parseCron(schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("time zone %q is unknown", schedule.TimeZone)
+	}
+	next := c.next(t.In(loc))
+	if next.IsZero() {
+		return nil, nil
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+func toLoadTestScheduleResult(schedule LoadTestSchedule) LoadTestScheduleResult {
+	var run RunLoadTestParam
+	if err := json.Unmarshal(schedule.Run, &run); err != nil {
+		log.Warn().Err(err).Uint("scheduleId", schedule.ID).Msg("Stored run of load test schedule could not be read")
+	}
+
+	return LoadTestScheduleResult{
+		ID:                schedule.ID,
+		Name:              schedule.Name,
+		Description:       schedule.Description,
+		Cron:              schedule.Cron,
+		RunAt:             schedule.RunAt,
+		TimeZone:          schedule.TimeZone,
+		Enabled:           schedule.Enabled,
+		ScenarioCatalogId: schedule.ScenarioCatalogId,
+		NsId:              schedule.NsId,
+		InfraId:           schedule.InfraId,
+		NodeId:            schedule.NodeId,
+		Run:               run,
+		NextRunAt:         schedule.NextRunAt,
+		LastRunAt:         schedule.LastRunAt,
+		CreatedAt:         schedule.CreatedAt,
+		UpdatedAt:         schedule.UpdatedAt,
+	}
+}
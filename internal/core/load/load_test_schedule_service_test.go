@@ -0,0 +1,73 @@
+package load
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleFiresInItsTimeZone(t *testing.T) {
+	now := time.Date(2026, 3, 27, 12, 0, 0, 0, time.UTC)
+	schedule, err := scheduleOf(SaveLoadTestScheduleParam{
+		Name: "nightly", Cron: "0 4 * * *", TimeZone: "Europe/Berlin", Enabled: true, ScenarioCatalogId: 1,
+		Run: RunLoadTestParam{VirtualUsers: "5", HttpReqs: []RunLoadTestHttpParam{{Method: "GET"}}},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4 am in Berlin is 3 am UTC before the clocks go forward on the 29th and 2 am after
+	want := time.Date(2026, 3, 28, 3, 0, 0, 0, time.UTC)
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(want) {
+		t.Errorf("expected the first run at %s, got %v", want, schedule.NextRunAt)
+	}
+	next, err := nextRunAt(schedule, want)
+	if err != nil || next == nil || !next.Equal(want.Add(23*time.Hour)) {
+		t.Errorf("expected the next run 23 hours later, got %v, %v", next, err)
+	}
+
+	var run RunLoadTestParam
+	if err := json.Unmarshal(schedule.Run, &run); err != nil || len(run.HttpReqs) != 1 {
+		t.Errorf("expected the run to be kept as it was accepted, got %+v, %v", run, err)
+	}
+}
+
+func TestOneShotScheduleFiresOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	runAt := now.Add(time.Hour)
+	schedule, err := scheduleOf(SaveLoadTestScheduleParam{Name: "once", RunAt: &runAt, Enabled: true, ScenarioCatalogId: 1}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(runAt) {
+		t.Errorf("expected it to fire at %s, got %v", runAt, schedule.NextRunAt)
+	}
+	if next, _ := nextRunAt(schedule, runAt); next != nil {
+		t.Errorf("expected nothing after it fired, got %s", next)
+	}
+
+	// a disabled schedule keeps a time that passed, but is not due
+	disabled, err := scheduleOf(SaveLoadTestScheduleParam{Name: "once", RunAt: &now, ScenarioCatalogId: 1}, runAt)
+	if err != nil || disabled.NextRunAt != nil {
+		t.Errorf("expected a disabled schedule without a next run, got %v, %v", disabled.NextRunAt, err)
+	}
+}
+
+func TestSchedulesThatCannotFireAreRefused(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	for name, param := range map[string]SaveLoadTestScheduleParam{
+		"neither":        {Name: "x", ScenarioCatalogId: 1, Enabled: true},
+		"both":           {Name: "x", ScenarioCatalogId: 1, Enabled: true, Cron: "@daily", RunAt: &now},
+		"unknown zone":   {Name: "x", ScenarioCatalogId: 1, Enabled: true, Cron: "@daily", TimeZone: "Mars/Olympus"},
+		"bad cron":       {Name: "x", ScenarioCatalogId: 1, Enabled: true, Cron: "every night"},
+		"never":          {Name: "x", ScenarioCatalogId: 1, Enabled: true, Cron: "0 0 31 4 *"},
+		"passed":         {Name: "x", ScenarioCatalogId: 1, Enabled: true, RunAt: &past},
+		"no catalog set": {Name: "x", Enabled: true, Cron: "@daily"},
+	} {
+		if _, err := scheduleOf(param, now); !errors.Is(err, ErrInvalidLoadTestSchedule) {
+			t.Errorf("%s: expected the schedule to be refused, got %v", name, err)
+		}
+	}
+}
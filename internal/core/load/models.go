@@ -77,6 +77,9 @@ type LoadTestExecutionState struct {
 	gorm.Model
 	LoadTestKey string `gorm:"index:idx_state_load_test_key,unique"`
 
+	// ParentLoadTestKey is the run this one was made again from; empty for a run of its own.
+	ParentLoadTestKey string `gorm:"index"`
+
 	NsId    string
 	InfraId string
 	NodeId  string
@@ -104,12 +107,41 @@ type LoadTestExecutionState struct {
 	ExecutionDuration           string
 	WithMetrics                 bool
 
+	// Engine is the tool that generated the load, which decides how the run is stopped and how
+	// its result file is read. Runs recorded before there was a choice were JMeter runs.
+	Engine constant.LoadGeneratorType `gorm:"default:jmeter"`
+
 	// not to make one to one relationship between LoadTestExecutionInfo and LoadGeneratorInstallInfo
 	TestExecutionInfoId    uint
 	GeneratorInstallInfoId uint
 
 	// Steps are the per-stage progress records (FR-MA2-PERF-007-08).
 	Steps []LoadTestExecutionStep `gorm:"foreignKey:LoadTestExecutionStateId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Verdict is set once a run with SLA thresholds has finished successfully; runs without
+	// thresholds, and runs that failed before producing a result, have none.
+	Verdict       constant.SlaVerdict
+	SlaViolations []LoadTestExecutionSlaViolation `gorm:"foreignKey:LoadTestExecutionStateId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// ScenarioCatalogId is the scenario catalog entry the run is of; 0 for a run of none.
+	ScenarioCatalogId uint `gorm:"index"`
+
+	// BaselineLoadTestKey is the baseline a run that finished successfully was compared with,
+	// BaselineVerdict what came of it and BaselineMessage what regressed, or why the comparison
+	// could not be made. All are empty for a run that had no baseline.
+	BaselineLoadTestKey string
+	BaselineVerdict     constant.BaselineVerdict `gorm:"index"`
+	BaselineMessage     string
+}
+
+// LoadTestExecutionSlaViolation is one SLA threshold a run missed.
+type LoadTestExecutionSlaViolation struct {
+	gorm.Model
+	LoadTestExecutionStateId uint `gorm:"index"`
+	Criterion                constant.SlaCriterion
+	Threshold                float64
+	Actual                   float64
+	Message                  string
 }
 
 // LoadTestExecutionStep records the status of one stage of a load test run so the web
@@ -137,6 +169,17 @@ type LoadTestExecutionInfo struct {
 	RampUpTime   string
 	RampUpSteps  string
 
+	// LoadShape is how the load changed over time; runs recorded before there was a choice
+	// used the concurrency thread group. LoadStages are the stages of a stages run, as given.
+	LoadShape     constant.LoadShape `gorm:"default:concurrency"`
+	ArrivalRate   string
+	SpikeUsers    string
+	SpikeDuration string
+	LoadStages    []LoadTestExecutionLoadStage
+
+	// ThinkTime is the run's default think time; a request's own one is stored with it.
+	ThinkTime LoadTestThinkTime `gorm:"embedded;embeddedPrefix:think_time_"`
+
 	NsId    string
 	InfraId string
 	NodeId  string
@@ -144,10 +187,35 @@ type LoadTestExecutionInfo struct {
 	AgentHostname  string
 	AgentInstalled bool
 
+	Engine constant.LoadGeneratorType `gorm:"default:jmeter"`
+
 	CompileDuration            string
 	ExecutionDuration          string
 	LoadTestExecutionHttpInfos []LoadTestExecutionHttpInfo
 
+	// UploadedFiles is the user supplied plan and its data files, for runs that brought their
+	// own plan instead of HttpInfos.
+	UploadedFiles []LoadTestExecutionUploadedFile
+
+	// DataSets are the CSV files the run read, kept with it like the uploaded files.
+	DataSets []LoadTestExecutionDataSet
+
+	// Sla are the thresholds the run is judged by; the verdict is kept with its state.
+	Sla LoadTestSla `gorm:"embedded;embeddedPrefix:sla_"`
+
+	// AutoStop are the rules that stop the run early; the one that did is told in its steps.
+	AutoStop LoadTestAutoStop `gorm:"embedded;embeddedPrefix:auto_stop_"`
+
+	// HttpClient are the client options the run's requests were sent with, defaults included,
+	// so a result can be read knowing how long a request was allowed to take.
+	HttpClient LoadTestHttpClient `gorm:"embedded;embeddedPrefix:http_client_"`
+
+	// Targets are the non-HTTP targets of the run, in the order they are sent.
+	Targets []LoadTestExecutionTarget
+
+	// Regions are the places the run sent load from, in the order the result parts are numbered.
+	Regions []LoadTestExecutionRegion
+
 	// LoadTestExecutionInfo has one LoadTestExecutionState
 	LoadTestExecutionStateId uint
 	LoadTestExecutionState   LoadTestExecutionState
@@ -160,6 +228,214 @@ type LoadTestExecutionInfo struct {
 	LoadGeneratorInstallInfo   LoadGeneratorInstallInfo
 }
 
+// LoadTestThinkTime is a think time as stored; an empty Type means there is none.
+type LoadTestThinkTime struct {
+	Type  constant.ThinkTimeType
+	Delay int
+	Range int
+}
+
+func thinkTimeModelOf(param *RunLoadTestThinkTimeParam) LoadTestThinkTime {
+	if param == nil {
+		return LoadTestThinkTime{}
+	}
+	return LoadTestThinkTime{Type: param.Type, Delay: param.Delay, Range: param.Range}
+}
+
+func (t LoadTestThinkTime) param() *RunLoadTestThinkTimeParam {
+	if t.Type == "" {
+		return nil
+	}
+	return &RunLoadTestThinkTimeParam{Type: t.Type, Delay: t.Delay, Range: t.Range}
+}
+
+// LoadTestSla is an SLA as stored; a nil threshold is not checked.
+type LoadTestSla struct {
+	MaxErrorPercent *float64
+	MaxP95          *float64
+	MaxP99          *float64
+	MinThroughput   *float64
+}
+
+func slaModelOf(param *RunLoadTestSlaParam) LoadTestSla {
+	if param == nil {
+		return LoadTestSla{}
+	}
+	return LoadTestSla{
+		MaxErrorPercent: param.MaxErrorPercent,
+		MaxP95:          param.MaxP95,
+		MaxP99:          param.MaxP99,
+		MinThroughput:   param.MinThroughput,
+	}
+}
+
+func (s LoadTestSla) param() *RunLoadTestSlaParam {
+	if s.MaxErrorPercent == nil && s.MaxP95 == nil && s.MaxP99 == nil && s.MinThroughput == nil {
+		return nil
+	}
+	return &RunLoadTestSlaParam{
+		MaxErrorPercent: s.MaxErrorPercent,
+		MaxP95:          s.MaxP95,
+		MaxP99:          s.MaxP99,
+		MinThroughput:   s.MinThroughput,
+	}
+}
+
+// LoadTestAutoStop are auto-stop rules as stored; a nil limit is not checked.
+type LoadTestAutoStop struct {
+	MaxErrorPercent    *float64
+	ErrorPercentForSec int
+	MaxP95             *float64
+	MaxAgentCpuPercent *float64
+}
+
+func autoStopModelOf(param *RunLoadTestAutoStopParam) LoadTestAutoStop {
+	if param == nil {
+		return LoadTestAutoStop{}
+	}
+	return LoadTestAutoStop{
+		MaxErrorPercent:    param.MaxErrorPercent,
+		ErrorPercentForSec: param.ErrorPercentForSec,
+		MaxP95:             param.MaxP95,
+		MaxAgentCpuPercent: param.MaxAgentCpuPercent,
+	}
+}
+
+func (a LoadTestAutoStop) param() *RunLoadTestAutoStopParam {
+	if a.MaxErrorPercent == nil && a.MaxP95 == nil && a.MaxAgentCpuPercent == nil {
+		return nil
+	}
+	return &RunLoadTestAutoStopParam{
+		MaxErrorPercent:    a.MaxErrorPercent,
+		ErrorPercentForSec: a.ErrorPercentForSec,
+		MaxP95:             a.MaxP95,
+		MaxAgentCpuPercent: a.MaxAgentCpuPercent,
+	}
+}
+
+// LoadTestHttpClient are HTTP client options as stored. They are stored with the engine's
+// defaults filled in, so a zero ResponseTimeout marks a run made before they were kept.
+type LoadTestHttpClient struct {
+	ConnectTimeout            int
+	ResponseTimeout           int
+	KeepAlive                 bool
+	FollowRedirects           bool
+	Implementation            constant.HttpImplementation
+	DownloadEmbeddedResources bool
+	TlsVersion                constant.TlsVersion
+	InsecureSkipVerify        bool
+}
+
+func httpClientModelOf(param *RunLoadTestHttpClientParam) LoadTestHttpClient {
+	if param == nil {
+		return LoadTestHttpClient{}
+	}
+	return LoadTestHttpClient{
+		ConnectTimeout:            param.ConnectTimeout,
+		ResponseTimeout:           param.ResponseTimeout,
+		KeepAlive:                 param.KeepAlive != nil && *param.KeepAlive,
+		FollowRedirects:           param.FollowRedirects != nil && *param.FollowRedirects,
+		Implementation:            param.Implementation,
+		DownloadEmbeddedResources: param.DownloadEmbeddedResources != nil && *param.DownloadEmbeddedResources,
+		TlsVersion:                param.TlsVersion,
+		InsecureSkipVerify:        param.InsecureSkipVerify != nil && *param.InsecureSkipVerify,
+	}
+}
+
+func (c LoadTestHttpClient) param() *RunLoadTestHttpClientParam {
+	if c.ResponseTimeout == 0 {
+		return nil
+	}
+	keepAlive, followRedirects := c.KeepAlive, c.FollowRedirects
+	downloadEmbeddedResources, insecureSkipVerify := c.DownloadEmbeddedResources, c.InsecureSkipVerify
+	return &RunLoadTestHttpClientParam{
+		ConnectTimeout:            c.ConnectTimeout,
+		ResponseTimeout:           c.ResponseTimeout,
+		KeepAlive:                 &keepAlive,
+		FollowRedirects:           &followRedirects,
+		Implementation:            c.Implementation,
+		DownloadEmbeddedResources: &downloadEmbeddedResources,
+		TlsVersion:                c.TlsVersion,
+		InsecureSkipVerify:        &insecureSkipVerify,
+	}
+}
+
+type LoadTestExecutionLoadStage struct {
+	gorm.Model
+	Target   int
+	Duration int
+
+	LoadTestExecutionInfoId uint
+}
+
+type LoadTestExecutionUploadedFile struct {
+	gorm.Model
+	FileName   string
+	IsTestPlan bool
+	Size       int
+	Content    []byte
+
+	LoadTestExecutionInfoId uint
+}
+
+// LoadTestExecutionDataSet keeps a data set's file with the run, so a re-run reads the same
+// lines even after the stored data set it came from has been deleted. Columns are the names
+// from the header line, comma separated, so a listing can show them without the content.
+type LoadTestExecutionDataSet struct {
+	gorm.Model
+	DataSetId  uint
+	FileName   string
+	Columns    string
+	ShareMode  constant.DataSetShareMode
+	Recycle    bool
+	StopThread bool
+	Size       int
+	Content    []byte
+
+	LoadTestExecutionInfoId uint
+}
+
+// LoadTestExecutionTarget is a non-HTTP target as a run sent it. Like an uploaded file, its
+// .proto is kept so the run can be made again.
+type LoadTestExecutionTarget struct {
+	gorm.Model
+	Protocol constant.TargetProtocol
+	Hostname string
+	Port     string
+	Timeout  int
+
+	Path    string
+	Secure  bool
+	Message string
+
+	Method        string
+	ProtoFileName string
+	ProtoFile     []byte
+
+	EolByte *int
+
+	Driver   constant.JdbcDriver
+	Database string
+	Username string
+	Password string
+	Query    string
+
+	LoadTestExecutionInfoId uint
+}
+
+// LoadTestExecutionRegion is a region a run sent load from. Part is the number of the result
+// file its generator left; VirtualUsers is its share of the run's users.
+type LoadTestExecutionRegion struct {
+	gorm.Model
+	Provider     string
+	Region       string
+	Weight       int
+	VirtualUsers string
+	Part         int
+
+	LoadTestExecutionInfoId uint
+}
+
 type LoadTestExecutionHttpInfo struct {
 	gorm.Model
 	Method   string
@@ -168,10 +444,69 @@ type LoadTestExecutionHttpInfo struct {
 	Port     string
 	Path     string
 	BodyData string
+	BodyType constant.HttpBodyType
+
+	// Fields holds the request's headers, explicit query params and form params, told apart
+	// by Kind and kept in the order they were given.
+	Fields []LoadTestExecutionHttpField
+	Files  []LoadTestExecutionHttpFile
+
+	// Extractors are stored with the request they belong to so a re-run renders the same
+	// chained flow rather than a list of unrelated requests.
+	Extractors []LoadTestExecutionHttpExtractor
+	Assertions []LoadTestExecutionHttpAssertion
+
+	Weight    int
+	ThinkTime LoadTestThinkTime `gorm:"embedded;embeddedPrefix:think_time_"`
+
+	// HttpClient are the options this request was sent with: its own over the run's.
+	HttpClient LoadTestHttpClient `gorm:"embedded;embeddedPrefix:http_client_"`
 
 	LoadTestExecutionInfoId uint
 }
 
+type LoadTestExecutionHttpField struct {
+	gorm.Model
+	Kind  constant.HttpFieldKind
+	Name  string
+	Value string
+
+	LoadTestExecutionHttpInfoId uint
+}
+
+// LoadTestExecutionHttpFile keeps the content of an uploaded file so a re-run can send it
+// again. Size is stored separately so listings can describe the file without loading it.
+type LoadTestExecutionHttpFile struct {
+	gorm.Model
+	ParamName string
+	FileName  string
+	MimeType  string
+	Size      int
+	Content   []byte
+
+	LoadTestExecutionHttpInfoId uint
+}
+
+type LoadTestExecutionHttpExtractor struct {
+	gorm.Model
+	Type         constant.ExtractorType
+	VarName      string
+	Expression   string
+	MatchNo      int
+	DefaultValue string
+
+	LoadTestExecutionHttpInfoId uint
+}
+
+type LoadTestExecutionHttpAssertion struct {
+	gorm.Model
+	Type       constant.AssertionType
+	Expression string
+	Value      string
+
+	LoadTestExecutionHttpInfoId uint
+}
+
 type LoadTestScenarioCatalog struct {
 	gorm.Model
 	Name         string `gorm:"not null;index:idx_scenario_catalog_name"`
@@ -180,4 +515,185 @@ type LoadTestScenarioCatalog struct {
 	Duration     string `gorm:"not null"`
 	RampUpTime   string `gorm:"not null"`
 	RampUpSteps  string `gorm:"not null"`
+
+	LoadShape     constant.LoadShape `gorm:"default:concurrency"`
+	ArrivalRate   string
+	SpikeUsers    string
+	SpikeDuration string
+	Stages        []LoadTestScenarioCatalogStage `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	ThinkTime LoadTestThinkTime `gorm:"embedded;embeddedPrefix:think_time_"`
+}
+
+type LoadTestScenarioCatalogStage struct {
+	gorm.Model
+	Target   int
+	Duration int
+
+	LoadTestScenarioCatalogId uint
+}
+
+// LoadTestDataSet is a CSV file kept for runs to read. Columns and Rows are worked out once, on
+// upload, so listing data sets never reads their content.
+type LoadTestDataSet struct {
+	gorm.Model
+	Name        string `gorm:"not null;index:idx_data_set_name"`
+	Description string
+	FileName    string
+	Columns     string
+	Rows        int
+	Size        int
+	Content     []byte
+}
+
+// LoadTestSchedule starts a run of a scenario catalog against a target at the times of a cron
+// expression, or once at RunAt. Run is the rest of the run as it was accepted, as JSON; the
+// catalog's load profile is laid over it every time the schedule fires, so a changed catalog
+// applies to the next run. NextRunAt is nil once a schedule has nothing left to fire.
+type LoadTestSchedule struct {
+	gorm.Model
+	Name        string `gorm:"not null;index:idx_schedule_name"`
+	Description string
+	Cron        string
+	RunAt       *time.Time
+	TimeZone    string `gorm:"default:UTC"`
+	Enabled     bool
+
+	ScenarioCatalogId uint
+	NsId              string
+	InfraId           string
+	NodeId            string
+	Run               []byte
+
+	NextRunAt *time.Time `gorm:"index:idx_schedule_next_run_at"`
+	LastRunAt *time.Time
+}
+
+// LoadTestBaseline pins a run as the reference the runs of a scenario catalog entry, or of a
+// target node, are compared with. It has either ScenarioCatalogId or the node, and there is
+// one row at most for each, which a deleted baseline keeps until the scope is pinned again.
+type LoadTestBaseline struct {
+	gorm.Model
+	ScenarioCatalogId uint   `gorm:"uniqueIndex:idx_baseline_scope"`
+	NsId              string `gorm:"uniqueIndex:idx_baseline_scope"`
+	InfraId           string `gorm:"uniqueIndex:idx_baseline_scope"`
+	NodeId            string `gorm:"uniqueIndex:idx_baseline_scope"`
+	LoadTestKey       string
+}
+
+// LoadTestScheduleRun is a time a schedule fired. LoadTestKey is the run it started; a firing
+// that could not start one says why in Message.
+type LoadTestScheduleRun struct {
+	gorm.Model
+	ScheduledAt time.Time
+	Status      constant.ScheduleRunStatus
+	LoadTestKey string
+	Message     string
+
+	LoadTestScheduleId uint `gorm:"index:idx_schedule_run_schedule_id"`
+}
+
+// LoadTestQueuedRun is a run waiting for the concurrency limits to let it start. Its state is
+// queued until then. Run is the accepted run as JSON, data sets already read, so it starts as
+// it was asked for even if the server restarted in between. Once the run starts, StartedAt is
+// set and the row holds its generator and target until it ends, for every server sharing the
+// database to count. OwnerId is the server driving the started run, which moves HeartbeatAt
+// on while it does; no other server takes the run up until the heartbeat has expired.
+type LoadTestQueuedRun struct {
+	gorm.Model
+	LoadTestKey  string `gorm:"index:idx_queued_run_load_test_key,unique"`
+	GeneratorKey string
+	TargetKey    string
+	Run          []byte
+	StartedAt    *time.Time `gorm:"index"`
+	OwnerId      string
+	HeartbeatAt  *time.Time
+}
+
+// LoadTestResult is the result of a run as it was collected, kept in the database so it is
+// served without the raw result files. It is written once, when the files are in.
+type LoadTestResult struct {
+	gorm.Model
+	LoadTestKey string `gorm:"index:idx_result_load_test_key,unique"`
+	WithMetrics bool
+
+	Statistics []LoadTestResultStatistic
+	Samples    []LoadTestResultSample
+	Buckets    []LoadTestResultBucket
+	Metrics    []LoadTestResultMetric
+}
+
+// LoadTestResultStatistic is the aggregate of one label of a run, or of the part of it sent
+// from one region. Region is empty for the run as a whole, which also has a Total row taking
+// all of its labels together; the counts and the span let it be compared once the raw result
+// is discarded.
+type LoadTestResultStatistic struct {
+	gorm.Model
+	LoadTestResultId uint `gorm:"index"`
+	Provider         string
+	Region           string
+	Label            string
+	RequestCount     int
+	Average          float64
+	Median           float64
+	NinetyPercent    float64
+	NinetyFive       float64
+	NinetyNine       float64
+	MinTime          float64
+	MaxTime          float64
+	ErrorPercent     float64
+	Throughput       float64
+	ReceivedKB       float64
+	SentKB           float64
+	ErrorCount       int
+	SpanSeconds      float64 // from the first request to the last
+	Total            bool
+}
+
+// LoadTestResultSample is the sample standing for one label of a run in one time bucket.
+type LoadTestResultSample struct {
+	gorm.Model
+	LoadTestResultId uint `gorm:"index"`
+	Label            string
+	No               int
+	Elapsed          int
+	Bytes            int
+	SentBytes        int
+	URL              string
+	Latency          int
+	IdleTime         int
+	Connection       int
+	IsError          bool
+	Timestamp        time.Time
+	ActiveThreads    int
+}
+
+// LoadTestResultBucket adds up the requests of one label of a run within one time bucket, so
+// its time series can be drawn once the raw result is discarded. Elapsed is the encoded
+// histogram of their response times.
+type LoadTestResultBucket struct {
+	gorm.Model
+	LoadTestResultId uint `gorm:"index"`
+	Label            string
+	At               time.Time
+	Requests         int
+	Errors           int
+	TotalElapsed     int
+	MinElapsed       int
+	MaxElapsed       int
+	Bytes            int
+	SentBytes        int
+	ActiveThreads    int
+	Elapsed          []byte
+}
+
+// LoadTestResultMetric is one reading of the target's metrics during a run.
+type LoadTestResultMetric struct {
+	gorm.Model
+	LoadTestResultId uint `gorm:"index"`
+	Label            string
+	Value            string
+	Unit             string
+	IsError          bool
+	Timestamp        time.Time
 }
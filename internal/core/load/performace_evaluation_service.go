@@ -69,6 +69,7 @@ func (l *LoadService) GetAllLoadTestExecutionState(param GetAllLoadTestExecution
 		// state.LoadGeneratorInstallInfo = mapLoadGeneratorInstallInfoResult(loadTestExecutionState.LoadGeneratorInstallInfo)
 		states = append(states, state)
 	}
+	l.withQueuePositions(ctx, states)
 
 	res.LoadTestExecutionStates = states
 	res.TotalRow = totalRows
@@ -91,7 +92,9 @@ func (l *LoadService) GetLoadTestExecutionState(param GetLoadTestExecutionStateP
 
 	res = mapLoadTestExecutionStateResult(state)
 	// res.LoadGeneratorInstallInfo = mapLoadGeneratorInstallInfoResult(state.LoadGeneratorInstallInfo)
-	return res, nil
+	states := []LoadTestExecutionStateResult{res}
+	l.withQueuePositions(ctx, states)
+	return states[0], nil
 }
 
 func (l *LoadService) GetAllLoadTestExecutionInfos(param GetAllLoadTestExecutionInfosParam) (GetAllLoadTestExecutionInfosResult, error) {
@@ -137,14 +140,70 @@ func (l *LoadService) GetLoadTestExecutionInfo(param GetLoadTestExecutionInfoPar
 }
 
 func mapLoadTestExecutionHttpInfoResult(h LoadTestExecutionHttpInfo) LoadTestExecutionHttpInfoResult {
+	var extractors []LoadTestExecutionHttpExtractorResult
+	for _, e := range h.Extractors {
+		extractors = append(extractors, LoadTestExecutionHttpExtractorResult{
+			ID:           e.ID,
+			Type:         e.Type,
+			VarName:      e.VarName,
+			Expression:   e.Expression,
+			MatchNo:      e.MatchNo,
+			DefaultValue: e.DefaultValue,
+		})
+	}
+
+	var assertions []LoadTestExecutionHttpAssertionResult
+	for _, a := range h.Assertions {
+		assertions = append(assertions, LoadTestExecutionHttpAssertionResult{
+			ID:         a.ID,
+			Type:       a.Type,
+			Expression: a.Expression,
+			Value:      a.Value,
+		})
+	}
+
+	var headers, queryParams, formParams []RunLoadTestNameValueParam
+	for _, f := range h.Fields {
+		nv := RunLoadTestNameValueParam{Name: f.Name, Value: f.Value}
+		switch f.Kind {
+		case constant.HeaderField:
+			headers = append(headers, nv)
+		case constant.QueryField:
+			queryParams = append(queryParams, nv)
+		case constant.FormField:
+			formParams = append(formParams, nv)
+		}
+	}
+
+	var files []LoadTestExecutionHttpFileResult
+	for _, f := range h.Files {
+		files = append(files, LoadTestExecutionHttpFileResult{
+			ID:        f.ID,
+			ParamName: f.ParamName,
+			FileName:  f.FileName,
+			MimeType:  f.MimeType,
+			Size:      f.Size,
+		})
+	}
+
 	return LoadTestExecutionHttpInfoResult{
-		ID:       h.ID,
-		Method:   h.Method,
-		Protocol: h.Protocol,
-		Hostname: h.Hostname,
-		Port:     h.Port,
-		Path:     h.Path,
-		BodyData: h.BodyData,
+		ID:          h.ID,
+		Method:      h.Method,
+		Protocol:    h.Protocol,
+		Hostname:    h.Hostname,
+		Port:        h.Port,
+		Path:        h.Path,
+		BodyData:    h.BodyData,
+		Headers:     headers,
+		QueryParams: queryParams,
+		BodyType:    h.BodyType,
+		FormParams:  formParams,
+		Files:       files,
+		Extractors:  extractors,
+		Weight:      h.Weight,
+		ThinkTime:   h.ThinkTime.param(),
+		Assertions:  assertions,
+		HttpClient:  h.HttpClient.param(),
 	}
 }
 
@@ -152,6 +211,7 @@ func mapLoadTestExecutionStateResult(state LoadTestExecutionState) LoadTestExecu
 	stateResult := &LoadTestExecutionStateResult{
 		ID:                          state.ID,
 		LoadTestKey:                 state.LoadTestKey,
+		ParentLoadTestKey:           state.ParentLoadTestKey,
 		NodeUid:                     state.NodeUid,
 		ExecutionStatus:             state.ExecutionStatus,
 		StartAt:                     state.StartAt,
@@ -161,8 +221,13 @@ func mapLoadTestExecutionStateResult(state LoadTestExecutionState) LoadTestExecu
 		FailureMessage:              state.FailureMessage,
 		CompileDuration:             state.CompileDuration,
 		ExecutionDuration:           state.ExecutionDuration,
+		Engine:                      state.Engine,
 		CreatedAt:                   state.CreatedAt,
 		UpdatedAt:                   state.UpdatedAt,
+		ScenarioCatalogId:           state.ScenarioCatalogId,
+		BaselineLoadTestKey:         state.BaselineLoadTestKey,
+		BaselineVerdict:             state.BaselineVerdict,
+		BaselineMessage:             state.BaselineMessage,
 	}
 
 	if stateResult.ExecutionStatus == constant.Successed {
@@ -177,6 +242,16 @@ func mapLoadTestExecutionStateResult(state LoadTestExecutionState) LoadTestExecu
 	// phase and stamps each one with how long it has taken.
 	stateResult.Steps = buildStepTree(state.Steps, time.Now())
 
+	stateResult.Verdict = state.Verdict
+	for _, v := range state.SlaViolations {
+		stateResult.SlaViolations = append(stateResult.SlaViolations, LoadTestExecutionSlaViolationResult{
+			Criterion: v.Criterion,
+			Threshold: v.Threshold,
+			Actual:    v.Actual,
+			Message:   v.Message,
+		})
+	}
+
 	return *stateResult
 
 }
@@ -200,6 +275,8 @@ func mapLoadGeneratorServerResult(s LoadGeneratorServer) LoadGeneratorServerResu
 		StartTime:       s.StartTime,
 		AdditionalVmKey: s.AdditionalVmKey,
 		Label:           s.Label,
+		IsCluster:       s.IsCluster,
+		IsMaster:        s.IsMaster,
 		CreatedAt:       s.CreatedAt,
 	}
 }
@@ -217,6 +294,9 @@ func mapLoadGeneratorInstallInfoResult(install LoadGeneratorInstallInfo) LoadGen
 		InstallPath:          install.InstallPath,
 		InstallVersion:       install.InstallVersion,
 		Status:               install.Status,
+		IsCluster:            install.IsCluster,
+		MasterId:             install.MasterId,
+		ClusterSize:          install.ClusterSize,
 		CreatedAt:            install.CreatedAt,
 		UpdatedAt:            install.UpdatedAt,
 		PublicKeyName:        install.PublicKeyName,
@@ -231,6 +311,67 @@ func mapLoadTestExecutionInfoResult(executionInfo LoadTestExecutionInfo) LoadTes
 		httpResults = append(httpResults, mapLoadTestExecutionHttpInfoResult(h))
 	}
 
+	var uploadedFiles []LoadTestExecutionUploadedFileResult
+	for _, f := range executionInfo.UploadedFiles {
+		uploadedFiles = append(uploadedFiles, LoadTestExecutionUploadedFileResult{
+			ID:         f.ID,
+			FileName:   f.FileName,
+			Size:       f.Size,
+			IsTestPlan: f.IsTestPlan,
+		})
+	}
+
+	var dataSets []LoadTestExecutionDataSetResult
+	for _, ds := range executionInfo.DataSets {
+		dataSets = append(dataSets, LoadTestExecutionDataSetResult{
+			ID:         ds.ID,
+			DataSetId:  ds.DataSetId,
+			FileName:   ds.FileName,
+			Columns:    dataSetColumnsOf(ds.Columns),
+			Size:       ds.Size,
+			ShareMode:  ds.ShareMode,
+			Recycle:    ds.Recycle,
+			StopThread: ds.StopThread,
+		})
+	}
+
+	var targetResults []LoadTestExecutionTargetResult
+	for _, t := range executionInfo.Targets {
+		targetResults = append(targetResults, LoadTestExecutionTargetResult{
+			ID:            t.ID,
+			Protocol:      t.Protocol,
+			Hostname:      t.Hostname,
+			Port:          t.Port,
+			Timeout:       t.Timeout,
+			Path:          t.Path,
+			Secure:        t.Secure,
+			Message:       t.Message,
+			Method:        t.Method,
+			ProtoFileName: t.ProtoFileName,
+			EolByte:       t.EolByte,
+			Driver:        t.Driver,
+			Database:      t.Database,
+			Username:      t.Username,
+			Query:         t.Query,
+		})
+	}
+
+	var regionResults []LoadTestExecutionRegionResult
+	for _, r := range executionInfo.Regions {
+		regionResults = append(regionResults, LoadTestExecutionRegionResult{
+			ID:           r.ID,
+			Provider:     r.Provider,
+			Region:       r.Region,
+			Weight:       r.Weight,
+			VirtualUsers: r.VirtualUsers,
+		})
+	}
+
+	var stages []RunLoadTestStageParam
+	for _, st := range executionInfo.LoadStages {
+		stages = append(stages, RunLoadTestStageParam{Target: st.Target, Duration: st.Duration})
+	}
+
 	executionState := mapLoadTestExecutionStateResult(executionInfo.LoadTestExecutionState)
 	installInfo := mapLoadGeneratorInstallInfoResult(executionInfo.LoadGeneratorInstallInfo)
 
@@ -242,11 +383,25 @@ func mapLoadTestExecutionInfoResult(executionInfo LoadTestExecutionInfo) LoadTes
 		Duration:                   executionInfo.Duration,
 		RampUpTime:                 executionInfo.RampUpTime,
 		RampUpSteps:                executionInfo.RampUpSteps,
+		LoadShape:                  executionInfo.LoadShape,
+		ArrivalRate:                executionInfo.ArrivalRate,
+		SpikeUsers:                 executionInfo.SpikeUsers,
+		SpikeDuration:              executionInfo.SpikeDuration,
+		Stages:                     stages,
+		ThinkTime:                  executionInfo.ThinkTime.param(),
+		Engine:                     executionInfo.Engine,
 		AgentHostname:              executionInfo.AgentHostname,
 		AgentInstalled:             executionInfo.AgentInstalled,
 		CompileDuration:            executionInfo.CompileDuration,
 		ExecutionDuration:          executionInfo.ExecutionDuration,
 		LoadTestExecutionHttpInfos: httpResults,
+		UploadedFiles:              uploadedFiles,
+		DataSets:                   dataSets,
+		Sla:                        executionInfo.Sla.param(),
+		AutoStop:                   executionInfo.AutoStop.param(),
+		HttpClient:                 executionInfo.HttpClient.param(),
+		Targets:                    targetResults,
+		Regions:                    regionResults,
 		LoadTestExecutionState:     executionState,
 		LoadGeneratorInstallInfo:   installInfo,
 	}
@@ -1,10 +1,14 @@
 package load
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -71,51 +75,91 @@ var tags = map[string]metricsUnits{
 	},
 }
 
+// resultEngineOf returns the engine whose result file a run left behind. A run that cannot be
+// looked up is read as JMeter's, which is what every run was before there was a choice.
+func (l *LoadService) resultEngineOf(ctx context.Context, loadTestKey string) loadEngine {
+	engine, _ := engineFor(constant.Jmeter)
+	state, err := l.loadRepo.GetLoadTestExecutionStateTx(ctx, GetLoadTestExecutionStateParam{LoadTestKey: loadTestKey})
+	if err != nil {
+		log.Warn().Msgf("could not look up the engine of %s, reading its result as %s; %v", loadTestKey, constant.Jmeter, err)
+		return engine
+	}
+	if e, err := engineFor(state.Engine); err == nil {
+		engine = e
+	}
+	return engine
+}
+
 func (l *LoadService) GetLoadTestResult(param GetLoadTestResultParam) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
 	loadTestKey := param.LoadTestKey
+	if param.Format == constant.TimeSeries {
+		return l.timeSeriesOf(ctx, l.resultEngineOf(ctx, loadTestKey), loadTestKey, param.Window)
+	}
+	if stored, found := l.storedResultOf(ctx, loadTestKey, param.Format); found {
+		return stored, nil
+	}
+
 	fileName := fmt.Sprintf("%s_result.csv", loadTestKey)
 	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
 	toFilePath := fmt.Sprintf("%s/%s", resultFolderPath, fileName)
-	resultMap, err := appendResultRawData(toFilePath)
+	return resultFormat(param.Format, l.resultEngineOf(ctx, loadTestKey), toFilePath)
+}
+
+// GetLoadTestRegionResult returns the statistics of a run from several regions, one region at
+// a time, in the order the regions were given. A run from one region is read from its whole
+// result; a run from none has no breakdown.
+func (l *LoadService) GetLoadTestRegionResult(param GetLoadTestResultParam) ([]LoadTestRegionResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	loadTestKey := param.LoadTestKey
+	executionInfo, err := l.loadRepo.GetLoadTestExecutionInfoTx(ctx, GetLoadTestExecutionInfoParam{LoadTestKey: loadTestKey})
 	if err != nil {
 		return nil, err
 	}
-
-	var resultSummaries []*ResultSummary
-
-	for label, results := range resultMap {
-		resultSummaries = append(resultSummaries, &ResultSummary{
-			Label:   label,
-			Results: results,
-		})
+	if regionResults, found := l.storedRegionResultOf(ctx, loadTestKey, executionInfo.Regions); found {
+		return regionResults, nil
 	}
 
-	formattedDate, err := resultFormat(param.Format, resultSummaries)
+	engine := l.resultEngineOf(ctx, loadTestKey)
+	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
+	regionResults := make([]LoadTestRegionResult, 0, len(executionInfo.Regions))
+	for _, r := range executionInfo.Regions {
+		// one server leaves the result whole, with no parts to merge
+		toFilePath := fmt.Sprintf("%s/%s_result.csv", resultFolderPath, loadTestKey)
+		if len(executionInfo.Regions) > 1 {
+			toFilePath = fmt.Sprintf("%s/%s", resultFolderPath, resultPartFileName(loadTestKey, r.Part))
+		}
+		digest, err := digestResult(engine, toFilePath, 0)
+		if err != nil {
+			return nil, fmt.Errorf("reading the result of %s; %w", regionKey(r.Provider, r.Region), err)
+		}
 
-	if err != nil {
-		return nil, err
+		regionResults = append(regionResults, LoadTestRegionResult{
+			Provider:     r.Provider,
+			Region:       r.Region,
+			Weight:       r.Weight,
+			VirtualUsers: r.VirtualUsers,
+			Statistics:   digest.statistics(),
+		})
 	}
-
-	return formattedDate, nil
+	return regionResults, nil
 }
 
 func (l *LoadService) GetLoadTestMetrics(param GetLoadTestResultParam) ([]MetricsSummary, error) {
-	loadTestKey := param.LoadTestKey
-	metrics := []string{"cpu", "disk", "memory", "network"}
-	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
-
-	metricsMap := make(map[string][]*MetricsRawData)
-	var err error
-	for _, v := range metrics {
-
-		fileName := fmt.Sprintf("%s_%s_result.csv", loadTestKey, v)
-		toPath := fmt.Sprintf("%s/%s", resultFolderPath, fileName)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-		metricsMap, err = appendMetricsRawData(metricsMap, toPath)
-		if err != nil {
-			return nil, err
-		}
+	if stored, found := l.storedMetricsOf(ctx, param.LoadTestKey); found {
+		return stored, nil
+	}
 
+	metricsMap, err := readLoadTestMetrics(param.LoadTestKey)
+	if err != nil {
+		return nil, err
 	}
 
 	var metricsSummaries []MetricsSummary
@@ -151,30 +195,23 @@ func (l *LoadService) GetLastLoadTestResult(param GetLastLoadTestResultParam) (i
 	}
 
 	loadTestKey := state.LoadTestKey
+	if param.Format != constant.TimeSeries {
+		if stored, found := l.storedResultOf(ctx, loadTestKey, param.Format); found {
+			return stored, nil
+		}
+	}
+
 	fileName := fmt.Sprintf("%s_result.csv", loadTestKey)
 	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
 	toFilePath := fmt.Sprintf("%s/%s", resultFolderPath, fileName)
-	resultMap, err := appendResultRawData(toFilePath)
+	engine, err := engineFor(state.Engine)
 	if err != nil {
 		return nil, err
 	}
-
-	var resultSummaries []*ResultSummary
-
-	for label, results := range resultMap {
-		resultSummaries = append(resultSummaries, &ResultSummary{
-			Label:   label,
-			Results: results,
-		})
+	if param.Format == constant.TimeSeries {
+		return l.timeSeriesOf(ctx, engine, loadTestKey, param.Window)
 	}
-
-	formattedDate, err := resultFormat(param.Format, resultSummaries)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return formattedDate, nil
+	return resultFormat(param.Format, engine, toFilePath)
 }
 
 func (l *LoadService) GetLastLoadTestMetrics(param GetLastLoadTestResultParam) ([]MetricsSummary, error) {
@@ -200,6 +237,9 @@ func (l *LoadService) GetLastLoadTestMetrics(param GetLastLoadTestResultParam) (
 		log.Error().Msgf("%s does not contain metrics collection", loadTestKey)
 		return nil, errors.New("metrics does not collected while performance evaluation")
 	}
+	if stored, found := l.storedMetricsOf(ctx, loadTestKey); found {
+		return stored, nil
+	}
 
 	metrics := []string{"cpu", "disk", "memory", "network"}
 	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
@@ -233,34 +273,32 @@ func (l *LoadService) GetLastLoadTestMetrics(param GetLastLoadTestResultParam) (
 
 	return metricsSummaries, nil
 }
-func calculatePercentile(elapsedList []int, percentile float64) float64 {
-	index := int(math.Ceil(float64(len(elapsedList))*percentile)) - 1
 
-	return float64(elapsedList[index])
-}
+// readLoadTestMetrics reads the target's metric files of a run into readings by label.
+func readLoadTestMetrics(loadTestKey string) (map[string][]*MetricsRawData, error) {
+	metrics := []string{"cpu", "disk", "memory", "network"}
+	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
 
-func calculateMedian(data []int) float64 {
-	n := len(data)
-	if n%2 == 0 {
-		return float64(data[n/2-1]+data[n/2]) / 2
-	}
-	return float64(data[n/2])
-}
+	metricsMap := make(map[string][]*MetricsRawData)
+	var err error
+	for _, v := range metrics {
 
-func findMin(elapsedList []int) float64 {
-	if len(elapsedList) == 0 {
-		return 0
-	}
+		fileName := fmt.Sprintf("%s_%s_result.csv", loadTestKey, v)
+		toPath := fmt.Sprintf("%s/%s", resultFolderPath, fileName)
 
-	return float64(elapsedList[0])
-}
+		metricsMap, err = appendMetricsRawData(metricsMap, toPath)
+		if err != nil {
+			return nil, err
+		}
 
-func findMax(elapsedList []int) float64 {
-	if len(elapsedList) == 0 {
-		return 0
 	}
+	return metricsMap, nil
+}
 
-	return float64(elapsedList[len(elapsedList)-1])
+func calculatePercentile(elapsedList []int, percentile float64) float64 {
+	index := int(math.Ceil(float64(len(elapsedList))*percentile)) - 1
+
+	return float64(elapsedList[index])
 }
 
 func calculateErrorPercent(errorCount, requestCount int) float64 {
@@ -283,19 +321,47 @@ func calculateSentKBPerSec(totalBytes int, totalMillTime int) float64 {
 	return (float64(totalBytes) / 1024) / (float64(totalMillTime)) * 1000
 }
 
-func appendResultRawData(filePath string) (map[string][]*ResultRawData, error) {
-	var resultMap = make(map[string][]*ResultRawData)
-
-	csvRows, err := utils.ReadCSV(filePath)
-	if err != nil || csvRows == nil {
-		return nil, err
+// scanResultRawData reads a JMeter result file a row at a time.
+func scanResultRawData(filePath string, fn func(label string, r *ResultRawData)) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Error().Msgf("can not open file: %s; %s", filePath, err)
+		return err
 	}
+	defer file.Close()
+
+	return scanResultRows(file, filePath, fn)
+}
 
-	if len(*csvRows) <= 1 {
-		return nil, errors.New("result data file is empty")
+// scanResultRows reads JMeter result rows, the header first, from r; source names it in the log.
+func scanResultRows(r io.Reader, source string, fn func(label string, r *ResultRawData)) error {
+	reader := csv.NewReader(bufio.NewReaderSize(r, 1<<20))
+	reader.ReuseRecord = true
+
+	// the header
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return errors.New("result data file is empty")
+		}
+		return err
 	}
+
 	// every time is basically millisecond
-	for i, row := range (*csvRows)[1:] {
+	i := 0
+	for ; ; i++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to read CSV file from path %s; %v", source, err)
+			return err
+		}
+		if len(row) < 17 {
+			log.Printf("[%d] row has %d columns\n", i, len(row))
+			continue
+		}
+
 		label := row[2]
 
 		elapsed, err := strconv.Atoi(row[1])
@@ -334,11 +400,14 @@ func appendResultRawData(filePath string) (map[string][]*ResultRawData, error) {
 			continue
 		}
 
+		// allThreads is told by every JMeter version, but is not worth a row when it is not
+		activeThreads, _ := strconv.Atoi(row[12])
+
 		isError := row[7] == "false"
 		url := row[13]
 		t := time.UnixMilli(unixMilliseconds)
 
-		tr := &ResultRawData{
+		fn(label, &ResultRawData{
 			No:         i,
 			Elapsed:    elapsed,
 			Bytes:      bytes,
@@ -349,254 +418,143 @@ func appendResultRawData(filePath string) (map[string][]*ResultRawData, error) {
 			IdleTime:   idleTime,
 			Connection: connection,
 			Timestamp:  t,
-		}
-		if _, ok := resultMap[label]; !ok {
-			resultMap[label] = []*ResultRawData{tr}
-		} else {
-			resultMap[label] = append(resultMap[label], tr)
-		}
-	}
-
-	return resultMap, nil
-}
 
-func SampleDataByTimeInterval(data []*ResultRawData, intervalMs int) []*ResultRawData {
-	if len(data) == 0 {
-		return nil
-	}
-
-	sort.Slice(data, func(i, j int) bool {
-		return data[i].Timestamp.Before(data[j].Timestamp)
-	})
-
-	var sampledData []*ResultRawData
-	startTime := data[0].Timestamp
-	var currentInterval []*ResultRawData
-
-	for _, record := range data {
-		elapsedTime := record.Timestamp.Sub(startTime).Milliseconds()
-		if elapsedTime < int64(intervalMs) {
-			currentInterval = append(currentInterval, record)
-		} else {
-			if len(currentInterval) > 0 {
-				sampledData = append(sampledData, calculateRepresentative(currentInterval))
-			}
-			startTime = record.Timestamp
-			currentInterval = []*ResultRawData{record}
-		}
+			ActiveThreads: activeThreads,
+		})
 	}
 
-	if len(currentInterval) > 0 {
-		sampledData = append(sampledData, calculateRepresentative(currentInterval))
+	if i == 0 {
+		return errors.New("result data file is empty")
 	}
-
-	return sampledData
+	return nil
 }
 
-func calculateRepresentative(data []*ResultRawData) *ResultRawData {
-	if len(data) == 0 {
-		return nil
-	}
-
-	var totalElapsed int
-	for _, record := range data {
-		totalElapsed += record.Elapsed
+func appendMetricsRawData(mrds map[string][]*MetricsRawData, filePath string) (map[string][]*MetricsRawData, error) {
+	err := scanMetricsRawData(filePath, func(label string, rd *MetricsRawData) {
+		mrds[label] = append(mrds[label], rd)
+	})
+	if err != nil {
+		return nil, err
 	}
-	avgElapsed := totalElapsed / len(data)
 
-	// Find the record closest to the average elapsed time
-	var closestRecord *ResultRawData
-	minDiff := int(^uint(0) >> 1) // Max int value
-	for _, record := range data {
-		diff := abs(record.Elapsed - avgElapsed)
-		if diff < minDiff {
-			minDiff = diff
-			closestRecord = record
+	for _, vals := range mrds {
+		if len(vals) > 0 {
+			sort.Slice(vals, func(i, j int) bool {
+				return vals[i].Timestamp.Before(vals[j].Timestamp)
+			})
 		}
 	}
 
-	return closestRecord
+	return mrds, nil
 }
 
-func abs(a int) int {
-	if a < 0 {
-		return -a
+// scanMetricsRawData reads a metrics file a row at a time.
+func scanMetricsRawData(filePath string, fn func(label string, rd *MetricsRawData)) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Error().Msgf("can not open file: %s; %s", filePath, err)
+		return err
 	}
-	return a
-}
+	defer file.Close()
 
-func appendMetricsRawData(mrds map[string][]*MetricsRawData, filePath string) (map[string][]*MetricsRawData, error) {
-	csvRows, err := utils.ReadCSV(filePath)
-	if err != nil || csvRows == nil {
-		return nil, err
-	}
+	return scanMetricsRows(file, filePath, fn)
+}
 
-	if len(*csvRows) <= 1 {
-		return nil, errors.New("metrics data file is empty")
-	}
+// scanMetricsRows reads metrics rows, the header first, from r; source names it in the log.
+func scanMetricsRows(r io.Reader, source string, fn func(label string, rd *MetricsRawData)) error {
+	reader := csv.NewReader(bufio.NewReaderSize(r, 1<<20))
+	reader.ReuseRecord = true
 
-	for i, row := range (*csvRows)[1:] {
-		isError := row[7] == "false"
-		intValue, err := strconv.Atoi(row[1])
-		if err != nil {
-			log.Printf("[%d] value has error %s\n", i, err)
-			continue
+	// the header
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return errors.New("metrics data file is empty")
 		}
+		return err
+	}
 
-		var label string
-		var value string
-		var u string
-
-		if isError {
-			label = row[2]
-		} else {
-			words := strings.Split(row[2], " ")
-			label = words[len(words)-1]
-
-			unit, ok := tags[label]
-			if !ok {
-				continue
-			}
-
-			floatValue := float64(intValue) * unit.Multiple
-			value = strconv.FormatFloat(floatValue, 'f', 3, 64)
-			u = unit.Unit
+	i := 0
+	for ; ; i++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
 		}
-
-		unixMilliseconds, err := strconv.ParseInt(row[0], 10, 64)
 		if err != nil {
-			log.Printf("[%d] time has error %s\n", i, err)
-			continue
-		}
-
-		t := time.UnixMilli(unixMilliseconds)
-
-		rd := &MetricsRawData{
-			Value:     value,
-			Unit:      u,
-			IsError:   isError,
-			Timestamp: t,
+			log.Printf("Failed to read CSV file from path %s; %v", source, err)
+			return err
 		}
-
-		if _, ok := mrds[label]; !ok {
-			mrds[label] = []*MetricsRawData{rd}
-		} else {
-			mrds[label] = append(mrds[label], rd)
+		if label, rd, ok := metricsRawDataOf(i, row); ok {
+			fn(label, rd)
 		}
 	}
 
-	for _, vals := range mrds {
-		if len(vals) > 0 {
-			sort.Slice(vals, func(i, j int) bool {
-				return vals[i].Timestamp.Before(vals[j].Timestamp)
-			})
-		}
+	if i == 0 {
+		return errors.New("metrics data file is empty")
 	}
-
-	return mrds, nil
+	return nil
 }
 
-func aggregate(resultRawDatas []*ResultSummary) []*LoadTestStatistics {
-	var statistics []*LoadTestStatistics
-
-	for i := range resultRawDatas {
-
-		record := resultRawDatas[i]
-
-		if record == nil {
-			log.Warn().Msgf("record must not be nil; %d", i)
-			continue
-		}
+// metricsRawDataOf reads row i of a metrics file into its label and reading; false for a row
+// that is not one.
+func metricsRawDataOf(i int, row []string) (string, *MetricsRawData, bool) {
+	if len(row) < 8 {
+		return "", nil, false
+	}
+	isError := row[7] == "false"
+	intValue, err := strconv.Atoi(row[1])
+	if err != nil {
+		log.Printf("[%d] value has error %s\n", i, err)
+		return "", nil, false
+	}
 
-		var requestCount, totalElapsed, totalBytes, totalSentBytes, errorCount int
-		var elapsedList []int
-		if len(record.Results) < 1 {
-			continue
-		}
+	var label string
+	var value string
+	var u string
 
-		startTime := record.Results[0].Timestamp
-		endTime := record.Results[0].Timestamp
-		for _, record := range record.Results {
-			requestCount++
-			if !record.IsError {
-				totalElapsed += record.Elapsed
-			}
-
-			totalBytes += record.Bytes
-			totalSentBytes += record.SentBytes
-
-			if record.IsError {
-				errorCount++
-			}
-
-			if record.Timestamp.Before(startTime) {
-				startTime = record.Timestamp
-			}
-			if record.Timestamp.After(endTime) {
-				endTime = record.Timestamp
-			}
-
-			elapsedList = append(elapsedList, record.Elapsed)
-		}
+	if isError {
+		label = row[2]
+	} else {
+		words := strings.Split(row[2], " ")
+		label = words[len(words)-1]
 
-		// total Elapsed time and running time is different
-		runningTime := endTime.Sub(startTime).Milliseconds()
-
-		// for percentile calculation purpose
-		sort.Ints(elapsedList)
-
-		average := float64(totalElapsed) / float64(requestCount)
-		median := calculateMedian(elapsedList)
-		ninetyPercent := calculatePercentile(elapsedList, 0.9)
-		ninetyFive := calculatePercentile(elapsedList, 0.95)
-		ninetyNine := calculatePercentile(elapsedList, 0.99)
-		calcMin := findMin(elapsedList)
-		calcMax := findMax(elapsedList)
-		errorPercent := calculateErrorPercent(errorCount, requestCount)
-		throughput := calculateThroughput(requestCount, int(runningTime))
-		receivedKB := calculateReceivedKBPerSec(totalBytes, int(runningTime))
-		sentKB := calculateSentKBPerSec(totalSentBytes, int(runningTime))
-
-		labelStat := LoadTestStatistics{
-			Label:         record.Label,
-			RequestCount:  requestCount,
-			Average:       average,
-			Median:        median,
-			NinetyPercent: ninetyPercent,
-			NinetyFive:    ninetyFive,
-			NinetyNine:    ninetyNine,
-			MinTime:       calcMin,
-			MaxTime:       calcMax,
-			ErrorPercent:  errorPercent,
-			Throughput:    throughput,
-			ReceivedKB:    receivedKB,
-			SentKB:        sentKB,
+		unit, ok := tags[label]
+		if !ok {
+			return "", nil, false
 		}
 
-		statistics = append(statistics, &labelStat)
+		floatValue := float64(intValue) * unit.Multiple
+		value = strconv.FormatFloat(floatValue, 'f', 3, 64)
+		u = unit.Unit
 	}
 
-	return statistics
-}
-
-func resultFormat(format constant.ResultFormat, resultSummaries []*ResultSummary) (any, error) {
-	if resultSummaries == nil {
-		return nil, nil
+	unixMilliseconds, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		log.Printf("[%d] time has error %s\n", i, err)
+		return "", nil, false
 	}
 
-	switch format {
-	case constant.Aggregate:
-		return aggregate(resultSummaries), nil
-	case constant.Normal:
+	return label, &MetricsRawData{
+		Value:     value,
+		Unit:      u,
+		IsError:   isError,
+		Timestamp: time.UnixMilli(unixMilliseconds),
+	}, true
+}
 
-		for i := range resultSummaries {
-			rs := resultSummaries[i]
-			if len(rs.Results) > 0 {
-				rs.Results = SampleDataByTimeInterval(rs.Results, 100)
-			}
+// resultFormat reads the result file of a run in the format asked for: the statistics of each
+// label, or the samples standing for each label in every bucket of resultSampleIntervalMs.
+func resultFormat(format constant.ResultFormat, engine loadEngine, filePath string) (any, error) {
+	if format == constant.Aggregate {
+		digest, err := digestResult(engine, filePath, 0)
+		if err != nil {
+			return nil, err
 		}
-
+		return digest.statistics(), nil
 	}
 
-	return resultSummaries, nil
+	digest, err := digestResult(engine, filePath, resultSampleIntervalMs)
+	if err != nil {
+		return nil, err
+	}
+	return digest.summaries(), nil
 }
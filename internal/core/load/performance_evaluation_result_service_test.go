@@ -4,9 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/exp/rand"
@@ -62,8 +67,35 @@ func generateCSVData(numRecords int) (string, error) {
 	return file.Name(), nil
 }
 
+// writeResultFile writes results into a JMeter result file of a temporary directory of tb.
+func writeResultFile(tb testing.TB, results map[string][]*ResultRawData) string {
+	var b strings.Builder
+	b.WriteString("timeStamp,elapsed,label,responseCode,responseMessage,threadName,dataType,success,failureMessage,bytes,sentBytes,grpThreads,allThreads,URL,Latency,IdleTime,Connect\n")
+	for label, rows := range results {
+		for _, r := range rows {
+			fmt.Fprintf(&b, "%d,%d,%s,200,OK,Thread1,text,%t,,%d,%d,%d,%d,%s,%d,%d,%d\n",
+				r.Timestamp.UnixMilli(), r.Elapsed, label, !r.IsError, r.Bytes, r.SentBytes, r.ActiveThreads, r.ActiveThreads,
+				r.URL, r.Latency, r.IdleTime, r.Connection)
+		}
+	}
+	path := filepath.Join(tb.TempDir(), "result.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// digestOf digests results the way a run's result file is, from the file they make.
+func digestOf(tb testing.TB, results map[string][]*ResultRawData, intervalMs int) *resultDigest {
+	digest, err := digestResult(jmeterEngine{}, writeResultFile(tb, results), intervalMs)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return digest
+}
+
 // {"level":"info","time":"2024-11-20T14:46:21+09:00","message":"Time taken to process CSV: 10.501558875s"}
-func TestAppendResultRawData(t *testing.T) {
+func TestDigestLargeResultFile(t *testing.T) {
 	numRecords := 100_000_00 // 10 million
 
 	filePath, err := generateCSVData(numRecords)
@@ -71,9 +103,216 @@ func TestAppendResultRawData(t *testing.T) {
 	defer os.Remove(filePath)
 	start := time.Now()
 
-	_, err = appendResultRawData(filePath)
+	_, err = digestResult(jmeterEngine{}, filePath, resultSampleIntervalMs)
 	assert.NoError(t, err)
 
 	duration := time.Since(start)
 	log.Info().Msgf("Time taken to process CSV: %v", duration)
 }
+
+func TestDigestResultMatchesTheRowsInMemory(t *testing.T) {
+	filePath, err := generateCSVData(20_000)
+	assert.NoError(t, err)
+	defer os.Remove(filePath)
+
+	digest, err := digestResult(jmeterEngine{}, filePath, resultSampleIntervalMs)
+	assert.NoError(t, err)
+	rows := make(map[string][]*ResultRawData)
+	err = scanResultRawData(filePath, func(label string, r *ResultRawData) {
+		rows[label] = append(rows[label], r)
+	})
+	assert.NoError(t, err)
+
+	statistics := digest.statistics()
+	assert.Len(t, statistics, len(rows))
+	for _, s := range statistics {
+		var elapsed []int
+		for _, r := range rows[s.Label] {
+			elapsed = append(elapsed, r.Elapsed)
+		}
+		sort.Ints(elapsed)
+
+		// response times below elapsedExactLimit are counted one by one, so nothing is lost
+		assert.Equal(t, len(elapsed), s.RequestCount, s.Label)
+		assert.Equal(t, calculatePercentile(elapsed, 0.95), s.NinetyFive, s.Label)
+		assert.Equal(t, calculatePercentile(elapsed, 0.99), s.NinetyNine, s.Label)
+		assert.Equal(t, float64(elapsed[0]), s.MinTime, s.Label)
+		assert.Equal(t, float64(elapsed[len(elapsed)-1]), s.MaxTime, s.Label)
+	}
+
+	for _, summary := range digest.summaries() {
+		for i := 1; i < len(summary.Results); i++ {
+			assert.True(t, summary.Results[i-1].Timestamp.UnixMilli()/resultSampleIntervalMs < summary.Results[i].Timestamp.UnixMilli()/resultSampleIntervalMs,
+				"expected one sample of %s per bucket, in time order", summary.Label)
+		}
+	}
+}
+
+func TestElapsedHistogramKeepsLongResponseTimesClose(t *testing.T) {
+	var acc statisticsAccumulator
+	var elapsed []int
+	for i := range 100_000 {
+		v := 1000 + i*7%300_000
+		elapsed = append(elapsed, v)
+		acc.add(&ResultRawData{Elapsed: v})
+	}
+	sort.Ints(elapsed)
+
+	for _, p := range []float64{0.5, 0.9, 0.95, 0.99} {
+		exact := calculatePercentile(elapsed, p)
+		assert.InEpsilon(t, exact, acc.percentile(p), 0.001, "percentile %v", p)
+	}
+	assert.Less(t, len(acc.elapsed.buckets()), 10_000, "expected the buckets to stay few")
+}
+
+func TestMetricsAreReadARowAtATime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpu_result.csv")
+	content := "timeStamp,elapsed,label,responseCode,responseMessage,threadName,dataType,success,failureMessage,bytes,sentBytes,grpThreads,allThreads,URL,Latency,IdleTime,Connect\n" +
+		"1718000002000,42000,perfmon 127.0.0.1 cpu_all_combined,,,,text,true,,0,0,1,1,,0,0,0\n" +
+		"1718000001000,40000,perfmon 127.0.0.1 cpu_all_combined,,,,text,true,,0,0,1,1,,0,0,0\n" +
+		"1718000001000,0,agent unreachable,,,,text,false,,0,0,1,1,,0,0,0\n" +
+		"1718000001000,1,perfmon 127.0.0.1 not_a_metric,,,,text,true,,0,0,1,1,,0,0,0\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	metrics, err := appendMetricsRawData(make(map[string][]*MetricsRawData), path)
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 2)
+	cpu := metrics["cpu_all_combined"]
+	if assert.Len(t, cpu, 2) {
+		assert.Equal(t, "40.000", cpu[0].Value, "expected the readings in time order")
+		assert.Equal(t, "%", cpu[1].Unit)
+	}
+	assert.True(t, metrics["agent unreachable"][0].IsError)
+
+	assert.NoError(t, os.WriteFile(path, []byte("timeStamp,elapsed\n"), 0o644))
+	_, err = appendMetricsRawData(make(map[string][]*MetricsRawData), path)
+	assert.Error(t, err, "expected a file without readings to be empty")
+}
+
+// BenchmarkDigestResult reads a synthetic result file of a million requests the way the result
+// APIs do. It reports the memory the digest keeps, which does not grow with the requests.
+func BenchmarkDigestResult(b *testing.B) {
+	filePath, err := generateCSVData(1_000_000)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(filePath)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var digest *resultDigest
+	for range b.N {
+		digest, err = digestResult(jmeterEngine{}, filePath, resultSampleIntervalMs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	if len(digest.labels) == 0 {
+		b.Fatal("expected the labels of the file")
+	}
+	b.ReportMetric(float64(retainedBytes(func() { digest = nil }))/(1<<20), "MB-retained")
+}
+
+// appendCSVRows adds n requests to a result file, a millisecond apart from at on, as a
+// generator still running adds them.
+func appendCSVRows(filePath string, at time.Time, n int) error {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for i := range n {
+		_, err := fmt.Fprintf(writer, "%d,%d,Label%d,200,OK,Thread1,text,true,,%d,%d,10,10,https://example.com,%d,0,0\n",
+			at.Add(time.Duration(i)*time.Millisecond).UnixMilli(), rand.Intn(1000), rand.Intn(10), rand.Intn(1000), rand.Intn(1000), rand.Intn(500))
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// BenchmarkLiveFigures takes the live figures of a run whose result file already holds a
+// million requests, a tick at a time, with a thousand requests added before every tick. A tick
+// reads only those, and what the tail keeps is the requests of its window.
+func BenchmarkLiveFigures(b *testing.B) {
+	filePath, err := generateCSVData(1_000_000)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(filePath)
+
+	tail := newLiveTail(liveFiguresWindow)
+	if _, err := tail.read(constant.Jmeter, filePath); err != nil {
+		b.Fatal(err)
+	}
+	at := time.Now().Add(1_000_000 * time.Millisecond)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		b.StopTimer()
+		if err := appendCSVRows(filePath, at.Add(time.Duration(i)*time.Second), 1000); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		if _, err := tail.read(constant.Jmeter, filePath); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(retainedBytes(func() { tail = nil }))/(1<<20), "MB-retained")
+}
+
+// BenchmarkMergeResultParts merges the result files of a cluster of two, half a million
+// requests each, a fetch at a time, with a thousand requests added to each before every fetch.
+// A merge copies only those.
+func BenchmarkMergeResultParts(b *testing.B) {
+	var parts []string
+	for range 2 {
+		part, err := generateCSVData(500_000)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer os.Remove(part)
+		parts = append(parts, part)
+	}
+	dst := parts[0] + "_merged.csv"
+	defer os.Remove(dst)
+	defer os.Remove(resultMergeFileName(dst))
+	if err := mergeResultParts(dst, parts); err != nil {
+		b.Fatal(err)
+	}
+	at := time.Now().Add(500_000 * time.Millisecond)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		b.StopTimer()
+		for _, part := range parts {
+			if err := appendCSVRows(part, at.Add(time.Duration(i)*time.Second), 1000); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StartTimer()
+		if err := mergeResultParts(dst, parts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// retainedBytes is the heap that drop lets go of.
+func retainedBytes(drop func()) uint64 {
+	var kept, dropped runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&kept)
+	drop()
+	runtime.GC()
+	runtime.ReadMemStats(&dropped)
+	if kept.HeapAlloc < dropped.HeapAlloc {
+		return 0
+	}
+	return kept.HeapAlloc - dropped.HeapAlloc
+}
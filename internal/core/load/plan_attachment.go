@@ -0,0 +1,101 @@
+package load
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxPlanAttachmentBytes caps the files a single test carries to the generator. They travel
+// inline through a cb-tumblebug remote command, which is not built for bulk transfer, and they
+// are kept in the database so the test can be re-run.
+const MaxPlanAttachmentBytes = 10 << 20
+
+// planAttachment is a file the test plan reads at run time. Attachments are written next to
+// the plan, under test_plan/<loadTestKey>/, and removed together with it. A Name may put the
+// file in a folder of its own there.
+type planAttachment struct {
+	Name    string
+	Content []byte
+}
+
+func planAttachmentDir(installPath, loadTestKey string) string {
+	return fmt.Sprintf("%s/test_plan/%s", installPath, loadTestKey)
+}
+
+// attachmentName keeps files of different requests apart: two requests may well upload a
+// file under the same name with different content.
+func attachmentName(requestIndex int, fileName string) string {
+	return fmt.Sprintf("%d_%s", requestIndex, filepath.Base(fileName))
+}
+
+// planAttachments collects every file the plan for param refers to.
+func planAttachments(param RunLoadTestParam) []planAttachment {
+	var files []planAttachment
+	for i, req := range param.HttpReqs {
+		for _, f := range req.Files {
+			files = append(files, planAttachment{Name: attachmentName(i, f.FileName), Content: f.Content})
+		}
+	}
+	for i, ds := range param.DataSets {
+		files = append(files, planAttachment{Name: dataSetFileName(i, ds.FileName), Content: ds.Content})
+	}
+	files = append(files, targetAttachments(param.Targets)...)
+	if param.TestPlan != nil {
+		// an uploaded plan names its data files itself, so they keep their names
+		for _, f := range param.TestPlan.DataFiles {
+			files = append(files, planAttachment{Name: filepath.Base(f.FileName), Content: f.Content})
+		}
+	}
+	return files
+}
+
+// writeAttachmentsCmd builds the shell command that recreates the attachments on a remote
+// generator. Content is base64 encoded so binary files survive the heredoc.
+func writeAttachmentsCmd(dir string, files []planAttachment) string {
+	var builder strings.Builder
+	builder.WriteString("set -e\n")
+	builder.WriteString(fmt.Sprintf("mkdir -p %s\n", dir))
+	for _, f := range files {
+		if sub := filepath.Dir(f.Name); sub != "." {
+			builder.WriteString(fmt.Sprintf("mkdir -p %s/%s\n", dir, sub))
+		}
+		builder.WriteString(fmt.Sprintf("base64 -d > %s/%s << 'EOF'\n", dir, f.Name))
+		builder.WriteString(wrapBase64(base64.StdEncoding.EncodeToString(f.Content)))
+		builder.WriteString("EOF\n")
+	}
+	return builder.String()
+}
+
+// wrapBase64 breaks the encoding into lines, which base64 -d accepts and which keeps the
+// command free of single lines longer than a shell or ssh channel might like.
+func wrapBase64(encoded string) string {
+	const width = 76
+	var builder strings.Builder
+	for len(encoded) > width {
+		builder.WriteString(encoded[:width])
+		builder.WriteString("\n")
+		encoded = encoded[width:]
+	}
+	builder.WriteString(encoded)
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// writeAttachmentsLocal writes the attachments for a generator installed next to cm-ant.
+func writeAttachmentsLocal(dir string, files []planAttachment) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(fmt.Sprintf("%s/%s", dir, f.Name)), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fmt.Sprintf("%s/%s", dir, f.Name), f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package load
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -101,30 +103,12 @@ func (l *LoadService) runPrecheck(ctx context.Context, param RunLoadTestParam, r
 	// An open port only proves something is listening. It says nothing about the path the
 	// load will hit, and a target that answers from here may still be unreachable from the
 	// generator. So the configured request is sent once, and the status code is kept.
-	rec.begin(constant.SubTargetReachable, "Sending a test request to the target")
-	var code int
-	err = retry(rec, constant.SubTargetReachable, "Sending a test request to the target", func() error {
-		var e error
-		code, e = probeTarget(ctx, param.HttpReqs)
-		return e
-	})
-	if err != nil {
-		msg := fmt.Sprintf("Target port %s unreachable", portOf(param.HttpReqs))
-		rec.fail(constant.SubTargetReachable, msg, fmt.Sprintf(
-			"sent %s %s from cm-ant %d times over %s, each with a %s timeout; last error: %v\n"+
-				"check in this order: the service listens on the target, the security group allows inbound on that port, and the node has a reachable address",
-			methodOf(param.HttpReqs), targetURL(param.HttpReqs), precheckAttempts,
-			precheckRetryDelay*time.Duration(precheckAttempts-1), precheckHTTPTimeout, err))
-		rec.fail(constant.StepPrecheck, msg, err.Error())
-		return fmt.Errorf("target is not reachable: %w", err)
-	} else if code >= 400 {
-		// The target is alive but the path is not serving. Worth saying, not worth stopping
-		// for — the user may be load testing an endpoint that returns an error on purpose.
-		rec.progress(constant.SubTargetReachable, 0,
-			fmt.Sprintf("Target answered with status %d - check the request path", code), "")
-		rec.ok(constant.SubTargetReachable, fmt.Sprintf("Target answered with status %d", code))
-	} else {
-		rec.ok(constant.SubTargetReachable, fmt.Sprintf("Target answered with status %d", code))
+	if len(param.HttpReqs) == 0 && len(param.Targets) == 0 && param.TestPlan != nil {
+		// The requests live in the uploaded plan, possibly behind variables and controllers
+		// only JMeter can resolve, so there is no single request to try here.
+		rec.skip(constant.SubTargetReachable, "Requests are defined by the uploaded test plan")
+	} else if err := l.precheckTargetRequest(ctx, param, rec); err != nil {
+		return err
 	}
 
 	// ── the metric agent port ───────────────────────────────────────────────────────────
@@ -196,6 +180,66 @@ func (l *LoadService) runPrecheck(ctx context.Context, param RunLoadTestParam, r
 	return nil
 }
 
+// precheckTargetRequest sends the first configured request once and records how the target
+// answered, then makes sure every other protocol target speaks the protocol it is sent.
+func (l *LoadService) precheckTargetRequest(ctx context.Context, param RunLoadTestParam, rec *stepRecorder) error {
+	rec.begin(constant.SubTargetReachable, "Sending a test request to the target")
+	if len(param.HttpReqs) == 0 && len(param.Targets) > 0 {
+		return precheckProtocolTargets(ctx, param.Targets, rec)
+	}
+	var code int
+	err := retry(rec, constant.SubTargetReachable, "Sending a test request to the target", func() error {
+		var e error
+		code, e = probeTarget(ctx, param.HttpReqs)
+		return e
+	})
+	if err != nil {
+		msg := fmt.Sprintf("Target port %s unreachable", portOf(param.HttpReqs))
+		rec.fail(constant.SubTargetReachable, msg, fmt.Sprintf(
+			"sent %s %s from cm-ant %d times over %s, each with a %s timeout; last error: %v\n"+
+				"check in this order: the service listens on the target, the security group allows inbound on that port, and the node has a reachable address",
+			methodOf(param.HttpReqs), targetURL(param.HttpReqs), precheckAttempts,
+			precheckRetryDelay*time.Duration(precheckAttempts-1), precheckHTTPTimeout, err))
+		rec.fail(constant.StepPrecheck, msg, err.Error())
+		return fmt.Errorf("target is not reachable: %w", err)
+	} else if code >= 400 {
+		// The target is alive but the path is not serving. Worth saying, not worth stopping
+		// for — the user may be load testing an endpoint that returns an error on purpose.
+		rec.progress(constant.SubTargetReachable, 0,
+			fmt.Sprintf("Target answered with status %d - check the request path", code), "")
+	}
+	if len(param.Targets) > 0 {
+		rec.progress(constant.SubTargetReachable, 0, fmt.Sprintf("Target answered with status %d", code), "")
+		return precheckProtocolTargets(ctx, param.Targets, rec)
+	}
+	rec.ok(constant.SubTargetReachable, fmt.Sprintf("Target answered with status %d", code))
+
+	return nil
+}
+
+// precheckProtocolTargets probes each target in its own protocol. A port that accepts the
+// connection but answers in another protocol - a gRPC sampler pointed at a REST port, a JDBC
+// sampler pointed at the wrong database - would only show up as a run full of errors.
+func precheckProtocolTargets(ctx context.Context, targets []RunLoadTestTargetParam, rec *stepRecorder) error {
+	for i, t := range targets {
+		what := fmt.Sprintf("Checking %s target %s:%s", t.Protocol, t.Hostname, t.Port)
+		if err := retry(rec, constant.SubTargetReachable, what, func() error {
+			return probeProtocolTarget(ctx, t)
+		}); err != nil {
+			msg := fmt.Sprintf("%s target %d unreachable", t.Protocol, i+1)
+			rec.fail(constant.SubTargetReachable, msg, fmt.Sprintf(
+				"probed %s:%s as %s from cm-ant %d times over %s; last error: %v\n"+
+					"check that the service listens on the target, that it speaks %s on that port, and that the security group allows inbound on it",
+				t.Hostname, t.Port, t.Protocol, precheckAttempts,
+				precheckRetryDelay*time.Duration(precheckAttempts-1), err, t.Protocol))
+			rec.fail(constant.StepPrecheck, msg, err.Error())
+			return fmt.Errorf("%s target %s:%s is not reachable: %w", t.Protocol, t.Hostname, t.Port, err)
+		}
+	}
+	rec.ok(constant.SubTargetReachable, fmt.Sprintf("All %d protocol targets answered", len(targets)))
+	return nil
+}
+
 // probeTarget sends the configured request once and returns the status code.
 func probeTarget(ctx context.Context, reqs []RunLoadTestHttpParam) (int, error) {
 	if len(reqs) == 0 {
@@ -213,10 +257,7 @@ func probeTarget(ctx context.Context, reqs []RunLoadTestHttpParam) (int, error)
 	}
 	url := fmt.Sprintf("%s://%s:%s%s", scheme, r.Hostname, r.Port, path)
 
-	method := strings.ToUpper(strings.TrimSpace(r.Method))
-	if method == "" {
-		method = http.MethodGet
-	}
+	method := methodOf(reqs)
 
 	ctx, cancel := context.WithTimeout(ctx, precheckHTTPTimeout)
 	defer cancel()
@@ -249,11 +290,19 @@ func targetURL(reqs []RunLoadTestHttpParam) string {
 	return fmt.Sprintf("%s://%s:%s%s", scheme, r.Hostname, r.Port, path)
 }
 
+// methodOf is the method the probe sends. Requests that change the target are probed with HEAD
+// instead: the probe only needs an answer, and it must not be the first DELETE of the run. A
+// 405 in reply still proves the path is served.
 func methodOf(reqs []RunLoadTestHttpParam) string {
 	if len(reqs) == 0 || strings.TrimSpace(reqs[0].Method) == "" {
-		return "GET"
+		return http.MethodGet
 	}
-	return strings.ToUpper(strings.TrimSpace(reqs[0].Method))
+	method := strings.ToUpper(strings.TrimSpace(reqs[0].Method))
+	switch method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return http.MethodHead
+	}
+	return method
 }
 
 func portOf(reqs []RunLoadTestHttpParam) string {
@@ -278,6 +327,134 @@ func dial(host, port string) error {
 	return conn.Close()
 }
 
+// probeProtocolTarget checks that a target answers in its protocol. Nothing is sent that the
+// run itself would send: a handshake is enough, and it changes nothing on the target.
+func probeProtocolTarget(ctx context.Context, t RunLoadTestTargetParam) error {
+	switch t.Protocol {
+	case constant.TcpTarget:
+		return dial(t.Hostname, t.Port)
+	case constant.WebSocketTarget:
+		return probeWebSocket(ctx, t)
+	case constant.GrpcTarget:
+		return probeGrpc(ctx, t)
+	case constant.JdbcTarget:
+		return probeDatabase(t)
+	}
+	return fmt.Errorf("unknown target protocol %q", t.Protocol)
+}
+
+// probeWebSocket opens the handshake and expects the server to switch protocols.
+func probeWebSocket(ctx context.Context, t RunLoadTestTargetParam) error {
+	scheme := "http"
+	if t.Secure {
+		scheme = "https"
+	}
+	path := t.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, precheckHTTPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(t.Hostname, t.Port), path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "Y20tYW50LXByZWNoZWNrIQ==")
+	resp, err := (&http.Client{Timeout: precheckHTTPTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("websocket handshake answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// probeGrpc calls the standard health check with an empty message. Any grpc-status in the
+// answer - Unimplemented included - proves a gRPC server is on the port.
+func probeGrpc(ctx context.Context, t RunLoadTestTargetParam) error {
+	var protocols http.Protocols
+	scheme := "http"
+	if t.Secure {
+		scheme = "https"
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, precheckHTTPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s://%s/grpc.health.v1.Health/Check", scheme, net.JoinHostPort(t.Hostname, t.Port)),
+		bytes.NewReader(make([]byte, 5)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	client := &http.Client{Timeout: precheckHTTPTimeout, Transport: &http.Transport{Protocols: &protocols}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.Header.Get("Grpc-Status") == "" && resp.Trailer.Get("Grpc-Status") == "" {
+		return fmt.Errorf("no grpc-status in the answer (http status %d); the port does not serve gRPC", resp.StatusCode)
+	}
+	return nil
+}
+
+// probeDatabase reads the first thing the database says on a new connection. PostgreSQL is
+// asked whether it speaks TLS, and answers with one byte; MySQL greets every client first.
+// Neither needs the credentials, so a wrong password is left for the run to report.
+func probeDatabase(t RunLoadTestTargetParam) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(t.Hostname, t.Port), precheckDialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(precheckDialTimeout)); err != nil {
+		return err
+	}
+
+	switch t.Driver {
+	case constant.PostgresDriver:
+		sslRequest := []byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f}
+		if _, err := conn.Write(sslRequest); err != nil {
+			return err
+		}
+		answer := make([]byte, 1)
+		if _, err := io.ReadFull(conn, answer); err != nil {
+			return fmt.Errorf("no answer to a postgresql handshake: %w", err)
+		}
+		if answer[0] != 'S' && answer[0] != 'N' {
+			return fmt.Errorf("the port does not answer like postgresql")
+		}
+	case constant.MysqlDriver:
+		// A 4 byte packet header, then the protocol version. A server that refuses this host
+		// sends an error packet instead, which still proves it is MySQL.
+		greeting := make([]byte, 5)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return fmt.Errorf("no mysql greeting: %w", err)
+		}
+		if greeting[4] != 10 && greeting[4] != 0xff {
+			return fmt.Errorf("the port does not answer like mysql")
+		}
+	default:
+		return fmt.Errorf("unknown jdbc driver %q", t.Driver)
+	}
+	return nil
+}
+
 // probeRemoteCommand runs a trivial command on the target and checks the answer comes back.
 func (l *LoadService) probeRemoteCommand(ctx context.Context, nsId, infraId, nodeId string) error {
 	ctx, cancel := context.WithTimeout(ctx, precheckRemoteTimeout)
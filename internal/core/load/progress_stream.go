@@ -0,0 +1,333 @@
+package load
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// The console used to poll a run's state to follow its steps, and saw nothing of the load
+// itself until the results were fetched at the end, too late to stop a run that was going
+// badly. Those watching a run are now pushed every step as it changes and every change of its
+// state, and while the load runs, rolling figures read from the part of the result file
+// fetched so far.
+
+const (
+	// liveFiguresInterval is how often a watched run's result file is fetched for figures.
+	liveFiguresInterval = 10 * time.Second
+	// liveFiguresWindow is the span of samples the rolling figures are taken over.
+	liveFiguresWindow = 30 * time.Second
+	// progressBuffer is how many events a watcher may fall behind by; a slower one misses
+	// events rather than holding up the run.
+	progressBuffer = 64
+)
+
+// progressHub passes the progress of runs to those watching them, by load test key.
+type progressHub struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan LoadTestProgressEvent]struct{}
+}
+
+var progress = &progressHub{watchers: make(map[string]map[chan LoadTestProgressEvent]struct{})}
+
+func (h *progressHub) watch(loadTestKey string) chan LoadTestProgressEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan LoadTestProgressEvent, progressBuffer)
+	if h.watchers[loadTestKey] == nil {
+		h.watchers[loadTestKey] = make(map[chan LoadTestProgressEvent]struct{})
+	}
+	h.watchers[loadTestKey][ch] = struct{}{}
+	return ch
+}
+
+// unwatch closes ch, unless closeAll has already.
+func (h *progressHub) unwatch(loadTestKey string, ch chan LoadTestProgressEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.watchers[loadTestKey][ch]; !ok {
+		return
+	}
+	delete(h.watchers[loadTestKey], ch)
+	if len(h.watchers[loadTestKey]) == 0 {
+		delete(h.watchers, loadTestKey)
+	}
+	close(ch)
+}
+
+// closeAll ends every watch, so the streams behind them let a shutting down server go.
+func (h *progressHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, chs := range h.watchers {
+		for ch := range chs {
+			close(ch)
+		}
+		delete(h.watchers, key)
+	}
+}
+
+func (h *progressHub) watched(loadTestKey string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.watchers[loadTestKey]) > 0
+}
+
+func (h *progressHub) publish(loadTestKey string, event LoadTestProgressEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.watchers[loadTestKey] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// publishStep passes on a step as it was just recorded.
+func publishStep(loadTestKey string, step LoadTestExecutionStep) {
+	progress.publish(loadTestKey, LoadTestProgressEvent{
+		Type: constant.StepEvent,
+		Step: &LoadTestExecutionStepResult{
+			Seq:        step.Seq,
+			Name:       step.Name,
+			Status:     step.Status,
+			Attempt:    step.Attempt,
+			StartAt:    step.StartAt,
+			FinishAt:   step.FinishAt,
+			Message:    step.Message,
+			Detail:     step.Detail,
+			ElapsedSec: elapsedSec(step.StartAt, step.FinishAt, time.Now()),
+		},
+	})
+}
+
+// publishState passes on the state of a run as it was just saved. Its steps go as step events.
+func publishState(state LoadTestExecutionState) {
+	result := mapLoadTestExecutionStateResult(state)
+	result.Steps = nil
+	progress.publish(state.LoadTestKey, LoadTestProgressEvent{Type: constant.StateEvent, State: &result})
+}
+
+// WatchLoadTestProgress returns the progress of a run as it happens, starting with its state
+// as it is now. The channel is closed when ctx is done or the server shuts down; a finished
+// run sends its state and nothing more.
+func (l *LoadService) WatchLoadTestProgress(ctx context.Context, loadTestKey string) (<-chan LoadTestProgressEvent, error) {
+	// watch first, so nothing that happens while the state is read is missed
+	ch := progress.watch(loadTestKey)
+	state, err := l.GetLoadTestExecutionState(GetLoadTestExecutionStateParam{LoadTestKey: loadTestKey})
+	if err != nil {
+		progress.unwatch(loadTestKey, ch)
+		return nil, err
+	}
+
+	out := make(chan LoadTestProgressEvent, progressBuffer)
+	go func() {
+		defer close(out)
+		defer progress.unwatch(loadTestKey, ch)
+
+		out <- LoadTestProgressEvent{Type: constant.StateEvent, State: &state}
+		if state.ExecutionStatus.Finished() {
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+				if event.Type == constant.StateEvent && event.State.ExecutionStatus.Finished() {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// takeLiveFigures reads rolling figures from what was added to the result file since the last
+// tick, and passes them on to those watching the run and to its auto-stop rules.
+func (l *LoadService) takeLiveFigures(f *fetchDataParam, watched, guarded bool) {
+	if f.live == nil {
+		f.live = newLiveTail(liveFiguresWindow)
+	}
+	figures, err := f.live.read(f.Engine, fmt.Sprintf("%s/%s_result.csv", utils.JoinRootPathWith("/result/"+f.LoadTestKey), f.LoadTestKey))
+	if err != nil {
+		log.Debug().Msgf("no live figures for load test %s yet; %v", f.LoadTestKey, err)
+		return
+	}
+	if watched {
+		progress.publish(f.LoadTestKey, LoadTestProgressEvent{Type: constant.FiguresEvent, Figures: figures})
+	}
+	if guarded {
+		l.checkAutoStop(f, figures)
+	}
+}
+
+// fileTail follows a file that is still being written, from where the last read of it stopped.
+type fileTail struct {
+	offset int64
+	header []byte
+}
+
+// read hands fn the header of the file and the complete lines added to it since the last read,
+// streamed from the file; fn is not called when none were. A file that got shorter was written
+// anew, and is followed again from its start.
+func (t *fileTail) read(path string, fn func(lines io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < t.offset {
+		*t = fileTail{}
+	}
+
+	end, err := lastLineEnd(f, t.offset, info.Size())
+	if err != nil || end == t.offset {
+		return err
+	}
+	start := t.offset
+	if start == 0 {
+		header, err := bufio.NewReader(io.NewSectionReader(f, 0, end)).ReadBytes('\n')
+		if err != nil {
+			return err
+		}
+		t.header, start = header, int64(len(header))
+	}
+	// the lines are taken once, whether fn reads them all or not
+	t.offset = end
+	return fn(io.MultiReader(bytes.NewReader(t.header), io.NewSectionReader(f, start, end-start)))
+}
+
+// liveRow is what the rolling figures take of a request.
+type liveRow struct {
+	at      time.Time
+	elapsed int
+	isError bool
+}
+
+// liveTail follows the result file of a run as it is fetched. The totals are kept as the rows
+// come in and only the rows within the window of the latest one are held, so a tick costs the
+// rows added since the last one rather than the whole file.
+type liveTail struct {
+	file   fileTail
+	window time.Duration
+
+	totalSamples int
+	totalErrors  int
+	first, at    time.Time
+	recent       []liveRow
+}
+
+func newLiveTail(window time.Duration) *liveTail {
+	return &liveTail{window: window}
+}
+
+// read takes in the rows added to the result file at path since the last read, and returns the
+// figures as they are now.
+func (t *liveTail) read(engineType constant.LoadGeneratorType, path string) (*LiveFigures, error) {
+	engine, err := engineFor(engineType)
+	if err != nil {
+		return nil, err
+	}
+	err = t.file.read(path, func(rows io.Reader) error {
+		return engine.ScanRows(rows, path, t.add)
+	})
+	// a part with no request in it is only an error while none has come in
+	if err != nil && t.totalSamples == 0 {
+		return nil, err
+	}
+	return t.figures(), nil
+}
+
+// add takes in one request.
+func (t *liveTail) add(_ string, r *ResultRawData) {
+	t.totalSamples++
+	if r.IsError {
+		t.totalErrors++
+	}
+	if t.first.IsZero() || r.Timestamp.Before(t.first) {
+		t.first = r.Timestamp
+	}
+	t.recent = append(t.recent, liveRow{at: r.Timestamp, elapsed: r.Elapsed, isError: r.IsError})
+	if !r.Timestamp.After(t.at) {
+		return
+	}
+	t.at = r.Timestamp
+
+	// the rows come in about the order they were sent, so a file read a long way at once is
+	// trimmed as it goes, once its oldest row is twice the window behind
+	if len(t.recent) > 0 && t.recent[0].at.Before(t.at.Add(-2*t.window)) {
+		t.trim()
+	}
+}
+
+// trim drops the rows that fell out of the window up to the latest one.
+func (t *liveTail) trim() {
+	from := t.at.Add(-t.window)
+	kept := t.recent[:0]
+	for _, row := range t.recent {
+		if row.at.After(from) {
+			kept = append(kept, row)
+		}
+	}
+	t.recent = kept
+}
+
+// figures takes the figures of the requests in the window up to the latest one, holding on to
+// those only. Throughput is over the part of the window the requests cover, so it is not
+// understated while the run is younger than the window.
+func (t *liveTail) figures() *LiveFigures {
+	t.trim()
+	figures := &LiveFigures{WindowSec: int(t.window / time.Second), TotalSamples: t.totalSamples, TotalErrors: t.totalErrors, At: t.at}
+	if t.totalSamples == 0 {
+		return figures
+	}
+
+	from := t.at.Add(-t.window)
+	var elapsed []int
+	errs, sum := 0, 0
+	for _, r := range t.recent {
+		elapsed = append(elapsed, r.elapsed)
+		sum += r.elapsed
+		if r.isError {
+			errs++
+		}
+	}
+	sort.Ints(elapsed)
+
+	span := t.at.Sub(from)
+	if t.first.After(from) {
+		span = t.at.Sub(t.first)
+	}
+	span = max(span, time.Second)
+
+	figures.Samples = len(elapsed)
+	figures.Throughput = float64(len(elapsed)) / span.Seconds()
+	figures.AvgElapsed = float64(sum) / float64(len(elapsed))
+	figures.P95Elapsed = calculatePercentile(elapsed, 0.95)
+	figures.ErrorPercent = calculateErrorPercent(errs, len(elapsed))
+	return figures
+}
@@ -0,0 +1,113 @@
+package load
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestLiveFiguresCoverTheLatestWindow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	sample := func(sec, elapsed int, isError bool) *ResultRawData {
+		return &ResultRawData{Timestamp: start.Add(time.Duration(sec) * time.Second), Elapsed: elapsed, IsError: isError}
+	}
+	tail := newLiveTail(30 * time.Second)
+	for _, r := range []*ResultRawData{
+		sample(0, 900, true), sample(5, 900, true), sample(40, 100, false), sample(50, 300, false),
+		sample(45, 200, true), sample(60, 400, false),
+	} {
+		tail.add("", r)
+	}
+
+	got := tail.figures()
+	if !got.At.Equal(start.Add(60*time.Second)) || got.TotalSamples != 6 || got.TotalErrors != 3 {
+		t.Fatalf("expected totals over the whole file up to the latest sample, got %+v", got)
+	}
+	// the samples from 40s on, whatever order they came in
+	if got.Samples != 4 || got.AvgElapsed != 250 || got.P95Elapsed != 400 || got.ErrorPercent != 25 {
+		t.Errorf("expected the figures of the last 30 seconds, got %+v", got)
+	}
+	if got.Throughput != 4.0/30 {
+		t.Errorf("expected 4 samples over 30 seconds, got %v", got.Throughput)
+	}
+	if len(tail.recent) != 4 {
+		t.Errorf("expected only the rows of the window held, got %d", len(tail.recent))
+	}
+
+	young := newLiveTail(30 * time.Second)
+	for sec := range 3 {
+		young.add("", sample(sec, 10, false))
+	}
+	if got := young.figures(); got.Throughput != 1.5 {
+		t.Errorf("expected a run younger than the window to be measured over its own span, got %v", got.Throughput)
+	}
+}
+
+func TestLiveTailReadsOnlyTheNewCompleteLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.csv")
+	row := func(ms int64, elapsed int) string {
+		return fmt.Sprintf("%d,%d,login,200,OK,t 1-1,text,true,,100,50,1,1,http://x/login,%d,0,0\n", ms, elapsed, elapsed)
+	}
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := "timeStamp,elapsed,label,responseCode,responseMessage,threadName,dataType,success,failureMessage,bytes,sentBytes,grpThreads,allThreads,URL,Latency,IdleTime,Connect\n"
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC).UnixMilli()
+
+	tail := newLiveTail(30 * time.Second)
+	content := header + row(start, 100) + row(start+1000, 200)
+	// the last line is still being written
+	write(content + "17")
+	got, err := tail.read(constant.Jmeter, path)
+	if err != nil || got.TotalSamples != 2 {
+		t.Fatalf("expected the two complete rows, got %+v, %v", got, err)
+	}
+
+	content += row(start+2000, 300)
+	write(content)
+	if got, err = tail.read(constant.Jmeter, path); err != nil || got.TotalSamples != 3 || got.AvgElapsed != 200 {
+		t.Errorf("expected the third row taken in once, got %+v, %v", got, err)
+	}
+	if got, err = tail.read(constant.Jmeter, path); err != nil || got.TotalSamples != 3 {
+		t.Errorf("expected nothing new, got %+v, %v", got, err)
+	}
+	if tail.file.offset != int64(len(content)) {
+		t.Errorf("expected the file read up to its end once, at %d of %d", tail.file.offset, len(content))
+	}
+}
+
+func TestProgressGoesOnlyToTheRunsWatchers(t *testing.T) {
+	hub := &progressHub{watchers: make(map[string]map[chan LoadTestProgressEvent]struct{})}
+	a := hub.watch("a")
+	b := hub.watch("b")
+
+	hub.publish("a", LoadTestProgressEvent{Type: constant.StepEvent})
+	if len(a) != 1 || len(b) != 0 {
+		t.Errorf("expected the event on a only, got %d on a and %d on b", len(a), len(b))
+	}
+
+	// a watcher that falls behind misses events instead of blocking the run
+	for range progressBuffer + 1 {
+		hub.publish("b", LoadTestProgressEvent{Type: constant.FiguresEvent})
+	}
+	if len(b) != progressBuffer {
+		t.Errorf("expected b to hold %d events, got %d", progressBuffer, len(b))
+	}
+
+	hub.unwatch("a", a)
+	hub.closeAll()
+	hub.unwatch("b", b) // already closed by closeAll
+	if hub.watched("a") || hub.watched("b") {
+		t.Errorf("expected no watchers left")
+	}
+	<-a // the step event
+	if _, ok := <-a; ok {
+		t.Errorf("expected a to be closed")
+	}
+}
@@ -0,0 +1,228 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/infra/outbound/tumblebug"
+	"github.com/rs/zerolog/log"
+)
+
+// A migrated service is used from where its users are, and what they wait for includes the way
+// there. A run from several regions sends its load from a generator server in each of them at
+// once, split by weight the way a cluster splits it between its servers. Each server leaves a
+// result part of its own, numbered in the order the regions were given, so the run can be read
+// region by region as well as merged.
+//
+// The regional servers are VMs of the one generator MCI, added as subgroups of their own; an
+// MCI spans connections, so the install, the key and the commands reach them like any other.
+
+// MaxRegions bounds the regions one run is sent from.
+const MaxRegions = 5
+
+// ValidateRegions checks the regions a run is sent from. Every region has to be given at least
+// one virtual user, or one iteration a second, by its weight; a server with nothing to run
+// would leave no result to read.
+func ValidateRegions(param RunLoadTestParam) error {
+	if len(param.Regions) == 0 {
+		return nil
+	}
+	if len(param.Regions) > MaxRegions {
+		return fmt.Errorf("a run can be sent from at most %d regions", MaxRegions)
+	}
+	if param.TestPlan != nil {
+		return errors.New("an uploaded test plan decides its own load and cannot be split between regions")
+	}
+	if param.InstallLoadGenerator.ClusterSize > 1 {
+		return errors.New("a run from several regions uses one generator server in each and cannot use a cluster")
+	}
+	if param.LoadGeneratorInstallInfoId == 0 && param.InstallLoadGenerator.InstallLocation != constant.Remote {
+		return errors.New("a run from several regions needs a remote load generator")
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range param.Regions {
+		if strings.TrimSpace(r.Provider) == "" || strings.TrimSpace(r.Region) == "" {
+			return fmt.Errorf("region %d needs a provider and a region", i+1)
+		}
+		if r.Weight < 0 {
+			return fmt.Errorf("region %d weight must not be negative", i+1)
+		}
+		key := regionKey(r.Provider, r.Region)
+		if seen[key] {
+			return fmt.Errorf("region %s is given more than once", key)
+		}
+		seen[key] = true
+	}
+
+	for i, p := range regionShares(param) {
+		if peakOf(p) < 1 {
+			r := param.Regions[i]
+			return fmt.Errorf("region %s gets no load at weight %d; raise its weight or the load of the run",
+				regionKey(r.Provider, r.Region), regionWeight(r))
+		}
+	}
+	return nil
+}
+
+// regionKey names a region the way errors and results show it, e.g. aws/ap-northeast-2.
+func regionKey(provider, region string) string {
+	return strings.ToLower(strings.TrimSpace(provider)) + "/" + strings.ToLower(strings.TrimSpace(region))
+}
+
+// regionWeight is the weight a region is given its load by.
+func regionWeight(r RunLoadTestRegionParam) int {
+	return max(1, r.Weight)
+}
+
+// weightedShare is region i's part of total by the weights of every region. What the weights
+// do not divide evenly goes to the first regions.
+func weightedShare(total, i int, weights []int) int {
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	rest := total
+	for _, w := range weights {
+		rest -= total * w / sum
+	}
+	s := total * weights[i] / sum
+	if i < rest {
+		s++
+	}
+	return s
+}
+
+// regionShares splits the load of param between its regions by weight and returns what each
+// of them runs, in the order the regions were given. Only the first region collects the
+// target's system metrics.
+func regionShares(param RunLoadTestParam) []RunLoadTestParam {
+	if len(param.Regions) == 0 {
+		return nil
+	}
+	weights := make([]int, len(param.Regions))
+	for i, r := range param.Regions {
+		weights[i] = regionWeight(r)
+	}
+	return splitLoad(param, len(weights), func(total, i int) int { return weightedShare(total, i, weights) })
+}
+
+// usersOf is the virtual users param runs with; a staged run counts its busiest stage.
+func usersOf(param RunLoadTestParam) string {
+	if loadShapeOf(param) == constant.StagesShape {
+		return strconv.Itoa(peakOf(param))
+	}
+	return param.VirtualUsers
+}
+
+// inRegion reports whether a server of csp in region is in region want of provider.
+func inRegion(csp, region, provider, want string) bool {
+	return regionKey(csp, region) == regionKey(provider, want)
+}
+
+// regionServers returns a server of the generator for every region, in the order the regions
+// were given. Where a region has several, the master is taken, then the oldest.
+func regionServers(regions []RunLoadTestRegionParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) ([]LoadGeneratorServer, error) {
+	all := serversMasterFirst(loadGeneratorInstallInfo)
+	servers := make([]LoadGeneratorServer, 0, len(regions))
+	for _, r := range regions {
+		found := false
+		for _, s := range all {
+			if inRegion(s.Csp, s.Region, r.Provider, r.Region) {
+				servers = append(servers, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the load generator has no server in %s", regionKey(r.Provider, r.Region))
+		}
+	}
+	return servers, nil
+}
+
+// installRegionsOf lists the regions a run needs generator servers in.
+func installRegionsOf(regions []RunLoadTestRegionParam) []LoadGeneratorRegionParam {
+	var installRegions []LoadGeneratorRegionParam
+	for _, r := range regions {
+		installRegions = append(installRegions, LoadGeneratorRegionParam{Provider: r.Provider, Region: r.Region})
+	}
+	return installRegions
+}
+
+// missingRegions lists the regions that have no running VM among vms.
+func missingRegions(vms []tumblebug.VmRes, regions []LoadGeneratorRegionParam) []string {
+	var missing []string
+	for _, r := range regions {
+		found := false
+		for _, vm := range vms {
+			if strings.EqualFold(vm.Status, "Running") &&
+				inRegion(vm.ConnectionConfig.ProviderName, vm.Region.Region, r.Provider, r.Region) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, regionKey(r.Provider, r.Region))
+		}
+	}
+	return missing
+}
+
+// addRegionVms adds a generator VM to the MCI in every region of regions it has none in, with
+// the spec recommended there and an image that connection offers. A VM that is there but not
+// running is left for the resume that follows.
+func (l *LoadService) addRegionVms(ctx context.Context, nsId, mciId, vmName string, regions []LoadGeneratorRegionParam, antMci tumblebug.MciRes) (tumblebug.MciRes, error) {
+	for _, r := range regions {
+		present := false
+		for _, vm := range antMci.Vm {
+			if inRegion(vm.ConnectionConfig.ProviderName, vm.Region.Region, r.Provider, r.Region) {
+				present = true
+				break
+			}
+		}
+		if present {
+			continue
+		}
+
+		provider := strings.ToLower(strings.TrimSpace(r.Provider))
+		region := strings.ToLower(strings.TrimSpace(r.Region))
+		connectionName := fmt.Sprintf("%s-%s", provider, region)
+		log.Info().Msgf("MCI has no VM in %s, adding one with dynamic resource provisioning", connectionName)
+
+		recommendVm, err := l.getRecommendVm(ctx, []string{region}, provider)
+		if err != nil {
+			return antMci, fmt.Errorf("no generator spec found in %s; %w", regionKey(provider, region), err)
+		}
+		image, err := l.getAvailableImage(ctx, connectionName)
+		if err != nil {
+			return antMci, fmt.Errorf("no generator image found in %s; %w", regionKey(provider, region), err)
+		}
+
+		dynamicVmArg := tumblebug.DynamicVmReq{
+			ImageId:        image,
+			SpecId:         recommendVm[0].Name,
+			ConnectionName: connectionName,
+			Description:    antVmDescription,
+			Label:          map[string]string{antLabelKey: antVmLabel},
+			Name:           fmt.Sprintf("%s-%s", vmName, connectionName),
+			RootDiskSize:   antVmRootDiskSize,
+			RootDiskType:   antVmRootDiskType,
+			SubGroupSize:   antVmSubGroupSize,
+			VMUserPassword: antVmUserPassword,
+			// SSH key, VNet, Security Group will be auto-created by CB-Tumblebug
+		}
+
+		antMci, err = l.tumblebugClient.DynamicVmWithContext(ctx, nsId, mciId, dynamicVmArg)
+		time.Sleep(defaultDelay)
+		if err != nil {
+			return antMci, err
+		}
+	}
+	return antMci, nil
+}
@@ -0,0 +1,107 @@
+package load
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/infra/outbound/tumblebug"
+)
+
+func TestRegionSharesFollowTheWeights(t *testing.T) {
+	param := RunLoadTestParam{VirtualUsers: "11", CollectAdditionalSystemMetrics: true, Regions: []RunLoadTestRegionParam{
+		{Provider: "aws", Region: "ap-northeast-2", Weight: 3},
+		{Provider: "gcp", Region: "europe-west3", Weight: 1},
+		{Provider: "azure", Region: "eastus"},
+	}}
+	shares := regionShares(param)
+	for i, want := range []string{"7", "2", "2"} {
+		if shares[i].VirtualUsers != want {
+			t.Errorf("region %d: expected %s users, got %s", i, want, shares[i].VirtualUsers)
+		}
+		if shares[i].CollectAdditionalSystemMetrics != (i == 0) {
+			t.Errorf("region %d: expected only the first region to collect metrics", i)
+		}
+	}
+
+	staged := RunLoadTestParam{LoadShape: constant.StagesShape, Stages: []RunLoadTestStageParam{{Target: 4, Duration: 10}},
+		Regions: []RunLoadTestRegionParam{{Provider: "aws", Region: "a"}, {Provider: "aws", Region: "b"}}}
+	if users := usersOf(regionShares(staged)[1]); users != "2" {
+		t.Errorf("expected the busiest stage to count as the users of a staged region, got %s", users)
+	}
+}
+
+func TestRegionsAreValidated(t *testing.T) {
+	remote := InstallLoadGeneratorParam{InstallLocation: constant.Remote}
+	for name, param := range map[string]RunLoadTestParam{
+		"duplicate": {InstallLoadGenerator: remote, VirtualUsers: "10", Regions: []RunLoadTestRegionParam{
+			{Provider: "aws", Region: "eu-central-1"}, {Provider: "AWS", Region: "eu-central-1"},
+		}},
+		"starved region": {InstallLoadGenerator: remote, VirtualUsers: "3", Regions: []RunLoadTestRegionParam{
+			{Provider: "aws", Region: "a", Weight: 10}, {Provider: "aws", Region: "b"},
+		}},
+		"uploaded plan": {InstallLoadGenerator: remote, VirtualUsers: "10", TestPlan: &RunLoadTestPlanParam{},
+			Regions: []RunLoadTestRegionParam{{Provider: "aws", Region: "a"}}},
+		"local generator": {InstallLoadGenerator: InstallLoadGeneratorParam{InstallLocation: constant.Local}, VirtualUsers: "10",
+			Regions: []RunLoadTestRegionParam{{Provider: "aws", Region: "a"}}},
+	} {
+		if err := ValidateRegions(param); err == nil {
+			t.Errorf("%s: expected the regions to be rejected", name)
+		}
+	}
+
+	// a generator that is already installed is checked for its servers when the run starts
+	reused := RunLoadTestParam{LoadGeneratorInstallInfoId: 1, VirtualUsers: "2", Regions: []RunLoadTestRegionParam{
+		{Provider: "aws", Region: "a"}, {Provider: "gcp", Region: "b"},
+	}}
+	if err := ValidateRegions(reused); err != nil {
+		t.Errorf("expected a reused generator to be accepted, got %v", err)
+	}
+}
+
+func TestRegionServersTakeOnePerRegion(t *testing.T) {
+	info := &LoadGeneratorInstallInfo{LoadGeneratorServers: []LoadGeneratorServer{
+		{VmName: "seoul-1", Csp: "aws", Region: "ap-northeast-2"},
+		{VmName: "frankfurt", Csp: "aws", Region: "eu-central-1"},
+		{VmName: "seoul-m", Csp: "aws", Region: "ap-northeast-2", IsMaster: true},
+	}}
+	info.LoadGeneratorServers[0].ID, info.LoadGeneratorServers[1].ID, info.LoadGeneratorServers[2].ID = 1, 2, 3
+
+	param := RunLoadTestParam{VirtualUsers: "4", Regions: []RunLoadTestRegionParam{
+		{Provider: "aws", Region: "eu-central-1"}, {Provider: "AWS", Region: "ap-northeast-2"},
+	}}
+	servers, shares, err := runServers(param, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 || servers[0].VmName != "frankfurt" || servers[1].VmName != "seoul-m" {
+		t.Errorf("expected frankfurt then the master in seoul, got %+v", servers)
+	}
+	if len(shares) != 2 || shares[0].VirtualUsers != "2" {
+		t.Errorf("expected the load split between both regions, got %+v", shares)
+	}
+	if workers := fetchWorkersOf(servers); len(workers) != 1 {
+		t.Errorf("expected the second region to be fetched beside the first, got %+v", workers)
+	}
+
+	param.Regions = append(param.Regions, RunLoadTestRegionParam{Provider: "gcp", Region: "us-east1"})
+	if _, _, err := runServers(param, info); err == nil {
+		t.Error("expected a region without a server to be refused")
+	}
+}
+
+func TestMissingRegionsNeedARunningVm(t *testing.T) {
+	vm := func(provider, region, status string) tumblebug.VmRes {
+		var v tumblebug.VmRes
+		v.ConnectionConfig.ProviderName = provider
+		v.Region.Region = region
+		v.Status = status
+		return v
+	}
+	vms := []tumblebug.VmRes{vm("aws", "ap-northeast-2", "Running"), vm("gcp", "europe-west3", "Suspended")}
+	regions := []LoadGeneratorRegionParam{{Provider: "aws", Region: "ap-northeast-2"}, {Provider: "gcp", Region: "europe-west3"}}
+
+	missing := missingRegions(vms, regions)
+	if len(missing) != 1 || missing[0] != "gcp/europe-west3" {
+		t.Errorf("expected the suspended region to be missing, got %v", missing)
+	}
+}
@@ -198,10 +198,19 @@ func (r *LoadRepository) UpdateLoadGeneratorInstallInfoTx(ctx context.Context, p
 		// 	Error
 
 		// Association().Replace() 는 update 시 id 충돌의 경우 foreign key 를 null 로 업데이트
-		return d.Model(param).
+		err := d.Model(param).
 			Session(&gorm.Session{FullSaveAssociations: true}).
 			Updates(param).
 			Error
+		if err != nil {
+			return err
+		}
+
+		// Updates skips zero values, and a cluster shrunk back to one server is all zero values
+		return d.Model(param).
+			Select("IsCluster", "MasterId", "ClusterSize").
+			Updates(param).
+			Error
 	})
 
 	return err
@@ -299,6 +308,10 @@ func (r *LoadRepository) UpdateLoadTestExecutionStateTx(ctx context.Context, par
 			Error
 	})
 
+	// every change of a run's state is saved here, so it is passed on to its watchers here
+	if err == nil {
+		publishState(*param)
+	}
 	return err
 }
 
@@ -375,6 +388,9 @@ func (r *LoadRepository) GetPagingLoadTestExecutionStateTx(ctx context.Context,
 			Preload("Steps", func(db *gorm.DB) *gorm.DB {
 				return db.Order("seq asc")
 			}).
+			Preload("SlaViolations", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
 			Order("load_test_execution_states.created_at desc")
 
 		if param.LoadTestKey != "" {
@@ -385,6 +401,14 @@ func (r *LoadRepository) GetPagingLoadTestExecutionStateTx(ctx context.Context,
 			q = q.Where("execution_status = ?", param.ExecutionStatus)
 		}
 
+		if param.ParentLoadTestKey != "" {
+			q = q.Where("parent_load_test_key = ?", param.ParentLoadTestKey)
+		}
+
+		if param.BaselineVerdict != "" {
+			q = q.Where("baseline_verdict = ?", param.BaselineVerdict)
+		}
+
 		if err := q.Count(&totalRows).Error; err != nil {
 			return err
 		}
@@ -409,6 +433,9 @@ func (r *LoadRepository) GetLoadTestExecutionStateTx(ctx context.Context, param
 		q := d.Model(&loadTestExecutionState).
 			Preload("Steps", func(db *gorm.DB) *gorm.DB {
 				return db.Order("seq asc")
+			}).
+			Preload("SlaViolations", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
 			})
 
 		if param.LoadTestKey != "" {
@@ -452,7 +479,41 @@ func (r *LoadRepository) GetPagingLoadTestExecutionHistoryTx(ctx context.Context
 			Preload("LoadTestExecutionState.Steps", func(db *gorm.DB) *gorm.DB {
 				return db.Order("seq asc")
 			}).
-			Preload("LoadTestExecutionHttpInfos").
+			Preload("LoadTestExecutionState.SlaViolations", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos", func(db *gorm.DB) *gorm.DB {
+				// request order is the scenario order; extracted variables only flow forward
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Extractors", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Assertions", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Fields", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Files", func(db *gorm.DB) *gorm.DB {
+				// a history page describes files, it does not need their content
+				return db.Omit("content").Order("id asc")
+			}).
+			Preload("UploadedFiles", func(db *gorm.DB) *gorm.DB {
+				return db.Omit("content").Order("id asc")
+			}).
+			Preload("DataSets", func(db *gorm.DB) *gorm.DB {
+				return db.Omit("content").Order("id asc")
+			}).
+			Preload("Targets", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("Regions", func(db *gorm.DB) *gorm.DB {
+				return db.Order("part asc")
+			}).
+			Preload("LoadStages", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
 			Preload("LoadGeneratorInstallInfo").
 			Preload("LoadGeneratorInstallInfo.LoadGeneratorServers").
 			Order("load_test_execution_infos.created_at desc")
@@ -482,7 +543,40 @@ func (r *LoadRepository) GetLoadTestExecutionInfoTx(ctx context.Context, param G
 			Preload("LoadTestExecutionState.Steps", func(db *gorm.DB) *gorm.DB {
 				return db.Order("seq asc")
 			}).
-			Preload("LoadTestExecutionHttpInfos").
+			Preload("LoadTestExecutionState.SlaViolations", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos", func(db *gorm.DB) *gorm.DB {
+				// request order is the scenario order; extracted variables only flow forward
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Extractors", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Assertions", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Fields", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("LoadTestExecutionHttpInfos.Files", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("UploadedFiles", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("DataSets", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("Targets", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
+			Preload("Regions", func(db *gorm.DB) *gorm.DB {
+				return db.Order("part asc")
+			}).
+			Preload("LoadStages", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id asc")
+			}).
 			Preload("LoadGeneratorInstallInfo").
 			Preload("LoadGeneratorInstallInfo.LoadGeneratorServers").
 			First(&loadTestExecutionInfo, "load_test_execution_infos.load_test_key = ?", param.LoadTestKey).
@@ -0,0 +1,63 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// The requests of a scenario run in order on every iteration unless they say otherwise. A
+// weight turns a request into part of a traffic mix and a think time spaces it out the way a
+// person would; both engines render the two the same way, from the helpers below.
+
+// withDefaultThinkTime gives every request that has no think time of its own the run's one.
+// The requests are copied, so the run's parameters keep saying what was asked for.
+func withDefaultThinkTime(httpReqs []RunLoadTestHttpParam, thinkTime *RunLoadTestThinkTimeParam) []RunLoadTestHttpParam {
+	reqs := make([]RunLoadTestHttpParam, len(httpReqs))
+	copy(reqs, httpReqs)
+	if thinkTime == nil {
+		return reqs
+	}
+	for i := range reqs {
+		if reqs[i].ThinkTime == nil {
+			reqs[i].ThinkTime = thinkTime
+		}
+	}
+	return reqs
+}
+
+// weightPercents returns for every request the percentage of iterations it is sent in, or 0
+// for a request without a weight, which is sent in all of them. Weights are relative, so
+// 80/15/5 and 16/3/1 make the same mix.
+func weightPercents(httpReqs []RunLoadTestHttpParam) []float64 {
+	total := 0
+	for _, req := range httpReqs {
+		if req.Weight > 0 {
+			total += req.Weight
+		}
+	}
+	percents := make([]float64, len(httpReqs))
+	for i, req := range httpReqs {
+		if req.Weight > 0 {
+			percents[i] = float64(req.Weight) * 100 / float64(total)
+		}
+	}
+	return percents
+}
+
+// ValidateThinkTime rejects a think time neither engine can render.
+func ValidateThinkTime(thinkTime *RunLoadTestThinkTimeParam) error {
+	if thinkTime == nil {
+		return nil
+	}
+	switch thinkTime.Type {
+	case constant.ConstantThinkTime, constant.UniformThinkTime, constant.GaussianThinkTime:
+	default:
+		return fmt.Errorf("think time type %q is not supported", thinkTime.Type)
+	}
+	if thinkTime.Delay < 0 || thinkTime.Range < 0 {
+		return errors.New("think time must not have a negative delay or range")
+	}
+	return nil
+}
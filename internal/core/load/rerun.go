@@ -0,0 +1,254 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// A run is stored with everything it was accepted with, down to the content of its files, so
+// it can be made again without the request that made it. The new run is a run of its own with
+// a key of its own; it names the run it was made from, so the runs of one scenario can be
+// followed from the first through every re-run.
+
+// ErrLoadTestNotFound is returned on re-running or comparing a run there is no record of.
+var ErrLoadTestNotFound = errors.New("load test not found")
+
+// RerunLoadTest makes a run again as it was stored, with the overrides of param, and returns
+// the key of the new run. The new run goes on the same load generator while it is still
+// there; otherwise one is installed as param.InstallLoadGenerator says.
+func (l *LoadService) RerunLoadTest(param RerunLoadTestParam) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	info, err := l.loadRepo.GetLoadTestExecutionInfoTx(ctx, GetLoadTestExecutionInfoParam{LoadTestKey: param.LoadTestKey})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("%w: %s", ErrLoadTestNotFound, param.LoadTestKey)
+		}
+		return "", err
+	}
+	state, err := l.loadRepo.GetLoadTestExecutionStateTx(ctx, GetLoadTestExecutionStateParam{LoadTestKey: param.LoadTestKey})
+	if err != nil {
+		return "", err
+	}
+
+	run := runParamOf(info)
+	run.LoadTestKey = ""
+	run.ParentLoadTestKey = info.LoadTestKey
+	run.ScenarioCatalogId = state.ScenarioCatalogId
+	if err := applyRerunOverrides(&run, param); err != nil {
+		return "", err
+	}
+	// an entry changed since is not what the run was of any more
+	if err := l.ValidateRunOfScenarioCatalog(ctx, run); err != nil {
+		log.Info().Msgf("re-run of load test %s is no longer of its scenario catalog entry; %v", info.LoadTestKey, err)
+		run.ScenarioCatalogId = 0
+	}
+
+	if run.LoadGeneratorInstallInfoId != 0 {
+		if _, err := l.loadRepo.GetValidLoadGeneratorInstallInfoByIdTx(ctx, run.LoadGeneratorInstallInfoId); err != nil {
+			log.Info().Msgf("load generator %d of load test %s is gone; installing one for its re-run", run.LoadGeneratorInstallInfoId, info.LoadTestKey)
+			run.LoadGeneratorInstallInfoId = 0
+		}
+	}
+	if run.LoadGeneratorInstallInfoId == 0 {
+		location := param.InstallLoadGenerator.InstallLocation
+		if location == "" {
+			location = info.LoadGeneratorInstallInfo.InstallLocation
+		}
+		if location != constant.Local && location != constant.Remote {
+			return "", fmt.Errorf("the load generator of load test %s is gone; give the install location of a new one", info.LoadTestKey)
+		}
+		run.InstallLoadGenerator = param.InstallLoadGenerator
+		run.InstallLoadGenerator.InstallLocation = location
+	}
+
+	return l.RunLoadTest(run)
+}
+
+// applyRerunOverrides changes what param overrides in run. A stages run is described by its
+// stages alone, so its users and duration cannot be overridden. A run with another load is no
+// longer a run of its scenario catalog entry, and is not held to the entry's baseline.
+func applyRerunOverrides(run *RunLoadTestParam, param RerunLoadTestParam) error {
+	if param.TestName != "" {
+		run.TestName = param.TestName
+	}
+	if param.VirtualUsers != "" || param.Duration != "" {
+		if run.LoadShape == constant.StagesShape {
+			return errors.New("a stages run is described by its stages; its virtual users and duration cannot be overridden")
+		}
+	}
+	if param.VirtualUsers != "" {
+		run.VirtualUsers = param.VirtualUsers
+	}
+	if param.Duration != "" {
+		run.Duration = param.Duration
+	}
+	if param.VirtualUsers != "" || param.Duration != "" {
+		run.ScenarioCatalogId = 0
+	}
+
+	target := param.NsId != "" || param.InfraId != "" || param.NodeId != ""
+	if target {
+		if param.NsId == "" || param.InfraId == "" || param.NodeId == "" {
+			return errors.New("a new target needs its nsId, infraId and nodeId together")
+		}
+		run.NsId, run.InfraId, run.NodeId = param.NsId, param.InfraId, param.NodeId
+		// the agent is looked up again on the new target
+		run.AgentHostname = ""
+	}
+	if param.Hostname != "" {
+		for i := range run.HttpReqs {
+			run.HttpReqs[i].Hostname = param.Hostname
+		}
+		for i := range run.Targets {
+			run.Targets[i].Hostname = param.Hostname
+		}
+	}
+	return nil
+}
+
+// runParamOf rebuilds a run from its stored info as it was accepted, files and all.
+func runParamOf(info LoadTestExecutionInfo) RunLoadTestParam {
+	param := RunLoadTestParam{
+		LoadTestKey:   info.LoadTestKey,
+		TestName:      info.TestName,
+		VirtualUsers:  info.VirtualUsers,
+		Duration:      info.Duration,
+		RampUpTime:    info.RampUpTime,
+		RampUpSteps:   info.RampUpSteps,
+		LoadShape:     info.LoadShape,
+		ArrivalRate:   info.ArrivalRate,
+		SpikeUsers:    info.SpikeUsers,
+		SpikeDuration: info.SpikeDuration,
+		ThinkTime:     info.ThinkTime.param(),
+		Sla:           info.Sla.param(),
+		AutoStop:      info.AutoStop.param(),
+		HttpClient:    info.HttpClient.param(),
+		NsId:          info.NsId,
+		InfraId:       info.InfraId,
+		NodeId:        info.NodeId,
+		AgentHostname: info.AgentHostname,
+		Engine:        info.Engine,
+
+		CollectAdditionalSystemMetrics: info.AgentInstalled,
+	}
+	if info.LoadGeneratorInstallInfoId != nil {
+		param.LoadGeneratorInstallInfoId = *info.LoadGeneratorInstallInfoId
+	}
+	for _, st := range info.LoadStages {
+		param.Stages = append(param.Stages, RunLoadTestStageParam{Target: st.Target, Duration: st.Duration})
+	}
+	for _, r := range info.Regions {
+		param.Regions = append(param.Regions, RunLoadTestRegionParam{Provider: r.Provider, Region: r.Region, Weight: r.Weight})
+	}
+	for _, h := range info.LoadTestExecutionHttpInfos {
+		param.HttpReqs = append(param.HttpReqs, httpParamOf(h))
+	}
+	for _, t := range info.Targets {
+		param.Targets = append(param.Targets, RunLoadTestTargetParam{
+			Protocol:      t.Protocol,
+			Hostname:      t.Hostname,
+			Port:          t.Port,
+			Timeout:       t.Timeout,
+			Path:          t.Path,
+			Secure:        t.Secure,
+			Message:       t.Message,
+			Method:        t.Method,
+			ProtoFileName: t.ProtoFileName,
+			ProtoFile:     t.ProtoFile,
+			EolByte:       t.EolByte,
+			Driver:        t.Driver,
+			Database:      t.Database,
+			Username:      t.Username,
+			Password:      t.Password,
+			Query:         t.Query,
+		})
+	}
+	for _, ds := range info.DataSets {
+		param.DataSets = append(param.DataSets, RunLoadTestDataSetParam{
+			DataSetId:  ds.DataSetId,
+			FileName:   ds.FileName,
+			Content:    ds.Content,
+			ShareMode:  ds.ShareMode,
+			Recycle:    ds.Recycle,
+			StopThread: ds.StopThread,
+		})
+	}
+
+	var dataFiles []RunLoadTestDataFileParam
+	for _, f := range info.UploadedFiles {
+		if f.IsTestPlan {
+			param.TestPlan = &RunLoadTestPlanParam{FileName: f.FileName, Content: f.Content}
+			continue
+		}
+		dataFiles = append(dataFiles, RunLoadTestDataFileParam{FileName: f.FileName, Content: f.Content})
+	}
+	if param.TestPlan != nil {
+		param.TestPlan.DataFiles = dataFiles
+	}
+	return param
+}
+
+// httpParamOf rebuilds a request as it was sent. Its client options were stored merged with
+// the run's, so the TLS settings, which only the run may have, are left to the run.
+func httpParamOf(h LoadTestExecutionHttpInfo) RunLoadTestHttpParam {
+	param := RunLoadTestHttpParam{
+		Method:     h.Method,
+		Protocol:   h.Protocol,
+		Hostname:   h.Hostname,
+		Port:       h.Port,
+		Path:       h.Path,
+		BodyData:   h.BodyData,
+		BodyType:   h.BodyType,
+		Weight:     h.Weight,
+		ThinkTime:  h.ThinkTime.param(),
+		HttpClient: h.HttpClient.param(),
+	}
+	if param.HttpClient != nil {
+		param.HttpClient.TlsVersion = ""
+		param.HttpClient.InsecureSkipVerify = nil
+	}
+	for _, f := range h.Fields {
+		field := RunLoadTestNameValueParam{Name: f.Name, Value: f.Value}
+		switch f.Kind {
+		case constant.HeaderField:
+			param.Headers = append(param.Headers, field)
+		case constant.QueryField:
+			param.QueryParams = append(param.QueryParams, field)
+		case constant.FormField:
+			param.FormParams = append(param.FormParams, field)
+		}
+	}
+	for _, f := range h.Files {
+		param.Files = append(param.Files, RunLoadTestFileParam{
+			ParamName: f.ParamName,
+			FileName:  f.FileName,
+			MimeType:  f.MimeType,
+			Content:   f.Content,
+		})
+	}
+	for _, e := range h.Extractors {
+		param.Extractors = append(param.Extractors, RunLoadTestExtractorParam{
+			Type:         e.Type,
+			VarName:      e.VarName,
+			Expression:   e.Expression,
+			MatchNo:      e.MatchNo,
+			DefaultValue: e.DefaultValue,
+		})
+	}
+	for _, a := range h.Assertions {
+		param.Assertions = append(param.Assertions, RunLoadTestAssertionParam{
+			Type:       a.Type,
+			Expression: a.Expression,
+			Value:      a.Value,
+		})
+	}
+	return param
+}
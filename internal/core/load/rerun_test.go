@@ -0,0 +1,88 @@
+package load
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestRunParamOfRebuildsTheRequestsAsSent(t *testing.T) {
+	param := runParamOf(LoadTestExecutionInfo{
+		LoadTestKey: "key",
+		HttpClient:  LoadTestHttpClient{ResponseTimeout: 60000, KeepAlive: true, TlsVersion: constant.Tls13},
+		LoadTestExecutionHttpInfos: []LoadTestExecutionHttpInfo{{
+			Method:   "POST",
+			Hostname: "10.0.0.1",
+			BodyType: constant.MultipartBody,
+			Fields: []LoadTestExecutionHttpField{
+				{Kind: constant.HeaderField, Name: "Authorization", Value: "Bearer ${token}"},
+				{Kind: constant.QueryField, Name: "page", Value: "1"},
+				{Kind: constant.FormField, Name: "name", Value: "ant"},
+			},
+			Files:      []LoadTestExecutionHttpFile{{ParamName: "file", FileName: "a.txt", Content: []byte("a")}},
+			Extractors: []LoadTestExecutionHttpExtractor{{Type: constant.JsonPathExtractor, VarName: "id", Expression: "$.id", MatchNo: 1}},
+			HttpClient: LoadTestHttpClient{ResponseTimeout: 1000, TlsVersion: constant.Tls13, InsecureSkipVerify: true},
+		}},
+		DataSets:      []LoadTestExecutionDataSet{{DataSetId: 3, FileName: "users.csv", Content: []byte("user\nant\n")}},
+		UploadedFiles: []LoadTestExecutionUploadedFile{{FileName: "plan.jmx", IsTestPlan: true, Content: []byte("<jmx/>")}, {FileName: "data.csv", Content: []byte("x\n")}},
+	})
+
+	if len(param.HttpReqs) != 1 {
+		t.Fatalf("expected the request of the run, got %+v", param.HttpReqs)
+	}
+	h := param.HttpReqs[0]
+	if len(h.Headers) != 1 || len(h.QueryParams) != 1 || len(h.FormParams) != 1 || h.Headers[0].Value != "Bearer ${token}" {
+		t.Errorf("expected the fields told apart by kind, got %+v %+v %+v", h.Headers, h.QueryParams, h.FormParams)
+	}
+	if len(h.Files) != 1 || string(h.Files[0].Content) != "a" || len(h.Extractors) != 1 {
+		t.Errorf("expected the files and extractors of the request, got %+v %+v", h.Files, h.Extractors)
+	}
+	if err := ValidateHttpClient(h.HttpClient, true); err != nil || h.HttpClient.ResponseTimeout != 1000 {
+		t.Errorf("expected the request's client options without the run's tls, got %+v; %v", h.HttpClient, err)
+	}
+	if param.HttpClient == nil || param.HttpClient.TlsVersion != constant.Tls13 {
+		t.Errorf("expected the run's client options, got %+v", param.HttpClient)
+	}
+	if len(param.DataSets) != 1 || string(param.DataSets[0].Content) != "user\nant\n" {
+		t.Errorf("expected the data set with its lines, got %+v", param.DataSets)
+	}
+	if param.TestPlan == nil || string(param.TestPlan.Content) != "<jmx/>" || len(param.TestPlan.DataFiles) != 1 {
+		t.Errorf("expected the uploaded plan with its data file, got %+v", param.TestPlan)
+	}
+}
+
+func TestRerunOverridesWhatIsGiven(t *testing.T) {
+	run := RunLoadTestParam{
+		TestName:          "checkout",
+		ScenarioCatalogId: 7,
+		VirtualUsers:      "10",
+		Duration:          "60",
+		NsId:              "ns",
+		InfraId:           "infra",
+		NodeId:            "node-1",
+		AgentHostname:     "10.0.0.1",
+		HttpReqs:          []RunLoadTestHttpParam{{Hostname: "10.0.0.1"}},
+		Targets:           []RunLoadTestTargetParam{{Hostname: "10.0.0.1"}},
+	}
+	err := applyRerunOverrides(&run, RerunLoadTestParam{VirtualUsers: "50", NsId: "ns", InfraId: "infra", NodeId: "node-2", Hostname: "10.0.0.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.TestName != "checkout" || run.VirtualUsers != "50" || run.Duration != "60" {
+		t.Errorf("expected only the users overridden, got %+v", run)
+	}
+	if run.ScenarioCatalogId != 0 {
+		t.Errorf("expected a run with other users no longer to be of its scenario catalog entry")
+	}
+	if run.NodeId != "node-2" || run.AgentHostname != "" || run.HttpReqs[0].Hostname != "10.0.0.2" || run.Targets[0].Hostname != "10.0.0.2" {
+		t.Errorf("expected the run moved to the new target, got %+v", run)
+	}
+
+	if err := applyRerunOverrides(&run, RerunLoadTestParam{NodeId: "node-3"}); err == nil {
+		t.Errorf("expected a target without its ns and infra to be refused")
+	}
+	stages := RunLoadTestParam{LoadShape: constant.StagesShape}
+	if err := applyRerunOverrides(&stages, RerunLoadTestParam{Duration: "30"}); err == nil {
+		t.Errorf("expected the duration of a stages run not to be overridden")
+	}
+}
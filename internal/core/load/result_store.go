@@ -0,0 +1,511 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/config"
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// The result of a run used to be read back from its raw csv files on every request, which is
+// slow for a long run and lost for good with the files. Once the files are in, the figures the
+// result APIs serve are worked out once and stored: the statistics of every label, of the run
+// as a whole and of each region it was sent from, the samples standing for each label in every
+// bucket of resultSampleIntervalMs, what the requests of each label in every such bucket add up
+// to, and the target's metric readings. The result APIs serve
+// these, falling back to the files for a run collected before there was a store. The files are
+// kept as an artifact unless load.result.discardRawFiles says otherwise.
+
+// resultSampleIntervalMs is the width of the time buckets a label's samples and figures are
+// reduced to.
+const resultSampleIntervalMs = 100
+
+// resultInsertBatchSize bounds the rows written in one statement.
+const resultInsertBatchSize = 500
+
+// persistResults stores the result of a run whose files have just been collected. A result
+// that cannot be stored is still served from its files, so it does not fail the collection.
+func (l *LoadService) persistResults(f *fetchDataParam) {
+	f.StepRec.begin(constant.SubPersist, "Storing results")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := l.readLoadTestResult(ctx, f)
+	if err == nil {
+		err = l.storeLoadTestResult(ctx, result)
+	}
+	if err != nil {
+		log.Error().Msgf("failed to store the result of %s; %v", f.LoadTestKey, err)
+		f.StepRec.fail(constant.SubPersist, "Results could not be stored",
+			fmt.Sprintf("%v; they are served from the result files instead", err))
+		return
+	}
+
+	f.StepRec.ok(constant.SubPersist, fmt.Sprintf("Results stored (%d statistics, %d samples, %d buckets, %d metric readings)",
+		len(result.Statistics), len(result.Samples), len(result.Buckets), len(result.Metrics)))
+}
+
+// readLoadTestResult reads the collected files of a run into the result to store.
+func (l *LoadService) readLoadTestResult(ctx context.Context, f *fetchDataParam) (LoadTestResult, error) {
+	resultFolderPath := utils.JoinRootPathWith("/result/" + f.LoadTestKey)
+	engine, err := engineFor(f.Engine)
+	if err != nil {
+		engine, _ = engineFor(constant.Jmeter)
+	}
+
+	digest, err := digestResult(engine, fmt.Sprintf("%s/%s_result.csv", resultFolderPath, f.LoadTestKey), resultSampleIntervalMs)
+	if err != nil {
+		return LoadTestResult{}, fmt.Errorf("reading the result; %w", err)
+	}
+	result := loadTestResultOf(f.LoadTestKey, digest)
+
+	// a run from several regions also keeps what each of them saw; one from a single region
+	// is that region's as a whole
+	executionInfo, err := l.loadRepo.GetLoadTestExecutionInfoTx(ctx, GetLoadTestExecutionInfoParam{LoadTestKey: f.LoadTestKey})
+	if err != nil {
+		log.Warn().Msgf("storing the result of %s without its regions; %v", f.LoadTestKey, err)
+	} else if len(executionInfo.Regions) > 1 {
+		for _, r := range executionInfo.Regions {
+			part, err := digestResult(engine, fmt.Sprintf("%s/%s", resultFolderPath, resultPartFileName(f.LoadTestKey, r.Part)), 0)
+			if err != nil {
+				log.Warn().Msgf("storing the result of %s without region %s; %v", f.LoadTestKey, regionKey(r.Provider, r.Region), err)
+				continue
+			}
+			result.Statistics = append(result.Statistics, statisticRowsOf(r.Provider, r.Region, part)...)
+		}
+	}
+
+	if f.CollectAdditionalSystemMetrics {
+		metrics := make(map[string][]*MetricsRawData)
+		for _, v := range []string{"cpu", "disk", "memory", "network"} {
+			// a metric file that never arrived leaves its charts empty, as it does when read
+			// from the files
+			toPath := fmt.Sprintf("%s/%s_%s_result.csv", resultFolderPath, f.LoadTestKey, v)
+			if m, err := appendMetricsRawData(metrics, toPath); err == nil && m != nil {
+				metrics = m
+			}
+		}
+		result.WithMetrics = true
+		result.Metrics = metricRowsOf(metrics)
+	}
+	return result, nil
+}
+
+// loadTestResultOf takes the statistics, the samples and the buckets of each label of a run
+// from its digest, and the statistics of all of them together.
+func loadTestResultOf(loadTestKey string, digest *resultDigest) LoadTestResult {
+	result := LoadTestResult{
+		LoadTestKey: loadTestKey,
+		Statistics:  statisticRowsOf("", "", digest),
+		Buckets:     bucketRowsOf(digest),
+	}
+	if total, ok := statisticRowOf("", "", comparisonTotalLabel, &digest.total); ok {
+		total.Total = true
+		result.Statistics = append(result.Statistics, total)
+	}
+	for _, s := range digest.summaries() {
+		for _, r := range s.Results {
+			result.Samples = append(result.Samples, LoadTestResultSample{
+				Label:      s.Label,
+				No:         r.No,
+				Elapsed:    r.Elapsed,
+				Bytes:      r.Bytes,
+				SentBytes:  r.SentBytes,
+				URL:        r.URL,
+				Latency:    r.Latency,
+				IdleTime:   r.IdleTime,
+				Connection: r.Connection,
+				IsError:    r.IsError,
+				Timestamp:  r.Timestamp,
+
+				ActiveThreads: r.ActiveThreads,
+			})
+		}
+	}
+	return result
+}
+
+// statisticRowsOf returns the statistics of every label of a digest, in label order.
+func statisticRowsOf(provider, region string, digest *resultDigest) []LoadTestResultStatistic {
+	var rows []LoadTestResultStatistic
+	for _, label := range digest.sortedLabels() {
+		if row, ok := statisticRowOf(provider, region, label, &digest.labels[label].statisticsAccumulator); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// statisticRowOf returns what was added up of label as its row; false when nothing was.
+func statisticRowOf(provider, region, label string, a *statisticsAccumulator) (LoadTestResultStatistic, bool) {
+	s := a.statistics(label)
+	if s == nil {
+		return LoadTestResultStatistic{}, false
+	}
+	return LoadTestResultStatistic{
+		Provider:      provider,
+		Region:        region,
+		Label:         s.Label,
+		RequestCount:  s.RequestCount,
+		Average:       s.Average,
+		Median:        s.Median,
+		NinetyPercent: s.NinetyPercent,
+		NinetyFive:    s.NinetyFive,
+		NinetyNine:    s.NinetyNine,
+		MinTime:       s.MinTime,
+		MaxTime:       s.MaxTime,
+		ErrorPercent:  s.ErrorPercent,
+		Throughput:    s.Throughput,
+		ReceivedKB:    s.ReceivedKB,
+		SentKB:        s.SentKB,
+		ErrorCount:    a.errorCount,
+		SpanSeconds:   a.spanSeconds(),
+	}, true
+}
+
+// bucketRowsOf returns the buckets of every label of a digest, in label and then time order.
+func bucketRowsOf(digest *resultDigest) []LoadTestResultBucket {
+	var rows []LoadTestResultBucket
+	for _, label := range digest.sortedLabels() {
+		ld := digest.labels[label]
+		for _, start := range ld.sortedBuckets() {
+			b := ld.buckets[start]
+			rows = append(rows, LoadTestResultBucket{
+				Label:         label,
+				At:            time.UnixMilli(start),
+				Requests:      b.requests,
+				Errors:        b.errors,
+				TotalElapsed:  b.totalElapsed,
+				MinElapsed:    b.minElapsed,
+				MaxElapsed:    b.maxElapsed,
+				Bytes:         b.bytes,
+				SentBytes:     b.sentBytes,
+				ActiveThreads: b.activeThreads,
+				Elapsed:       b.elapsed.encode(),
+			})
+		}
+	}
+	return rows
+}
+
+func metricRowsOf(metrics map[string][]*MetricsRawData) []LoadTestResultMetric {
+	labels := make([]string, 0, len(metrics))
+	for label := range metrics {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var rows []LoadTestResultMetric
+	for _, label := range labels {
+		for _, m := range metrics[label] {
+			rows = append(rows, LoadTestResultMetric{
+				Label:     label,
+				Value:     m.Value,
+				Unit:      m.Unit,
+				IsError:   m.IsError,
+				Timestamp: m.Timestamp,
+			})
+		}
+	}
+	return rows
+}
+
+// storeLoadTestResult writes the result of a run in place of any stored before, which a run
+// resumed after a restart may have.
+func (l *LoadService) storeLoadTestResult(ctx context.Context, result LoadTestResult) error {
+	return l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []LoadTestResult
+		if err := tx.Unscoped().Where("load_test_key = ?", result.LoadTestKey).Find(&existing).Error; err != nil {
+			return err
+		}
+		for _, e := range existing {
+			for _, child := range []any{&LoadTestResultStatistic{}, &LoadTestResultSample{}, &LoadTestResultBucket{}, &LoadTestResultMetric{}} {
+				if err := tx.Unscoped().Where("load_test_result_id = ?", e.ID).Delete(child).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Unscoped().Delete(&e).Error; err != nil {
+				return err
+			}
+		}
+
+		header := LoadTestResult{LoadTestKey: result.LoadTestKey, WithMetrics: result.WithMetrics}
+		if err := tx.Create(&header).Error; err != nil {
+			return err
+		}
+		for i := range result.Statistics {
+			result.Statistics[i].LoadTestResultId = header.ID
+		}
+		for i := range result.Samples {
+			result.Samples[i].LoadTestResultId = header.ID
+		}
+		for i := range result.Buckets {
+			result.Buckets[i].LoadTestResultId = header.ID
+		}
+		for i := range result.Metrics {
+			result.Metrics[i].LoadTestResultId = header.ID
+		}
+		if len(result.Statistics) > 0 {
+			if err := tx.CreateInBatches(result.Statistics, resultInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(result.Samples) > 0 {
+			if err := tx.CreateInBatches(result.Samples, resultInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(result.Buckets) > 0 {
+			if err := tx.CreateInBatches(result.Buckets, resultInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(result.Metrics) > 0 {
+			if err := tx.CreateInBatches(result.Metrics, resultInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// storedLoadTestResult returns the stored result of a run, without its rows.
+func (l *LoadService) storedLoadTestResult(ctx context.Context, loadTestKey string) (LoadTestResult, bool, error) {
+	var result LoadTestResult
+	err := l.db.WithContext(ctx).Where("load_test_key = ? AND deleted_at IS NULL", loadTestKey).First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, false, nil
+		}
+		return result, false, err
+	}
+	return result, true, nil
+}
+
+// storedResultOf returns the stored result of a run in the format asked for, or false for a
+// run that has none. A store that cannot be read is told as none, so the files are read.
+func (l *LoadService) storedResultOf(ctx context.Context, loadTestKey string, format constant.ResultFormat) (any, bool) {
+	stored, found, err := l.storedLoadTestResult(ctx, loadTestKey)
+	if err != nil {
+		log.Warn().Msgf("could not read the stored result of %s, reading its files; %v", loadTestKey, err)
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	if format == constant.Aggregate {
+		rows, err := l.storedStatistics(ctx, stored.ID)
+		if err != nil {
+			log.Warn().Msgf("could not read the stored statistics of %s, reading its files; %v", loadTestKey, err)
+			return nil, false
+		}
+		var statistics []*LoadTestStatistics
+		for _, row := range rows {
+			if row.Provider == "" && row.Region == "" && !row.Total {
+				statistics = append(statistics, toLoadTestStatistics(row))
+			}
+		}
+		return statistics, true
+	}
+
+	summaries, err := l.storedSamples(ctx, stored.ID)
+	if err != nil {
+		log.Warn().Msgf("could not read the stored samples of %s, reading its files; %v", loadTestKey, err)
+		return nil, false
+	}
+	if summaries == nil {
+		// as the files answer for a run without a single sample
+		return nil, true
+	}
+	return summaries, true
+}
+
+// storedRegionResultOf returns the stored statistics of each region of a run, in the order the
+// regions were given, or false for a run that has none stored. A run from a single region is
+// that region's as a whole.
+func (l *LoadService) storedRegionResultOf(ctx context.Context, loadTestKey string, regions []LoadTestExecutionRegion) ([]LoadTestRegionResult, bool) {
+	stored, found, err := l.storedLoadTestResult(ctx, loadTestKey)
+	if err != nil || !found {
+		return nil, false
+	}
+	rows, err := l.storedStatistics(ctx, stored.ID)
+	if err != nil {
+		log.Warn().Msgf("could not read the stored statistics of %s, reading its files; %v", loadTestKey, err)
+		return nil, false
+	}
+
+	byRegion := make(map[string][]*LoadTestStatistics)
+	for _, row := range rows {
+		if row.Total {
+			continue
+		}
+		key := ""
+		if row.Provider != "" || row.Region != "" {
+			key = regionKey(row.Provider, row.Region)
+		}
+		byRegion[key] = append(byRegion[key], toLoadTestStatistics(row))
+	}
+
+	regionResults := make([]LoadTestRegionResult, 0, len(regions))
+	for _, r := range regions {
+		key := ""
+		if len(regions) > 1 {
+			key = regionKey(r.Provider, r.Region)
+		}
+		statistics, ok := byRegion[key]
+		if !ok {
+			// a region whose part could not be read when the result was stored
+			return nil, false
+		}
+		regionResults = append(regionResults, LoadTestRegionResult{
+			Provider:     r.Provider,
+			Region:       r.Region,
+			Weight:       r.Weight,
+			VirtualUsers: r.VirtualUsers,
+			Statistics:   statistics,
+		})
+	}
+	return regionResults, true
+}
+
+// storedMetricsOf returns the stored metric readings of a run by label, or false for a run that
+// has none stored.
+func (l *LoadService) storedMetricsOf(ctx context.Context, loadTestKey string) ([]MetricsSummary, bool) {
+	stored, found, err := l.storedLoadTestResult(ctx, loadTestKey)
+	if err != nil || !found || !stored.WithMetrics {
+		return nil, false
+	}
+	metrics, err := l.storedMetrics(ctx, stored.ID)
+	if err != nil {
+		log.Warn().Msgf("could not read the stored metrics of %s, reading its files; %v", loadTestKey, err)
+		return nil, false
+	}
+
+	labels := make([]string, 0, len(metrics))
+	for label := range metrics {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var metricsSummaries []MetricsSummary
+	for _, label := range labels {
+		metricsSummaries = append(metricsSummaries, MetricsSummary{Label: label, Metrics: metrics[label]})
+	}
+	return metricsSummaries, true
+}
+
+func (l *LoadService) storedStatistics(ctx context.Context, resultId uint) ([]LoadTestResultStatistic, error) {
+	var rows []LoadTestResultStatistic
+	err := l.db.WithContext(ctx).
+		Where("load_test_result_id = ? AND deleted_at IS NULL", resultId).
+		Order("label ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// storedSamples returns the stored samples of a run by label, in label order.
+func (l *LoadService) storedSamples(ctx context.Context, resultId uint) ([]*ResultSummary, error) {
+	var rows []LoadTestResultSample
+	err := l.db.WithContext(ctx).
+		Where("load_test_result_id = ? AND deleted_at IS NULL", resultId).
+		Order("label ASC, timestamp ASC, id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return summariesOfSamples(rows), nil
+}
+
+// storedMetrics returns the stored metric readings of a run by label.
+func (l *LoadService) storedMetrics(ctx context.Context, resultId uint) (map[string][]*MetricsRawData, error) {
+	var rows []LoadTestResultMetric
+	err := l.db.WithContext(ctx).
+		Where("load_test_result_id = ? AND deleted_at IS NULL", resultId).
+		Order("label ASC, id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	metrics := make(map[string][]*MetricsRawData)
+	for _, row := range rows {
+		metrics[row.Label] = append(metrics[row.Label], &MetricsRawData{
+			Value:     row.Value,
+			Unit:      row.Unit,
+			IsError:   row.IsError,
+			Timestamp: row.Timestamp,
+		})
+	}
+	return metrics, nil
+}
+
+// summariesOfSamples groups samples read in label order back into the results of each label.
+func summariesOfSamples(rows []LoadTestResultSample) []*ResultSummary {
+	var summaries []*ResultSummary
+	for _, row := range rows {
+		if len(summaries) == 0 || summaries[len(summaries)-1].Label != row.Label {
+			summaries = append(summaries, &ResultSummary{Label: row.Label})
+		}
+		last := summaries[len(summaries)-1]
+		last.Results = append(last.Results, &ResultRawData{
+			No:         row.No,
+			Elapsed:    row.Elapsed,
+			Bytes:      row.Bytes,
+			SentBytes:  row.SentBytes,
+			URL:        row.URL,
+			Latency:    row.Latency,
+			IdleTime:   row.IdleTime,
+			Connection: row.Connection,
+			IsError:    row.IsError,
+			Timestamp:  row.Timestamp,
+
+			ActiveThreads: row.ActiveThreads,
+		})
+	}
+	return summaries
+}
+
+func toLoadTestStatistics(row LoadTestResultStatistic) *LoadTestStatistics {
+	return &LoadTestStatistics{
+		Label:         row.Label,
+		RequestCount:  row.RequestCount,
+		Average:       row.Average,
+		Median:        row.Median,
+		NinetyPercent: row.NinetyPercent,
+		NinetyFive:    row.NinetyFive,
+		NinetyNine:    row.NinetyNine,
+		MinTime:       row.MinTime,
+		MaxTime:       row.MaxTime,
+		ErrorPercent:  row.ErrorPercent,
+		Throughput:    row.Throughput,
+		ReceivedKB:    row.ReceivedKB,
+		SentKB:        row.SentKB,
+	}
+}
+
+// discardRawResults removes the result files of a run once its result is stored, when the
+// configuration says they are not to be kept. It is done only after the run is judged, since
+// the sla and the baseline are judged from the files.
+func (l *LoadService) discardRawResults(ctx context.Context, loadTestKey string) {
+	if !config.AppConfig.Load.Result.DiscardRawFiles {
+		return
+	}
+	if _, found, err := l.storedLoadTestResult(ctx, loadTestKey); err != nil || !found {
+		// a result that is not stored is only in its files
+		return
+	}
+	if err := os.RemoveAll(utils.JoinRootPathWith("/result/" + loadTestKey)); err != nil {
+		log.Warn().Msgf("could not remove the result files of %s; %v", loadTestKey, err)
+		return
+	}
+	log.Info().Msgf("removed the result files of %s, its result is served from the store", loadTestKey)
+}
@@ -0,0 +1,78 @@
+package load
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStoredResultIsWhatTheFilesServe(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	results := map[string][]*ResultRawData{
+		"login": comparisonSamples(start, repeated(100, 30), 3),
+		"list":  comparisonSamples(start, repeated(50, 20), 0),
+	}
+
+	stored := loadTestResultOf("a", digestOf(t, results, resultSampleIntervalMs))
+	if len(stored.Statistics) != 3 || stored.Statistics[0].Label != "list" || stored.Statistics[1].Label != "login" {
+		t.Fatalf("expected the statistics of both labels in order and the total, got %+v", stored.Statistics)
+	}
+	if s := stored.Statistics[1]; s.Provider != "" || s.Region != "" || s.RequestCount != 30 || s.ErrorPercent != 10 || s.ErrorCount != 3 || s.Total {
+		t.Errorf("expected login as a whole with 10%% errors, got %+v", s)
+	}
+	if s := stored.Statistics[2]; !s.Total || s.RequestCount != 50 || s.SpanSeconds != 2.9 {
+		t.Errorf("expected all of the labels together last, got %+v", s)
+	}
+	// a sample every 100 ms is one per bucket already
+	if len(stored.Samples) != 50 {
+		t.Errorf("expected a sample per bucket of each label, got %d", len(stored.Samples))
+	}
+
+	if len(stored.Buckets) != 50 || stored.Buckets[0].Label != "list" || stored.Buckets[20].Label != "login" {
+		t.Fatalf("expected a bucket per 100 ms of each label in label order, got %d", len(stored.Buckets))
+	}
+	if b := stored.Buckets[20]; b.Requests != 1 || b.Errors != 1 || b.TotalElapsed != 100 || b.MinElapsed != 100 || !b.At.Equal(start) {
+		t.Errorf("expected the first login bucket to add up its one failed request, got %+v", b)
+	}
+
+	summaries := summariesOfSamples(stored.Samples)
+	if len(summaries) != 2 || summaries[0].Label != "list" || len(summaries[0].Results) != 20 || len(summaries[1].Results) != 30 {
+		t.Fatalf("expected the samples grouped back by label, got %+v", summaries)
+	}
+	if got := summaries[1].Results[0]; got.Elapsed != 100 || !got.IsError || !got.Timestamp.Equal(start) {
+		t.Errorf("expected the first login sample back as it was, got %+v", got)
+	}
+
+	if got := toLoadTestStatistics(stored.Statistics[1]); got.Label != "login" || got.RequestCount != 30 || got.Average != stored.Statistics[1].Average {
+		t.Errorf("expected the statistics back as they were, got %+v", got)
+	}
+}
+
+func TestStoredMetricsAreInLabelOrder(t *testing.T) {
+	at := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	rows := metricRowsOf(map[string][]*MetricsRawData{
+		"disk_use":         {{Value: "50", Unit: "%", Timestamp: at}},
+		"cpu_all_combined": {{Value: "40", Unit: "%", Timestamp: at}, {Value: "41", Unit: "%", Timestamp: at.Add(time.Second)}},
+	})
+	if len(rows) != 3 || rows[0].Label != "cpu_all_combined" || rows[1].Value != "41" || rows[2].Label != "disk_use" {
+		t.Errorf("expected the readings by label in order, got %+v", rows)
+	}
+}
+
+func TestStoredHistogramReadsBack(t *testing.T) {
+	h := elapsedHistogram{significantBits: timeSeriesSignificantBits}
+	for _, v := range []int{0, 3, 3, 127, 128, 5000, 5000, 90000} {
+		h.add(v)
+	}
+
+	back := elapsedHistogram{significantBits: timeSeriesSignificantBits}
+	if err := back.decode(h.encode()); err != nil {
+		t.Fatalf("expected the histogram to read back, got %v", err)
+	}
+	if back.total != h.total || !slices.Equal(back.valuesAt(0, 2, 4, 7), h.valuesAt(0, 2, 4, 7)) {
+		t.Errorf("expected the same counts back, got %v for %v", back.valuesAt(0, 2, 4, 7), h.valuesAt(0, 2, 4, 7))
+	}
+	if err := back.decode([]byte{0x80}); err == nil {
+		t.Error("expected a cut short histogram to be refused")
+	}
+}
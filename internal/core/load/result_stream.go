@@ -0,0 +1,414 @@
+package load
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+// An hour of a busy run leaves a result file of several gigabytes, which used to be read whole
+// into memory, turned into a row object per request, and then copied into a list of every
+// response time per label to take its percentiles. The result is now read a row at a time:
+// the statistics of a label are added up as its rows go by, its response times are counted in
+// an elapsedHistogram rather than kept, and its samples are picked per time bucket. What a
+// result takes in memory grows with its labels and its duration, not with its requests.
+
+const (
+	// elapsedExactLimit is the response time, in ms, up to which an elapsedHistogram counts
+	// every value on its own.
+	elapsedExactLimit = 1 << elapsedSignificantBits
+	// elapsedSignificantBits are the bits of a response time an elapsedHistogram keeps above
+	// elapsedExactLimit, which puts any percentile within 0.1% of the exact one.
+	elapsedSignificantBits = 11
+)
+
+// elapsedHistogram counts response times in buckets that are one millisecond wide up to
+// elapsedExactLimit and grow with the value above it, in the manner of an HDR histogram.
+// Percentiles read from it are exact for response times below the limit, which is nearly all
+// of them, however many requests there were.
+type elapsedHistogram struct {
+	// significantBits are the bits of a response time its buckets keep; 0 is
+	// elapsedSignificantBits, below whose limit values are counted in a dense slice.
+	significantBits int
+	exact           []int       // by value, below elapsedExactLimit
+	above           map[int]int // by the lowest value of the bucket
+	total           int
+}
+
+// bucketOf is the lowest value of the bucket v is counted in.
+func (h *elapsedHistogram) bucketOf(v int) int {
+	significant := h.significantBits
+	if significant == 0 {
+		significant = elapsedSignificantBits
+	}
+	if v < 1<<significant {
+		return max(v, 0)
+	}
+	shift := bits.Len(uint(v)) - significant
+	return v >> shift << shift
+}
+
+// dense tells whether bucket is counted in the dense slice.
+func (h *elapsedHistogram) dense(bucket int) bool {
+	return h.significantBits == 0 && bucket < elapsedExactLimit
+}
+
+func (h *elapsedHistogram) addCount(bucket, count int) {
+	if h.dense(bucket) {
+		if h.exact == nil {
+			h.exact = make([]int, elapsedExactLimit)
+		}
+		h.exact[bucket] += count
+	} else {
+		if h.above == nil {
+			h.above = make(map[int]int)
+		}
+		h.above[bucket] += count
+	}
+	h.total += count
+}
+
+func (h *elapsedHistogram) add(v int) {
+	h.addCount(h.bucketOf(v), 1)
+}
+
+func (h *elapsedHistogram) merge(o *elapsedHistogram) {
+	for _, bucket := range o.buckets() {
+		h.addCount(h.bucketOf(bucket), o.countOf(bucket))
+	}
+}
+
+// countOf is the count of the bucket starting at bucket.
+func (h *elapsedHistogram) countOf(bucket int) int {
+	if h.dense(bucket) {
+		if h.exact == nil {
+			return 0
+		}
+		return h.exact[bucket]
+	}
+	return h.above[bucket]
+}
+
+// buckets returns the buckets that have a count, in order.
+func (h *elapsedHistogram) buckets() []int {
+	var buckets []int
+	for v, count := range h.exact {
+		if count > 0 {
+			buckets = append(buckets, v)
+		}
+	}
+	above := make([]int, 0, len(h.above))
+	for bucket := range h.above {
+		above = append(above, bucket)
+	}
+	sort.Ints(above)
+	return append(buckets, above...)
+}
+
+// errMalformedHistogram is returned for a stored histogram that cannot be read back.
+var errMalformedHistogram = errors.New("malformed elapsed histogram")
+
+// encode returns the counts of the histogram to be stored, as pairs of the step from the
+// previous bucket and the count, each a uvarint.
+func (h *elapsedHistogram) encode() []byte {
+	var buf []byte
+	previous := 0
+	for _, bucket := range h.buckets() {
+		buf = binary.AppendUvarint(buf, uint64(bucket-previous))
+		buf = binary.AppendUvarint(buf, uint64(h.countOf(bucket)))
+		previous = bucket
+	}
+	return buf
+}
+
+// decode adds the counts of an encoded histogram to h.
+func (h *elapsedHistogram) decode(buf []byte) error {
+	bucket := 0
+	for len(buf) > 0 {
+		step, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return errMalformedHistogram
+		}
+		buf = buf[n:]
+		count, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return errMalformedHistogram
+		}
+		buf = buf[n:]
+		bucket += int(step)
+		h.addCount(h.bucketOf(bucket), int(count))
+	}
+	return nil
+}
+
+// valuesAt returns the value at each of the 0-based ranks, which are in order, as a sorted list
+// of every value counted would have it.
+func (h *elapsedHistogram) valuesAt(ranks ...int) []int {
+	values := make([]int, len(ranks))
+	seen, next := 0, 0
+	for _, bucket := range h.buckets() {
+		seen += h.countOf(bucket)
+		for next < len(ranks) && ranks[next] < seen {
+			values[next] = bucket
+			next++
+		}
+	}
+	return values
+}
+
+// statisticsAccumulator adds up the statistics of a label one request at a time.
+type statisticsAccumulator struct {
+	requestCount   int
+	errorCount     int
+	totalElapsed   int // of the requests that did not fail
+	totalBytes     int
+	totalSentBytes int
+	minElapsed     int
+	maxElapsed     int
+	start, end     time.Time
+	elapsed        elapsedHistogram
+}
+
+func (a *statisticsAccumulator) add(r *ResultRawData) {
+	if a.requestCount == 0 || r.Timestamp.Before(a.start) {
+		a.start = r.Timestamp
+	}
+	if a.requestCount == 0 || r.Timestamp.After(a.end) {
+		a.end = r.Timestamp
+	}
+	if a.requestCount == 0 || r.Elapsed < a.minElapsed {
+		a.minElapsed = r.Elapsed
+	}
+	if a.requestCount == 0 || r.Elapsed > a.maxElapsed {
+		a.maxElapsed = r.Elapsed
+	}
+
+	a.requestCount++
+	if r.IsError {
+		a.errorCount++
+	} else {
+		a.totalElapsed += r.Elapsed
+	}
+	a.totalBytes += r.Bytes
+	a.totalSentBytes += r.SentBytes
+	a.elapsed.add(r.Elapsed)
+}
+
+func (a *statisticsAccumulator) merge(o *statisticsAccumulator) {
+	if o.requestCount == 0 {
+		return
+	}
+	if a.requestCount == 0 {
+		a.start, a.end, a.minElapsed, a.maxElapsed = o.start, o.end, o.minElapsed, o.maxElapsed
+	} else {
+		if o.start.Before(a.start) {
+			a.start = o.start
+		}
+		if o.end.After(a.end) {
+			a.end = o.end
+		}
+		a.minElapsed = min(a.minElapsed, o.minElapsed)
+		a.maxElapsed = max(a.maxElapsed, o.maxElapsed)
+	}
+	a.requestCount += o.requestCount
+	a.errorCount += o.errorCount
+	a.totalElapsed += o.totalElapsed
+	a.totalBytes += o.totalBytes
+	a.totalSentBytes += o.totalSentBytes
+	a.elapsed.merge(&o.elapsed)
+}
+
+// spanSeconds is the time from the first request to the last, which throughput is taken over.
+func (a *statisticsAccumulator) spanSeconds() float64 {
+	return a.end.Sub(a.start).Seconds()
+}
+
+// percentile is the response time at or below which the share p of the requests fall.
+func (a *statisticsAccumulator) percentile(p float64) float64 {
+	return a.clamped(a.elapsed.valuesAt(int(math.Ceil(float64(a.requestCount)*p)) - 1)[0])
+}
+
+// clamped keeps a value read from a bucket within the response times that were seen.
+func (a *statisticsAccumulator) clamped(v int) float64 {
+	return float64(min(max(v, a.minElapsed), a.maxElapsed))
+}
+
+// statistics returns what was added up as the statistics of label; nil when nothing was.
+func (a *statisticsAccumulator) statistics(label string) *LoadTestStatistics {
+	if a.requestCount == 0 {
+		return nil
+	}
+
+	n := a.requestCount
+	var median float64
+	if n%2 == 0 {
+		middle := a.elapsed.valuesAt(n/2-1, n/2)
+		median = (a.clamped(middle[0]) + a.clamped(middle[1])) / 2
+	} else {
+		median = a.clamped(a.elapsed.valuesAt(n / 2)[0])
+	}
+
+	// total elapsed time and running time are different
+	runningTime := int(a.end.Sub(a.start).Milliseconds())
+
+	return &LoadTestStatistics{
+		Label:         label,
+		RequestCount:  n,
+		Average:       float64(a.totalElapsed) / float64(n),
+		Median:        median,
+		NinetyPercent: a.percentile(0.9),
+		NinetyFive:    a.percentile(0.95),
+		NinetyNine:    a.percentile(0.99),
+		MinTime:       float64(a.minElapsed),
+		MaxTime:       float64(a.maxElapsed),
+		ErrorPercent:  calculateErrorPercent(a.errorCount, n),
+		Throughput:    calculateThroughput(n, runningTime),
+		ReceivedKB:    calculateReceivedKBPerSec(a.totalBytes, runningTime),
+		SentKB:        calculateSentKBPerSec(a.totalSentBytes, runningTime),
+	}
+}
+
+// sampleBucket is one time bucket of a label: what its requests add up to, and the request
+// standing for them, which is the one whose response time is closest to their average, the
+// first of them on a tie.
+type sampleBucket struct {
+	seriesBucket
+	sample *ResultRawData
+}
+
+// labelDigest is what is kept of one label of a result.
+type labelDigest struct {
+	statisticsAccumulator
+	buckets map[int64]*sampleBucket // by the start of the bucket in unix ms; nil when not sampled
+}
+
+// sortedBuckets returns the starts of the label's buckets in order.
+func (ld *labelDigest) sortedBuckets() []int64 {
+	starts := make([]int64, 0, len(ld.buckets))
+	for start := range ld.buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	return starts
+}
+
+// resultDigest is what is kept of a result read a row at a time: the statistics of every
+// label and of all of them together, and, when sampled, what the requests of each label in
+// every bucket of intervalMs add up to and a request standing for them.
+type resultDigest struct {
+	intervalMs int
+	labels     map[string]*labelDigest
+	total      statisticsAccumulator
+}
+
+func newResultDigest(intervalMs int) *resultDigest {
+	return &resultDigest{intervalMs: intervalMs, labels: make(map[string]*labelDigest)}
+}
+
+// bucketOf is the start of the bucket r falls in.
+func (d *resultDigest) bucketOf(r *ResultRawData) int64 {
+	at := r.Timestamp.UnixMilli()
+	return at - ((at%int64(d.intervalMs))+int64(d.intervalMs))%int64(d.intervalMs)
+}
+
+// add takes a request into the statistics, and into its bucket when sampled.
+func (d *resultDigest) add(label string, r *ResultRawData) {
+	ld, ok := d.labels[label]
+	if !ok {
+		ld = &labelDigest{}
+		if d.intervalMs > 0 {
+			ld.buckets = make(map[int64]*sampleBucket)
+		}
+		d.labels[label] = ld
+	}
+	ld.add(r)
+	d.total.add(r)
+
+	if ld.buckets == nil {
+		return
+	}
+	b, ok := ld.buckets[d.bucketOf(r)]
+	if !ok {
+		b = &sampleBucket{seriesBucket: *newSeriesBucket()}
+		ld.buckets[d.bucketOf(r)] = b
+	}
+	b.seriesBucket.add(r)
+}
+
+// pick offers a request, once every request has been added, as the sample of its bucket.
+func (d *resultDigest) pick(label string, r *ResultRawData) {
+	ld, ok := d.labels[label]
+	if !ok || ld.buckets == nil {
+		return
+	}
+	b, ok := ld.buckets[d.bucketOf(r)]
+	if !ok {
+		return
+	}
+	average := b.totalElapsed / b.requests
+	if b.sample == nil || abs(r.Elapsed-average) < abs(b.sample.Elapsed-average) {
+		b.sample = r
+	}
+}
+
+// sortedLabels returns the labels of the result in order.
+func (d *resultDigest) sortedLabels() []string {
+	labels := make([]string, 0, len(d.labels))
+	for label := range d.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// statistics returns the statistics of every label, in label order.
+func (d *resultDigest) statistics() []*LoadTestStatistics {
+	var statistics []*LoadTestStatistics
+	for _, label := range d.sortedLabels() {
+		if s := d.labels[label].statistics(label); s != nil {
+			statistics = append(statistics, s)
+		}
+	}
+	return statistics
+}
+
+// summaries returns the samples of every label in time order, in label order.
+func (d *resultDigest) summaries() []*ResultSummary {
+	var summaries []*ResultSummary
+	for _, label := range d.sortedLabels() {
+		ld := d.labels[label]
+		summary := &ResultSummary{Label: label}
+		for _, start := range ld.sortedBuckets() {
+			if s := ld.buckets[start].sample; s != nil {
+				summary.Results = append(summary.Results, s)
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// digestResult reads the result file of a run a row at a time. With an interval it is read a
+// second time, to pick the sample of each bucket once the bucket's average is known.
+func digestResult(engine loadEngine, filePath string, intervalMs int) (*resultDigest, error) {
+	d := newResultDigest(intervalMs)
+	if err := engine.ScanResult(filePath, d.add); err != nil {
+		return nil, err
+	}
+	if intervalMs > 0 {
+		if err := engine.ScanResult(filePath, d.pick); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
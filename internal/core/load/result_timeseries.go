@@ -0,0 +1,410 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// The normal format hands over a request standing for each label in every 100 ms, which the
+// console then has to add up itself to draw a chart, and which tells nothing of throughput.
+// The timeseries format adds the requests of a run up per bucket of a width the caller
+// chooses, within a window of the run, into the figures a throughput or latency chart draws
+// as they are. It is drawn from the buckets of resultSampleIntervalMs stored with the result,
+// which add up to any interval and window in whole multiples of them; the raw result file is
+// read a row at a time only for a run stored without them or a window that splits them. What
+// it keeps grows with its buckets, which are bounded, not with the requests.
+
+const (
+	defaultTimeSeriesIntervalMs = 1000
+	minTimeSeriesIntervalMs     = 100
+	// maxTimeSeriesBuckets bounds the buckets of each label a series may have.
+	maxTimeSeriesBuckets = 3600
+	// timeSeriesSignificantBits are the bits of a response time the buckets of a series keep,
+	// which puts their percentiles within 1% of the exact ones.
+	timeSeriesSignificantBits = 7
+)
+
+// ErrInvalidTimeSeries is returned for a time series that cannot be drawn as asked, so the
+// handler can answer with the caller's mistake rather than a server error.
+var ErrInvalidTimeSeries = errors.New("time series is not valid")
+
+// ErrRawResultUnavailable is returned for a time series of a run whose raw result file is not
+// there, because it never came in or was discarded once the result was stored.
+var ErrRawResultUnavailable = errors.New("raw result of the load test is not available")
+
+// ValidateTimeSeriesWindow checks the window of a time series before the result is read. An
+// interval of 0 is the default one.
+func ValidateTimeSeriesWindow(w TimeSeriesWindow) error {
+	w = timeSeriesWindowOf(w)
+	if w.IntervalMs < minTimeSeriesIntervalMs {
+		return fmt.Errorf("time series interval must be at least %d ms", minTimeSeriesIntervalMs)
+	}
+	if w.From.IsZero() || w.To.IsZero() {
+		return nil
+	}
+	if !w.To.After(w.From) {
+		return errors.New("time series window must end after it starts")
+	}
+	if n := bucketsBetween(w.From.UnixMilli(), w.To.UnixMilli(), w.IntervalMs); n > maxTimeSeriesBuckets {
+		return fmt.Errorf("a window of %s in buckets of %d ms has %d buckets, more than %d; choose a larger interval or a narrower window",
+			w.To.Sub(w.From), w.IntervalMs, n, maxTimeSeriesBuckets)
+	}
+	return nil
+}
+
+// timeSeriesWindowOf is w with the default interval when it has none.
+func timeSeriesWindowOf(w TimeSeriesWindow) TimeSeriesWindow {
+	if w.IntervalMs == 0 {
+		w.IntervalMs = defaultTimeSeriesIntervalMs
+	}
+	return w
+}
+
+// bucketsBetween is how many buckets of intervalMs it takes to cover from up to to.
+func bucketsBetween(from, to int64, intervalMs int) int64 {
+	return (to - from + int64(intervalMs) - 1) / int64(intervalMs)
+}
+
+// storedBucketsFit tells whether the stored buckets of a run add up to the buckets of window,
+// which they do when its interval and its bounds are whole multiples of them.
+func storedBucketsFit(window TimeSeriesWindow) bool {
+	aligned := func(t time.Time) bool { return t.IsZero() || t.UnixMilli()%resultSampleIntervalMs == 0 }
+	return window.IntervalMs%resultSampleIntervalMs == 0 && aligned(window.From) && aligned(window.To)
+}
+
+// timeSeriesOf returns the time series of a run, from its stored buckets when they fit the
+// window, or else from its result file. A store that cannot be read is told as none, so the
+// file is read.
+func (l *LoadService) timeSeriesOf(ctx context.Context, engine loadEngine, loadTestKey string, window TimeSeriesWindow) (LoadTestTimeSeries, error) {
+	window = timeSeriesWindowOf(window)
+	if err := ValidateTimeSeriesWindow(window); err != nil {
+		return LoadTestTimeSeries{}, fmt.Errorf("%w; %v", ErrInvalidTimeSeries, err)
+	}
+
+	stored, found, err := l.storedLoadTestResult(ctx, loadTestKey)
+	if err == nil && found {
+		found, err = l.hasStoredBuckets(ctx, stored.ID)
+	}
+	if err != nil {
+		log.Warn().Msgf("could not read the stored buckets of %s, reading its files; %v", loadTestKey, err)
+		found = false
+	}
+	if found && storedBucketsFit(window) {
+		series, err := l.storedTimeSeries(ctx, stored.ID, window)
+		if err == nil || errors.Is(err, ErrInvalidTimeSeries) {
+			return series, err
+		}
+		log.Warn().Msgf("could not read the stored buckets of %s, reading its files; %v", loadTestKey, err)
+	}
+
+	resultPath := fmt.Sprintf("%s/%s_result.csv", utils.JoinRootPathWith("/result/"+loadTestKey), loadTestKey)
+	if !utils.ExistCheck(resultPath) {
+		if found {
+			return LoadTestTimeSeries{}, fmt.Errorf("%w; the raw result of %s is discarded, and its stored buckets of %d ms only make up an interval and a window in whole multiples of them",
+				ErrInvalidTimeSeries, loadTestKey, resultSampleIntervalMs)
+		}
+		return LoadTestTimeSeries{}, fmt.Errorf("%w: %s", ErrRawResultUnavailable, loadTestKey)
+	}
+
+	series := newTimeSeries(window)
+	if err := engine.ScanResult(resultPath, series.add); err != nil {
+		return LoadTestTimeSeries{}, err
+	}
+	return series.result()
+}
+
+// hasStoredBuckets tells whether the result stored as resultId has its buckets, which one
+// stored before there were any does not.
+func (l *LoadService) hasStoredBuckets(ctx context.Context, resultId uint) (bool, error) {
+	var rows []LoadTestResultBucket
+	err := l.db.WithContext(ctx).Select("id").Where("load_test_result_id = ?", resultId).Limit(1).Find(&rows).Error
+	return len(rows) > 0, err
+}
+
+// storedTimeSeries adds the stored buckets of the result stored as resultId up into the time
+// series of window, a batch of them at a time.
+func (l *LoadService) storedTimeSeries(ctx context.Context, resultId uint, window TimeSeriesWindow) (LoadTestTimeSeries, error) {
+	q := l.db.WithContext(ctx).Where("load_test_result_id = ?", resultId)
+	if !window.From.IsZero() {
+		q = q.Where("at >= ?", window.From)
+	}
+	if !window.To.IsZero() {
+		q = q.Where("at < ?", window.To)
+	}
+
+	series := newTimeSeries(window)
+	var rows []LoadTestResultBucket
+	err := q.FindInBatches(&rows, resultInsertBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range rows {
+			if err := series.addRow(&rows[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return LoadTestTimeSeries{}, err
+	}
+	return series.result()
+}
+
+// seriesBucketOf reads a stored bucket back.
+func seriesBucketOf(row *LoadTestResultBucket) (*seriesBucket, error) {
+	b := newSeriesBucket()
+	if err := b.elapsed.decode(row.Elapsed); err != nil {
+		return nil, fmt.Errorf("bucket of %s at %v; %w", row.Label, row.At, err)
+	}
+	b.requests = row.Requests
+	b.errors = row.Errors
+	b.totalElapsed = row.TotalElapsed
+	b.minElapsed = row.MinElapsed
+	b.maxElapsed = row.MaxElapsed
+	b.bytes = row.Bytes
+	b.sentBytes = row.SentBytes
+	b.activeThreads = row.ActiveThreads
+	return b, nil
+}
+
+// seriesBucket adds up the requests of one label within one bucket.
+type seriesBucket struct {
+	requests      int
+	errors        int
+	totalElapsed  int
+	minElapsed    int
+	maxElapsed    int
+	bytes         int
+	sentBytes     int
+	activeThreads int
+	elapsed       elapsedHistogram
+}
+
+func newSeriesBucket() *seriesBucket {
+	return &seriesBucket{elapsed: elapsedHistogram{significantBits: timeSeriesSignificantBits}}
+}
+
+func (b *seriesBucket) add(r *ResultRawData) {
+	if b.requests == 0 || r.Elapsed < b.minElapsed {
+		b.minElapsed = r.Elapsed
+	}
+	if b.requests == 0 || r.Elapsed > b.maxElapsed {
+		b.maxElapsed = r.Elapsed
+	}
+	b.requests++
+	if r.IsError {
+		b.errors++
+	}
+	b.totalElapsed += r.Elapsed
+	b.bytes += r.Bytes
+	b.sentBytes += r.SentBytes
+	b.activeThreads = max(b.activeThreads, r.ActiveThreads)
+	b.elapsed.add(r.Elapsed)
+}
+
+// merge adds what another bucket added up to b.
+func (b *seriesBucket) merge(o *seriesBucket) {
+	if o.requests == 0 {
+		return
+	}
+	if b.requests == 0 || o.minElapsed < b.minElapsed {
+		b.minElapsed = o.minElapsed
+	}
+	if b.requests == 0 || o.maxElapsed > b.maxElapsed {
+		b.maxElapsed = o.maxElapsed
+	}
+	b.requests += o.requests
+	b.errors += o.errors
+	b.totalElapsed += o.totalElapsed
+	b.bytes += o.bytes
+	b.sentBytes += o.sentBytes
+	b.activeThreads = max(b.activeThreads, o.activeThreads)
+	b.elapsed.merge(&o.elapsed)
+}
+
+// percentiles returns the response times at or below which each share of ps of the requests
+// fall; ps are in order.
+func (b *seriesBucket) percentiles(ps ...float64) []float64 {
+	ranks := make([]int, len(ps))
+	for i, p := range ps {
+		ranks[i] = int(math.Ceil(float64(b.requests)*p)) - 1
+	}
+	values := make([]float64, len(ps))
+	for i, v := range b.elapsed.valuesAt(ranks...) {
+		values[i] = float64(min(max(v, b.minElapsed), b.maxElapsed))
+	}
+	return values
+}
+
+// figures returns what was added up as the bucket starting at at, widthMs wide.
+func (b *seriesBucket) figures(at time.Time, widthMs int64) TimeSeriesBucket {
+	bucket := TimeSeriesBucket{At: at}
+	if b == nil || b.requests == 0 {
+		return bucket
+	}
+	seconds := float64(widthMs) / 1000
+	p := b.percentiles(0.5, 0.95, 0.99)
+
+	bucket.Requests = b.requests
+	bucket.Errors = b.errors
+	bucket.RequestsPerSec = float64(b.requests) / seconds
+	bucket.ErrorsPerSec = float64(b.errors) / seconds
+	bucket.MeanElapsed = float64(b.totalElapsed) / float64(b.requests)
+	bucket.P50Elapsed, bucket.P95Elapsed, bucket.P99Elapsed = p[0], p[1], p[2]
+	bucket.ActiveThreads = b.activeThreads
+	bucket.ReceivedKBPerSec = float64(b.bytes) / 1024 / seconds
+	bucket.SentKBPerSec = float64(b.sentBytes) / 1024 / seconds
+	return bucket
+}
+
+// timeSeries adds the requests of a run up per label and bucket. Buckets start at the window's
+// From, or at whole multiples of the interval when it has none; they are keyed by their start
+// in unix ms.
+type timeSeries struct {
+	window TimeSeriesWindow
+	labels map[string]map[int64]*seriesBucket
+	total  map[int64]*seriesBucket
+	first  int64
+	last   int64
+	err    error
+}
+
+func newTimeSeries(window TimeSeriesWindow) *timeSeries {
+	return &timeSeries{window: window, labels: make(map[string]map[int64]*seriesBucket), total: make(map[int64]*seriesBucket)}
+}
+
+// bucketOf is the start of the bucket a request sent at at falls in.
+func (t *timeSeries) bucketOf(at int64) int64 {
+	var anchor int64
+	if !t.window.From.IsZero() {
+		anchor = t.window.From.UnixMilli()
+	}
+	interval := int64(t.window.IntervalMs)
+	offset := at - anchor
+	return anchor + (offset - ((offset%interval)+interval)%interval)
+}
+
+// slot returns the start of the bucket of what happened at at, or false when it is outside the
+// window. A run that turns out to span more buckets than a series may have is not added to any
+// further.
+func (t *timeSeries) slot(at int64) (int64, bool) {
+	if t.err != nil {
+		return 0, false
+	}
+	if !t.window.From.IsZero() && at < t.window.From.UnixMilli() {
+		return 0, false
+	}
+	if !t.window.To.IsZero() && at >= t.window.To.UnixMilli() {
+		return 0, false
+	}
+
+	start := t.bucketOf(at)
+	if len(t.total) == 0 || start < t.first {
+		t.first = start
+	}
+	if len(t.total) == 0 || start > t.last {
+		t.last = start
+	}
+	if n := (t.last-t.first)/int64(t.window.IntervalMs) + 1; n > maxTimeSeriesBuckets {
+		t.err = fmt.Errorf("%w; the run spans more than %d buckets of %d ms; choose a larger interval or a narrower window",
+			ErrInvalidTimeSeries, maxTimeSeriesBuckets, t.window.IntervalMs)
+		return 0, false
+	}
+	return start, true
+}
+
+// bucketsAt returns the bucket starting at start of label and that of all of the labels.
+func (t *timeSeries) bucketsAt(label string, start int64) []*seriesBucket {
+	buckets, ok := t.labels[label]
+	if !ok {
+		buckets = make(map[int64]*seriesBucket)
+		t.labels[label] = buckets
+	}
+	var at []*seriesBucket
+	for _, series := range []map[int64]*seriesBucket{buckets, t.total} {
+		b, ok := series[start]
+		if !ok {
+			b = newSeriesBucket()
+			series[start] = b
+		}
+		at = append(at, b)
+	}
+	return at
+}
+
+// add takes a request into its bucket, unless it is outside the window.
+func (t *timeSeries) add(label string, r *ResultRawData) {
+	start, ok := t.slot(r.Timestamp.UnixMilli())
+	if !ok {
+		return
+	}
+	for _, b := range t.bucketsAt(label, start) {
+		b.add(r)
+	}
+}
+
+// addRow takes a stored bucket into the bucket it falls in, unless it is outside the window.
+// The stored bucket must not be wider than the series' own, nor straddle them.
+func (t *timeSeries) addRow(row *LoadTestResultBucket) error {
+	start, ok := t.slot(row.At.UnixMilli())
+	if !ok {
+		return nil
+	}
+	stored, err := seriesBucketOf(row)
+	if err != nil {
+		return err
+	}
+	for _, b := range t.bucketsAt(row.Label, start) {
+		b.merge(stored)
+	}
+	return nil
+}
+
+// result returns the series, every bucket of the window for every label in label order, with
+// all of the labels together last. A window left open ends at the first or last request.
+func (t *timeSeries) result() (LoadTestTimeSeries, error) {
+	if t.err != nil {
+		return LoadTestTimeSeries{}, t.err
+	}
+
+	interval := int64(t.window.IntervalMs)
+	from, to := t.first, t.last+interval
+	if !t.window.From.IsZero() {
+		from = t.window.From.UnixMilli()
+	}
+	if !t.window.To.IsZero() {
+		to = t.window.To.UnixMilli()
+	}
+	series := LoadTestTimeSeries{IntervalMs: t.window.IntervalMs, Labels: []LabelTimeSeriesResult{}}
+	if len(t.total) == 0 && (t.window.From.IsZero() || t.window.To.IsZero()) {
+		// no request to say where an open window ends
+		return series, nil
+	}
+	series.From, series.To = time.UnixMilli(from), time.UnixMilli(to)
+
+	labels := make([]string, 0, len(t.labels))
+	for label := range t.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	bucketsOf := func(label string, buckets map[int64]*seriesBucket) LabelTimeSeriesResult {
+		ls := LabelTimeSeriesResult{Label: label, Buckets: make([]TimeSeriesBucket, 0, bucketsBetween(from, to, t.window.IntervalMs))}
+		for start := from; start < to; start += interval {
+			// the last bucket of a window that is not a whole number of them is cut short
+			ls.Buckets = append(ls.Buckets, buckets[start].figures(time.UnixMilli(start), min(interval, to-start)))
+		}
+		return ls
+	}
+	for _, label := range labels {
+		series.Labels = append(series.Labels, bucketsOf(label, t.labels[label]))
+	}
+	series.Labels = append(series.Labels, bucketsOf(comparisonTotalLabel, t.total))
+	return series, nil
+}
@@ -0,0 +1,203 @@
+package load
+
+import (
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func seriesOf(t *testing.T, window TimeSeriesWindow, results map[string][]*ResultRawData) LoadTestTimeSeries {
+	t.Helper()
+	series := newTimeSeries(timeSeriesWindowOf(window))
+	for label, rs := range results {
+		for _, r := range rs {
+			series.add(label, r)
+		}
+	}
+	result, err := series.result()
+	if err != nil {
+		t.Fatalf("expected a series, got %v", err)
+	}
+	return result
+}
+
+func TestTimeSeriesBucketsEachLabel(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	login := comparisonSamples(start, repeated(100, 20), 2)
+	for _, r := range login {
+		r.Bytes, r.SentBytes, r.ActiveThreads = 1024, 512, 5
+	}
+	// nothing of list in the second second, and a request in the third
+	list := append(comparisonSamples(start, repeated(50, 10), 0), &ResultRawData{Elapsed: 70, Timestamp: start.Add(2500 * time.Millisecond)})
+
+	series := seriesOf(t, TimeSeriesWindow{}, map[string][]*ResultRawData{"login": login, "list": list})
+	if series.IntervalMs != defaultTimeSeriesIntervalMs || !series.From.Equal(start) || !series.To.Equal(start.Add(3*time.Second)) {
+		t.Fatalf("expected three one second buckets from the first request, got %d ms from %v to %v", series.IntervalMs, series.From, series.To)
+	}
+	if len(series.Labels) != 3 || series.Labels[0].Label != "list" || series.Labels[1].Label != "login" || series.Labels[2].Label != comparisonTotalLabel {
+		t.Fatalf("expected the labels in order with the total last, got %+v", series.Labels)
+	}
+
+	first := series.Labels[1].Buckets[0]
+	if first.Requests != 10 || first.Errors != 2 || first.RequestsPerSec != 10 || first.ErrorsPerSec != 2 {
+		t.Errorf("expected 10 requests and 2 errors in the first second of login, got %+v", first)
+	}
+	if first.MeanElapsed != 104.5 || first.P50Elapsed != 104 || first.P95Elapsed != 109 || first.P99Elapsed != 109 {
+		t.Errorf("expected the response times of 100 to 109, got %+v", first)
+	}
+	if first.ActiveThreads != 5 || first.ReceivedKBPerSec != 10 || first.SentKBPerSec != 5 {
+		t.Errorf("expected 5 threads, 10 KB/s in and 5 KB/s out, got %+v", first)
+	}
+
+	for _, b := range series.Labels[1].Buckets[2:] {
+		if b.Requests != 0 || b.RequestsPerSec != 0 || b.At.IsZero() {
+			t.Errorf("expected an empty bucket where login had no request, got %+v", b)
+		}
+	}
+	if b := series.Labels[0].Buckets[1]; b.Requests != 0 || !b.At.Equal(start.Add(time.Second)) {
+		t.Errorf("expected the second second of list filled in empty, got %+v", b)
+	}
+	if b := series.Labels[2].Buckets[0]; b.Requests != 20 || b.Errors != 2 {
+		t.Errorf("expected the total of both labels in the first second, got %+v", b)
+	}
+}
+
+func TestTimeSeriesKeepsToItsWindow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	window := TimeSeriesWindow{IntervalMs: 400, From: start.Add(500 * time.Millisecond), To: start.Add(1500 * time.Millisecond)}
+
+	series := seriesOf(t, window, map[string][]*ResultRawData{"login": comparisonSamples(start, repeated(100, 30), 0)})
+	buckets := series.Labels[0].Buckets
+	if len(buckets) != 3 || !buckets[0].At.Equal(window.From) || !buckets[2].At.Equal(start.Add(1300*time.Millisecond)) {
+		t.Fatalf("expected three buckets from the start of the window, got %+v", buckets)
+	}
+	// 0.5 ~ 0.8, 0.9 ~ 1.2 and what is left of 1.3 ~ 1.5
+	if buckets[0].Requests != 4 || buckets[1].Requests != 4 || buckets[2].Requests != 2 {
+		t.Errorf("expected only the requests within the window, got %+v", buckets)
+	}
+	if buckets[2].RequestsPerSec != 10 {
+		t.Errorf("expected the rate of the cut short last bucket over its 200 ms, got %v", buckets[2].RequestsPerSec)
+	}
+
+	empty := seriesOf(t, TimeSeriesWindow{}, nil)
+	if len(empty.Labels) != 0 || !empty.From.IsZero() {
+		t.Errorf("expected no buckets for a run without requests, got %+v", empty)
+	}
+}
+
+func TestTimeSeriesPercentilesStayClose(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	var results []*ResultRawData
+	for i := 1; i <= 1000; i++ {
+		results = append(results, &ResultRawData{Elapsed: 1000 + i*37, Timestamp: start})
+	}
+
+	b := seriesOf(t, TimeSeriesWindow{}, map[string][]*ResultRawData{"login": results}).Labels[0].Buckets[0]
+	for _, c := range []struct {
+		name string
+		got  float64
+		want float64
+	}{{"p50", b.P50Elapsed, 1000 + 500*37}, {"p95", b.P95Elapsed, 1000 + 950*37}, {"p99", b.P99Elapsed, 1000 + 990*37}} {
+		if off := math.Abs(c.got-c.want) / c.want; off > 0.01 {
+			t.Errorf("expected %s within 1%% of %v, got %v", c.name, c.want, c.got)
+		}
+	}
+}
+
+func TestTimeSeriesWithTooManyBuckets(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	series := newTimeSeries(timeSeriesWindowOf(TimeSeriesWindow{IntervalMs: 100}))
+	series.add("login", &ResultRawData{Timestamp: start})
+	series.add("login", &ResultRawData{Timestamp: start.Add(time.Hour)})
+	if _, err := series.result(); !errors.Is(err, ErrInvalidTimeSeries) {
+		t.Errorf("expected an hour in buckets of 100 ms to be too many, got %v", err)
+	}
+}
+
+func TestTimeSeriesWindowIsValidated(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		window  TimeSeriesWindow
+		wantErr bool
+	}{
+		{"the default", TimeSeriesWindow{}, false},
+		{"an open end", TimeSeriesWindow{IntervalMs: 100, From: start}, false},
+		{"a short interval", TimeSeriesWindow{IntervalMs: 50}, true},
+		{"a window ending first", TimeSeriesWindow{From: start, To: start.Add(-time.Second)}, true},
+		{"an hour of seconds", TimeSeriesWindow{From: start, To: start.Add(time.Hour)}, false},
+		{"an hour of tenths", TimeSeriesWindow{IntervalMs: 100, From: start, To: start.Add(time.Hour)}, true},
+	}
+	for _, c := range cases {
+		if err := ValidateTimeSeriesWindow(c.window); (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
+
+func TestTimeSeriesFromStoredBuckets(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	login := comparisonSamples(start, repeated(100, 30), 3)
+	for i, r := range login {
+		r.Elapsed += i * 37
+		r.Bytes, r.SentBytes, r.ActiveThreads = 1024, 512, 1+i%4
+	}
+	list := comparisonSamples(start.Add(50*time.Millisecond), repeated(50, 20), 1)
+
+	path := writeResultFile(t, map[string][]*ResultRawData{"login": login, "list": list})
+	digest, err := digestResult(jmeterEngine{}, path, resultSampleIntervalMs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := loadTestResultOf("a", digest).Buckets
+
+	windows := []TimeSeriesWindow{
+		{},
+		{IntervalMs: 500},
+		{IntervalMs: 300, From: start.Add(200 * time.Millisecond), To: start.Add(2700 * time.Millisecond)},
+	}
+	var raw []LoadTestTimeSeries
+	for _, w := range windows {
+		series := newTimeSeries(timeSeriesWindowOf(w))
+		if err := (jmeterEngine{}).ScanResult(path, series.add); err != nil {
+			t.Fatal(err)
+		}
+		result, err := series.result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw = append(raw, result)
+	}
+
+	// the raw result is discarded once it is stored
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range windows {
+		w = timeSeriesWindowOf(w)
+		if !storedBucketsFit(w) {
+			t.Fatalf("expected the stored buckets to fit %+v", w)
+		}
+		series := newTimeSeries(w)
+		for j := range rows {
+			if err := series.addRow(&rows[j]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		stored, err := series.result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stored, raw[i]) {
+			t.Errorf("expected the stored buckets to add up to what the raw result does for %+v, got %+v, want %+v", w, stored, raw[i])
+		}
+	}
+
+	split := []TimeSeriesWindow{{IntervalMs: 250}, {IntervalMs: 1000, From: start.Add(50 * time.Millisecond)}}
+	if storedBucketsFit(split[0]) || storedBucketsFit(split[1]) {
+		t.Error("expected buckets of 100 ms not to fit an interval or a window that splits them")
+	}
+}
@@ -18,17 +18,22 @@ type fetchDataParam struct {
 	LoadTestKey                    string
 	InstallLocation                constant.InstallLocation
 	InstallPath                    string
+	Engine                         constant.LoadGeneratorType
+	AutoStop                       *autoStopWatch
 	PublicKeyName                  string
 	PrivateKeyName                 string
 	Username                       string
 	PublicIp                       string
 	Port                           string
+	Workers                        []fetchWorker // the other servers of a cluster or multi-region run; nil for one generator
 	CollectAdditionalSystemMetrics bool
 	fetchMx                        sync.Mutex
 	fetchRunning                   bool
 	Home                           string
 	StepRec                        *stepRecorder // FR-MA2-PERF-007-08 (nil = no recording)
 	Finished                       chan struct{} // closed when fetchData returns, after the final rsync (BAR-1413; nil = not awaited)
+	live                           *liveTail     // the result file as far as live figures have read it; nil until they do
+	agentCpu                       *agentCpuTail // the target's cpu file as far as auto-stop has read it; nil until it does
 }
 
 func (f *fetchDataParam) setFetchRunning(running bool) {
@@ -43,6 +48,12 @@ func (f *fetchDataParam) isRunning() bool {
 	return f.fetchRunning
 }
 
+// fetchWorker is a server of a run beside the first; it is reached with the same key.
+type fetchWorker struct {
+	Username string
+	PublicIp string
+}
+
 const (
 	defaultFetchIntervalSec = 30
 )
@@ -53,18 +64,34 @@ func (l *LoadService) fetchData(f *fetchDataParam) {
 	}
 	ticker := time.NewTicker(defaultFetchIntervalSec * time.Second)
 	defer ticker.Stop()
+	live := time.NewTicker(liveFiguresInterval)
+	defer live.Stop()
 
 	done := f.LoadTestDone
 	for {
 		select {
 		case <-ticker.C:
-			if !f.isRunning() {
+			// a server that is shutting down starts no transfer the final one does not need
+			if !f.isRunning() && !isDraining() {
 				f.setFetchRunning(true)
 				if _, err := rsyncFiles(f); err != nil {
 					log.Error().Msgf("error while fetching data from rsync %s", err.Error())
 				}
 				f.setFetchRunning(false)
 			}
+		case <-live.C:
+			// a run someone is watching, or whose load is held to auto-stop rules, is fetched
+			// more often, for its rolling figures
+			watched := progress.watched(f.LoadTestKey)
+			guarded := f.AutoStop != nil && phaseOf(f.LoadTestKey) == constant.StepJmeterRun
+			if (watched || guarded) && !f.isRunning() && !isDraining() {
+				f.setFetchRunning(true)
+				_, err := rsyncFiles(f)
+				f.setFetchRunning(false)
+				if err == nil {
+					l.takeLiveFigures(f, watched, guarded)
+				}
+			}
 		case <-done:
 			// FR-MA2-PERF-007-08: record the final result collection as its own step so a
 			// rsync failure is visible (previously it was only logged and the run was still
@@ -82,6 +109,9 @@ func (l *LoadService) fetchData(f *fetchDataParam) {
 			// arriving means more are coming, while nothing new across a whole round means
 			// nothing more is coming.
 			outcome, fetchErr := l.collectResults(f)
+			if fetchErr == nil {
+				l.persistResults(f)
+			}
 
 			switch {
 			case fetchErr != nil:
@@ -278,6 +308,8 @@ type fetchOutcome struct {
 // Metric files also tend to land later than the main one, so what looks missing now often
 // arrives on a later pass — hence the retries here and the periodic fetch above.
 func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
+	defer beginRsync()()
+
 	var outcome fetchOutcome
 	loadTestKey := f.LoadTestKey
 	installLocation := f.InstallLocation
@@ -292,9 +324,10 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 
 	type fileResult struct {
 		prefix string
+		part   int // server of a cluster run, -1 when the file is not a part
 		err    error
 	}
-	resultChan := make(chan fileResult, len(resultsPrefix))
+	resultChan := make(chan fileResult, len(resultsPrefix)+len(f.Workers))
 
 	resultFolderPath := utils.JoinRootPathWith("/result/" + loadTestKey)
 
@@ -311,11 +344,14 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 	maxRetries := config.AppConfig.Load.Retry
 	retryDelay := 2 * time.Second
 
-	rsync := func(prefix string) {
+	rsync := func(prefix string, part int, username, publicIp string) {
 		defer wg.Done()
 		fileName := fmt.Sprintf("%s%s_result.csv", loadTestKey, prefix)
 		fromFilePath := fmt.Sprintf("%s/result/%s", loadGeneratorInstallPath, fileName)
 		toFilePath := fmt.Sprintf("%s/%s", resultFolderPath, fileName)
+		if part >= 0 {
+			toFilePath = fmt.Sprintf("%s/%s", resultFolderPath, resultPartFileName(loadTestKey, part))
+		}
 
 		// Retry logic
 		for attempt := 1; attempt <= maxRetries; attempt++ {
@@ -325,8 +361,8 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 			} else if installLocation == constant.Remote {
 				cmd = fmt.Sprintf(`rsync -avvz -e "ssh -i %s -o StrictHostKeyChecking=no" %s@%s:%s %s`,
 					fmt.Sprintf("%s/.ssh/%s", f.Home, f.PrivateKeyName),
-					f.Username,
-					f.PublicIp,
+					username,
+					publicIp,
 					fromFilePath,
 					toFilePath)
 			}
@@ -335,7 +371,7 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 			err := utils.InlineCmd(cmd)
 
 			if err == nil {
-				resultChan <- fileResult{prefix: prefix}
+				resultChan <- fileResult{prefix: prefix, part: part}
 				return // Success, exit the retry loop
 			}
 
@@ -348,13 +384,25 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 			}
 		}
 
-		resultChan <- fileResult{prefix: prefix, err: fmt.Errorf("failed to rsync %s after %d attempts", fileName, maxRetries)}
+		resultChan <- fileResult{prefix: prefix, part: part, err: fmt.Errorf("failed to rsync %s from %s after %d attempts", fileName, publicIp, maxRetries)}
 	}
 
+	// A cluster or multi-region run leaves a result file on every server. Each is pulled as a
+	// part of its own, numbered in the order of the run's servers, and the parts are merged
+	// into the result file a single generator would have left.
+	isCluster := installLocation == constant.Remote && len(f.Workers) > 0
 	if installLocation == constant.Local || installLocation == constant.Remote {
 		for _, p := range resultsPrefix {
 			wg.Add(1)
-			go rsync(p)
+			if p == "" && isCluster {
+				go rsync(p, 0, f.Username, f.PublicIp)
+				for i, w := range f.Workers {
+					wg.Add(1)
+					go rsync(p, i+1, w.Username, w.PublicIp)
+				}
+				continue
+			}
+			go rsync(p, -1, f.Username, f.PublicIp)
 		}
 	}
 
@@ -374,5 +422,15 @@ func rsyncFiles(f *fetchDataParam) (fetchOutcome, error) {
 	}
 	sort.Strings(outcome.MissingMetrics)
 
+	if isCluster && mainErr == nil {
+		parts := make([]string, 0, len(f.Workers)+1)
+		for i := 0; i <= len(f.Workers); i++ {
+			parts = append(parts, fmt.Sprintf("%s/%s", resultFolderPath, resultPartFileName(loadTestKey, i)))
+		}
+		if err := mergeResultParts(fmt.Sprintf("%s/%s_result.csv", resultFolderPath, loadTestKey), parts); err != nil {
+			mainErr = fmt.Errorf("failed to merge the result files of the generators; %w", err)
+		}
+	}
+
 	return outcome, mainErr
 }
@@ -0,0 +1,379 @@
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/config"
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Runs do not start the moment they are accepted. Two runs on the one shared generator MCI
+// contend for its CPU and race its install and reset, and two runs against one node measure
+// each other (BAR-1414); turning the second one away made callers retry by hand. A run is
+// queued instead and started by the dispatcher once the limits allow it: runs in all, runs
+// per load generator and runs per target node. The queue is kept in the database, so a run
+// that was waiting when the server stopped still starts once it is back, and so are the runs
+// going: a run keeps its queue entry, marked started, until it ends. The limits are counted
+// from those entries in the same transaction that starts the next runs, which servers sharing
+// the database take one at a time, so together they keep to the limits as one server would.
+
+// runSlot is what a run holds while it goes: its load generator and its target node.
+type runSlot struct {
+	generator string
+	target    string
+}
+
+// runLimits are how many runs may hold a slot at once, in all and per generator and target.
+type runLimits struct {
+	total        int
+	perGenerator int
+	perTarget    int
+}
+
+// dispatchMu keeps one dispatch of this server at a time; queueLockId keeps out those of
+// other servers.
+var dispatchMu sync.Mutex
+
+// queueLockId is the PostgreSQL advisory lock a dispatch holds for its transaction.
+const queueLockId = 0x616e7471 // "antq"
+
+// startedRun is a run a dispatch has taken off the queue, to be started once it commits.
+type startedRun struct {
+	state LoadTestExecutionState
+	param RunLoadTestParam
+}
+
+// errRunNotQueued is returned on stopping a queued run that has started in the meantime.
+var errRunNotQueued = errors.New("load test has just left the queue and is starting; stop it again in a moment")
+
+// queueLimits returns the run limits. FR-MA2-PERF-007-01: they are configurable (config.yaml
+// load.queue / ANT_LOAD_QUEUE_*), falling back to the built-in defaults when unset.
+func queueLimits() runLimits {
+	q := config.AppConfig.Load.Queue
+	lim := runLimits{total: q.MaxConcurrentRuns, perGenerator: q.MaxRunsPerGenerator, perTarget: q.MaxRunsPerTarget}
+	if lim.total <= 0 {
+		lim.total = 2
+	}
+	if lim.perGenerator <= 0 {
+		lim.perGenerator = 1
+	}
+	if lim.perTarget <= 0 {
+		lim.perTarget = 1
+	}
+	return lim
+}
+
+// generatorKey names the load generator of a location. Every remote run goes to the shared
+// MCI, whatever install record it names.
+func generatorKey(location constant.InstallLocation) string {
+	if location == constant.Remote {
+		nsId, mciId, _, _ := getResourceNames()
+		return fmt.Sprintf("remote:%s/%s", nsId, mciId)
+	}
+	return string(constant.Local)
+}
+
+// generatorKeyOf is the generator param runs on. A run that reuses a generator by id has its
+// install location blanked, so the install record is consulted.
+func (l *LoadService) generatorKeyOf(ctx context.Context, param RunLoadTestParam) string {
+	location := param.InstallLoadGenerator.InstallLocation
+	if param.LoadGeneratorInstallInfoId != 0 {
+		if info, err := l.loadRepo.GetValidLoadGeneratorInstallInfoByIdTx(ctx, param.LoadGeneratorInstallInfoId); err == nil {
+			location = info.InstallLocation
+		}
+	}
+	return generatorKey(location)
+}
+
+// targetKeyOf is the node param is run against; empty for a run not tied to one.
+func targetKeyOf(param RunLoadTestParam) string {
+	if param.NsId == "" && param.InfraId == "" && param.NodeId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s", param.NsId, param.InfraId, param.NodeId)
+}
+
+// lockQueueTx keeps the dispatches of other servers sharing the database waiting until tx
+// ends. SQLite lets one server write at a time anyway.
+func lockQueueTx(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", queueLockId).Error
+}
+
+// dispatchable returns which of the queued runs, in queue order, can start next to the
+// running ones. A run that has to wait keeps its generator and target for itself: a run
+// behind it that needs either waits too, so no run is overtaken for good by later ones.
+func dispatchable(queued, running []runSlot, limits runLimits) []int {
+	byGenerator := make(map[string]int)
+	byTarget := make(map[string]int)
+	for _, r := range running {
+		byGenerator[r.generator]++
+		if r.target != "" {
+			byTarget[r.target]++
+		}
+	}
+	total := len(running)
+
+	waitingGenerator := make(map[string]bool)
+	waitingTarget := make(map[string]bool)
+	var starts []int
+	for i, q := range queued {
+		if total >= limits.total {
+			break
+		}
+		free := !waitingGenerator[q.generator] && byGenerator[q.generator] < limits.perGenerator
+		if q.target != "" {
+			free = free && !waitingTarget[q.target] && byTarget[q.target] < limits.perTarget
+		}
+		if !free {
+			waitingGenerator[q.generator] = true
+			if q.target != "" {
+				waitingTarget[q.target] = true
+			}
+			continue
+		}
+
+		starts = append(starts, i)
+		total++
+		byGenerator[q.generator]++
+		if q.target != "" {
+			byTarget[q.target]++
+		}
+	}
+	return starts
+}
+
+// enqueueRun queues a run whose state was saved as queued and dispatches the queue.
+func (l *LoadService) enqueueRun(ctx context.Context, param RunLoadTestParam) error {
+	run, err := json.Marshal(param)
+	if err != nil {
+		return err
+	}
+	entry := LoadTestQueuedRun{
+		LoadTestKey:  param.LoadTestKey,
+		GeneratorKey: l.generatorKeyOf(ctx, param),
+		TargetKey:    targetKeyOf(param),
+		Run:          run,
+	}
+	if err := l.db.WithContext(ctx).Create(&entry).Error; err != nil {
+		return err
+	}
+
+	l.DispatchQueuedRuns()
+	return nil
+}
+
+// DispatchQueuedRuns starts every queued run the limits let start now. It is called as runs
+// are queued and as they end, and once when the server starts for the runs still queued. A
+// server that is shutting down starts none; they wait for the next one.
+func (l *LoadService) DispatchQueuedRuns() {
+	if isDraining() {
+		return
+	}
+	dispatchMu.Lock()
+	defer dispatchMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var started []startedRun
+	var failed []LoadTestExecutionState
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockQueueTx(tx); err != nil {
+			return err
+		}
+
+		var entries []LoadTestQueuedRun
+		if err := tx.Order("id asc").Find(&entries).Error; err != nil {
+			return err
+		}
+		var queued []LoadTestQueuedRun
+		var slots, running []runSlot
+		for _, e := range entries {
+			slot := runSlot{generator: e.GeneratorKey, target: e.TargetKey}
+			if e.StartedAt != nil {
+				running = append(running, slot)
+				continue
+			}
+			queued = append(queued, e)
+			slots = append(slots, slot)
+		}
+
+		for _, i := range dispatchable(slots, running, queueLimits()) {
+			run, err := startQueuedRunTx(tx, queued[i])
+			if err != nil {
+				return fmt.Errorf("starting queued load test %s; %w", queued[i].LoadTestKey, err)
+			}
+			if run == nil {
+				continue
+			}
+			if run.state.ExecutionStatus == constant.TestFailed {
+				failed = append(failed, run.state)
+				continue
+			}
+			started = append(started, *run)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error().Msgf("failed to dispatch the load test queue; %v", err)
+		return
+	}
+
+	for _, state := range failed {
+		publishState(state)
+	}
+	for i := range started {
+		l.launchRun(started[i])
+	}
+}
+
+// startQueuedRunTx marks a queued run started, holding its slot, and returns it; nil when the
+// run has left the queue in the meantime. A run that cannot be read is failed and given back
+// with its state failed.
+func startQueuedRunTx(tx *gorm.DB, entry LoadTestQueuedRun) (*startedRun, error) {
+	var state LoadTestExecutionState
+	if err := tx.Where("load_test_key = ?", entry.LoadTestKey).First(&state).Error; err != nil {
+		return nil, err
+	}
+
+	var param RunLoadTestParam
+	if err := json.Unmarshal(entry.Run, &param); err != nil {
+		if err := tx.Unscoped().Where("id = ?", entry.ID).Delete(&LoadTestQueuedRun{}).Error; err != nil {
+			return nil, err
+		}
+		finishAt := time.Now()
+		state.ExecutionStatus = constant.TestFailed
+		state.FailureMessage = fmt.Sprintf("queued run could not be read; %v", err)
+		state.FinishAt = &finishAt
+		return &startedRun{state: state}, tx.Save(&state).Error
+	}
+
+	// whoever marks the entry starts the run; a run stopped while queued is gone already
+	now := time.Now()
+	res := tx.Model(&LoadTestQueuedRun{}).Where("id = ? AND started_at IS NULL", entry.ID).
+		Updates(map[string]any{"started_at": now, "owner_id": serverId, "heartbeat_at": now})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	state.ExecutionStatus = constant.OnProcessing
+	state.StartAt = now
+	state.ExpectedFinishAt = now.Add(time.Duration(state.TotalExpectedExcutionSecond) * time.Second)
+	if err := tx.Save(&state).Error; err != nil {
+		return nil, err
+	}
+	return &startedRun{state: state, param: param}, nil
+}
+
+// launchRun drives a run a dispatch has started.
+func (l *LoadService) launchRun(run startedRun) {
+	log.Info().Msgf("Starting queued load test with key: %s", run.state.LoadTestKey)
+	publishState(run.state)
+	trackRun(run.state.LoadTestKey, "")
+	go func() {
+		defer l.releaseRun(run.state.LoadTestKey)
+		l.processLoadTestAsync(run.param, &run.state)
+		l.applyGeneratorIdlePolicy(run.param) // BAR-1413: keep / suspend / terminate the generator when idle
+	}()
+}
+
+// holdRunSlot makes a resumed run keep its slot while it goes, if it does not already; one
+// started before runs kept their queue entry was claimed with an entry that holds none.
+func (l *LoadService) holdRunSlot(ctx context.Context, loadTestKey string, slot runSlot) error {
+	return l.db.WithContext(ctx).Model(&LoadTestQueuedRun{}).
+		Where("load_test_key = ? AND (generator_key = '' OR generator_key IS NULL)", loadTestKey).
+		Updates(map[string]any{"generator_key": slot.generator, "target_key": slot.target}).Error
+}
+
+// releaseRun gives back the slot of a run that ended and starts what was waiting for it.
+func (l *LoadService) releaseRun(loadTestKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := l.db.WithContext(ctx).Unscoped().Where("load_test_key = ?", loadTestKey).Delete(&LoadTestQueuedRun{}).Error; err != nil {
+		log.Error().Msgf("failed to give back the slot of load test %s; %v", loadTestKey, err)
+	}
+	untrackRun(loadTestKey)
+
+	l.DispatchQueuedRuns()
+}
+
+// releaseStaleSlots gives back the slots of runs that ended without giving them back, because
+// the server driving them stopped once they had. A run whose owner still beats is left to it,
+// which gives the slot back itself.
+func (l *LoadService) releaseStaleSlots(ctx context.Context) error {
+	ended := l.db.Model(&LoadTestExecutionState{}).
+		Select("load_test_key").
+		Where("execution_status IN ?", []constant.ExecutionStatus{constant.Successed, constant.TestFailed})
+	return l.db.WithContext(ctx).Unscoped().
+		Where("started_at IS NOT NULL AND load_test_key IN (?) AND "+ownerLeftCond, ended, time.Now().Add(-runOwnerExpiry)).
+		Delete(&LoadTestQueuedRun{}).Error
+}
+
+// dequeueRun takes a run that has not started off the queue and marks it failed. It returns
+// false when the run is no longer queued, e.g. because it has just been started.
+func (l *LoadService) dequeueRun(ctx context.Context, state *LoadTestExecutionState) (bool, error) {
+	res := l.db.WithContext(ctx).Unscoped().Where("load_test_key = ? AND started_at IS NULL", state.LoadTestKey).Delete(&LoadTestQueuedRun{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, l.failQueuedRun(ctx, state, "stopped while queued")
+}
+
+func (l *LoadService) failQueuedRun(ctx context.Context, state *LoadTestExecutionState, message string) error {
+	finishAt := time.Now()
+	state.ExecutionStatus = constant.TestFailed
+	state.FailureMessage = message
+	state.FinishAt = &finishAt
+	return l.loadRepo.UpdateLoadTestExecutionStateTx(ctx, state)
+}
+
+// queuePositions returns the place of every queued run in the queue, from 1, by load test key.
+func (l *LoadService) queuePositions(ctx context.Context) (map[string]int, error) {
+	var keys []string
+	if err := l.db.WithContext(ctx).Model(&LoadTestQueuedRun{}).Where("started_at IS NULL").Order("id asc").Pluck("load_test_key", &keys).Error; err != nil {
+		return nil, err
+	}
+	positions := make(map[string]int, len(keys))
+	for i, k := range keys {
+		positions[k] = i + 1
+	}
+	return positions, nil
+}
+
+// withQueuePositions fills in the queue position of the queued runs among states.
+func (l *LoadService) withQueuePositions(ctx context.Context, states []LoadTestExecutionStateResult) {
+	queued := false
+	for _, s := range states {
+		queued = queued || s.ExecutionStatus == constant.Queued
+	}
+	if !queued {
+		return
+	}
+
+	positions, err := l.queuePositions(ctx)
+	if err != nil {
+		log.Warn().Msgf("failed to read the load test queue; %v", err)
+		return
+	}
+	for i := range states {
+		if states[i].ExecutionStatus == constant.Queued {
+			states[i].QueuePosition = positions[states[i].LoadTestKey]
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package load
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDispatchableKeepsToTheLimits(t *testing.T) {
+	limits := runLimits{total: 2, perGenerator: 1, perTarget: 1}
+	remote, local := runSlot{generator: "remote"}, runSlot{generator: "local"}
+	on := func(s runSlot, target string) runSlot { s.target = target; return s }
+
+	for name, tc := range map[string]struct {
+		queued, running []runSlot
+		want            []int
+	}{
+		"idle server starts what fits": {
+			queued: []runSlot{on(remote, "a"), on(remote, "b"), on(local, "c")},
+			want:   []int{0, 2},
+		},
+		"busy generator": {
+			queued:  []runSlot{on(remote, "a"), on(local, "b")},
+			running: []runSlot{on(remote, "c")},
+			want:    []int{1},
+		},
+		"busy target": {
+			queued:  []runSlot{on(local, "a")},
+			running: []runSlot{on(remote, "a")},
+			want:    nil,
+		},
+		"full": {
+			queued:  []runSlot{on(local, "a")},
+			running: []runSlot{on(remote, "b"), {generator: "other"}},
+			want:    nil,
+		},
+		"a waiting run keeps its target from later ones": {
+			queued:  []runSlot{on(remote, "a"), on(local, "a")},
+			running: []runSlot{on(remote, "b")},
+			want:    nil,
+		},
+		"runs without a target only count for the generator": {
+			queued: []runSlot{local, remote},
+			want:   []int{0, 1},
+		},
+	} {
+		if got := dispatchable(tc.queued, tc.running, limits); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected %v to start, got %v", name, tc.want, got)
+		}
+	}
+}
+
+func TestQueueLimitsDefaultWhenUnset(t *testing.T) {
+	if got := queueLimits(); got != (runLimits{total: 2, perGenerator: 1, perTarget: 1}) {
+		t.Errorf("expected the built-in limits, got %+v", got)
+	}
+	if key := targetKeyOf(RunLoadTestParam{}); key != "" {
+		t.Errorf("expected a run without a target to have no target key, got %q", key)
+	}
+}
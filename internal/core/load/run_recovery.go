@@ -0,0 +1,420 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm/clause"
+)
+
+// A run is driven by a goroutine of the server that started it, so a server that stops - a
+// deploy, a crash, a SIGTERM - leaves every run it was driving on_processing or on_fetching
+// for good, with nobody left to finish it or fail it. Another server settles them: a run whose
+// load was going is looked for on its generator and waited for, a run whose load had finished
+// has its results fetched, and a run that stopped while it was being prepared is failed,
+// saying so, since there is nothing on the generator to pick up.
+//
+// Servers may share the database, so a run found unfinished may well be going on another one.
+// The server driving a run owns its queue entry and moves its heartbeat on every
+// runHeartbeatInterval; a run is only taken up once the heartbeat of its owner has expired,
+// by claiming the entry in a single conditional update, which one server wins. Runs are
+// looked for when the server starts and at every heartbeat after, since the server that left
+// them may have stopped after this one started.
+
+// resumeAction is what is done with a run found unfinished when the server starts.
+type resumeAction int
+
+const (
+	// resumeLost fails the run; it stopped before its load started, or before it had a
+	// generator, and cannot be picked up again.
+	resumeLost resumeAction = iota
+	// resumeAwait waits for the load to end on the generator, if it is still going, and then
+	// fetches the results.
+	resumeAwait
+	// resumeFetch fetches the results of a load that had ended already.
+	resumeFetch
+)
+
+const (
+	engineRunningMark = "ant-engine-running"
+	engineStoppedMark = "ant-engine-stopped"
+
+	// resumeProbeGrace is how long past its expected end a run is waited for while its
+	// generator cannot be asked whether the load is still going.
+	resumeProbeGrace = 10 * time.Minute
+
+	// runHeartbeatInterval is how often a server moves on the heartbeat of the runs it drives.
+	runHeartbeatInterval = 30 * time.Second
+	// runOwnerExpiry is how long after its last heartbeat a run is taken to be left by its
+	// owner.
+	runOwnerExpiry = 3 * runHeartbeatInterval
+
+	// ownerLeftCond matches the queue entries whose owner has left them, given the time the
+	// heartbeat expires at; an entry of a run started before there were owners has none.
+	ownerLeftCond = "(owner_id = '' OR owner_id IS NULL OR heartbeat_at IS NULL OR heartbeat_at < ?)"
+)
+
+// serverId tells this server apart from the others sharing the database, and from the one
+// before it; it is new every time the server starts.
+var serverId = uuid.NewString()
+
+// ownerLeft tells whether the owner of a queue entry, whose heartbeat was last moved on at
+// heartbeatAt, has left it by now, as ownerLeftCond does.
+func ownerLeft(entry LoadTestQueuedRun, now time.Time) bool {
+	return entry.OwnerId == "" || entry.HeartbeatAt == nil || entry.HeartbeatAt.Before(now.Add(-runOwnerExpiry))
+}
+
+// processRunningCmd prints engineRunningMark while a process matching grepRegex is running
+// and engineStoppedMark once none is.
+func processRunningCmd(grepRegex string) string {
+	return fmt.Sprintf("if ps -ef | grep -E '%s' | grep -v grep > /dev/null; then echo %s; else echo %s; fi",
+		grepRegex, engineRunningMark, engineStoppedMark)
+}
+
+// resumeActionOf decides what is done with an unfinished run and returns the step it was in
+// when the server stopped.
+func resumeActionOf(state LoadTestExecutionState, steps []LoadTestExecutionStep) (resumeAction, constant.ExecutionStep) {
+	phases := make(map[constant.ExecutionStep]constant.StepStatus)
+	for _, s := range steps {
+		phases[s.Name] = s.Status
+	}
+
+	switch {
+	case state.GeneratorInstallInfoId == 0:
+		return resumeLost, interruptedStep(steps)
+	case state.ExecutionStatus == constant.OnFetching || phases[constant.StepJmeterRun] == constant.StepOk:
+		return resumeFetch, constant.StepResultFetch
+	case phases[constant.StepJmeterRun] == constant.StepRunning:
+		return resumeAwait, constant.StepJmeterRun
+	}
+	return resumeLost, interruptedStep(steps)
+}
+
+// interruptedStep is the latest phase that was running, or the latest one that had started
+// when none was; "" when the run had not got to any.
+func interruptedStep(steps []LoadTestExecutionStep) constant.ExecutionStep {
+	var running, started constant.ExecutionStep
+	for _, s := range steps {
+		seq, ok := stepSeq[s.Name]
+		if !ok {
+			continue
+		}
+		if s.Status == constant.StepRunning && seq > stepSeq[running] {
+			running = s.Name
+		}
+		if s.Status != constant.StepPending && seq > stepSeq[started] {
+			started = s.Name
+		}
+	}
+	if running != "" {
+		return running
+	}
+	return started
+}
+
+// ResumeUnfinishedRuns settles the runs left on_processing or on_fetching by a server that
+// no longer drives them. It is called when the server starts, before the queue is dispatched,
+// so the runs it picks up hold their generator and target like any other running run, and at
+// every heartbeat after. A server that is shutting down takes none up.
+func (l *LoadService) ResumeUnfinishedRuns() {
+	if isDraining() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var states []LoadTestExecutionState
+	err := l.db.WithContext(ctx).
+		Where("execution_status IN ?", []constant.ExecutionStatus{constant.OnProcessing, constant.OnFetching}).
+		Order("id asc").
+		Find(&states).Error
+	if err != nil {
+		log.Error().Msgf("failed to read unfinished load tests; %v", err)
+		return
+	}
+	keys := make([]string, 0, len(states))
+	for _, s := range states {
+		keys = append(keys, s.LoadTestKey)
+	}
+	var entries []LoadTestQueuedRun
+	if err := l.db.WithContext(ctx).Where("load_test_key IN ?", keys).Find(&entries).Error; err != nil {
+		log.Error().Msgf("failed to read the owners of unfinished load tests; %v", err)
+		return
+	}
+	owned := make(map[string]LoadTestQueuedRun, len(entries))
+	for _, e := range entries {
+		owned[e.LoadTestKey] = e
+	}
+
+	now := time.Now()
+	for i := range states {
+		key := states[i].LoadTestKey
+		if isTracked(key) {
+			continue
+		}
+		if e, ok := owned[key]; ok && !ownerLeft(e, now) {
+			continue
+		}
+		claimed, err := l.claimRun(ctx, key, now)
+		if err != nil {
+			log.Error().Msgf("failed to claim load test %s; %v", key, err)
+			continue
+		}
+		if !claimed {
+			// another server took it up first
+			continue
+		}
+		if err := l.resumeRun(ctx, &states[i]); err != nil {
+			log.Error().Msgf("failed to resume load test %s; %v", key, err)
+		}
+		if !isTracked(key) {
+			// a run failed outright, or to be tried again, holds no slot meanwhile
+			if err := l.db.WithContext(ctx).Unscoped().Where("load_test_key = ?", key).Delete(&LoadTestQueuedRun{}).Error; err != nil {
+				log.Error().Msgf("failed to give back the slot of load test %s; %v", key, err)
+			}
+		}
+	}
+	// the runs that could not be picked up, among others, hold their slots no more
+	if err := l.releaseStaleSlots(ctx); err != nil {
+		log.Error().Msgf("failed to give back the slots of ended load tests; %v", err)
+	}
+}
+
+// claimRun makes this server the owner of an unfinished run whose owner has left it, and
+// reports whether it did; of servers claiming a run at once, one does.
+func (l *LoadService) claimRun(ctx context.Context, loadTestKey string, now time.Time) (bool, error) {
+	res := l.db.WithContext(ctx).Model(&LoadTestQueuedRun{}).
+		Where("load_test_key = ? AND "+ownerLeftCond, loadTestKey, now.Add(-runOwnerExpiry)).
+		Updates(map[string]any{"owner_id": serverId, "heartbeat_at": now})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// a run started before runs kept their queue entry has none to claim; the unique key lets
+	// one server add it
+	entry := LoadTestQueuedRun{LoadTestKey: loadTestKey, StartedAt: &now, OwnerId: serverId, HeartbeatAt: &now}
+	res = l.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+	return res.RowsAffected > 0, res.Error
+}
+
+// RunHeartbeats moves on the heartbeat of the runs this server drives, and takes up the runs
+// other servers have left, until ctx is done.
+func (l *LoadService) RunHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(runHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		l.beat(ctx)
+		l.ResumeUnfinishedRuns()
+		l.DispatchQueuedRuns()
+	}
+}
+
+// beat moves on the heartbeat of the runs this server drives.
+func (l *LoadService) beat(ctx context.Context) {
+	keys, _ := trackedRuns()
+	if len(keys) == 0 {
+		return
+	}
+	err := l.db.WithContext(ctx).Model(&LoadTestQueuedRun{}).
+		Where("owner_id = ? AND load_test_key IN ?", serverId, keys).
+		Update("heartbeat_at", time.Now()).Error
+	if err != nil {
+		log.Error().Msgf("failed to move on the heartbeat of load tests [%s]; %v", strings.Join(keys, ", "), err)
+	}
+}
+
+// resumeRun settles one unfinished run, in the background unless it is failed outright.
+func (l *LoadService) resumeRun(ctx context.Context, state *LoadTestExecutionState) error {
+	var steps []LoadTestExecutionStep
+	if err := l.db.WithContext(ctx).Where("load_test_execution_state_id = ?", state.ID).Order("seq asc").Find(&steps).Error; err != nil {
+		return err
+	}
+
+	action, step := resumeActionOf(*state, steps)
+	if action == resumeLost {
+		return l.settleLostRun(ctx, state, steps, step)
+	}
+
+	info, err := l.loadRepo.GetLoadTestExecutionInfoTx(ctx, GetLoadTestExecutionInfoParam{LoadTestKey: state.LoadTestKey})
+	if err != nil {
+		return l.settleLostRun(ctx, state, steps, step)
+	}
+	installInfo, err := l.loadRepo.GetValidLoadGeneratorInstallInfoByIdTx(ctx, state.GeneratorInstallInfoId)
+	if err != nil {
+		return l.settleLostRun(ctx, state, steps, step)
+	}
+	param := runParamOf(info)
+	param.CollectAdditionalSystemMetrics = state.WithMetrics
+	servers, _, err := runServers(param, &installInfo)
+	if err != nil {
+		return l.settleLostRun(ctx, state, steps, step)
+	}
+
+	log.Info().Msgf("Resuming load test %s at %s", state.LoadTestKey, step)
+	slot := runSlot{generator: generatorKey(installInfo.InstallLocation), target: targetKeyOf(param)}
+	if err := l.holdRunSlot(ctx, state.LoadTestKey, slot); err != nil {
+		log.Warn().Msgf("resumed load test %s holds no slot; %v", state.LoadTestKey, err)
+	}
+	trackRun(state.LoadTestKey, step)
+
+	go func() {
+		defer l.releaseRun(state.LoadTestKey)
+		l.finishResumedRun(param, &installInfo, servers, state, action)
+		l.applyGeneratorIdlePolicy(param)
+	}()
+	return nil
+}
+
+// finishResumedRun waits for the load of a resumed run to end, if it was going, and fetches
+// its results. The run succeeds when its main result file is collected.
+func (l *LoadService) finishResumedRun(param RunLoadTestParam, installInfo *LoadGeneratorInstallInfo, servers []LoadGeneratorServer, state *LoadTestExecutionState, action resumeAction) {
+	rec := l.newStepRecorder(state)
+	ctx := context.Background()
+
+	// the key that reaches the generator may have gone with the old server's container
+	if err := l.ensureGeneratorReachable(*installInfo, nil); err != nil {
+		log.Warn().Msgf("generator of resumed load test %s is not reachable; %v", state.LoadTestKey, err)
+	}
+
+	loadTestDone := make(chan bool, 1)
+	dataParam, err := newFetchDataParam(param, installInfo, rec, loadTestDone)
+	if err != nil {
+		l.failResumedRun(ctx, state, fmt.Sprintf("user home dir is not valid; %s", err))
+		return
+	}
+	dataParam.Finished = make(chan struct{})
+	go l.fetchData(dataParam)
+
+	if action == resumeAwait {
+		rec.progress(constant.StepJmeterRun, 0, "Reattached to the load test after cm-ant restarted", "")
+		exited := l.awaitEngineExit(state, installInfo.InstallLocation, servers)
+		switch fired := dataParam.AutoStop.firedRule(); {
+		case fired != "":
+			rec.fail(constant.StepJmeterRun, "Load test stopped by an auto-stop rule", fired)
+		case exited:
+			rec.ok(constant.StepJmeterRun, "Load test finished")
+		default:
+			rec.ok(constant.StepJmeterRun, "Load test finished while cm-ant was restarting")
+		}
+		if state.ExecutionDuration == "" {
+			state.ExecutionDuration = utils.DurationString(state.StartAt)
+		}
+	}
+	if state.ExecutionStatus != constant.OnFetching {
+		if err := l.updateLoadTestExecution(state); err != nil {
+			log.Error().Msgf("failed to record the end of resumed load test %s; %v", state.LoadTestKey, err)
+		}
+	}
+
+	loadTestDone <- true
+	close(loadTestDone)
+	<-dataParam.Finished
+
+	resultPath := fmt.Sprintf("%s/%s_result.csv", utils.JoinRootPathWith("/result/"+state.LoadTestKey), state.LoadTestKey)
+	if !utils.ExistCheck(resultPath) {
+		l.failResumedRun(ctx, state, "cm-ant restarted during the run and its results could not be collected afterwards; run the test again")
+		return
+	}
+
+	if fired := dataParam.AutoStop.firedRule(); fired != "" {
+		l.failResumedRun(ctx, state, fmt.Sprintf("load test stopped by an auto-stop rule; %s", fired))
+		return
+	}
+
+	state.ExecutionStatus = constant.Successed
+	if param.Sla != nil {
+		l.judgeSla(ctx, param.LoadTestKey, param.Sla, state)
+	}
+	l.judgeBaseline(ctx, state)
+	if err := l.loadRepo.UpdateLoadTestExecutionStateTx(ctx, state); err != nil {
+		log.Error().Msgf("Error updating load test execution state: %v", err)
+		return
+	}
+	l.discardRawResults(ctx, state.LoadTestKey)
+	log.Info().Msgf("successfully done resumed load test for %s", state.LoadTestKey)
+}
+
+// awaitEngineExit waits until the engine of the run is running on none of its servers, and
+// reports whether it was still running when first asked. A generator that cannot be asked is
+// waited for until a while after the run was expected to end.
+func (l *LoadService) awaitEngineExit(state *LoadTestExecutionState, location constant.InstallLocation, servers []LoadGeneratorServer) bool {
+	engine, err := engineFor(state.Engine)
+	if err != nil {
+		return false
+	}
+	deadline := state.ExpectedFinishAt.Add(resumeProbeGrace)
+
+	for first := true; ; first = false {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		running, err := l.engineRunning(ctx, engine.RunningCmd(state.LoadTestKey), location, servers)
+		cancel()
+		if err != nil {
+			log.Warn().Msgf("could not ask the generator whether load test %s is running; %v", state.LoadTestKey, err)
+			running = time.Now().Before(deadline)
+		}
+		if !running {
+			return !first
+		}
+		time.Sleep(defaultFetchIntervalSec * time.Second)
+	}
+}
+
+// engineRunning reports whether runningCmd finds the engine running on any of servers.
+func (l *LoadService) engineRunning(ctx context.Context, runningCmd string, location constant.InstallLocation, servers []LoadGeneratorServer) (bool, error) {
+	if location == constant.Local {
+		out, err := utils.InlineCmdOutput(runningCmd)
+		return strings.Contains(out, engineRunningMark), err
+	}
+
+	nsId, mciId, _, _ := getResourceNames()
+	for _, server := range servers {
+		out, err := l.commandToServer(ctx, nsId, mciId, server, runningCmd)
+		if err != nil {
+			return false, err
+		}
+		if strings.Contains(out, engineRunningMark) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// settleLostRun fails a run that cannot be picked up again, and the steps it left running.
+func (l *LoadService) settleLostRun(ctx context.Context, state *LoadTestExecutionState, steps []LoadTestExecutionStep, step constant.ExecutionStep) error {
+	message := "cm-ant restarted before the run got going; run the test again"
+	if step != "" {
+		message = fmt.Sprintf("cm-ant restarted during %s and the run could not be picked up again; run the test again", step)
+	}
+	log.Warn().Msgf("load test %s is lost; %s", state.LoadTestKey, message)
+
+	rec := l.newStepRecorder(state)
+	for _, s := range steps {
+		if s.Status == constant.StepRunning {
+			rec.fail(s.Name, "Interrupted by a cm-ant restart", message)
+		}
+	}
+	return l.failResumedRun(ctx, state, message)
+}
+
+func (l *LoadService) failResumedRun(ctx context.Context, state *LoadTestExecutionState, message string) error {
+	finishAt := time.Now()
+	state.ExecutionStatus = constant.TestFailed
+	state.FailureMessage = message
+	state.FinishAt = &finishAt
+	return l.loadRepo.UpdateLoadTestExecutionStateTx(ctx, state)
+}
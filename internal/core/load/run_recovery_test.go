@@ -0,0 +1,113 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func TestResumeActionFollowsWhereTheRunStopped(t *testing.T) {
+	step := func(name constant.ExecutionStep, status constant.StepStatus) LoadTestExecutionStep {
+		return LoadTestExecutionStep{Name: name, Status: status}
+	}
+	prepared := []LoadTestExecutionStep{
+		step(constant.StepPrecheck, constant.StepOk),
+		step(constant.StepGeneratorInstall, constant.StepOk),
+		step(constant.StepAgentInstall, constant.StepSkipped),
+		step(constant.StepJmxPrepare, constant.StepOk),
+	}
+	processing := LoadTestExecutionState{ExecutionStatus: constant.OnProcessing, GeneratorInstallInfoId: 1}
+
+	for name, tc := range map[string]struct {
+		state      LoadTestExecutionState
+		steps      []LoadTestExecutionStep
+		wantAction resumeAction
+		wantStep   constant.ExecutionStep
+	}{
+		"load running": {
+			state:      processing,
+			steps:      append(prepared, step(constant.StepJmeterRun, constant.StepRunning), step(constant.SubLoadHold, constant.StepRunning)),
+			wantAction: resumeAwait,
+			wantStep:   constant.StepJmeterRun,
+		},
+		"load ended before the state was moved on": {
+			state:      processing,
+			steps:      append(prepared, step(constant.StepJmeterRun, constant.StepOk)),
+			wantAction: resumeFetch,
+			wantStep:   constant.StepResultFetch,
+		},
+		"fetching": {
+			state:      LoadTestExecutionState{ExecutionStatus: constant.OnFetching, GeneratorInstallInfoId: 1},
+			steps:      append(prepared, step(constant.StepJmeterRun, constant.StepOk), step(constant.StepResultFetch, constant.StepRunning)),
+			wantAction: resumeFetch,
+			wantStep:   constant.StepResultFetch,
+		},
+		"preparing the plan": {
+			state:      processing,
+			steps:      append(prepared[:3:3], step(constant.StepJmxPrepare, constant.StepRunning)),
+			wantAction: resumeLost,
+			wantStep:   constant.StepJmxPrepare,
+		},
+		"installing the generator": {
+			state:      LoadTestExecutionState{ExecutionStatus: constant.OnProcessing},
+			steps:      []LoadTestExecutionStep{step(constant.StepPrecheck, constant.StepOk), step(constant.StepGeneratorInstall, constant.StepRunning)},
+			wantAction: resumeLost,
+			wantStep:   constant.StepGeneratorInstall,
+		},
+		"not started": {
+			state:      LoadTestExecutionState{ExecutionStatus: constant.OnProcessing},
+			steps:      []LoadTestExecutionStep{step(constant.StepPrecheck, constant.StepPending)},
+			wantAction: resumeLost,
+			wantStep:   "",
+		},
+	} {
+		action, step := resumeActionOf(tc.state, tc.steps)
+		if action != tc.wantAction || step != tc.wantStep {
+			t.Errorf("%s: expected %d at %q, got %d at %q", name, tc.wantAction, tc.wantStep, action, step)
+		}
+	}
+}
+
+func TestRunParamOfKeepsWhereTheRunRan(t *testing.T) {
+	id := uint(7)
+	param := runParamOf(LoadTestExecutionInfo{
+		LoadTestKey:                "key",
+		VirtualUsers:               "10",
+		Engine:                     constant.K6,
+		Regions:                    []LoadTestExecutionRegion{{Provider: "aws", Region: "ap-northeast-2", Weight: 2}},
+		UploadedFiles:              []LoadTestExecutionUploadedFile{{FileName: "data.csv"}, {FileName: "plan.jmx", IsTestPlan: true}},
+		LoadGeneratorInstallInfoId: &id,
+	})
+	if param.LoadGeneratorInstallInfoId != id || len(param.Regions) != 1 || param.Regions[0].Weight != 2 {
+		t.Errorf("expected the generator and regions of the run, got %+v", param)
+	}
+	if param.TestPlan == nil || param.TestPlan.FileName != "plan.jmx" {
+		t.Errorf("expected the run to be known as an uploaded plan run, got %+v", param.TestPlan)
+	}
+	if param.Sla != nil {
+		t.Errorf("expected a run without thresholds to have none, got %+v", param.Sla)
+	}
+}
+
+func TestRunIsOnlyTakenUpOnceItsOwnerHasLeft(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	for name, tc := range map[string]struct {
+		entry LoadTestQueuedRun
+		want  bool
+	}{
+		"beating":                          {LoadTestQueuedRun{OwnerId: "a", HeartbeatAt: at(-runHeartbeatInterval)}, false},
+		"a beat late":                      {LoadTestQueuedRun{OwnerId: "a", HeartbeatAt: at(-runOwnerExpiry + time.Second)}, false},
+		"expired":                          {LoadTestQueuedRun{OwnerId: "a", HeartbeatAt: at(-runOwnerExpiry - time.Second)}, true},
+		"left to the next server":          {LoadTestQueuedRun{}, true},
+		"started before there were owners": {LoadTestQueuedRun{HeartbeatAt: at(0)}, true},
+	} {
+		if got := ownerLeft(tc.entry, now); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, got)
+		}
+	}
+}
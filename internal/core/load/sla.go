@@ -0,0 +1,104 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+	"github.com/cloud-barista/cm-ant/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// A run ends as Successed whenever the generator exits cleanly, which says nothing about how
+// the target held up. The SLA of a run is checked once its result file is in, and its verdict
+// is kept with the run's state next to the status.
+
+// ValidateSla checks the thresholds of a run before it is accepted.
+func ValidateSla(sla *RunLoadTestSlaParam) error {
+	if sla == nil {
+		return nil
+	}
+	thresholds := []struct {
+		name  string
+		value *float64
+	}{
+		{"maxErrorPercent", sla.MaxErrorPercent},
+		{"maxP95", sla.MaxP95},
+		{"maxP99", sla.MaxP99},
+		{"minThroughput", sla.MinThroughput},
+	}
+	for _, t := range thresholds {
+		if t.value != nil && *t.value < 0 {
+			return fmt.Errorf("sla %s must not be negative", t.name)
+		}
+	}
+	if sla.MaxErrorPercent != nil && *sla.MaxErrorPercent > 100 {
+		return errors.New("sla maxErrorPercent must be at most 100")
+	}
+	return nil
+}
+
+// evaluateSlaOf judges a run by s, the statistics of all of its requests taken together; s is
+// nil when none was recorded. A label that is slow but rarely called should not fail a run its
+// SLA describes as a whole.
+func evaluateSlaOf(sla *RunLoadTestSlaParam, s *LoadTestStatistics) (constant.SlaVerdict, []LoadTestExecutionSlaViolation) {
+	if s == nil {
+		return constant.SlaFail, []LoadTestExecutionSlaViolation{{
+			Criterion: constant.ResultCriterion,
+			Message:   "no request was recorded",
+		}}
+	}
+
+	var violations []LoadTestExecutionSlaViolation
+	if v := sla.MaxErrorPercent; v != nil && s.ErrorPercent > *v {
+		violations = append(violations, slaViolation(constant.MaxErrorPercentCriterion, *v, s.ErrorPercent,
+			"error rate %.2f%% is above %.2f%%"))
+	}
+	if v := sla.MaxP95; v != nil && s.NinetyFive > *v {
+		violations = append(violations, slaViolation(constant.MaxP95Criterion, *v, s.NinetyFive,
+			"p95 %.2f ms is above %.2f ms"))
+	}
+	if v := sla.MaxP99; v != nil && s.NinetyNine > *v {
+		violations = append(violations, slaViolation(constant.MaxP99Criterion, *v, s.NinetyNine,
+			"p99 %.2f ms is above %.2f ms"))
+	}
+	if v := sla.MinThroughput; v != nil && s.Throughput < *v {
+		violations = append(violations, slaViolation(constant.MinThroughputCriterion, *v, s.Throughput,
+			"throughput %.2f req/s is below %.2f req/s"))
+	}
+
+	if len(violations) > 0 {
+		return constant.SlaFail, violations
+	}
+	return constant.SlaPass, nil
+}
+
+// slaViolation records a missed threshold; format takes the actual value, then the threshold.
+func slaViolation(criterion constant.SlaCriterion, threshold, actual float64, format string) LoadTestExecutionSlaViolation {
+	return LoadTestExecutionSlaViolation{
+		Criterion: criterion,
+		Threshold: threshold,
+		Actual:    actual,
+		Message:   fmt.Sprintf(format, actual, threshold),
+	}
+}
+
+// judgeSla reads the result of a finished run and records its verdict on the state. A result
+// that cannot be read fails the run: an SLA nobody could check has not been met.
+func (l *LoadService) judgeSla(ctx context.Context, loadTestKey string, sla *RunLoadTestSlaParam, state *LoadTestExecutionState) {
+	resultPath := fmt.Sprintf("%s/%s_result.csv", utils.JoinRootPathWith("/result/"+loadTestKey), loadTestKey)
+	digest, err := digestResult(l.resultEngineOf(ctx, loadTestKey), resultPath, 0)
+	if err != nil {
+		log.Error().Msgf("could not read the result of %s to check its sla; %v", loadTestKey, err)
+		state.Verdict = constant.SlaFail
+		state.SlaViolations = []LoadTestExecutionSlaViolation{{
+			Criterion: constant.ResultCriterion,
+			Message:   fmt.Sprintf("result could not be read; %v", err),
+		}}
+		return
+	}
+
+	state.Verdict, state.SlaViolations = evaluateSlaOf(sla, digest.total.statistics("total"))
+	log.Info().Msgf("sla verdict of %s is %s with %d violation(s)", loadTestKey, state.Verdict, len(state.SlaViolations))
+}
@@ -0,0 +1,65 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestSlaIsJudgedOnAllRequestsTogether(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := map[string][]*ResultRawData{}
+	// ten requests over nine seconds, one of them an error and one of them slow
+	for i := 0; i < 10; i++ {
+		label := "GET Request"
+		if i%2 == 1 {
+			label = "POST Request"
+		}
+		r := &ResultRawData{Elapsed: 100, Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if i == 3 {
+			r.IsError = true
+		}
+		if i == 7 {
+			r.Elapsed = 2000
+		}
+		results[label] = append(results[label], r)
+	}
+
+	total := digestOf(t, results, 0).total.statistics(comparisonTotalLabel)
+	verdict, violations := evaluateSlaOf(&RunLoadTestSlaParam{
+		MaxErrorPercent: floatPtr(20),
+		MaxP99:          floatPtr(2000),
+	}, total)
+	if verdict != constant.SlaPass || len(violations) != 0 {
+		t.Errorf("expected PASS, got %s with %v", verdict, violations)
+	}
+
+	verdict, violations = evaluateSlaOf(&RunLoadTestSlaParam{
+		MaxErrorPercent: floatPtr(5),
+		MaxP95:          floatPtr(1000),
+		MinThroughput:   floatPtr(2),
+	}, total)
+	if verdict != constant.SlaFail {
+		t.Fatalf("expected FAIL, got %s", verdict)
+	}
+	want := []constant.SlaCriterion{constant.MaxErrorPercentCriterion, constant.MaxP95Criterion, constant.MinThroughputCriterion}
+	if len(violations) != len(want) {
+		t.Fatalf("expected %d violations, got %v", len(want), violations)
+	}
+	for i, v := range violations {
+		if v.Criterion != want[i] {
+			t.Errorf("violation %d is %s, want %s", i, v.Criterion, want[i])
+		}
+	}
+	if violations[0].Actual != 10 || violations[0].Message != "error rate 10.00% is above 5.00%" {
+		t.Errorf("unexpected error rate violation %+v", violations[0])
+	}
+
+	verdict, violations = evaluateSlaOf(&RunLoadTestSlaParam{MaxP95: floatPtr(1000)}, nil)
+	if verdict != constant.SlaFail || len(violations) != 1 || violations[0].Criterion != constant.ResultCriterion {
+		t.Errorf("expected a run without results to fail, got %s with %v", verdict, violations)
+	}
+}
@@ -0,0 +1,368 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+// A migrated workload is rarely only REST. Targets cover what sits behind it - a WebSocket
+// endpoint, a gRPC service, a raw TCP port, a database - with a sampler of their own in the
+// same thread group as the HTTP requests, so the load profile and the result are shared.
+//
+// TCP and JDBC samplers ship with JMeter. WebSocket and gRPC need plugins, and JDBC needs the
+// database's driver; the engine installs what a run needs before the run, once per generator.
+
+// defaultTargetTimeoutMs is how long an exchange with a target may take when it does not say.
+const defaultTargetTimeoutMs = 10000
+
+// grpcMethod is a fully qualified method: package.Service/Method.
+var grpcMethod = regexp.MustCompile(`^[A-Za-z_][\w.]*/[A-Za-z_]\w*$`)
+
+// jmeterTargetPlugins are the plugins a protocol needs, by their Plugins Manager id, with the
+// prefix their jar is installed under.
+var jmeterTargetPlugins = map[constant.TargetProtocol]struct{ id, jar string }{
+	constant.WebSocketTarget: {id: "websocket-samplers", jar: "jmeter-websocket-samplers"},
+	constant.GrpcTarget:      {id: "jmeter-grpc-request", jar: "jmeter-grpc-request"},
+}
+
+type jdbcDriver struct {
+	class     string
+	urlScheme string
+	jar       string
+	jarUrl    string
+}
+
+var jdbcDrivers = map[constant.JdbcDriver]jdbcDriver{
+	constant.PostgresDriver: {
+		class:     "org.postgresql.Driver",
+		urlScheme: "jdbc:postgresql",
+		jar:       "postgresql-42.7.4.jar",
+		jarUrl:    "https://repo1.maven.org/maven2/org/postgresql/postgresql/42.7.4/postgresql-42.7.4.jar",
+	},
+	constant.MysqlDriver: {
+		class:     "com.mysql.cj.jdbc.Driver",
+		urlScheme: "jdbc:mysql",
+		jar:       "mysql-connector-j-8.4.0.jar",
+		jarUrl:    "https://repo1.maven.org/maven2/com/mysql/mysql-connector-j/8.4.0/mysql-connector-j-8.4.0.jar",
+	},
+}
+
+// ValidateTargets checks the targets of a run. JMeter reads the byte that ends a TCP answer
+// once per run, so every tcp target has to agree on it.
+func ValidateTargets(targets []RunLoadTestTargetParam) error {
+	var firstTcp *RunLoadTestTargetParam
+	for i, t := range targets {
+		if strings.TrimSpace(t.Hostname) == "" {
+			return fmt.Errorf("target %d needs a hostname", i+1)
+		}
+		if port, err := strconv.Atoi(strings.TrimSpace(t.Port)); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("target %d port %q is not a port number", i+1, t.Port)
+		}
+		if t.Timeout < 0 {
+			return fmt.Errorf("target %d timeout must not be negative", i+1)
+		}
+
+		switch t.Protocol {
+		case constant.WebSocketTarget:
+			if t.Message == "" {
+				return fmt.Errorf("websocket target %d needs a message to send", i+1)
+			}
+		case constant.GrpcTarget:
+			if !grpcMethod.MatchString(strings.TrimSpace(t.Method)) {
+				return fmt.Errorf("grpc target %d method %q must look like package.Service/Method", i+1, t.Method)
+			}
+			if len(t.ProtoFile) == 0 || !strings.HasSuffix(t.ProtoFileName, ".proto") {
+				return fmt.Errorf("grpc target %d needs the .proto file that defines %s", i+1, t.Method)
+			}
+		case constant.TcpTarget:
+			if t.Message == "" {
+				return fmt.Errorf("tcp target %d needs a message to send", i+1)
+			}
+			if t.EolByte != nil && (*t.EolByte < 0 || *t.EolByte > 127) {
+				return fmt.Errorf("tcp target %d eolByte must be in 0 to 127", i+1)
+			}
+			if firstTcp != nil && !sameEolByte(firstTcp.EolByte, t.EolByte) {
+				return errors.New("tcp targets of a run must all end their answers with the same eolByte")
+			}
+			if firstTcp == nil {
+				firstTcp = &targets[i]
+			}
+		case constant.JdbcTarget:
+			if _, ok := jdbcDrivers[t.Driver]; !ok {
+				return fmt.Errorf("jdbc target %d driver %q is not supported", i+1, t.Driver)
+			}
+			if strings.TrimSpace(t.Database) == "" || strings.TrimSpace(t.Query) == "" {
+				return fmt.Errorf("jdbc target %d needs a database and a query", i+1)
+			}
+		default:
+			return fmt.Errorf("target protocol %q is not supported", t.Protocol)
+		}
+	}
+	return nil
+}
+
+func sameEolByte(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// tcpEolByte returns the byte the tcp targets of a run end their answers with, if they set one.
+func tcpEolByte(targets []RunLoadTestTargetParam) *int {
+	for _, t := range targets {
+		if t.Protocol == constant.TcpTarget {
+			return t.EolByte
+		}
+	}
+	return nil
+}
+
+// protoDir keeps the .proto of every gRPC target in a folder of its own, since the plugin reads
+// every file of the folder it is given.
+func protoDir(targetIndex int) string {
+	return fmt.Sprintf("target_%d", targetIndex)
+}
+
+// isSelectQuery tells a query that returns rows from one that changes them, which JDBC runs
+// differently.
+func isSelectQuery(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToLower(fields[0]) {
+	case "select", "with", "show", "explain", "values":
+		return true
+	}
+	return false
+}
+
+type jmxTargetTemplateData struct {
+	Index    int
+	Hostname string
+	Port     string
+	Timeout  int
+
+	Path    string
+	Secure  bool
+	Message string
+
+	Method      string
+	ProtoFolder string
+
+	DataSource string
+	DbUrl      string
+	Driver     string
+	Username   string
+	Password   string
+	Query      string
+	QueryType  string
+}
+
+// jmxTargetTemplate renders a target's sampler. A JDBC sampler reads its connection from the
+// data source named after the target, which is rendered with it.
+var jmxTargetTemplate = map[constant.TargetProtocol]string{
+	constant.WebSocketTarget: `
+	<eu.luminis.jmeter.wssampler.RequestResponseWebSocketSampler guiclass="eu.luminis.jmeter.wssampler.RequestResponseWebSocketSamplerGui" testclass="eu.luminis.jmeter.wssampler.RequestResponseWebSocketSampler" testname="WebSocket Request" enabled="true">
+		<boolProp name="createNewConnection">true</boolProp>
+		<boolProp name="TLS">{{.Secure}}</boolProp>
+		<stringProp name="server">{{.Hostname}}</stringProp>
+		<stringProp name="port">{{.Port}}</stringProp>
+		<stringProp name="path">{{.Path}}</stringProp>
+		<stringProp name="connectTimeout">{{.Timeout}}</stringProp>
+		<stringProp name="payloadType">Text</stringProp>
+		<stringProp name="requestData">{{.Message}}</stringProp>
+		<stringProp name="readTimeout">{{.Timeout}}</stringProp>
+		<boolProp name="loadDataFromFile">false</boolProp>
+		<stringProp name="dataFile"></stringProp>
+	</eu.luminis.jmeter.wssampler.RequestResponseWebSocketSampler>
+	<hashTree/>
+	`,
+	constant.GrpcTarget: `
+	<vn.zalopay.benchmark.GRPCSampler guiclass="vn.zalopay.benchmark.GRPCSamplerGui" testclass="vn.zalopay.benchmark.GRPCSampler" testname="gRPC Request" enabled="true">
+		<stringProp name="GRPCSampler.protoFolder">{{.ProtoFolder}}</stringProp>
+		<stringProp name="GRPCSampler.libFolder"></stringProp>
+		<stringProp name="GRPCSampler.metadata"></stringProp>
+		<stringProp name="GRPCSampler.host">{{.Hostname}}</stringProp>
+		<stringProp name="GRPCSampler.port">{{.Port}}</stringProp>
+		<stringProp name="GRPCSampler.fullMethod">{{.Method}}</stringProp>
+		<stringProp name="GRPCSampler.deadline">{{.Timeout}}</stringProp>
+		<boolProp name="GRPCSampler.tls">{{.Secure}}</boolProp>
+		<boolProp name="GRPCSampler.tlsDisableVerification">true</boolProp>
+		<stringProp name="GRPCSampler.channelAwaitTermination">1000</stringProp>
+		<stringProp name="GRPCSampler.requestJson">{{.Message}}</stringProp>
+	</vn.zalopay.benchmark.GRPCSampler>
+	<hashTree/>
+	`,
+	constant.TcpTarget: `
+	<TCPSampler guiclass="TCPSamplerGui" testclass="TCPSampler" testname="TCP Request" enabled="true">
+		<stringProp name="TCPSampler.server">{{.Hostname}}</stringProp>
+		<stringProp name="TCPSampler.port">{{.Port}}</stringProp>
+		<boolProp name="TCPSampler.reUseConnection">false</boolProp>
+		<boolProp name="TCPSampler.closeConnection">true</boolProp>
+		<boolProp name="TCPSampler.nodelay">false</boolProp>
+		<stringProp name="TCPSampler.classname">TCPClientImpl</stringProp>
+		<stringProp name="TCPSampler.ctimeout">{{.Timeout}}</stringProp>
+		<stringProp name="TCPSampler.timeout">{{.Timeout}}</stringProp>
+		<stringProp name="TCPSampler.request">{{.Message}}</stringProp>
+	</TCPSampler>
+	<hashTree/>
+	`,
+	constant.JdbcTarget: `
+	<JDBCDataSource guiclass="TestBeanGUI" testclass="JDBCDataSource" testname="JDBC Connection {{.Index}}" enabled="true">
+		<stringProp name="dataSource">{{.DataSource}}</stringProp>
+		<stringProp name="dbUrl">{{.DbUrl}}</stringProp>
+		<stringProp name="driver">{{.Driver}}</stringProp>
+		<stringProp name="username">{{.Username}}</stringProp>
+		<stringProp name="password">{{.Password}}</stringProp>
+		<stringProp name="poolMax">0</stringProp>
+		<stringProp name="timeout">{{.Timeout}}</stringProp>
+		<stringProp name="trimInterval">60000</stringProp>
+		<boolProp name="autocommit">true</boolProp>
+		<stringProp name="transactionIsolation">DEFAULT</stringProp>
+		<boolProp name="preinit">false</boolProp>
+		<stringProp name="initQuery"></stringProp>
+		<boolProp name="keepAlive">true</boolProp>
+		<stringProp name="connectionAge">5000</stringProp>
+		<stringProp name="checkQuery"></stringProp>
+		<stringProp name="connectionProperties"></stringProp>
+	</JDBCDataSource>
+	<hashTree/>
+	<JDBCSampler guiclass="TestBeanGUI" testclass="JDBCSampler" testname="JDBC Request" enabled="true">
+		<stringProp name="dataSource">{{.DataSource}}</stringProp>
+		<stringProp name="queryType">{{.QueryType}}</stringProp>
+		<stringProp name="query">{{.Query}}</stringProp>
+		<stringProp name="queryArguments"></stringProp>
+		<stringProp name="queryArgumentsTypes"></stringProp>
+		<stringProp name="variableNames"></stringProp>
+		<stringProp name="resultVariable"></stringProp>
+		<stringProp name="queryTimeout">{{.Timeout}}</stringProp>
+		<stringProp name="resultSetMaxRows"></stringProp>
+		<stringProp name="resultSetHandler">Store as String</stringProp>
+	</JDBCSampler>
+	<hashTree/>
+	`,
+}
+
+// targetParseToJmx renders the targets of a run into samplers.
+func targetParseToJmx(targets []RunLoadTestTargetParam, attachmentDir string) (string, error) {
+	if err := ValidateTargets(targets); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for i, t := range targets {
+		tmpl, err := template.New(fmt.Sprintf("jmxTarget-%d-%s", i, t.Protocol)).Parse(jmxTargetTemplate[t.Protocol])
+		if err != nil {
+			return "", err
+		}
+
+		timeout := t.Timeout
+		if timeout == 0 {
+			timeout = defaultTargetTimeoutMs
+		}
+		path := strings.TrimSpace(t.Path)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		data := jmxTargetTemplateData{
+			Index:    i,
+			Hostname: html.EscapeString(strings.TrimSpace(t.Hostname)),
+			Port:     html.EscapeString(strings.TrimSpace(t.Port)),
+			Timeout:  timeout,
+			Path:     html.EscapeString(path),
+			Secure:   t.Secure,
+			Message:  html.EscapeString(t.Message),
+			Method:   html.EscapeString(strings.TrimSpace(t.Method)),
+		}
+		switch t.Protocol {
+		case constant.GrpcTarget:
+			data.ProtoFolder = html.EscapeString(fmt.Sprintf("%s/%s", attachmentDir, protoDir(i)))
+		case constant.JdbcTarget:
+			driver := jdbcDrivers[t.Driver]
+			data.DataSource = fmt.Sprintf("ant_jdbc_%d", i)
+			data.DbUrl = html.EscapeString(fmt.Sprintf("%s://%s:%s/%s", driver.urlScheme,
+				strings.TrimSpace(t.Hostname), strings.TrimSpace(t.Port), strings.TrimSpace(t.Database)))
+			data.Driver = driver.class
+			data.Username = html.EscapeString(t.Username)
+			data.Password = html.EscapeString(t.Password)
+			data.Query = html.EscapeString(strings.TrimSpace(t.Query))
+			data.QueryType = "Update Statement"
+			if isSelectQuery(t.Query) {
+				data.QueryType = "Select Statement"
+			}
+		}
+
+		if err := tmpl.Execute(&builder, data); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// targetAttachments are the .proto files the gRPC targets read.
+func targetAttachments(targets []RunLoadTestTargetParam) []planAttachment {
+	var files []planAttachment
+	for i, t := range targets {
+		if t.Protocol == constant.GrpcTarget {
+			name := fmt.Sprintf("%s/%s", protoDir(i), filepath.Base(t.ProtoFileName))
+			files = append(files, planAttachment{Name: name, Content: t.ProtoFile})
+		}
+	}
+	return files
+}
+
+// targetInstallCmd makes JMeter able to run the targets: it installs the plugins and drivers
+// they need unless they are already in place. "" when nothing is needed.
+func targetInstallCmd(targets []RunLoadTestTargetParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) string {
+	plugins := map[string]string{}
+	drivers := map[string]string{}
+	for _, t := range targets {
+		if p, ok := jmeterTargetPlugins[t.Protocol]; ok {
+			plugins[p.id] = p.jar
+		}
+		if t.Protocol == constant.JdbcTarget {
+			if d, ok := jdbcDrivers[t.Driver]; ok {
+				drivers[d.jar] = d.jarUrl
+			}
+		}
+	}
+	if len(plugins) == 0 && len(drivers) == 0 {
+		return ""
+	}
+
+	installPath := loadGeneratorInstallInfo.InstallPath
+	var builder strings.Builder
+	builder.WriteString("set -e\n")
+	builder.WriteString(fmt.Sprintf("jmeter=%s/apache-jmeter-%s\n", installPath, loadGeneratorInstallInfo.InstallVersion))
+	// non-interactive ssh does not read the profile the install script puts java on the path with
+	builder.WriteString(fmt.Sprintf("java=%s/jdk/bin/java; [ -x \"$java\" ] || java=java\n", installPath))
+	for _, id := range sortedKeys(plugins) {
+		builder.WriteString(fmt.Sprintf(
+			"ls \"$jmeter/lib/ext\" | grep -q '^%s' || sudo \"$java\" -jar \"$jmeter/lib/cmdrunner-2.2.1.jar\" --tool org.jmeterplugins.repository.PluginManagerCMD install %s\n",
+			plugins[id], id))
+	}
+	for _, jar := range sortedKeys(drivers) {
+		builder.WriteString(fmt.Sprintf("[ -f \"$jmeter/lib/%s\" ] || sudo curl -fsSL -o \"$jmeter/lib/%s\" %s\n", jar, jar, drivers[jar]))
+	}
+	return builder.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package load
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/cm-ant/internal/core/common/constant"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestTargetsRenderIntoSamplers(t *testing.T) {
+	targets := []RunLoadTestTargetParam{
+		{Protocol: constant.WebSocketTarget, Hostname: "ws.local", Port: "8080", Path: "chat", Message: `{"say":"<hi>"}`},
+		{Protocol: constant.GrpcTarget, Hostname: "grpc.local", Port: "50051", Method: "shop.Cart/Add",
+			Message: `{"item":1}`, ProtoFileName: "cart.proto", ProtoFile: []byte(`syntax = "proto3";`)},
+		{Protocol: constant.TcpTarget, Hostname: "tcp.local", Port: "7", Message: "ping", Timeout: 2000},
+		{Protocol: constant.JdbcTarget, Hostname: "db.local", Port: "5432", Driver: constant.PostgresDriver,
+			Database: "shop", Username: "ant", Password: "p&ss", Query: "SELECT 1"},
+	}
+	out, err := targetParseToJmx(targets, "/opt/ant/test_plan/key")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	wellFormed(t, out)
+
+	for _, want := range []string{
+		`<stringProp name="path">/chat</stringProp>`,
+		`<stringProp name="requestData">{&#34;say&#34;:&#34;&lt;hi&gt;&#34;}</stringProp>`,
+		`<stringProp name="GRPCSampler.protoFolder">/opt/ant/test_plan/key/target_1</stringProp>`,
+		`<stringProp name="GRPCSampler.fullMethod">shop.Cart/Add</stringProp>`,
+		`<stringProp name="TCPSampler.timeout">2000</stringProp>`,
+		`<stringProp name="dbUrl">jdbc:postgresql://db.local:5432/shop</stringProp>`,
+		`<stringProp name="password">p&amp;ss</stringProp>`,
+		`<stringProp name="queryType">Select Statement</stringProp>`,
+		`<stringProp name="dataSource">ant_jdbc_3</stringProp>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the samplers to contain %s", want)
+		}
+	}
+
+	files := targetAttachments(targets)
+	if len(files) != 1 || files[0].Name != "target_1/cart.proto" {
+		t.Errorf("expected the proto file in the target's folder, got %+v", files)
+	}
+}
+
+func TestTargetsAreValidated(t *testing.T) {
+	for name, targets := range map[string][]RunLoadTestTargetParam{
+		"unknown protocol": {{Protocol: "amqp", Hostname: "h", Port: "5672"}},
+		"bad port":         {{Protocol: constant.TcpTarget, Hostname: "h", Port: "0", Message: "a"}},
+		"grpc method":      {{Protocol: constant.GrpcTarget, Hostname: "h", Port: "1", Method: "Add", ProtoFileName: "a.proto", ProtoFile: []byte("x")}},
+		"jdbc driver":      {{Protocol: constant.JdbcTarget, Hostname: "h", Port: "1", Driver: "oracle", Database: "d", Query: "SELECT 1"}},
+		"eol byte range":   {{Protocol: constant.TcpTarget, Hostname: "h", Port: "1", Message: "a", EolByte: intPtr(200)}},
+		"eol byte differs": {
+			{Protocol: constant.TcpTarget, Hostname: "h", Port: "1", Message: "a", EolByte: intPtr(10)},
+			{Protocol: constant.TcpTarget, Hostname: "h", Port: "1", Message: "b"},
+		},
+	} {
+		if err := ValidateTargets(targets); err == nil {
+			t.Errorf("%s: expected the targets to be rejected", name)
+		}
+	}
+}
+
+func TestJmeterInstallsWhatTheTargetsNeed(t *testing.T) {
+	info := &LoadGeneratorInstallInfo{InstallPath: "/opt/ant", InstallVersion: "5.6"}
+	param := RunLoadTestParam{LoadTestKey: "key", Targets: []RunLoadTestTargetParam{
+		{Protocol: constant.TcpTarget, Hostname: "h", Port: "7", Message: "ping", EolByte: intPtr(10)},
+	}}
+	if cmd := (jmeterEngine{}).InstallCmd(param, info); cmd != "" {
+		t.Errorf("expected tcp to need nothing installed, got:\n%s", cmd)
+	}
+	if cmd := (jmeterEngine{}).RunCmd(param, info); !strings.Contains(cmd, " -Jtcp.eolByte=10") {
+		t.Errorf("expected the eol byte to be passed to jmeter, got:\n%s", cmd)
+	}
+
+	param.Targets = append(param.Targets,
+		RunLoadTestTargetParam{Protocol: constant.GrpcTarget},
+		RunLoadTestTargetParam{Protocol: constant.JdbcTarget, Driver: constant.MysqlDriver})
+	cmd := (jmeterEngine{}).InstallCmd(param, info)
+	for _, want := range []string{"PluginManagerCMD install jmeter-grpc-request", "mysql-connector-j-8.4.0.jar"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("expected the install command to contain %q, got:\n%s", want, cmd)
+		}
+	}
+
+	if err := (k6Engine{}).Validate(param); err == nil {
+		t.Error("expected k6 to refuse protocol targets")
+	}
+}
+
+func TestPostgresProbeNeedsAPostgresAnswer(t *testing.T) {
+	for answer, wantErr := range map[byte]bool{'N': false, 'H': true} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			buf := make([]byte, 8)
+			_, _ = conn.Read(buf)
+			_, _ = conn.Write([]byte{answer})
+		}()
+
+		host, port, _ := net.SplitHostPort(ln.Addr().String())
+		err = probeProtocolTarget(context.Background(), RunLoadTestTargetParam{
+			Protocol: constant.JdbcTarget, Driver: constant.PostgresDriver, Hostname: host, Port: port,
+		})
+		if (err != nil) != wantErr {
+			t.Errorf("answer %q: got error %v", answer, err)
+		}
+		ln.Close()
+	}
+}
@@ -0,0 +1,244 @@
+package load
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cloud-barista/cm-ant/internal/utils"
+)
+
+// A user supplied plan is run as it is, except for what cm-ant needs to make sense of the run:
+// a result writer with the column layout the result parser reads, the PerfMon collectors when
+// metrics are requested, and CSV Data Set paths that point at where the data files were put
+// on the generator. Everything else - thread groups, samplers, timers - is the user's.
+
+// jmxResultCollectorTemplate is cm-ant's result writer. Its columns are fixed by the flags
+// below rather than left to the generator's jmeter.properties, because the parser reads them
+// by position.
+var jmxResultCollectorTemplate = `
+      <ResultCollector guiclass="SimpleDataWriter" testclass="ResultCollector" testname="cm-ant result writer" enabled="true">
+        <boolProp name="ResultCollector.error_logging">false</boolProp>
+        <objProp>
+          <name>saveConfig</name>
+          <value class="SampleSaveConfiguration">
+            <time>true</time>
+            <latency>true</latency>
+            <timestamp>true</timestamp>
+            <success>true</success>
+            <label>true</label>
+            <code>true</code>
+            <message>true</message>
+            <threadName>true</threadName>
+            <dataType>true</dataType>
+            <encoding>false</encoding>
+            <assertions>true</assertions>
+            <subresults>true</subresults>
+            <responseData>false</responseData>
+            <samplerData>false</samplerData>
+            <xml>false</xml>
+            <fieldNames>true</fieldNames>
+            <responseHeaders>false</responseHeaders>
+            <requestHeaders>false</requestHeaders>
+            <responseDataOnError>false</responseDataOnError>
+            <saveAssertionResultsFailureMessage>true</saveAssertionResultsFailureMessage>
+            <assertionsResultsToSave>0</assertionsResultsToSave>
+            <bytes>true</bytes>
+            <sentBytes>true</sentBytes>
+            <url>true</url>
+            <threadCounts>true</threadCounts>
+            <idleTime>true</idleTime>
+            <connectTime>true</connectTime>
+          </value>
+        </objProp>
+        <stringProp name="filename">{{.}}</stringProp>
+      </ResultCollector>
+      <hashTree/>
+`
+
+var (
+	csvDataSetBlock    = regexp.MustCompile(`(?s)<CSVDataSet\b[^>]*>.*?</CSVDataSet>`)
+	csvDataSetFilename = regexp.MustCompile(`(<stringProp name="filename">)([^<]*)(</stringProp>)`)
+)
+
+// ValidateTestPlan checks an uploaded plan before anything is provisioned for it: that it is a
+// JMeter plan cm-ant can extend, that it has load to generate, and that every data file it
+// reads was uploaded with it.
+func ValidateTestPlan(plan RunLoadTestPlanParam) error {
+	if _, err := testPlanInsertOffset(plan.Content); err != nil {
+		return err
+	}
+	_, err := rewriteDataFilePaths(string(plan.Content), "", plan.DataFiles)
+	return err
+}
+
+// testPlanInsertOffset walks the plan and returns where elements can be added to the test
+// plan's own tree: just before the hashTree that holds the TestPlan's children is closed.
+// Elements placed there apply to the whole plan, the same spot the collectors occupy in
+// default_perfmon.jmx.
+func testPlanInsertOffset(content []byte) (int64, error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+
+	var (
+		stack          []string
+		sawRoot        bool
+		sawTestPlan    bool
+		sawThreadGroup bool
+		insertAt       int64 = -1
+	)
+
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("test plan is not valid XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			sawRoot = true
+			if len(stack) == 1 && t.Name.Local != "jmeterTestPlan" {
+				return 0, fmt.Errorf("test plan root element is %s, expected jmeterTestPlan", t.Name.Local)
+			}
+			if len(stack) == 3 && stack[1] == "hashTree" && t.Name.Local == "TestPlan" {
+				sawTestPlan = true
+			}
+			if isEnabledThreadGroup(t) {
+				sawThreadGroup = true
+			}
+		case xml.EndElement:
+			if len(stack) == 3 && stack[1] == "hashTree" && stack[2] == "hashTree" && insertAt < 0 {
+				insertAt = offset
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	switch {
+	case !sawRoot:
+		return 0, errors.New("test plan is not valid XML: there is no root element")
+	case !sawTestPlan:
+		return 0, errors.New("test plan has no TestPlan element")
+	case !sawThreadGroup:
+		return 0, errors.New("test plan has no enabled thread group, so it would generate no load")
+	case insertAt < 0:
+		return 0, errors.New("test plan has no element tree under its TestPlan")
+	}
+	return insertAt, nil
+}
+
+// isEnabledThreadGroup recognises the stock thread groups and the plugin ones (concurrency,
+// arrival rate, ...), which all carry a testclass ending in ThreadGroup.
+func isEnabledThreadGroup(t xml.StartElement) bool {
+	isThreadGroup := strings.HasSuffix(t.Name.Local, "ThreadGroup")
+	enabled := true
+	for _, a := range t.Attr {
+		switch a.Name.Local {
+		case "testclass":
+			if strings.HasSuffix(a.Value, "ThreadGroup") {
+				isThreadGroup = true
+			}
+		case "enabled":
+			enabled = a.Value != "false"
+		}
+	}
+	return isThreadGroup && enabled
+}
+
+// rewriteDataFilePaths points every enabled CSV Data Set at the uploaded file of the same base
+// name in dataDir. A file name built from a variable is left alone - it cannot be checked here -
+// but a literal one that was not uploaded is an error: the run would fail on the generator
+// after minutes of provisioning over something known now.
+func rewriteDataFilePaths(content, dataDir string, dataFiles []RunLoadTestDataFileParam) (string, error) {
+	uploaded := make(map[string]struct{}, len(dataFiles))
+	for _, f := range dataFiles {
+		uploaded[filepath.Base(f.FileName)] = struct{}{}
+	}
+
+	var missing []string
+	rewritten := csvDataSetBlock.ReplaceAllStringFunc(content, func(block string) string {
+		if strings.Contains(block[:strings.Index(block, ">")], `enabled="false"`) {
+			return block
+		}
+		return csvDataSetFilename.ReplaceAllStringFunc(block, func(prop string) string {
+			m := csvDataSetFilename.FindStringSubmatch(prop)
+			name := html.UnescapeString(m[2])
+			if strings.Contains(name, "${") {
+				return prop
+			}
+			base := filepath.Base(name)
+			if _, ok := uploaded[base]; !ok {
+				missing = append(missing, name)
+				return prop
+			}
+			return m[1] + html.EscapeString(fmt.Sprintf("%s/%s", dataDir, base)) + m[3]
+		})
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("test plan reads data files that were not uploaded: %s", strings.Join(missing, ", "))
+	}
+	return rewritten, nil
+}
+
+// parseUploadedTestPlan writes the user's plan with cm-ant's additions to w.
+func parseUploadedTestPlan(w io.Writer, param RunLoadTestParam, loadGeneratorInstallInfo *LoadGeneratorInstallInfo) error {
+	plan := param.TestPlan
+	resultPath := fmt.Sprintf("%s/result", loadGeneratorInstallInfo.InstallPath)
+	attachmentDir := planAttachmentDir(loadGeneratorInstallInfo.InstallPath, param.LoadTestKey)
+
+	content, err := rewriteDataFilePaths(string(plan.Content), attachmentDir, plan.DataFiles)
+	if err != nil {
+		return err
+	}
+
+	insertAt, err := testPlanInsertOffset([]byte(content))
+	if err != nil {
+		return err
+	}
+
+	var injected bytes.Buffer
+	collector := template.Must(template.New("jmxResultCollector").Parse(jmxResultCollectorTemplate))
+	resultFile := fmt.Sprintf("%s/%s_result.csv", resultPath, param.LoadTestKey)
+	if err := collector.Execute(&injected, html.EscapeString(resultFile)); err != nil {
+		return err
+	}
+
+	if param.AgentHostname != "" {
+		perfmon, err := template.ParseFiles(utils.JoinRootPathWith("/test_plan/perfmon_collectors.jmx"))
+		if err != nil {
+			return err
+		}
+		err = perfmon.Execute(&injected, jmxTemplateData{
+			AgentHost:         param.AgentHostname,
+			AgentPort:         metricAgentPort,
+			CpuResultPath:     fmt.Sprintf("%s/%s_cpu_result.csv", resultPath, param.LoadTestKey),
+			MemoryResultPath:  fmt.Sprintf("%s/%s_memory_result.csv", resultPath, param.LoadTestKey),
+			DiskResultPath:    fmt.Sprintf("%s/%s_disk_result.csv", resultPath, param.LoadTestKey),
+			NetworkResultPath: fmt.Sprintf("%s/%s_network_result.csv", resultPath, param.LoadTestKey),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, content[:insertAt]); err != nil {
+		return err
+	}
+	if _, err := w.Write(injected.Bytes()); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content[insertAt:])
+	return err
+}
@@ -0,0 +1,96 @@
+package load
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const uploadedPlan = `<?xml version="1.0" encoding="UTF-8"?>
+<jmeterTestPlan version="1.2" properties="5.0" jmeter="5.6">
+  <hashTree>
+    <TestPlan guiclass="TestPlanGui" testclass="TestPlan" testname="checkout" enabled="true"/>
+    <hashTree>
+      <ThreadGroup guiclass="ThreadGroupGui" testclass="ThreadGroup" testname="users" enabled="true"/>
+      <hashTree>
+        <CSVDataSet guiclass="TestBeanGUI" testclass="CSVDataSet" testname="users" enabled="true">
+          <stringProp name="filename">/home/me/users.csv</stringProp>
+        </CSVDataSet>
+        <hashTree/>
+        <CSVDataSet guiclass="TestBeanGUI" testclass="CSVDataSet" testname="dynamic" enabled="true">
+          <stringProp name="filename">${__P(data)}</stringProp>
+        </CSVDataSet>
+        <hashTree/>
+      </hashTree>
+    </hashTree>
+  </hashTree>
+</jmeterTestPlan>
+`
+
+func TestUploadedPlanGetsResultWriterAndDataPaths(t *testing.T) {
+	param := RunLoadTestParam{
+		LoadTestKey: "key",
+		TestPlan: &RunLoadTestPlanParam{
+			FileName:  "checkout.jmx",
+			Content:   []byte(uploadedPlan),
+			DataFiles: []RunLoadTestDataFileParam{{FileName: "users.csv", Content: []byte("id\n1\n")}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := parseUploadedTestPlan(&buf, param, &LoadGeneratorInstallInfo{InstallPath: "/opt/ant"}); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	out := buf.String()
+
+	wellFormed(t, out)
+
+	if !strings.Contains(out, `<stringProp name="filename">/opt/ant/test_plan/key/users.csv</stringProp>`) {
+		t.Errorf("expected the data set to read the uploaded file")
+	}
+	if !strings.Contains(out, `<stringProp name="filename">${__P(data)}</stringProp>`) {
+		t.Errorf("a data file named by a variable must be left alone")
+	}
+
+	// the writer belongs to the test plan's own tree, after the user's thread group
+	writer := strings.Index(out, `testname="cm-ant result writer"`)
+	if writer < 0 {
+		t.Fatal("expected the result writer to be injected")
+	}
+	if writer < strings.Index(out, "<ThreadGroup ") || writer > strings.LastIndex(out, "</hashTree>\n  </hashTree>") {
+		t.Errorf("result writer was injected outside the test plan tree:\n%s", out)
+	}
+	if !strings.Contains(out, `<stringProp name="filename">/opt/ant/result/key_result.csv</stringProp>`) {
+		t.Errorf("expected the result writer to write where the fetch step looks")
+	}
+}
+
+func TestUploadedPlanValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		plan    string
+		data    []RunLoadTestDataFileParam
+		wantErr string
+	}{
+		{"not xml", "jmeter", nil, "not valid XML"},
+		{"not a jmeter plan", `<project><hashTree/></project>`, nil, "expected jmeterTestPlan"},
+		{"no thread group", strings.Replace(uploadedPlan, `testclass="ThreadGroup" testname="users" enabled="true"`, `testclass="ThreadGroup" testname="users" enabled="false"`, 1), nil, "no enabled thread group"},
+		{"data file not uploaded", uploadedPlan, nil, "/home/me/users.csv"},
+		{"valid", uploadedPlan, []RunLoadTestDataFileParam{{FileName: "users.csv"}}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTestPlan(RunLoadTestPlanParam{Content: []byte(tc.plan), DataFiles: tc.data})
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected the plan to be accepted, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
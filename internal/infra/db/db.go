@@ -30,9 +30,30 @@ func migrateDB(defaultDb *gorm.DB) error {
 		&load.LoadGeneratorInstallInfo{},
 		&load.LoadTestExecutionInfo{},
 		&load.LoadTestExecutionHttpInfo{},
+		&load.LoadTestExecutionHttpField{},
+		&load.LoadTestExecutionHttpFile{},
+		&load.LoadTestExecutionHttpExtractor{},
+		&load.LoadTestExecutionHttpAssertion{},
+		&load.LoadTestExecutionTarget{},
+		&load.LoadTestExecutionRegion{},
+		&load.LoadTestExecutionUploadedFile{},
+		&load.LoadTestExecutionLoadStage{},
+		&load.LoadTestExecutionDataSet{},
 		&load.LoadTestExecutionState{},
+		&load.LoadTestQueuedRun{},
 		&load.LoadTestExecutionStep{},
+		&load.LoadTestExecutionSlaViolation{},
 		&load.LoadTestScenarioCatalog{},
+		&load.LoadTestScenarioCatalogStage{},
+		&load.LoadTestDataSet{},
+		&load.LoadTestSchedule{},
+		&load.LoadTestScheduleRun{},
+		&load.LoadTestBaseline{},
+		&load.LoadTestResult{},
+		&load.LoadTestResultStatistic{},
+		&load.LoadTestResultSample{},
+		&load.LoadTestResultBucket{},
+		&load.LoadTestResultMetric{},
 
 		&cost.EstimateCostInfo{},
 		&cost.EstimateForecastCostInfo{},
@@ -21,6 +21,20 @@ func InlineCmd(cmdStr string) error {
 	return nil
 }
 
+// InlineCmdOutput runs cmdStr like InlineCmd and returns what it printed.
+func InlineCmdOutput(cmdStr string) (string, error) {
+	cmd := exec.Command("bash", "-c", cmdStr)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Error().Msgf("error while execute bash call; %v", err)
+		log.Error().Msg(string(out))
+		return string(out), err
+	}
+
+	return string(out), nil
+}
+
 func Script(scriptPath string, envs []string, args ...string) error {
 	cmd := exec.Command(scriptPath, args...)
 
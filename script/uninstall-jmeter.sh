@@ -16,4 +16,7 @@ sudo rm -rf "$JMETER_PATH"
 sudo rm -rf "${JMETER_WORK_DIR}/jdk"
 sudo rm -f /etc/profile.d/cm-ant-jmeter.sh
 
+# k6 is fetched into the same work dir the first time a run uses it.
+sudo rm -rf "${JMETER_WORK_DIR}"/k6-v*
+
 echo "[CM-ANT] JMeter uninstalled."